@@ -0,0 +1,68 @@
+// Package adapt moves elements between containers of different kinds
+// through iterators, one element at a time, without intermediate slices.
+//
+// Where a container can be viewed as another kind without copying, its
+// package provides a constructor instead, such as list.AsQueue,
+// heap.WrapSlice and sortedslice.Wrap. The functions here cover the other
+// cases; for example, to move the elements of a queue into a heap:
+//
+//	adapt.PushAll(h, adapt.Drain(q))
+package adapt
+
+import (
+	"iter"
+
+	"github.com/weiwenchen2022/container"
+)
+
+// Drain returns an iterator that dequeues the elements of q as it yields
+// them, so q is empty after a complete iteration. Elements not yet yielded
+// when the iteration stops stay in q.
+func Drain[E any](q container.Queue[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for v, ok := q.Dequeue(); ok; v, ok = q.Dequeue() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// PopAll returns an iterator that pops the elements of s as it yields
+// them, from the top of the stack down.
+func PopAll[E any](s container.Stack[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for v, ok := s.Pop(); ok; v, ok = s.Pop() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// PopOrdered returns an iterator that pops the elements of q as it yields
+// them, in order of priority.
+func PopOrdered[E any](q container.PriorityQueue[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for q.Len() > 0 {
+			if !yield(q.Pop()) {
+				return
+			}
+		}
+	}
+}
+
+// EnqueueAll adds the values of seq to the back of q, in order.
+func EnqueueAll[E any](q container.Queue[E], seq iter.Seq[E]) {
+	for v := range seq {
+		q.Enqueue(v)
+	}
+}
+
+// PushAll pushes the values of seq onto s, which may be a
+// container.Stack or a container.PriorityQueue.
+func PushAll[E any](s interface{ Push(E) }, seq iter.Seq[E]) {
+	for v := range seq {
+		s.Push(v)
+	}
+}
@@ -0,0 +1,38 @@
+package adapt
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/list"
+)
+
+func TestMoves(t *testing.T) {
+	t.Parallel()
+
+	l := list.New[int]()
+	q := list.AsQueue(l)
+	EnqueueAll(q, slices.Values([]int{5, 1, 4, 2, 3}))
+
+	h := heap.New(func(a, b int) bool { return a < b })
+	PushAll(h, Drain(q))
+	if l.Len() != 0 {
+		t.Errorf("l.Len() after Drain = %d, want 0", l.Len())
+	}
+
+	s := list.AsStack(list.New[int]())
+	for v := range PopOrdered(h) {
+		s.Push(v)
+		if v == 3 {
+			break
+		}
+	}
+	if h.Len() != 2 {
+		t.Errorf("h.Len() after stopping early = %d, want 2", h.Len())
+	}
+
+	if got := slices.Collect(PopAll(s)); !slices.Equal(got, []int{3, 2, 1}) {
+		t.Errorf("PopAll = %v, want [3 2 1]", got)
+	}
+}
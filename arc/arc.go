@@ -0,0 +1,303 @@
+// Package arc implements an Adaptive Replacement Cache.
+//
+// ARC (Megiddo and Modha, "ARC: A Self-Tuning, Low Overhead Replacement
+// Cache") keeps two lists of resident entries: T1 for entries seen once
+// recently and T2 for entries seen at least twice. Two ghost lists, B1 and
+// B2, remember the keys recently evicted from T1 and T2. A hit in a ghost
+// list shifts the target size of T1, so the cache continuously adapts
+// between favoring recency and favoring frequency.
+//
+// A Cache is not safe for concurrent use by multiple goroutines.
+package arc
+
+import (
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/cachestats"
+	"github.com/weiwenchen2022/container/internal/memsize"
+	"github.com/weiwenchen2022/container/list"
+)
+
+const (
+	t1 = iota
+	t2
+	b1
+	b2
+)
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	where int // which of the four lists holds the entry
+}
+
+// Cache is an ARC cache holding at most a fixed number of entries.
+// To create a cache use arc.New.
+type Cache[K comparable, V any] struct {
+	capacity int
+	p        int // target size of T1
+
+	lists [4]*list.List[entry[K, V]] // front is most recently used
+	items map[K]*list.Element[entry[K, V]]
+
+	onEvict func(K, V)
+	onHit   func(K, V)
+
+	stats cachestats.Recorder
+}
+
+type option[K comparable, V any] func(*Cache[K, V])
+
+// WithOnEvict sets a function called with the key and value of each entry
+// evicted to make room for another.
+// It is not called for entries removed with Remove or Clear.
+func WithOnEvict[K comparable, V any](f func(K, V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onEvict = f
+	}
+}
+
+// WithOnHit sets a function called with the key and value of each entry
+// found by Get.
+func WithOnHit[K comparable, V any](f func(K, V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onHit = f
+	}
+}
+
+// New returns an empty cache holding at most capacity entries.
+// It panics if capacity is not positive.
+func New[K comparable, V any](capacity int, opts ...option[K, V]) *Cache[K, V] {
+	if capacity <= 0 {
+		panic("arc: non-positive capacity")
+	}
+
+	c := &Cache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element[entry[K, V]]),
+	}
+	for i := range c.lists {
+		c.lists[i] = list.New[entry[K, V]]()
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Len returns the number of resident entries in cache c.
+func (c *Cache[K, V]) Len() int { return c.lists[t1].Len() + c.lists[t2].Len() }
+
+// Cap returns the maximum number of entries cache c holds.
+func (c *Cache[K, V]) Cap() int { return c.capacity }
+
+// Sizes returns the lengths of the T1, T2, B1 and B2 lists.
+func (c *Cache[K, V]) Sizes() (t1Len, t2Len, b1Len, b2Len int) {
+	return c.lists[t1].Len(), c.lists[t2].Len(), c.lists[b1].Len(), c.lists[b2].Len()
+}
+
+// Target returns the current adaptive target size of the T1 list.
+func (c *Cache[K, V]) Target() int { return c.p }
+
+// Hits returns the number of calls to Get that found their key.
+func (c *Cache[K, V]) Hits() uint64 { return c.stats.Hits() }
+
+// Misses returns the number of calls to Get that did not find their key.
+func (c *Cache[K, V]) Misses() uint64 { return c.stats.Misses() }
+
+// Stats returns a snapshot of the statistics of cache c.
+func (c *Cache[K, V]) Stats() cachestats.Stats { return c.stats.Stats() }
+
+// resident returns the element holding key k if it is in T1 or T2.
+func (c *Cache[K, V]) resident(k K) *list.Element[entry[K, V]] {
+	if e, ok := c.items[k]; ok && (e.Value.where == t1 || e.Value.where == t2) {
+		return e
+	}
+
+	return nil
+}
+
+// move moves e to the front of list where, returning the new element.
+func (c *Cache[K, V]) move(e *list.Element[entry[K, V]], where int) *list.Element[entry[K, V]] {
+	if e.Value.where == where {
+		c.lists[where].MoveToFront(e)
+		return e
+	}
+
+	x := c.lists[e.Value.where].Remove(e)
+	x.where = where
+	e = c.lists[where].PushFront(x)
+	c.items[x.key] = e
+	return e
+}
+
+// Get returns the value stored for key k and records the access.
+// The ok result reports whether the key was resident.
+func (c *Cache[K, V]) Get(k K) (v V, ok bool) {
+	e := c.resident(k)
+	if e == nil {
+		c.stats.Miss()
+		return v, false
+	}
+
+	c.stats.Hit()
+	e = c.move(e, t2)
+	if c.onHit != nil {
+		c.onHit(k, e.Value.value)
+	}
+
+	return e.Value.value, true
+}
+
+// Peek returns the value stored for key k without recording an access.
+// The ok result reports whether the key was resident.
+func (c *Cache[K, V]) Peek(k K) (v V, ok bool) {
+	if e := c.resident(k); e != nil {
+		return e.Value.value, true
+	}
+
+	return v, false
+}
+
+// Contains reports whether key k is resident in cache c, without recording an access.
+func (c *Cache[K, V]) Contains(k K) bool { return c.resident(k) != nil }
+
+// Put stores value v for key k and records the access, evicting an entry
+// if the cache is full.
+func (c *Cache[K, V]) Put(k K, v V) {
+	if e, ok := c.items[k]; ok {
+		switch e.Value.where {
+		case t1, t2:
+		case b1:
+			c.p = min(c.capacity, c.p+max(c.lists[b2].Len()/c.lists[b1].Len(), 1))
+			c.replace(false)
+		case b2:
+			c.p = max(0, c.p-max(c.lists[b1].Len()/c.lists[b2].Len(), 1))
+			c.replace(true)
+		}
+
+		e = c.move(e, t2)
+		e.Value.value = v
+		return
+	}
+
+	l1 := c.lists[t1].Len() + c.lists[b1].Len()
+	l2 := c.lists[t2].Len() + c.lists[b2].Len()
+	switch {
+	case l1 == c.capacity:
+		if c.lists[t1].Len() < c.capacity {
+			c.drop(b1)
+			c.replace(false)
+		} else {
+			e := c.lists[t1].Back()
+			c.lists[t1].Remove(e)
+			delete(c.items, e.Value.key)
+			c.evicted(e.Value)
+		}
+	case l1 < c.capacity && l1+l2 >= c.capacity:
+		if l1+l2 == 2*c.capacity {
+			c.drop(b2)
+		}
+		c.replace(false)
+	}
+
+	c.items[k] = c.lists[t1].PushFront(entry[K, V]{key: k, value: v, where: t1})
+}
+
+// replace evicts the LRU entry of T1 or T2 into the corresponding ghost list.
+func (c *Cache[K, V]) replace(inB2 bool) {
+	from, to := t2, b2
+	if n := c.lists[t1].Len(); n > 0 && (n > c.p || inB2 && n == c.p) {
+		from, to = t1, b1
+	}
+
+	e := c.lists[from].Back()
+	if e == nil {
+		return
+	}
+
+	x := e.Value
+	var zero V
+	e.Value.value = zero // avoid memory leak
+	c.move(e, to)
+	c.evicted(x)
+}
+
+// drop forgets the LRU key of ghost list where.
+func (c *Cache[K, V]) drop(where int) {
+	if e := c.lists[where].Back(); e != nil {
+		c.lists[where].Remove(e)
+		delete(c.items, e.Value.key)
+	}
+}
+
+func (c *Cache[K, V]) evicted(x entry[K, V]) {
+	c.stats.Evict()
+
+	if c.onEvict != nil {
+		c.onEvict(x.key, x.value)
+	}
+}
+
+// Remove removes the entry for key k, forgetting it entirely, and reports
+// whether it was resident.
+func (c *Cache[K, V]) Remove(k K) bool {
+	e, ok := c.items[k]
+	if !ok {
+		return false
+	}
+
+	delete(c.items, k)
+	c.lists[e.Value.where].Remove(e)
+	return e.Value.where == t1 || e.Value.where == t2
+}
+
+// Clear removes all entries from cache c and resets its adaptation.
+// The statistics are not reset.
+func (c *Cache[K, V]) Clear() {
+	for _, l := range c.lists {
+		l.Init()
+	}
+	clear(c.items)
+	c.p = 0
+}
+
+// Clone returns a copy of cache c with the same resident entries, ghost
+// entries, adaptation target, options and statistics. The values are
+// copied by assignment, so this is a shallow clone.
+func (c *Cache[K, V]) Clone() *Cache[K, V] {
+	return c.CloneFunc(func(v V) V { return v })
+}
+
+// CloneFunc is like Clone but copies each resident value with f, which
+// allows a deep clone.
+func (c *Cache[K, V]) CloneFunc(f func(V) V) *Cache[K, V] {
+	d := *c
+	d.items = make(map[K]*list.Element[entry[K, V]], len(c.items))
+	d.stats = c.stats.Clone()
+	for i, l := range c.lists {
+		d.lists[i] = list.New[entry[K, V]]()
+		for e := l.Front(); e != nil; e = e.Next() {
+			x := e.Value
+			if i == t1 || i == t2 {
+				x.value = f(x.value)
+			}
+			d.items[x.key] = d.lists[i].PushBack(x)
+		}
+	}
+
+	return &d
+}
+
+// MemStats returns statistics about the memory used by cache c, including
+// the ghost entries that remember recently evicted keys.
+// Cap is the capacity of the cache.
+func (c *Cache[K, V]) MemStats() container.MemStats {
+	bytes := memsize.Of[Cache[K, V]]() + memsize.Map[K, *list.Element[entry[K, V]]](len(c.items))
+	for _, l := range c.lists {
+		bytes += l.MemStats().Bytes
+	}
+
+	return container.MemStats{Len: c.Len(), Cap: c.capacity, Bytes: bytes}
+}
@@ -0,0 +1,198 @@
+package arc
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container = (*Cache[int, int])(nil)
+	_ container.Sizer     = (*Cache[int, int])(nil)
+)
+
+func (c *Cache[K, V]) verify(t *testing.T) {
+	t.Helper()
+
+	n1, n2, g1, g2 := c.Sizes()
+	if n1+n2 > c.capacity {
+		t.Fatalf("|T1|+|T2| = %d > %d", n1+n2, c.capacity)
+	}
+	if n1+g1 > c.capacity {
+		t.Fatalf("|T1|+|B1| = %d > %d", n1+g1, c.capacity)
+	}
+	if n1+n2+g1+g2 > 2*c.capacity {
+		t.Fatalf("total = %d > %d", n1+n2+g1+g2, 2*c.capacity)
+	}
+	if c.p < 0 || c.p > c.capacity {
+		t.Fatalf("p = %d out of [0, %d]", c.p, c.capacity)
+	}
+	if len(c.items) != n1+n2+g1+g2 {
+		t.Fatalf("len(items) = %d, want %d", len(c.items), n1+n2+g1+g2)
+	}
+	for where, l := range c.lists {
+		for e := l.Front(); e != nil; e = e.Next() {
+			if e.Value.where != where || c.items[e.Value.key] != e {
+				t.Fatalf("entry %v misplaced", e.Value.key)
+			}
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	evictions := 0
+	c := New(64, WithOnEvict(func(k, v int) {
+		if k != v {
+			t.Fatalf("evicted %d with value %d", k, v)
+		}
+		evictions++
+	}))
+
+	for i := 0; i < 20000; i++ {
+		k := int(r.ExpFloat64() * 50)
+		if v, ok := c.Get(k); ok {
+			if v != k {
+				t.Fatalf("c.Get(%d) = %d", k, v)
+			}
+		} else {
+			c.Put(k, k)
+		}
+
+		if r.Intn(100) == 0 {
+			c.Remove(r.Intn(200))
+		}
+
+		c.verify(t)
+	}
+
+	if evictions == 0 {
+		t.Errorf("no evictions")
+	}
+	if c.Hits()+c.Misses() != 20000 {
+		t.Errorf("hits + misses = %d, want %d", c.Hits()+c.Misses(), 20000)
+	}
+}
+
+func TestScanResistance(t *testing.T) {
+	t.Parallel()
+
+	c := New[int, int](10)
+
+	// establish a frequently used working set
+	for round := 0; round < 3; round++ {
+		for k := 0; k < 5; k++ {
+			if _, ok := c.Get(k); !ok {
+				c.Put(k, k)
+			}
+		}
+	}
+
+	// a long scan of one-time keys
+	for k := 100; k < 200; k++ {
+		c.Put(k, k)
+		c.verify(t)
+	}
+
+	for k := 0; k < 5; k++ {
+		if !c.Contains(k) {
+			t.Errorf("frequent key %d evicted by scan", k)
+		}
+	}
+
+	c.Clear()
+	if n1, n2, g1, g2 := c.Sizes(); n1+n2+g1+g2 != 0 || c.Len() != 0 {
+		t.Errorf("sizes after Clear = %d %d %d %d", n1, n2, g1, g2)
+	}
+}
+
+func TestGhostHit(t *testing.T) {
+	t.Parallel()
+
+	c := New[int, int](2)
+	c.Put(1, 1)
+	c.Get(1) // 1 moves to T2
+	c.Put(2, 2)
+	c.Put(3, 3) // 2 moves to B1
+
+	if _, _, g1, _ := c.Sizes(); g1 != 1 {
+		t.Fatalf("|B1| = %d, want 1", g1)
+	}
+
+	c.Put(2, 20) // ghost hit grows the T1 target
+	if p := c.Target(); p != 1 {
+		t.Errorf("c.Target() = %d, want 1", p)
+	}
+	if v, ok := c.Peek(2); !ok || v != 20 {
+		t.Errorf("c.Peek(2) = %d, %t, want 20, true", v, ok)
+	}
+	c.verify(t)
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	hits := 0
+	c := New(1, WithOnHit(func(k, v int) { hits++ }))
+	c.Put(1, 1)
+	c.Get(1)
+	c.Get(2)
+	c.Put(2, 2)
+
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.Evictions != 1 || hits != 1 {
+		t.Errorf("c.Stats() = %+v, hits = %d", s, hits)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	c := New[int, int](4)
+	for i := range 10 {
+		c.Put(i, i)
+		c.Get(i / 2)
+	}
+
+	resident := func(c *Cache[int, int]) (ks []int) {
+		for k := range 10 {
+			if c.Contains(k) {
+				ks = append(ks, k)
+			}
+		}
+		return ks
+	}
+	want := resident(c)
+	n1, n2, g1, g2 := c.Sizes()
+
+	d := c.Clone()
+	d.verify(t)
+	if d.Target() != c.Target() || !slices.Equal(resident(d), want) || d.Stats() != c.Stats() {
+		t.Errorf("clone state differs from the cache")
+	}
+	if m1, m2, h1, h2 := d.Sizes(); m1 != n1 || m2 != n2 || h1 != g1 || h2 != g2 {
+		t.Errorf("clone sizes = %d, %d, %d, %d, want %d, %d, %d, %d", m1, m2, h1, h2, n1, n2, g1, g2)
+	}
+
+	for i := range 10 {
+		d.Put(100+i, i)
+	}
+	d.verify(t)
+	c.verify(t)
+	if got := resident(c); !slices.Equal(got, want) {
+		t.Errorf("changing the clone changed the cache: resident %v, want %v", got, want)
+	}
+
+	neg := c.CloneFunc(func(v int) int { return -v })
+	neg.verify(t)
+	for _, k := range want {
+		if v, _ := neg.Peek(k); v != -k {
+			t.Errorf("neg.Peek(%d) = %d, want %d", k, v, -k)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package bloom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateParameters(t *testing.T) {
+	t.Parallel()
+
+	m, k := EstimateParameters(1000, 0.01)
+	if m != 9586 || k != 7 {
+		t.Errorf("EstimateParameters(1000, 0.01) = %d, %d, want 9586, 7", m, k)
+	}
+
+	if p := FalsePositiveRate(m, k, 1000); math.Abs(p-0.01) > 0.001 {
+		t.Errorf("FalsePositiveRate(%d, %d, 1000) = %v, want ~0.01", m, k, p)
+	}
+}
+
+func TestCountingFilter(t *testing.T) {
+	t.Parallel()
+
+	const n = 1000
+
+	f := NewCountingWithEstimates[int](n, 0.01)
+	for i := 0; i < n; i++ {
+		f.Add(i)
+	}
+
+	for i := 0; i < n; i++ {
+		if !f.Test(i) {
+			t.Fatalf("f.Test(%d) = false after Add", i)
+		}
+	}
+
+	fp := 0
+	for i := n; i < 11*n; i++ {
+		if f.Test(i) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / (10 * n); rate > 0.03 {
+		t.Errorf("false positive rate = %v, want ~0.01", rate)
+	}
+
+	for i := 0; i < n/2; i++ {
+		if !f.Remove(i) {
+			t.Fatalf("f.Remove(%d) = false", i)
+		}
+	}
+
+	for i := n / 2; i < n; i++ {
+		if !f.Test(i) {
+			t.Fatalf("f.Test(%d) = false after removing other keys", i)
+		}
+	}
+
+	removed := 0
+	for i := 0; i < n/2; i++ {
+		if !f.Test(i) {
+			removed++
+		}
+	}
+	if removed < n/2*9/10 {
+		t.Errorf("only %d of %d removed keys test negative", removed, n/2)
+	}
+
+	f.Clear()
+	if f.Test(n - 1) {
+		t.Errorf("f.Test after Clear = true")
+	}
+	if f.Remove(n - 1) {
+		t.Errorf("f.Remove after Clear = true")
+	}
+}
+
+func TestSaturation(t *testing.T) {
+	t.Parallel()
+
+	f := NewCounting[string](64, 3)
+	for i := 0; i < 100; i++ {
+		f.Add("x")
+	}
+
+	// saturated counters never reach zero
+	for i := 0; i < 100; i++ {
+		f.Remove("x")
+	}
+	if !f.Test("x") {
+		t.Errorf("saturated key tests negative")
+	}
+}
+
+func TestCountingFilterClone(t *testing.T) {
+	t.Parallel()
+
+	f := NewCounting[string](1024, 4)
+	f.Add("a")
+
+	c := f.Clone()
+	c.Add("b")
+	c.Remove("a")
+	if !f.Test("a") || !c.Test("b") || c.Test("a") {
+		t.Errorf("clone is not independent of the filter")
+	}
+}
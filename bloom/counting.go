@@ -0,0 +1,114 @@
+// Package bloom implements Bloom filters.
+//
+// A Bloom filter is a space-efficient probabilistic set: Test may report
+// false positives but never false negatives.
+package bloom
+
+import "hash/maphash"
+
+const maxCounter = 15
+
+// CountingFilter is a Bloom filter of 4-bit counters that supports
+// removal. A counter that reaches its maximum saturates: it is never
+// decremented again, which keeps Remove from introducing false negatives
+// at the cost of a slightly higher false positive rate.
+// To create a filter use bloom.NewCounting or bloom.NewCountingWithEstimates.
+type CountingFilter[K comparable] struct {
+	seed maphash.Seed
+
+	m, k     uint
+	counters []byte // two 4-bit counters per byte
+}
+
+// NewCounting returns an empty counting filter with m counters and k hash functions.
+func NewCounting[K comparable](m, k uint) *CountingFilter[K] {
+	m, k = max(m, 1), max(k, 1)
+
+	return &CountingFilter[K]{
+		seed:     maphash.MakeSeed(),
+		m:        m,
+		k:        k,
+		counters: make([]byte, (m+1)/2),
+	}
+}
+
+// NewCountingWithEstimates returns an empty counting filter sized to hold
+// n elements with a false positive rate of at most p.
+func NewCountingWithEstimates[K comparable](n uint, p float64) *CountingFilter[K] {
+	return NewCounting[K](EstimateParameters(n, p))
+}
+
+// Cap returns the number of counters of filter f.
+func (f *CountingFilter[K]) Cap() uint { return f.m }
+
+// K returns the number of hash functions of filter f.
+func (f *CountingFilter[K]) K() uint { return f.k }
+
+func (f *CountingFilter[K]) get(i uint) byte {
+	return f.counters[i/2] >> (4 * (i % 2)) & 0xf
+}
+
+func (f *CountingFilter[K]) set(i uint, c byte) {
+	shift := 4 * (i % 2)
+	f.counters[i/2] = f.counters[i/2]&^(0xf<<shift) | c<<shift
+}
+
+// locations calls fn with each of the k counter indexes of key x.
+func (f *CountingFilter[K]) locations(x K, fn func(uint) bool) bool {
+	h := maphash.Comparable(f.seed, x)
+	h1, h2 := h, h>>32|1
+	for i := uint64(0); i < uint64(f.k); i++ {
+		if !fn(uint((h1 + i*h2) % uint64(f.m))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Add adds key x to filter f.
+func (f *CountingFilter[K]) Add(x K) {
+	f.locations(x, func(i uint) bool {
+		if c := f.get(i); c < maxCounter {
+			f.set(i, c+1)
+		}
+
+		return true
+	})
+}
+
+// Test reports whether key x may be in filter f.
+// A false result means x is definitely not in f.
+func (f *CountingFilter[K]) Test(x K) bool {
+	return f.locations(x, func(i uint) bool { return f.get(i) > 0 })
+}
+
+// Remove removes one occurrence of key x from filter f.
+// It reports false, leaving f unchanged, if x is definitely not in f.
+// Removing a key that was never added may introduce false negatives for
+// other keys.
+func (f *CountingFilter[K]) Remove(x K) bool {
+	if !f.Test(x) {
+		return false
+	}
+
+	f.locations(x, func(i uint) bool {
+		if c := f.get(i); c < maxCounter {
+			f.set(i, c-1)
+		}
+
+		return true
+	})
+
+	return true
+}
+
+// Clear removes all keys from filter f.
+func (f *CountingFilter[K]) Clear() { clear(f.counters) }
+
+// Clone returns a copy of filter f.
+func (f *CountingFilter[K]) Clone() *CountingFilter[K] {
+	c := *f
+	c.counters = append([]byte(nil), f.counters...)
+	return &c
+}
@@ -0,0 +1,21 @@
+package bloom
+
+import "math"
+
+// EstimateParameters returns the number of counters m and hash functions k
+// a filter needs to hold n elements with a false positive rate of at most p.
+func EstimateParameters(n uint, p float64) (m, k uint) {
+	if n == 0 {
+		n = 1
+	}
+
+	m = uint(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = uint(math.Round(float64(m) / float64(n) * math.Ln2))
+	return max(m, 1), max(k, 1)
+}
+
+// FalsePositiveRate returns the expected false positive rate of a filter
+// with m counters and k hash functions holding n elements.
+func FalsePositiveRate(m, k, n uint) float64 {
+	return math.Pow(1-math.Exp(-float64(k)*float64(n)/float64(m)), float64(k))
+}
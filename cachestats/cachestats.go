@@ -0,0 +1,106 @@
+// Package cachestats provides the statistics reported by the caches in this module.
+//
+// The caches record their activity in a Recorder and expose a Stats
+// snapshot through their Stats method, so metrics can be exported without
+// wrapping every call. Recorder may also be used by caches built outside
+// this module to report the same statistics.
+package cachestats
+
+import (
+	"slices"
+	"time"
+)
+
+// maxSamples bounds the number of load latencies retained for percentiles.
+const maxSamples = 1024
+
+// Percentiles summarizes a latency distribution.
+type Percentiles struct {
+	P50, P90, P99, Max time.Duration
+}
+
+// Stats is a snapshot of a cache's statistics.
+type Stats struct {
+	Hits      uint64 // lookups that found their key
+	Misses    uint64 // lookups that did not find their key
+	Evictions uint64 // entries removed to make room or because they expired
+
+	// Loads is the number of values produced by a loader function, and
+	// LoadLatency the distribution of the most recent load durations.
+	// Both are zero for caches that do not load values.
+	Loads       uint64
+	LoadLatency Percentiles
+}
+
+// HitRatio returns the fraction of lookups that were hits, or 0 if there were none.
+func (s Stats) HitRatio() float64 {
+	if n := s.Hits + s.Misses; n > 0 {
+		return float64(s.Hits) / float64(n)
+	}
+
+	return 0
+}
+
+// Recorder accumulates cache statistics.
+// The zero value for Recorder is ready to use.
+// A Recorder is not safe for concurrent use; callers synchronize access.
+type Recorder struct {
+	hits, misses, evictions, loads uint64
+
+	samples []time.Duration // ring of the latest load latencies
+	next    int
+}
+
+// Hit records a lookup that found its key.
+func (r *Recorder) Hit() { r.hits++ }
+
+// Miss records a lookup that did not find its key.
+func (r *Recorder) Miss() { r.misses++ }
+
+// Evict records the eviction of an entry.
+func (r *Recorder) Evict() { r.evictions++ }
+
+// Load records a value load that took d.
+func (r *Recorder) Load(d time.Duration) {
+	r.loads++
+
+	if len(r.samples) < maxSamples {
+		r.samples = append(r.samples, d)
+		return
+	}
+
+	r.samples[r.next] = d
+	r.next = (r.next + 1) % maxSamples
+}
+
+// Hits returns the number of recorded hits.
+func (r *Recorder) Hits() uint64 { return r.hits }
+
+// Misses returns the number of recorded misses.
+func (r *Recorder) Misses() uint64 { return r.misses }
+
+// Stats returns a snapshot of the recorded statistics.
+// The complexity is O(k log k) where k is the number of retained load latencies.
+func (r *Recorder) Stats() Stats {
+	s := Stats{Hits: r.hits, Misses: r.misses, Evictions: r.evictions, Loads: r.loads}
+
+	if n := len(r.samples); n > 0 {
+		sorted := slices.Clone(r.samples)
+		slices.Sort(sorted)
+
+		at := func(q float64) time.Duration { return sorted[int(q*float64(n-1)+0.5)] }
+		s.LoadLatency = Percentiles{P50: at(0.5), P90: at(0.9), P99: at(0.99), Max: sorted[n-1]}
+	}
+
+	return s
+}
+
+// Reset clears all recorded statistics.
+func (r *Recorder) Reset() { *r = Recorder{} }
+
+// Clone returns a copy of recorder r that records independently of it.
+func (r *Recorder) Clone() Recorder {
+	c := *r
+	c.samples = append([]time.Duration(nil), r.samples...)
+	return c
+}
@@ -0,0 +1,70 @@
+package cachestats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	var r Recorder
+	if s := r.Stats(); s != (Stats{}) {
+		t.Errorf("zero Recorder Stats() = %+v", s)
+	}
+
+	r.Hit()
+	r.Hit()
+	r.Hit()
+	r.Miss()
+	r.Evict()
+
+	for i := 1; i <= 100; i++ {
+		r.Load(time.Duration(i) * time.Millisecond)
+	}
+
+	s := r.Stats()
+	if s.Hits != 3 || s.Misses != 1 || s.Evictions != 1 || s.Loads != 100 {
+		t.Errorf("r.Stats() = %+v", s)
+	}
+
+	if ratio := s.HitRatio(); ratio != 0.75 {
+		t.Errorf("s.HitRatio() = %v, want 0.75", ratio)
+	}
+
+	want := Percentiles{P50: 51 * time.Millisecond, P90: 90 * time.Millisecond, P99: 99 * time.Millisecond, Max: 100 * time.Millisecond}
+	if s.LoadLatency != want {
+		t.Errorf("s.LoadLatency = %+v, want %+v", s.LoadLatency, want)
+	}
+
+	// only the latest samples are retained
+	for i := 0; i < maxSamples; i++ {
+		r.Load(time.Second)
+	}
+	if p := r.Stats().LoadLatency; p.P50 != time.Second || p.Max != time.Second {
+		t.Errorf("LoadLatency after overflow = %+v", p)
+	}
+
+	r.Reset()
+	if s := r.Stats(); s != (Stats{}) {
+		t.Errorf("Stats() after Reset = %+v", s)
+	}
+}
+
+func TestRecorderClone(t *testing.T) {
+	t.Parallel()
+
+	var r Recorder
+	r.Hit()
+	r.Load(time.Millisecond)
+
+	c := r.Clone()
+	c.Hit()
+	for i := 0; i < 2000; i++ {
+		c.Load(time.Second)
+	}
+
+	if s := r.Stats(); s.Hits != 1 || s.Loads != 1 || s.LoadLatency.Max != time.Millisecond {
+		t.Errorf("r.Stats() = %+v after changing the clone", s)
+	}
+}
@@ -0,0 +1,92 @@
+// Package clone copies composite values, including the containers of this
+// module nested in one another.
+package clone
+
+import "reflect"
+
+// Deep returns a deep copy of v.
+//
+// A value with a CloneFunc method, as the containers of this module have,
+// is copied by calling it with functions that copy each element, or node
+// and edge payload, with Deep. A value with only a Clone method is copied
+// by calling it. Slices, arrays, maps and the dynamic values of interfaces
+// are copied element by element; map keys are copied by assignment.
+// Other values, including pointers to types without Clone methods,
+// structs, channels and functions, are copied by assignment.
+//
+// A CloneFunc or Clone method is recognized if it returns a value of the
+// receiver's type and every argument of CloneFunc is a func(X) X.
+func Deep[T any](v T) T {
+	return deep(reflect.ValueOf(&v).Elem()).Interface().(T)
+}
+
+func deep(v reflect.Value) reflect.Value {
+	t := v.Type()
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+	}
+
+	if m := v.MethodByName("CloneFunc"); m.IsValid() && isCloneFunc(m.Type(), t) {
+		args := make([]reflect.Value, m.Type().NumIn())
+		for i := range args {
+			args[i] = reflect.MakeFunc(m.Type().In(i), func(in []reflect.Value) []reflect.Value {
+				return []reflect.Value{deep(in[0])}
+			})
+		}
+
+		return m.Call(args)[0]
+	}
+
+	if m := v.MethodByName("Clone"); m.IsValid() && m.Type().NumIn() == 0 && returns(m.Type(), t) {
+		return m.Call(nil)[0]
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		c := reflect.MakeSlice(t, v.Len(), v.Cap())
+		for i := range v.Len() {
+			c.Index(i).Set(deep(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(t).Elem()
+		for i := range v.Len() {
+			c.Index(i).Set(deep(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		c := reflect.MakeMapWithSize(t, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			c.SetMapIndex(it.Key(), deep(it.Value()))
+		}
+		return c
+	case reflect.Interface:
+		c := reflect.New(t).Elem()
+		c.Set(deep(v.Elem()))
+		return c
+	}
+
+	return v
+}
+
+func returns(m, t reflect.Type) bool {
+	return m.NumOut() == 1 && m.Out(0) == t
+}
+
+func isCloneFunc(m, t reflect.Type) bool {
+	if !returns(m, t) || m.NumIn() == 0 {
+		return false
+	}
+
+	for i := range m.NumIn() {
+		f := m.In(i)
+		if f.Kind() != reflect.Func || f.NumIn() != 1 || f.NumOut() != 1 || f.In(0) != f.Out(0) {
+			return false
+		}
+	}
+
+	return true
+}
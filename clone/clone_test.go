@@ -0,0 +1,100 @@
+package clone
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container/graph"
+	"github.com/weiwenchen2022/container/list"
+	"github.com/weiwenchen2022/container/wbtree"
+)
+
+func values[E any](l *list.List[E]) []E {
+	var vs []E
+	for e := l.Front(); e != nil; e = e.Next() {
+		vs = append(vs, e.Value)
+	}
+
+	return vs
+}
+
+func TestDeepNestedLists(t *testing.T) {
+	t.Parallel()
+
+	inner := list.New[int]()
+	inner.PushBackSlice([]int{1, 2})
+	outer := list.New[*list.List[int]]()
+	outer.PushBack(inner)
+
+	c := Deep(outer)
+	if c == outer || c.Len() != 1 || c.Front().Value == inner {
+		t.Fatalf("Deep did not copy the nested list")
+	}
+
+	c.Front().Value.PushBack(3)
+	if !slices.Equal(values(inner), []int{1, 2}) {
+		t.Errorf("changing the clone changed the original: %v", values(inner))
+	}
+}
+
+func TestDeepBuiltin(t *testing.T) {
+	t.Parallel()
+
+	m := map[string][]int{"a": {1, 2}}
+	c := Deep(m)
+	c["a"][0] = 9
+	c["b"] = nil
+	if m["a"][0] != 1 || len(m) != 1 {
+		t.Errorf("changing the clone changed the original: %v", m)
+	}
+
+	arr := [2][]string{{"x"}, {"y"}}
+	ca := Deep(arr)
+	ca[0][0] = "z"
+	if arr[0][0] != "x" {
+		t.Errorf("changing the cloned array changed the original")
+	}
+
+	var iface any = []int{1}
+	ci := Deep(iface)
+	ci.([]int)[0] = 2
+	if iface.([]int)[0] != 1 {
+		t.Errorf("changing the cloned interface changed the original")
+	}
+
+	var nilList *list.List[int]
+	if Deep(nilList) != nil {
+		t.Errorf("Deep(nil) != nil")
+	}
+	if Deep(42) != 42 {
+		t.Errorf("Deep(42) != 42")
+	}
+}
+
+func TestDeepContainers(t *testing.T) {
+	t.Parallel()
+
+	m := wbtree.New[int, []string](func(a, b int) bool { return a < b })
+	m.Set(1, []string{"one"})
+	cm := Deep(m)
+	v, _ := cm.Get(1)
+	v[0] = "uno"
+	if v, _ := m.Get(1); v[0] != "one" {
+		t.Errorf("changing the cloned map changed the original: %v", v)
+	}
+
+	g := graph.NewDirected[string, graph.Attrs, graph.Attrs]()
+	g.SetNode("a", graph.Attrs{"color": "red"})
+	g.AddEdge("a", "b", graph.Attrs{"weight": "1"})
+	cg := Deep(g)
+	a, _ := cg.Node("a")
+	a["color"] = "blue"
+	e, _ := cg.Edge("a", "b")
+	e["weight"] = "2"
+	if a, _ := g.Node("a"); a["color"] != "red" {
+		t.Errorf("changing the cloned node payload changed the original")
+	}
+	if e, _ := g.Edge("a", "b"); e["weight"] != "1" {
+		t.Errorf("changing the cloned edge payload changed the original")
+	}
+}
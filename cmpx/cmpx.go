@@ -0,0 +1,90 @@
+// Package cmpx provides helpers for building comparison functions.
+//
+// The containers of this module are ordered either by a less function,
+// reporting whether a sorts before b, or by a compare function in the style
+// of cmp.Compare, returning a negative number, zero or a positive number.
+// The helpers here build, combine and convert between the two forms.
+//
+// For example, to order people by age, oldest first, then by name:
+//
+//	slices.SortFunc(people, cmpx.Chain(
+//		cmpx.ReverseCompare(cmpx.CompareBy(func(p Person) int { return p.Age })),
+//		cmpx.CompareBy(func(p Person) string { return p.Name }),
+//	))
+package cmpx
+
+import "cmp"
+
+// Reverse returns a less function that orders elements the opposite way to less.
+func Reverse[E any](less func(a, b E) bool) func(a, b E) bool {
+	return func(a, b E) bool { return less(b, a) }
+}
+
+// ReverseCompare returns a compare function that orders elements the
+// opposite way to cmp.
+func ReverseCompare[E any](cmp func(a, b E) int) func(a, b E) int {
+	return func(a, b E) int { return cmp(b, a) }
+}
+
+// ByKey returns a less function that orders elements by the keys extracted
+// from them, ordered by less.
+func ByKey[E, K any](key func(E) K, less func(a, b K) bool) func(a, b E) bool {
+	return func(a, b E) bool { return less(key(a), key(b)) }
+}
+
+// CompareBy returns a compare function that orders elements by the keys
+// extracted from them, in the natural order of the keys.
+func CompareBy[E any, K cmp.Ordered](key func(E) K) func(a, b E) int {
+	return func(a, b E) int { return cmp.Compare(key(a), key(b)) }
+}
+
+// Chain returns a compare function that orders elements by the first of
+// cmps that does not consider them equal. With no cmps all elements are
+// equal.
+func Chain[E any](cmps ...func(a, b E) int) func(a, b E) int {
+	return func(a, b E) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	}
+}
+
+// NaNLast is like cmp.Compare, except that a NaN is considered greater than
+// any non-NaN value, instead of less.
+func NaNLast[T cmp.Ordered](a, b T) int {
+	aNaN, bNaN := a != a, b != b
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return +1
+	case bNaN:
+		return -1
+	}
+
+	return cmp.Compare(a, b)
+}
+
+// Less returns the less function corresponding to the compare function cmp.
+func Less[E any](cmp func(a, b E) int) func(a, b E) bool {
+	return func(a, b E) bool { return cmp(a, b) < 0 }
+}
+
+// Compare returns the compare function corresponding to the less function
+// less. It calls less up to twice per comparison.
+func Compare[E any](less func(a, b E) bool) func(a, b E) int {
+	return func(a, b E) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return +1
+		}
+
+		return 0
+	}
+}
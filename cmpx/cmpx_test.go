@@ -0,0 +1,66 @@
+package cmpx
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"testing"
+)
+
+type person struct {
+	name string
+	age  int
+}
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	people := []person{{"carol", 30}, {"alice", 30}, {"bob", 40}, {"dave", 20}}
+	slices.SortFunc(people, Chain(
+		ReverseCompare(CompareBy(func(p person) int { return p.age })),
+		CompareBy(func(p person) string { return p.name }),
+	))
+
+	want := []person{{"bob", 40}, {"alice", 30}, {"carol", 30}, {"dave", 20}}
+	if !slices.Equal(people, want) {
+		t.Errorf("sorted = %v, want %v", people, want)
+	}
+
+	if c := Chain[int]()(1, 2); c != 0 {
+		t.Errorf("Chain()(1, 2) = %d, want 0", c)
+	}
+}
+
+func TestLess(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+	for _, tt := range []struct{ a, b int }{{1, 2}, {2, 1}, {3, 3}} {
+		if got, want := Compare(less)(tt.a, tt.b), cmp.Compare(tt.a, tt.b); got != want {
+			t.Errorf("Compare(less)(%d, %d) = %d, want %d", tt.a, tt.b, got, want)
+		}
+		if got, want := Less(cmp.Compare[int])(tt.a, tt.b), tt.a < tt.b; got != want {
+			t.Errorf("Less(cmp.Compare)(%d, %d) = %t, want %t", tt.a, tt.b, got, want)
+		}
+		if got, want := Reverse(less)(tt.a, tt.b), tt.b < tt.a; got != want {
+			t.Errorf("Reverse(less)(%d, %d) = %t, want %t", tt.a, tt.b, got, want)
+		}
+	}
+
+	byLen := ByKey(func(s string) int { return len(s) }, less)
+	if !byLen("zz", "aaa") || byLen("aaa", "zz") {
+		t.Errorf("ByKey(len, less) orders incorrectly")
+	}
+}
+
+func TestNaNLast(t *testing.T) {
+	t.Parallel()
+
+	nan := math.NaN()
+	s := []float64{nan, 3, math.Inf(-1), nan, 1}
+	slices.SortFunc(s, NaNLast[float64])
+
+	if !slices.Equal(s[:3], []float64{math.Inf(-1), 1, 3}) || !math.IsNaN(s[3]) || !math.IsNaN(s[4]) {
+		t.Errorf("sorted = %v, want [-Inf 1 3 NaN NaN]", s)
+	}
+}
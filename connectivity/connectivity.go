@@ -0,0 +1,164 @@
+// Package connectivity answers connectivity queries on an undirected graph
+// whose edges are inserted and deleted over time.
+//
+// The graph keeps a spanning forest in a link-cut tree, so Connected and
+// edge insertions take O(log n) amortized time. Deleting an edge of the
+// spanning forest searches the other edges for a replacement and takes
+// O(m log n) time in the worst case; deleting any other edge is O(1).
+// This suits workloads, such as network-topology monitoring, where
+// queries and insertions are frequent and deletions are comparatively rare.
+package connectivity
+
+type pair[K comparable] struct{ u, v K }
+
+type edge struct {
+	count int  // multiplicity
+	tree  bool // in the spanning forest
+}
+
+// Graph is an undirected graph supporting connectivity queries.
+// To create a graph use connectivity.New.
+type Graph[K comparable] struct {
+	nodes map[K]*node
+	edges map[pair[K]]*edge
+
+	size       int // edges counted with multiplicity
+	components int
+}
+
+// New returns an empty graph.
+func New[K comparable]() *Graph[K] {
+	return &Graph[K]{
+		nodes: make(map[K]*node),
+		edges: make(map[pair[K]]*edge),
+	}
+}
+
+// Order returns the number of nodes of graph g.
+func (g *Graph[K]) Order() int { return len(g.nodes) }
+
+// Size returns the number of edges of graph g, counting parallel edges.
+func (g *Graph[K]) Size() int { return g.size }
+
+// Components returns the number of connected components of graph g.
+func (g *Graph[K]) Components() int { return g.components }
+
+// AddNode adds node u to graph g and reports whether it was not already present.
+func (g *Graph[K]) AddNode(u K) bool {
+	if _, ok := g.nodes[u]; ok {
+		return false
+	}
+
+	g.nodes[u] = new(node)
+	g.components++
+	return true
+}
+
+// HasNode reports whether node u is in graph g.
+func (g *Graph[K]) HasNode(u K) bool {
+	_, ok := g.nodes[u]
+	return ok
+}
+
+// Clone returns a copy of graph g.
+// The complexity is O((n + m) log n).
+func (g *Graph[K]) Clone() *Graph[K] {
+	c := New[K]()
+	for u := range g.nodes {
+		c.AddNode(u)
+	}
+	for k, e := range g.edges {
+		for range e.count {
+			c.AddEdge(k.u, k.v)
+		}
+	}
+
+	return c
+}
+
+func (g *Graph[K]) edge(u, v K) (pair[K], *edge) {
+	if e, ok := g.edges[pair[K]{u, v}]; ok {
+		return pair[K]{u, v}, e
+	}
+
+	return pair[K]{v, u}, g.edges[pair[K]{v, u}]
+}
+
+// AddEdge adds an edge between nodes u and v, adding the nodes if
+// necessary. Adding an edge that already exists adds a parallel edge,
+// which must be removed separately.
+func (g *Graph[K]) AddEdge(u, v K) {
+	g.AddNode(u)
+	g.AddNode(v)
+	g.size++
+
+	k, e := g.edge(u, v)
+	if e != nil {
+		e.count++
+		return
+	}
+
+	e = &edge{count: 1}
+	g.edges[k] = e
+	if nu, nv := g.nodes[u], g.nodes[v]; !connected(nu, nv) {
+		link(nu, nv)
+		e.tree = true
+		g.components--
+	}
+}
+
+// HasEdge reports whether graph g has an edge between nodes u and v.
+func (g *Graph[K]) HasEdge(u, v K) bool {
+	_, e := g.edge(u, v)
+	return e != nil
+}
+
+// RemoveEdge removes one edge between nodes u and v and reports whether
+// there was one.
+func (g *Graph[K]) RemoveEdge(u, v K) bool {
+	k, e := g.edge(u, v)
+	if e == nil {
+		return false
+	}
+
+	g.size--
+	if e.count--; e.count > 0 {
+		return true
+	}
+
+	delete(g.edges, k)
+	if !e.tree {
+		return true
+	}
+
+	cut(g.nodes[k.u], g.nodes[k.v])
+	g.components++
+
+	// Any edge now joining the two trees reconnects them.
+	for r, f := range g.edges {
+		if f.tree {
+			continue
+		}
+
+		if nu, nv := g.nodes[r.u], g.nodes[r.v]; !connected(nu, nv) {
+			link(nu, nv)
+			f.tree = true
+			g.components--
+			break
+		}
+	}
+
+	return true
+}
+
+// Connected reports whether nodes u and v are in graph g and connected by
+// a path.
+func (g *Graph[K]) Connected(u, v K) bool {
+	nu, ok := g.nodes[u]
+	if !ok {
+		return false
+	}
+
+	nv, ok := g.nodes[v]
+	return ok && connected(nu, nv)
+}
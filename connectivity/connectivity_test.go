@@ -0,0 +1,154 @@
+package connectivity
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+
+	g := New[string]()
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "a")
+	g.AddEdge("d", "e")
+
+	if !g.Connected("a", "c") || g.Connected("a", "d") || g.Components() != 2 {
+		t.Fatalf("initial connectivity is wrong")
+	}
+
+	// removing a cycle edge keeps the component
+	if !g.RemoveEdge("a", "b") || !g.Connected("a", "b") {
+		t.Errorf("a and b disconnected after removing one edge of a cycle")
+	}
+	if !g.RemoveEdge("c", "b") || g.Connected("a", "b") || g.Components() != 3 {
+		t.Errorf("b still connected after removing its last edge")
+	}
+	if g.RemoveEdge("c", "b") {
+		t.Errorf("RemoveEdge of a missing edge = true")
+	}
+
+	// parallel edges
+	g.AddEdge("d", "e")
+	g.RemoveEdge("e", "d")
+	if !g.Connected("d", "e") || !g.HasEdge("d", "e") {
+		t.Errorf("removing one of two parallel edges disconnected d and e")
+	}
+
+	if g.Connected("a", "z") || g.Connected("z", "z") {
+		t.Errorf("Connected reports a missing node")
+	}
+	if !g.Connected("a", "a") {
+		t.Errorf("a is not connected to itself")
+	}
+}
+
+// naive computes the components of the edge multiset by flood fill.
+func naive(n int, edges map[[2]int]int) []int {
+	adj := make([][]int, n)
+	for e := range edges {
+		adj[e[0]] = append(adj[e[0]], e[1])
+		adj[e[1]] = append(adj[e[1]], e[0])
+	}
+
+	comp := make([]int, n)
+	for i := range comp {
+		comp[i] = -1
+	}
+	for s := range n {
+		if comp[s] >= 0 {
+			continue
+		}
+
+		comp[s] = s
+		stack := []int{s}
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range adj[u] {
+				if comp[v] < 0 {
+					comp[v] = s
+					stack = append(stack, v)
+				}
+			}
+		}
+	}
+
+	return comp
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	const n = 40
+	r := rand.New(rand.NewPCG(1, 2))
+	g := New[int]()
+	for i := range n {
+		g.AddNode(i)
+	}
+
+	edges := make(map[[2]int]int)
+	var list [][2]int
+	for step := range 3000 {
+		if len(list) > 0 && r.IntN(5) < 2 {
+			i := r.IntN(len(list))
+			e := list[i]
+			list[i] = list[len(list)-1]
+			list = list[:len(list)-1]
+			if edges[e]--; edges[e] == 0 {
+				delete(edges, e)
+			}
+			if !g.RemoveEdge(e[1], e[0]) {
+				t.Fatalf("step %d: RemoveEdge(%v) = false", step, e)
+			}
+		} else {
+			u, v := r.IntN(n), r.IntN(n)
+			e := [2]int{min(u, v), max(u, v)}
+			edges[e]++
+			list = append(list, e)
+			g.AddEdge(u, v)
+		}
+
+		comp := naive(n, edges)
+		seen := make(map[int]bool)
+		for _, c := range comp {
+			seen[c] = true
+		}
+		if g.Components() != len(seen) {
+			t.Fatalf("step %d: Components = %d, want %d", step, g.Components(), len(seen))
+		}
+
+		for range 10 {
+			u, v := r.IntN(n), r.IntN(n)
+			if got, want := g.Connected(u, v), comp[u] == comp[v]; got != want {
+				t.Fatalf("step %d: Connected(%d, %d) = %t, want %t", step, u, v, got, want)
+			}
+		}
+	}
+
+	if g.Size() != len(list) {
+		t.Errorf("Size = %d, want %d", g.Size(), len(list))
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	g := New[int]()
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddNode(4)
+
+	c := g.Clone()
+	if c.Order() != 4 || c.Size() != 3 || c.Components() != 2 || !c.Connected(1, 3) {
+		t.Fatalf("clone: order %d, size %d, components %d", c.Order(), c.Size(), c.Components())
+	}
+
+	c.RemoveEdge(2, 3)
+	c.RemoveEdge(1, 2)
+	if !g.Connected(1, 3) || c.Connected(2, 3) || !c.Connected(1, 2) {
+		t.Errorf("clone is not independent of the graph")
+	}
+}
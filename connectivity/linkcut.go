@@ -0,0 +1,124 @@
+package connectivity
+
+// node is a node of a link-cut tree: a forest of splay trees over the
+// preferred paths of the represented trees, keyed by depth.
+type node struct {
+	ch  [2]*node
+	p   *node // parent in the splay tree, or path-parent at a splay root
+	rev bool  // children of the subtree are to be swapped
+}
+
+func (x *node) isRoot() bool {
+	return x.p == nil || x.p.ch[0] != x && x.p.ch[1] != x
+}
+
+func (x *node) push() {
+	if !x.rev {
+		return
+	}
+
+	x.ch[0], x.ch[1] = x.ch[1], x.ch[0]
+	for _, c := range x.ch {
+		if c != nil {
+			c.rev = !c.rev
+		}
+	}
+	x.rev = false
+}
+
+func (x *node) rotate() {
+	p, g := x.p, x.p.p
+	d := 0
+	if p.ch[1] == x {
+		d = 1
+	}
+
+	if !p.isRoot() {
+		if g.ch[0] == p {
+			g.ch[0] = x
+		} else {
+			g.ch[1] = x
+		}
+	}
+	x.p = g
+
+	p.ch[d] = x.ch[1-d]
+	if p.ch[d] != nil {
+		p.ch[d].p = p
+	}
+	x.ch[1-d] = p
+	p.p = x
+}
+
+func (x *node) splay() {
+	// push pending reversals from the splay root down to x
+	var path []*node
+	for y := x; ; y = y.p {
+		path = append(path, y)
+		if y.isRoot() {
+			break
+		}
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i].push()
+	}
+
+	for !x.isRoot() {
+		p := x.p
+		if !p.isRoot() {
+			if (p.p.ch[0] == p) == (p.ch[0] == x) {
+				p.rotate()
+			} else {
+				x.rotate()
+			}
+		}
+		x.rotate()
+	}
+}
+
+// access makes the path from the root of x's tree to x preferred and
+// splays x to the root of its splay tree.
+func (x *node) access() {
+	var last *node
+	for y := x; y != nil; y = y.p {
+		y.splay()
+		y.ch[1] = last
+		last = y
+	}
+	x.splay()
+}
+
+// evert makes x the root of its tree.
+func (x *node) evert() {
+	x.access()
+	x.rev = !x.rev
+}
+
+func (x *node) findRoot() *node {
+	x.access()
+	r := x
+	for r.push(); r.ch[0] != nil; r.push() {
+		r = r.ch[0]
+	}
+	r.splay()
+	return r
+}
+
+// link adds the edge x-y, which must join two different trees.
+func link(x, y *node) {
+	x.evert()
+	x.p = y
+}
+
+// cut removes the edge x-y, which must be in the forest.
+func cut(x, y *node) {
+	x.evert()
+	y.access()
+	// x is now the left child of y with no right subtree
+	y.ch[0].p = nil
+	y.ch[0] = nil
+}
+
+func connected(x, y *node) bool {
+	return x == y || x.findRoot() == y.findRoot()
+}
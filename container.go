@@ -0,0 +1,131 @@
+// Package container defines interfaces shared by the containers in this
+// module, so code can be written against a policy rather than a concrete
+// type and test suites can be reused across implementations.
+//
+// The containers in the subpackages satisfy these interfaces where their
+// methods allow; for example, *heap.Heap[E] is a PriorityQueue[E] and
+// *lru.Cache[K, V] is a Container.
+package container
+
+import "iter"
+
+// Container is implemented by mutable containers.
+type Container interface {
+	// Len returns the number of elements in the container.
+	Len() int
+
+	// Clear removes all elements from the container.
+	Clear()
+}
+
+// Iterable is implemented by containers whose elements can be iterated.
+type Iterable[E any] interface {
+	// Values returns an iterator over the elements of the container.
+	Values() iter.Seq[E]
+}
+
+// Cloner is implemented by containers that can be copied.
+// Clone returns a copy that is independent of the original: later changes
+// to either are not visible through the other. Elements are copied by
+// assignment; containers that also have a CloneFunc method accept a
+// function to copy each element, and clone.Deep copies nested containers.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// Queue is a first-in, first-out container.
+type Queue[E any] interface {
+	Container
+
+	// Enqueue adds v at the back of the queue.
+	Enqueue(v E)
+
+	// Dequeue removes and returns the element at the front of the queue.
+	// The ok result reports whether the queue was non-empty.
+	Dequeue() (v E, ok bool)
+
+	// Peek returns the element at the front of the queue without removing it.
+	// The ok result reports whether the queue is non-empty.
+	Peek() (v E, ok bool)
+}
+
+// Stack is a last-in, first-out container.
+type Stack[E any] interface {
+	Container
+
+	// Push adds v at the top of the stack.
+	Push(v E)
+
+	// Pop removes and returns the element at the top of the stack.
+	// The ok result reports whether the stack was non-empty.
+	Pop() (v E, ok bool)
+
+	// Peek returns the element at the top of the stack without removing it.
+	// The ok result reports whether the stack is non-empty.
+	Peek() (v E, ok bool)
+}
+
+// Deque is a double-ended queue.
+type Deque[E any] interface {
+	Container
+
+	// PushFront adds v at the front of the deque.
+	PushFront(v E)
+
+	// PushBack adds v at the back of the deque.
+	PushBack(v E)
+
+	// PopFront removes and returns the element at the front of the deque.
+	// The ok result reports whether the deque was non-empty.
+	PopFront() (v E, ok bool)
+
+	// PopBack removes and returns the element at the back of the deque.
+	// The ok result reports whether the deque was non-empty.
+	PopBack() (v E, ok bool)
+
+	// PeekFront returns the element at the front of the deque.
+	// The ok result reports whether the deque is non-empty.
+	PeekFront() (v E, ok bool)
+
+	// PeekBack returns the element at the back of the deque.
+	// The ok result reports whether the deque is non-empty.
+	PeekBack() (v E, ok bool)
+}
+
+// PriorityQueue is a container whose elements are removed in order of
+// priority.
+type PriorityQueue[E any] interface {
+	Container
+
+	// Push adds v to the queue.
+	Push(v E)
+
+	// Pop removes and returns the element of highest priority.
+	// The queue must be non-empty.
+	Pop() E
+
+	// Peek returns the element of highest priority without removing it.
+	// The queue must be non-empty.
+	Peek() E
+}
+
+// MemStats describes the memory used by a container.
+type MemStats struct {
+	// Len is the number of elements in the container.
+	Len int
+
+	// Cap is the number of elements the container can hold without
+	// allocating more memory, or, for bounded containers such as caches,
+	// its capacity.
+	Cap int
+
+	// Bytes estimates the memory used by the container, including unused
+	// capacity and per-element overhead such as nodes and map buckets, but
+	// not memory that elements point to.
+	Bytes int64
+}
+
+// Sizer is implemented by containers that report their memory use.
+type Sizer interface {
+	MemStats() MemStats
+}
@@ -0,0 +1,105 @@
+package containertest
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/list"
+	"github.com/weiwenchen2022/container/wbtree"
+)
+
+const (
+	checkCount = 100
+	checkSize  = 32
+)
+
+// Check runs prop against values generated by gen and fails t if prop
+// returns false. The failing value is minimized with shrink, which may be
+// nil, and reported together with the random seed.
+func Check[T any](t testing.TB, gen Gen[T], shrink func(T) iter.Seq[T], prop func(T) bool) {
+	t.Helper()
+
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+	for i := range checkCount {
+		v := gen(r, 1+i*checkSize/checkCount)
+		if prop(v) {
+			continue
+		}
+
+		if shrink != nil {
+			v = Minimize(v, shrink, func(v T) bool { return !prop(v) })
+		}
+		t.Fatalf("property failed (seed %d): %v", seed, v)
+	}
+}
+
+// CheckList reports whether the links of list l are consistent with each
+// other and with its length.
+func CheckList[E any](l *list.List[E]) error {
+	n := 0
+	var prev *list.Element[E]
+	for e := l.Front(); e != nil; e = e.Next() {
+		if e.Prev() != prev {
+			return fmt.Errorf("containertest: element %d: Prev does not link back", n)
+		}
+		prev = e
+		n++
+		if n > l.Len() {
+			return fmt.Errorf("containertest: more than %d elements forward", l.Len())
+		}
+	}
+	if n != l.Len() {
+		return fmt.Errorf("containertest: %d elements forward, Len = %d", n, l.Len())
+	}
+	if l.Back() != prev {
+		return fmt.Errorf("containertest: Back is not the last element")
+	}
+
+	return nil
+}
+
+// CheckHeap reports whether h is ordered by less.
+func CheckHeap[E any](h *heap.Heap[E], less func(a, b E) bool) error {
+	s := slices.Collect(h.Values())
+	if len(s) != h.Len() {
+		return fmt.Errorf("containertest: %d values, Len = %d", len(s), h.Len())
+	}
+	for i := 1; i < len(s); i++ {
+		if p := (i - 1) / 2; less(s[i], s[p]) {
+			return fmt.Errorf("containertest: element %d less than its parent %d", i, p)
+		}
+	}
+
+	return nil
+}
+
+// CheckTreeMap reports whether the keys of m are strictly increasing by
+// less and consistent with its length, At and Rank.
+func CheckTreeMap[K, V any](m *wbtree.Map[K, V], less func(a, b K) bool) error {
+	i := 0
+	var prev K
+	for k := range m.Keys() {
+		if i > 0 && !less(prev, k) {
+			return fmt.Errorf("containertest: key %d not greater than its predecessor", i)
+		}
+		if r := m.Rank(k); r != i {
+			return fmt.Errorf("containertest: Rank of key %d = %d", i, r)
+		}
+		if x, _ := m.At(i); less(x, k) || less(k, x) {
+			return fmt.Errorf("containertest: At(%d) differs from key %d", i, i)
+		}
+		prev = k
+		i++
+	}
+	if i != m.Len() {
+		return fmt.Errorf("containertest: %d keys, Len = %d", i, m.Len())
+	}
+
+	return nil
+}
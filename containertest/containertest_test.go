@@ -0,0 +1,93 @@
+package containertest
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	"github.com/weiwenchen2022/container/list"
+)
+
+func TestGenerators(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for range 100 {
+		l := List(IntRange(-5, 5))(r, 20)
+		if err := CheckList(l); err != nil {
+			t.Fatal(err)
+		}
+		if l.Len() > 20 {
+			t.Fatalf("l.Len() = %d, want <= 20", l.Len())
+		}
+
+		h := Heap(IntRange(0, 100), cmp.Less[int])(r, 20)
+		if err := CheckHeap(h, cmp.Less[int]); err != nil {
+			t.Fatal(err)
+		}
+
+		m := TreeMap(IntRange(0, 50), OneOf("a", "b"), cmp.Less[int])(r, 20)
+		if err := CheckTreeMap(m, cmp.Less[int]); err != nil {
+			t.Fatal(err)
+		}
+
+		for k := range Set(IntRange(3, 7))(r, 20) {
+			if k < 3 || k > 7 {
+				t.Fatalf("set member %d out of [3, 7]", k)
+			}
+		}
+	}
+}
+
+func TestCheckHeap(t *testing.T) {
+	t.Parallel()
+
+	h := Heap(Quick[int](), cmp.Less[int])(rand.New(rand.NewSource(1)), 50)
+	for h.Len() < 3 {
+		h.Push(h.Len())
+	}
+	if err := CheckHeap(h, func(a, b int) bool { return a > b }); err == nil {
+		t.Errorf("CheckHeap with the reverse order = nil, want error")
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	t.Parallel()
+
+	// fails whenever the slice contains a 7
+	fails := func(s []int) bool { return slices.Contains(s, 7) }
+
+	got := Minimize([]int{1, 2, 7, 3, 7, 4, 5}, ShrinkSlice[int], fails)
+	if want := []int{7}; !slices.Equal(got, want) {
+		t.Errorf("Minimize = %v, want %v", got, want)
+	}
+
+	m := Minimize(map[int]int{1: 1, 2: 7, 3: 3}, ShrinkMap[int, int], func(m map[int]int) bool { return len(m) >= 2 })
+	if len(m) != 2 {
+		t.Errorf("Minimize of map = %v, want 2 entries", m)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	Check(t, List(IntRange(0, 9)), ShrinkList[int], func(l *list.List[int]) bool {
+		c := l.Clone()
+		return CheckList(c) == nil && c.Len() == l.Len()
+	})
+}
+
+func TestQuick(t *testing.T) {
+	t.Parallel()
+
+	f := func(l QuickList[int8], h QuickHeap[string], m QuickTreeMap[uint, bool]) bool {
+		return CheckList(l.List) == nil &&
+			CheckHeap(h.Heap, cmp.Less[string]) == nil &&
+			CheckTreeMap(m.Map, cmp.Less[uint]) == nil
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,195 @@
+// Package containertest provides helpers for property testing code that
+// uses the containers of this module.
+//
+// A Gen generates random values; generators for the containers are built
+// from generators of their elements. A shrinker returns smaller variants
+// of a value, and Check runs a property against generated values and
+// reports a failure with the smallest failing value it can find:
+//
+//	gen := containertest.List(containertest.IntRange(0, 9))
+//	containertest.Check(t, gen, containertest.ShrinkList[int], func(l *list.List[int]) bool {
+//		return l.Clone().Len() == l.Len()
+//	})
+//
+// The Quick types implement testing/quick.Generator for use with
+// quick.Check, and the Check functions verify the internal invariants of
+// containers after code under test has manipulated them.
+package containertest
+
+import (
+	"iter"
+	"math/rand"
+
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/list"
+	"github.com/weiwenchen2022/container/wbtree"
+)
+
+// A Gen generates a random value. size bounds the number of elements of
+// generated containers.
+type Gen[T any] func(r *rand.Rand, size int) T
+
+// IntRange returns a generator of ints in [lo, hi].
+func IntRange(lo, hi int) Gen[int] {
+	return func(r *rand.Rand, size int) int { return lo + r.Intn(hi-lo+1) }
+}
+
+// OneOf returns a generator of the values vs.
+func OneOf[E any](vs ...E) Gen[E] {
+	return func(r *rand.Rand, size int) E { return vs[r.Intn(len(vs))] }
+}
+
+// Slice returns a generator of slices of up to size elements.
+func Slice[E any](elem Gen[E]) Gen[[]E] {
+	return func(r *rand.Rand, size int) []E {
+		s := make([]E, r.Intn(size+1))
+		for i := range s {
+			s[i] = elem(r, size)
+		}
+
+		return s
+	}
+}
+
+// List returns a generator of lists of up to size elements.
+func List[E any](elem Gen[E]) Gen[*list.List[E]] {
+	return func(r *rand.Rand, size int) *list.List[E] {
+		l := list.New[E]()
+		l.PushBackSlice(Slice(elem)(r, size))
+		return l
+	}
+}
+
+// Heap returns a generator of heaps ordered by less, of up to size
+// elements.
+func Heap[E any](elem Gen[E], less func(a, b E) bool) Gen[*heap.Heap[E]] {
+	return func(r *rand.Rand, size int) *heap.Heap[E] {
+		h := heap.New(less, heap.WithData(Slice(elem)(r, size)))
+		h.Init()
+		return h
+	}
+}
+
+// Set returns a generator of sets of up to size members.
+func Set[E comparable](elem Gen[E]) Gen[map[E]struct{}] {
+	return func(r *rand.Rand, size int) map[E]struct{} {
+		s := make(map[E]struct{})
+		for _, x := range Slice(elem)(r, size) {
+			s[x] = struct{}{}
+		}
+
+		return s
+	}
+}
+
+// Map returns a generator of maps of up to size entries.
+func Map[K comparable, V any](key Gen[K], value Gen[V]) Gen[map[K]V] {
+	return func(r *rand.Rand, size int) map[K]V {
+		m := make(map[K]V)
+		for range r.Intn(size + 1) {
+			m[key(r, size)] = value(r, size)
+		}
+
+		return m
+	}
+}
+
+// TreeMap returns a generator of ordered maps of up to size entries.
+func TreeMap[K, V any](key Gen[K], value Gen[V], less func(a, b K) bool) Gen[*wbtree.Map[K, V]] {
+	return func(r *rand.Rand, size int) *wbtree.Map[K, V] {
+		m := wbtree.New[K, V](less)
+		for range r.Intn(size + 1) {
+			m.Set(key(r, size), value(r, size))
+		}
+
+		return m
+	}
+}
+
+// ShrinkSlice returns smaller variants of s: its halves, then s without
+// each single element.
+func ShrinkSlice[E any](s []E) iter.Seq[[]E] {
+	return func(yield func([]E) bool) {
+		if len(s) == 0 {
+			return
+		}
+		if !yield(nil) {
+			return
+		}
+
+		if n := len(s); n > 2 {
+			if !yield(append([]E(nil), s[:n/2]...)) || !yield(append([]E(nil), s[n/2:]...)) {
+				return
+			}
+		}
+
+		for i := range s {
+			t := append(append([]E(nil), s[:i]...), s[i+1:]...)
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+func values[E any](l *list.List[E]) []E {
+	s := make([]E, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		s = append(s, e.Value)
+	}
+
+	return s
+}
+
+// ShrinkList returns smaller variants of list l, like ShrinkSlice.
+func ShrinkList[E any](l *list.List[E]) iter.Seq[*list.List[E]] {
+	return func(yield func(*list.List[E]) bool) {
+		for s := range ShrinkSlice(values(l)) {
+			c := list.New[E]()
+			c.PushBackSlice(s)
+			if !yield(c) {
+				return
+			}
+		}
+	}
+}
+
+// ShrinkMap returns smaller variants of map m: the empty map, then m
+// without each single key.
+func ShrinkMap[K comparable, V any](m map[K]V) iter.Seq[map[K]V] {
+	return func(yield func(map[K]V) bool) {
+		if len(m) == 0 || !yield(map[K]V{}) {
+			return
+		}
+
+		for k := range m {
+			c := make(map[K]V, len(m)-1)
+			for k2, v := range m {
+				if k2 != k {
+					c[k2] = v
+				}
+			}
+			if !yield(c) {
+				return
+			}
+		}
+	}
+}
+
+// Minimize repeatedly replaces v by the first of its variants returned by
+// shrink that still fails, and returns the value that has no failing
+// variant.
+func Minimize[T any](v T, shrink func(T) iter.Seq[T], fails func(T) bool) T {
+	for {
+		smaller := false
+		for c := range shrink(v) {
+			if fails(c) {
+				v, smaller = c, true
+				break
+			}
+		}
+		if !smaller {
+			return v
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package containertest
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing/quick"
+
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/list"
+	"github.com/weiwenchen2022/container/wbtree"
+)
+
+// value returns a random value of type E, generated by testing/quick.
+func value[E any](r *rand.Rand) E {
+	v, ok := quick.Value(reflect.TypeFor[E](), r)
+	if !ok {
+		panic(fmt.Sprintf("containertest: cannot generate values of type %v", reflect.TypeFor[E]()))
+	}
+
+	return v.Interface().(E)
+}
+
+// Quick returns a generator of values generated by testing/quick.
+func Quick[E any]() Gen[E] {
+	return func(r *rand.Rand, size int) E { return value[E](r) }
+}
+
+// QuickList is a list that implements quick.Generator, so functions
+// taking a QuickList can be checked with quick.Check.
+type QuickList[E any] struct{ *list.List[E] }
+
+// Generate implements quick.Generator.
+func (QuickList[E]) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(QuickList[E]{List(Quick[E]())(r, size)})
+}
+
+// QuickHeap is a min-heap that implements quick.Generator.
+type QuickHeap[E cmp.Ordered] struct{ *heap.Heap[E] }
+
+// Generate implements quick.Generator.
+func (QuickHeap[E]) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(QuickHeap[E]{Heap(Quick[E](), cmp.Less[E])(r, size)})
+}
+
+// QuickTreeMap is an ordered map that implements quick.Generator.
+type QuickTreeMap[K cmp.Ordered, V any] struct{ *wbtree.Map[K, V] }
+
+// Generate implements quick.Generator.
+func (QuickTreeMap[K, V]) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(QuickTreeMap[K, V]{TreeMap(Quick[K](), Quick[V](), cmp.Less[K])(r, size)})
+}
@@ -0,0 +1,211 @@
+// Package counter implements counters of occurrences of keys.
+package counter
+
+import (
+	"hash/maphash"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/weiwenchen2022/container/heap"
+)
+
+// Entry is a key and its count.
+type Entry[K comparable] struct {
+	Key   K
+	Count int64
+}
+
+type shard[K comparable] struct {
+	mu     sync.RWMutex
+	counts map[K]*atomic.Int64
+
+	_ [64]byte // keep shards on separate cache lines
+}
+
+// Sync is a counter safe for concurrent use, built for hot increment
+// paths. Keys are spread over shards, each guarded by its own lock, and
+// incrementing an existing key only takes a shared lock and adds
+// atomically, so concurrent increments rarely contend.
+// To create a counter use counter.NewSync.
+type Sync[K comparable] struct {
+	seed   maphash.Seed
+	shards []shard[K]
+}
+
+type syncOption[K comparable] func(*Sync[K])
+
+// WithShards sets the number of shards, rounded up to a power of two.
+// The default is four per CPU.
+// It panics if n < 1.
+func WithShards[K comparable](n int) syncOption[K] {
+	if n < 1 {
+		panic("counter: non-positive shard count")
+	}
+
+	return func(c *Sync[K]) {
+		c.shards = make([]shard[K], ceilPow2(n))
+	}
+}
+
+func ceilPow2(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+
+	return p
+}
+
+// NewSync returns an empty concurrent counter.
+func NewSync[K comparable](opts ...syncOption[K]) *Sync[K] {
+	c := &Sync[K]{seed: maphash.MakeSeed()}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.shards == nil {
+		c.shards = make([]shard[K], ceilPow2(4*runtime.GOMAXPROCS(0)))
+	}
+	for i := range c.shards {
+		c.shards[i].counts = make(map[K]*atomic.Int64)
+	}
+
+	return c
+}
+
+func (c *Sync[K]) shard(k K) *shard[K] {
+	h := maphash.Comparable(c.seed, k)
+	return &c.shards[h&uint64(len(c.shards)-1)]
+}
+
+// Add adds n to the count of key k and returns the new count.
+func (c *Sync[K]) Add(k K, n int64) int64 {
+	s := c.shard(k)
+
+	s.mu.RLock()
+	if v := s.counts[k]; v != nil {
+		defer s.mu.RUnlock()
+		return v.Add(n)
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := s.counts[k]
+	if v == nil {
+		v = new(atomic.Int64)
+		s.counts[k] = v
+	}
+
+	return v.Add(n)
+}
+
+// Inc adds 1 to the count of key k and returns the new count.
+func (c *Sync[K]) Inc(k K) int64 { return c.Add(k, 1) }
+
+// Get returns the count of key k, or 0 if it has not been counted.
+func (c *Sync[K]) Get(k K) int64 {
+	s := c.shard(k)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if v := s.counts[k]; v != nil {
+		return v.Load()
+	}
+
+	return 0
+}
+
+// Delete removes key k and returns its count.
+func (c *Sync[K]) Delete(k K) int64 {
+	s := c.shard(k)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := s.counts[k]
+	if v == nil {
+		return 0
+	}
+
+	delete(s.counts, k)
+	return v.Load()
+}
+
+// lockAll locks every shard, stopping all updates.
+func (c *Sync[K]) lockAll() func() {
+	for i := range c.shards {
+		c.shards[i].mu.Lock()
+	}
+
+	return func() {
+		for i := range c.shards {
+			c.shards[i].mu.Unlock()
+		}
+	}
+}
+
+// Len returns the number of keys counted.
+func (c *Sync[K]) Len() int {
+	defer c.lockAll()()
+
+	n := 0
+	for i := range c.shards {
+		n += len(c.shards[i].counts)
+	}
+
+	return n
+}
+
+// Clear removes all keys.
+func (c *Sync[K]) Clear() {
+	defer c.lockAll()()
+
+	for i := range c.shards {
+		clear(c.shards[i].counts)
+	}
+}
+
+// Snapshot returns the counts of all keys at a single point in time:
+// updates are paused while it is taken.
+func (c *Sync[K]) Snapshot() map[K]int64 {
+	defer c.lockAll()()
+
+	m := make(map[K]int64)
+	for i := range c.shards {
+		for k, v := range c.shards[i].counts {
+			m[k] = v.Load()
+		}
+	}
+
+	return m
+}
+
+// TopN returns up to n entries with the largest counts from a snapshot of
+// the counter, in descending order of count. Ties are broken arbitrarily.
+func (c *Sync[K]) TopN(n int) []Entry[K] {
+	if n <= 0 {
+		return nil
+	}
+
+	h := heap.New(func(a, b Entry[K]) bool { return a.Count < b.Count }, heap.WithInitialCap[Entry[K]](n+1))
+	for k, v := range c.Snapshot() {
+		if h.Len() < n {
+			h.Push(Entry[K]{k, v})
+		} else if v > h.Peek().Count {
+			h.Pop()
+			h.Push(Entry[K]{k, v})
+		}
+	}
+
+	top := make([]Entry[K], h.Len())
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i] = h.Pop()
+	}
+
+	return slices.Clip(top)
+}
@@ -0,0 +1,99 @@
+package counter
+
+import (
+	"maps"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var _ container.Container = (*Sync[int])(nil)
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	c := NewSync[string](WithShards[string](3))
+	if n := len(c.shards); n != 4 {
+		t.Errorf("len(c.shards) = %d, want 4", n)
+	}
+
+	const workers, n = 8, 1000
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range n {
+				c.Inc(strconv.Itoa(i % 10))
+				c.Add("w"+strconv.Itoa(w), 2)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range 10 {
+		if v := c.Get(strconv.Itoa(i)); v != workers*n/10 {
+			t.Errorf("c.Get(%d) = %d, want %d", i, v, workers*n/10)
+		}
+	}
+	if v := c.Get("missing"); v != 0 {
+		t.Errorf(`c.Get("missing") = %d, want 0`, v)
+	}
+	if l := c.Len(); l != 10+workers {
+		t.Errorf("c.Len() = %d, want %d", l, 10+workers)
+	}
+
+	snap := c.Snapshot()
+	if len(snap) != 10+workers || snap["w0"] != 2*n {
+		t.Errorf("c.Snapshot() = %v", snap)
+	}
+
+	if v := c.Delete("w0"); v != 2*n {
+		t.Errorf(`c.Delete("w0") = %d, want %d`, v, 2*n)
+	}
+	c.Clear()
+	if l := c.Len(); l != 0 {
+		t.Errorf("c.Len() after Clear = %d, want 0", l)
+	}
+}
+
+func TestTopN(t *testing.T) {
+	t.Parallel()
+
+	c := NewSync[int]()
+	for i := range 100 {
+		c.Add(i, int64(i*i))
+	}
+
+	top := c.TopN(3)
+	want := []Entry[int]{{99, 9801}, {98, 9604}, {97, 9409}}
+	if len(top) != 3 || top[0] != want[0] || top[1] != want[1] || top[2] != want[2] {
+		t.Errorf("c.TopN(3) = %v, want %v", top, want)
+	}
+
+	if top := c.TopN(1000); len(top) != 100 {
+		t.Errorf("len(c.TopN(1000)) = %d, want 100", len(top))
+	}
+	if top := c.TopN(0); top != nil {
+		t.Errorf("c.TopN(0) = %v, want nil", top)
+	}
+
+	snap := c.Snapshot()
+	c.Inc(0)
+	if maps.Equal(snap, c.Snapshot()) {
+		t.Errorf("snapshot tracks later updates")
+	}
+}
+
+func BenchmarkInc(b *testing.B) {
+	c := NewSync[int]()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c.Inc(i & 15)
+			i++
+		}
+	})
+}
@@ -0,0 +1,202 @@
+// Package cowslice implements a copy-on-write array with O(1) snapshots.
+//
+// An Array stores its elements in fixed-size chunks. Snapshot returns an
+// independent copy in constant time by sharing all chunks between the two
+// versions; chunks are tagged with the version that owns them, and a write
+// copies the chunk it touches unless it owns it, so a version that is not
+// snapshotted writes in place. The first write after a snapshot also
+// copies the directory of chunk pointers, of n/64 entries.
+//
+// This lets a writer keep mutating an array while readers in other
+// goroutines each work on a consistent snapshot, without copying the
+// whole array for every version.
+//
+// To iterate over an array (where a is an *Array):
+//
+//	for i, v := range a.All() {
+//		// do something with i and v
+//	}
+package cowslice
+
+import (
+	"iter"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+const (
+	chunkBits = 6
+	chunkSize = 1 << chunkBits
+)
+
+// owner identifies the version of an array allowed to modify a chunk or
+// directory in place.
+type owner struct{ _ byte }
+
+type chunk[E any] struct {
+	s     [chunkSize]E
+	owner *owner
+}
+
+// Array is a copy-on-write array of elements of type E.
+// The zero value for Array is an empty array ready to use.
+type Array[E any] struct {
+	dir      []*chunk[E]
+	dirOwner *owner
+	owner    *owner
+	n        int
+}
+
+// New returns an array holding xs.
+func New[E any](xs ...E) *Array[E] {
+	a := new(Array[E])
+	a.Append(xs...)
+	return a
+}
+
+// Len returns the number of elements of array a.
+func (a *Array[E]) Len() int { return a.n }
+
+// Clear removes all elements from array a.
+func (a *Array[E]) Clear() {
+	a.dir, a.dirOwner, a.n = nil, nil, 0
+}
+
+// Snapshot returns an independent copy of array a.
+// Later modifications of either array are not visible through the other.
+// The complexity is O(1).
+func (a *Array[E]) Snapshot() *Array[E] {
+	a.owner = new(owner)
+	return &Array[E]{dir: a.dir, owner: new(owner), n: a.n}
+}
+
+// Clone returns an independent copy of array a. It is equivalent to
+// Snapshot.
+func (a *Array[E]) Clone() *Array[E] { return a.Snapshot() }
+
+func (a *Array[E]) checkIndex(i int) {
+	if i < 0 || i >= a.n {
+		panic("cowslice: index out of range")
+	}
+}
+
+// At returns the element at index i.
+// It panics if i is out of range.
+func (a *Array[E]) At(i int) E {
+	a.checkIndex(i)
+	return a.dir[i>>chunkBits].s[i&(chunkSize-1)]
+}
+
+// mutDir copies the directory of a if a does not own it.
+func (a *Array[E]) mutDir() {
+	if a.owner == nil {
+		a.owner = new(owner)
+	}
+	if a.dirOwner != a.owner {
+		a.dir = append([]*chunk[E](nil), a.dir...)
+		a.dirOwner = a.owner
+	}
+}
+
+// mut returns the chunk at index c of the directory, copying it and the
+// directory first if a does not own them.
+func (a *Array[E]) mut(c int) *chunk[E] {
+	a.mutDir()
+	ch := a.dir[c]
+	if ch.owner != a.owner {
+		ch = &chunk[E]{s: ch.s, owner: a.owner}
+		a.dir[c] = ch
+	}
+
+	return ch
+}
+
+// Set sets the element at index i to v. It copies the chunk of i if it
+// is shared with a snapshot.
+// It panics if i is out of range.
+func (a *Array[E]) Set(i int, v E) {
+	a.checkIndex(i)
+	a.mut(i >> chunkBits).s[i&(chunkSize-1)] = v
+}
+
+// Append adds xs at the end of array a.
+func (a *Array[E]) Append(xs ...E) {
+	for _, x := range xs {
+		if a.n&(chunkSize-1) == 0 {
+			a.mutDir()
+			a.dir = append(a.dir[:a.n>>chunkBits], &chunk[E]{owner: a.owner})
+		}
+
+		a.mut(a.n >> chunkBits).s[a.n&(chunkSize-1)] = x
+		a.n++
+	}
+}
+
+// Pop removes and returns the last element of array a.
+// The ok result reports whether a was non-empty.
+func (a *Array[E]) Pop() (v E, ok bool) {
+	if a.n == 0 {
+		return v, false
+	}
+
+	a.n--
+	c, i := a.n>>chunkBits, a.n&(chunkSize-1)
+	v = a.dir[c].s[i]
+	if i == 0 {
+		a.mutDir()
+		a.dir[c] = nil
+		a.dir = a.dir[:c]
+	} else {
+		var zero E
+		a.mut(c).s[i] = zero // avoid memory leak
+	}
+
+	return v, true
+}
+
+// All returns an iterator over the indexes and elements of array a.
+// The array must not be modified during iteration; iterate a Snapshot
+// instead.
+func (a *Array[E]) All() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		for i := range a.n {
+			if !yield(i, a.dir[i>>chunkBits].s[i&(chunkSize-1)]) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the elements of array a.
+// The array must not be modified during iteration.
+func (a *Array[E]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, v := range a.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// AppendTo appends the elements of array a to dst and returns the
+// extended slice.
+func (a *Array[E]) AppendTo(dst []E) []E {
+	for c, ch := range a.dir {
+		dst = append(dst, ch.s[:min(chunkSize, a.n-c*chunkSize)]...)
+	}
+
+	return dst
+}
+
+// MemStats returns statistics about the memory used by array a, counting
+// chunks shared with snapshots in full.
+func (a *Array[E]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len: a.n,
+		Cap: len(a.dir) * chunkSize,
+		Bytes: memsize.Of[Array[E]]() + memsize.Slice[*chunk[E]](cap(a.dir)) +
+			int64(len(a.dir))*memsize.Of[chunk[E]](),
+	}
+}
@@ -0,0 +1,120 @@
+package cowslice
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container           = (*Array[int])(nil)
+	_ container.Iterable[int]       = (*Array[int])(nil)
+	_ container.Cloner[*Array[int]] = (*Array[int])(nil)
+	_ container.Sizer               = (*Array[int])(nil)
+)
+
+func check(t *testing.T, a *Array[int], want []int) {
+	t.Helper()
+
+	if got := a.AppendTo(nil); !slices.Equal(got, want) {
+		t.Fatalf("a = %v, want %v", got, want)
+	}
+	if got := slices.Collect(a.Values()); !slices.Equal(got, want) {
+		t.Fatalf("a.Values() = %v, want %v", got, want)
+	}
+	for i, v := range want {
+		if a.At(i) != v {
+			t.Fatalf("a.At(%d) = %d, want %d", i, a.At(i), v)
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	var a Array[int]
+	var want []int
+	var snaps []*Array[int]
+	var wants [][]int
+	for i := range 5000 {
+		switch op := r.Intn(10); {
+		case op == 0:
+			snaps = append(snaps, a.Snapshot())
+			wants = append(wants, slices.Clone(want))
+		case op < 3 && len(want) > 0:
+			v, ok := a.Pop()
+			if !ok || v != want[len(want)-1] {
+				t.Fatalf("a.Pop() = %d, %t, want %d, true", v, ok, want[len(want)-1])
+			}
+			want = want[:len(want)-1]
+		case op < 6 && len(want) > 0:
+			j := r.Intn(len(want))
+			a.Set(j, i)
+			want[j] = i
+		default:
+			a.Append(i)
+			want = append(want, i)
+		}
+	}
+
+	check(t, &a, want)
+	for i, s := range snaps {
+		check(t, s, wants[i])
+	}
+
+	// snapshots are writable and independent
+	if len(wants[0]) > 0 {
+		snaps[0].Set(0, -1)
+		if len(wants[1]) > 0 && snaps[1].At(0) == -1 || len(want) > 0 && a.At(0) == -1 {
+			t.Errorf("write to a snapshot visible in other versions")
+		}
+	}
+}
+
+func TestConcurrentReaders(t *testing.T) {
+	t.Parallel()
+
+	a := New[int]()
+	for i := range 1000 {
+		a.Append(i)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		s := a.Snapshot()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, v := range s.All() {
+				if v != i {
+					t.Errorf("s.At(%d) = %d", i, v)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := range 1000 {
+		a.Set(i, -i)
+	}
+	wg.Wait()
+}
+
+func TestClear(t *testing.T) {
+	t.Parallel()
+
+	a := New(1, 2, 3)
+	s := a.Snapshot()
+	a.Clear()
+	a.Append(4)
+	check(t, a, []int{4})
+	check(t, s, []int{1, 2, 3})
+	if _, ok := New[int]().Pop(); ok {
+		t.Errorf("Pop of an empty array reports ok")
+	}
+}
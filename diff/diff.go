@@ -0,0 +1,185 @@
+// Package diff computes differences between sequences, sets and maps.
+//
+// Slices and Lists return an edit script: a sequence of operations that
+// turns the first sequence into the second, keeping a longest common
+// subsequence of the two and inserting or deleting everything else.
+// Sets and Maps report the keys added, removed and changed.
+package diff
+
+import (
+	"strconv"
+
+	"github.com/weiwenchen2022/container/list"
+)
+
+// OpKind is the kind of an edit operation.
+type OpKind int
+
+const (
+	Keep   OpKind = iota // the value is in both sequences
+	Insert               // the value is only in the second sequence
+	Delete               // the value is only in the first sequence
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case Keep:
+		return "Keep"
+	case Insert:
+		return "Insert"
+	case Delete:
+		return "Delete"
+	}
+
+	return "OpKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Op is an edit operation on a value. A is the index of the value in the
+// first sequence and B its index in the second; the index of a sequence
+// the value is not in is the position it would have there.
+type Op[E any] struct {
+	Kind  OpKind
+	Value E
+	A, B  int
+}
+
+// Slices returns an edit script turning a into b.
+// The complexity is O(len(a)×len(b)) time and space, less a common prefix
+// and suffix.
+func Slices[S ~[]E, E comparable](a, b S) []Op[E] {
+	return SlicesFunc(a, b, func(x, y E) bool { return x == y })
+}
+
+// SlicesFunc is like Slices but compares values with eq.
+func SlicesFunc[S ~[]E, E any](a, b S, eq func(x, y E) bool) []Op[E] {
+	ops := make([]Op[E], 0, max(len(a), len(b)))
+
+	// common prefix and suffix
+	pre := 0
+	for pre < len(a) && pre < len(b) && eq(a[pre], b[pre]) {
+		ops = append(ops, Op[E]{Keep, a[pre], pre, pre})
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && eq(a[len(a)-1-suf], b[len(b)-1-suf]) {
+		suf++
+	}
+
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	n, m := len(ma), len(mb)
+
+	// lcs[i*(m+1)+j] is the length of the LCS of ma[i:] and mb[j:].
+	lcs := make([]int32, (n+1)*(m+1))
+	at := func(i, j int) int32 { return lcs[i*(m+1)+j] }
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if eq(ma[i], mb[j]) {
+				lcs[i*(m+1)+j] = at(i+1, j+1) + 1
+			} else {
+				lcs[i*(m+1)+j] = max(at(i+1, j), at(i, j+1))
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && eq(ma[i], mb[j]):
+			ops = append(ops, Op[E]{Keep, ma[i], pre + i, pre + j})
+			i, j = i+1, j+1
+		case j < m && (i == n || at(i, j+1) >= at(i+1, j)):
+			ops = append(ops, Op[E]{Insert, mb[j], pre + i, pre + j})
+			j++
+		default:
+			ops = append(ops, Op[E]{Delete, ma[i], pre + i, pre + j})
+			i++
+		}
+	}
+
+	for k := suf; k > 0; k-- {
+		ops = append(ops, Op[E]{Keep, a[len(a)-k], len(a) - k, len(b) - k})
+	}
+
+	return ops
+}
+
+func values[E any](l *list.List[E]) []E {
+	s := make([]E, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		s = append(s, e.Value)
+	}
+
+	return s
+}
+
+// Lists returns an edit script turning list a into list b, with values
+// indexed by their position in the lists.
+func Lists[E comparable](a, b *list.List[E]) []Op[E] {
+	return Slices(values(a), values(b))
+}
+
+// Apply returns the result of applying the edit script ops to a: kept
+// values are taken from a and inserted values from ops.
+// It panics if ops refers to indexes out of the range of a.
+func Apply[S ~[]E, E any](a S, ops []Op[E]) S {
+	var b S
+	for _, op := range ops {
+		switch op.Kind {
+		case Keep:
+			b = append(b, a[op.A])
+		case Insert:
+			b = append(b, op.Value)
+		}
+	}
+
+	return b
+}
+
+// Sets returns the members of set b not in set a, and those of a not in b,
+// in unspecified order.
+func Sets[K comparable](a, b map[K]struct{}) (added, removed []K) {
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			added = append(added, k)
+		}
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+
+	return added, removed
+}
+
+// MapDiff is the difference between two maps.
+// The keys of each slice are in unspecified order.
+type MapDiff[K any] struct {
+	Added   []K // keys only in the second map
+	Removed []K // keys only in the first map
+	Changed []K // keys in both maps with different values
+}
+
+// Maps returns the difference from map a to map b.
+func Maps[K comparable, V comparable](a, b map[K]V) MapDiff[K] {
+	return MapsFunc(a, b, func(x, y V) bool { return x == y })
+}
+
+// MapsFunc is like Maps but compares values with eq.
+func MapsFunc[K comparable, V any](a, b map[K]V, eq func(x, y V) bool) MapDiff[K] {
+	var d MapDiff[K]
+	for k, vb := range b {
+		if va, ok := a[k]; !ok {
+			d.Added = append(d.Added, k)
+		} else if !eq(va, vb) {
+			d.Changed = append(d.Changed, k)
+		}
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			d.Removed = append(d.Removed, k)
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,126 @@
+package diff
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/weiwenchen2022/container/list"
+)
+
+func script[E any](ops []Op[E]) string {
+	var b strings.Builder
+	for _, op := range ops {
+		b.WriteByte(" +-"[op.Kind])
+	}
+
+	return b.String()
+}
+
+func TestSlices(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		a, b, want string
+	}{
+		{"", "", ""},
+		{"abc", "abc", "   "},
+		{"", "ab", "++"},
+		{"ab", "", "--"},
+		{"abcabba", "cbabac", "-+  - + -+"},
+		{"xaby", "xcby", " -+  "},
+	} {
+		a, b := strings.Split(tt.a, ""), strings.Split(tt.b, "")
+		ops := Slices(a, b)
+
+		keeps := 0
+		for _, op := range ops {
+			if op.Kind == Keep {
+				keeps++
+				if a[op.A] != b[op.B] || op.Value != a[op.A] {
+					t.Errorf("Slices(%q, %q): bad Keep %+v", tt.a, tt.b, op)
+				}
+			}
+		}
+		if got := strings.Join(Apply(a, ops), ""); got != tt.b {
+			t.Errorf("Apply(%q, Slices(%q, %q)) = %q", tt.a, tt.a, tt.b, got)
+		}
+		if want := strings.Count(tt.want, " "); keeps != want {
+			t.Errorf("Slices(%q, %q) = %q keeps %d, want %d (%q)", tt.a, tt.b, script(ops), keeps, want, tt.want)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	gen := func() []int {
+		s := make([]int, r.Intn(30))
+		for i := range s {
+			s[i] = r.Intn(4)
+		}
+		return s
+	}
+
+	for range 500 {
+		a, b := gen(), gen()
+		ops := Slices(a, b)
+		if got := Apply(a, ops); !slices.Equal(got, b) {
+			t.Fatalf("Apply(%v, Slices(%v, %v)) = %v", a, a, b, got)
+		}
+
+		// indexes are consistent with the positions in a and b
+		i, j := 0, 0
+		for _, op := range ops {
+			if op.A != i || op.B != j {
+				t.Fatalf("Slices(%v, %v): op %+v at positions %d, %d", a, b, op, i, j)
+			}
+			switch op.Kind {
+			case Keep:
+				i, j = i+1, j+1
+			case Insert:
+				j++
+			case Delete:
+				i++
+			}
+		}
+	}
+}
+
+func TestLists(t *testing.T) {
+	t.Parallel()
+
+	a, b := list.New[int](), list.New[int]()
+	a.PushBackSlice([]int{1, 2, 3})
+	b.PushBackSlice([]int{1, 3, 4})
+
+	ops := Lists(a, b)
+	if s := script(ops); s != " - +" {
+		t.Errorf("Lists = %q, want %q", s, " - +")
+	}
+	if ops[1].Kind.String() != "Delete" || ops[1].Value != 2 || OpKind(7).String() != "OpKind(7)" {
+		t.Errorf("ops[1] = %v %+v", ops[1].Kind, ops[1])
+	}
+}
+
+func TestMaps(t *testing.T) {
+	t.Parallel()
+
+	added, removed := Sets(map[int]struct{}{1: {}, 2: {}}, map[int]struct{}{2: {}, 3: {}, 4: {}})
+	slices.Sort(added)
+	if !slices.Equal(added, []int{3, 4}) || !slices.Equal(removed, []int{1}) {
+		t.Errorf("Sets = %v, %v, want [3 4], [1]", added, removed)
+	}
+
+	d := Maps(map[string]int{"a": 1, "b": 2, "c": 3}, map[string]int{"b": 2, "c": 30, "d": 4})
+	if !slices.Equal(d.Added, []string{"d"}) || !slices.Equal(d.Removed, []string{"a"}) || !slices.Equal(d.Changed, []string{"c"}) {
+		t.Errorf("Maps = %+v", d)
+	}
+
+	df := MapsFunc(map[int][]int{1: {1}}, map[int][]int{1: {1}}, slices.Equal[[]int])
+	if len(df.Added)+len(df.Removed)+len(df.Changed) != 0 {
+		t.Errorf("MapsFunc of equal maps = %+v", df)
+	}
+}
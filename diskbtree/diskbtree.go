@@ -0,0 +1,492 @@
+// Package diskbtree implements an ordered map of byte strings stored in a
+// file, for indexes larger than memory.
+//
+// The map is a B+tree of fixed-size pages. Pages are read on demand and
+// kept in an LRU cache of decoded nodes, so memory use is bounded by the
+// cache size rather than by the size of the map.
+//
+// Changes are made copy-on-write: a modified node is kept in memory until
+// Commit writes it to a new page, so the pages of the last committed
+// version are never overwritten. Commit first syncs the new pages, then
+// writes and syncs a checksummed meta record naming the new root, in one
+// of two alternating slots. If the process crashes at any point, Open
+// finds the last meta record that was written completely and the map is
+// as of that commit. The space of replaced pages is not reused; copy the
+// entries to a new map to reclaim it.
+//
+// A Map is not safe for concurrent use by multiple goroutines.
+package diskbtree
+
+import (
+	"bytes"
+	"errors"
+	"iter"
+	"os"
+
+	"github.com/weiwenchen2022/container/lru"
+)
+
+var (
+	// ErrCorrupt is returned when a file is not a valid map.
+	ErrCorrupt = errors.New("diskbtree: corrupt file")
+
+	// ErrTooLarge is returned by Set for an entry that takes more than a
+	// quarter of a page.
+	ErrTooLarge = errors.New("diskbtree: entry too large")
+)
+
+const (
+	defaultPageSize = 4096
+	minPageSize     = 2 * metaSlot
+	maxPageSize     = 1 << 24
+)
+
+// Map is an ordered map from byte strings to byte strings stored in a
+// file. Keys are ordered by bytes.Compare.
+// To open a map use diskbtree.Open.
+type Map struct {
+	f        *os.File
+	pageSize int
+	cache    *lru.Cache[uint64, *node]
+
+	meta   meta // the last committed version
+	root   child
+	count  int
+	npages uint64
+
+	err error
+}
+
+type options struct {
+	pageSize   int
+	cachePages int
+}
+
+type option func(*options)
+
+// WithPageSize sets the page size of a new file. The default is 4096.
+// It has no effect when opening an existing file, whose page size is
+// recorded in it.
+// It panics if n < 1024 or n > 16 MiB.
+func WithPageSize(n int) option {
+	if n < minPageSize {
+		panic("diskbtree: page size less than 1024")
+	}
+	if n > maxPageSize {
+		panic("diskbtree: page size more than 16 MiB")
+	}
+
+	return func(o *options) {
+		o.pageSize = n
+	}
+}
+
+// WithCachePages sets the number of decoded pages kept in memory.
+// The default is 1024.
+// It panics if n < 1.
+func WithCachePages(n int) option {
+	if n < 1 {
+		panic("diskbtree: non-positive cache size")
+	}
+
+	return func(o *options) {
+		o.cachePages = n
+	}
+}
+
+// Open opens the map stored in the named file, creating an empty map if
+// the file does not exist or is empty.
+func Open(name string, opts ...option) (*Map, error) {
+	o := options{pageSize: defaultPageSize, cachePages: 1024}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o666)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Map{f: f, cache: lru.New[uint64, *node](o.cachePages)}
+	if err := m.init(o.pageSize); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// init reads the last committed meta record, or writes the first one to
+// an empty file.
+func (m *Map) init(pageSize int) error {
+	fi, err := m.f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if fi.Size() == 0 {
+		// Page 0 holds the meta records.
+		m.meta = meta{pageSize: uint32(pageSize), npages: 1}
+		b := make([]byte, pageSize)
+		m.meta.encode(b)
+		if _, err := m.f.WriteAt(b, 0); err != nil {
+			return err
+		}
+		if err := m.f.Sync(); err != nil {
+			return err
+		}
+	} else {
+		b := make([]byte, minPageSize)
+		if _, err := m.f.ReadAt(b, 0); err != nil {
+			return ErrCorrupt
+		}
+
+		var ms [2]meta
+		ok0, ok1 := ms[0].decode(b), ms[1].decode(b[metaSlot:])
+		switch {
+		case ok0 && (!ok1 || ms[0].txid > ms[1].txid):
+			m.meta = ms[0]
+		case ok1:
+			m.meta = ms[1]
+		default:
+			return ErrCorrupt
+		}
+	}
+
+	m.pageSize = int(m.meta.pageSize)
+	m.Rollback()
+	return nil
+}
+
+func (m *Map) writeMeta(mt meta) error {
+	b := make([]byte, metaSize)
+	mt.encode(b)
+	if _, err := m.f.WriteAt(b, int64(mt.txid%2)*metaSlot); err != nil {
+		return err
+	}
+
+	return m.f.Sync()
+}
+
+// Close closes the file of map m, discarding uncommitted changes.
+func (m *Map) Close() error { return m.f.Close() }
+
+// Len returns the number of entries of map m, including uncommitted
+// changes.
+func (m *Map) Len() int { return m.count }
+
+// Clear removes all entries from map m. Like other changes, it takes
+// effect in the file at the next commit.
+func (m *Map) Clear() {
+	m.root = child{}
+	m.count = 0
+}
+
+// Err returns the first error encountered while iterating map m.
+func (m *Map) Err() error { return m.err }
+
+// load returns the node c refers to.
+func (m *Map) load(c child) (*node, error) {
+	switch {
+	case c.n != nil:
+		return c.n, nil
+	case c.pg == 0:
+		return &node{leaf: true}, nil
+	}
+
+	if n, ok := m.cache.Get(c.pg); ok {
+		return n, nil
+	}
+
+	if c.pg >= m.npages {
+		return nil, ErrCorrupt
+	}
+	b := make([]byte, m.pageSize)
+	if _, err := m.f.ReadAt(b, int64(c.pg)*int64(m.pageSize)); err != nil {
+		return nil, err
+	}
+	n, err := decode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	m.cache.Put(c.pg, n)
+	return n, nil
+}
+
+// mutable returns a version of the node c refers to that may be modified.
+func (m *Map) mutable(c child) (*node, error) {
+	n, err := m.load(c)
+	if err != nil || c.n != nil {
+		return n, err
+	}
+
+	return n.clone(), nil
+}
+
+// Get returns the value stored for key k.
+// The ok result reports whether the key was present.
+func (m *Map) Get(k []byte) (v []byte, ok bool, err error) {
+	c := m.root
+	for {
+		n, err := m.load(c)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if n.leaf {
+			if i, ok := n.search(k); ok {
+				return bytes.Clone(n.vals[i]), true, nil
+			}
+			return nil, false, nil
+		}
+
+		c = n.kids[n.kid(k)]
+	}
+}
+
+// Set sets the value for key k to v.
+func (m *Map) Set(k, v []byte) error {
+	if leafEntrySize(k, v) > m.pageSize/4 {
+		return ErrTooLarge
+	}
+
+	root, sep, right, added, err := m.insert(m.root, bytes.Clone(k), bytes.Clone(v))
+	if err != nil {
+		return err
+	}
+
+	if right != nil {
+		root = child{n: &node{keys: [][]byte{sep}, kids: []child{root, *right}}}
+	}
+	m.root = root
+	if added {
+		m.count++
+	}
+
+	return nil
+}
+
+func (m *Map) insert(c child, k, v []byte) (_ child, sep []byte, right *child, added bool, err error) {
+	n, err := m.mutable(c)
+	if err != nil {
+		return c, nil, nil, false, err
+	}
+
+	if n.leaf {
+		i, found := n.search(k)
+		if found {
+			n.vals[i] = v
+		} else {
+			n.keys = insertAt(n.keys, i, k)
+			n.vals = insertAt(n.vals, i, v)
+			added = true
+		}
+	} else {
+		i := n.kid(k)
+		var kid child
+		var kidSep []byte
+		var kidRight *child
+		kid, kidSep, kidRight, added, err = m.insert(n.kids[i], k, v)
+		if err != nil {
+			return c, nil, nil, false, err
+		}
+
+		n.kids[i] = kid
+		if kidRight != nil {
+			n.keys = insertAt(n.keys, i, kidSep)
+			n.kids = insertAt(n.kids, i+1, *kidRight)
+		}
+	}
+
+	if n.size() > m.pageSize {
+		sep, r := n.split()
+		return child{n: n}, sep, &child{n: r}, added, nil
+	}
+
+	return child{n: n}, nil, nil, added, nil
+}
+
+func insertAt[E any](s []E, i int, v E) []E {
+	s = append(s, v)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
+
+func deleteAt[E any](s []E, i int) []E {
+	copy(s[i:], s[i+1:])
+	var zero E
+	s[len(s)-1] = zero
+	return s[:len(s)-1]
+}
+
+// Delete removes the entry for key k and reports whether it was present.
+func (m *Map) Delete(k []byte) (bool, error) {
+	if _, ok, err := m.Get(k); !ok || err != nil {
+		return false, err
+	}
+
+	root, err := m.delete(m.root, k)
+	if err != nil {
+		return false, err
+	}
+
+	// Shrink the tree while the root is a branch with a single child.
+	for root.n != nil && !root.n.leaf && len(root.n.kids) == 1 {
+		root = root.n.kids[0]
+	}
+	if root.n != nil && root.n.empty() {
+		root = child{}
+	}
+	m.root = root
+	m.count--
+	return true, nil
+}
+
+// delete removes k, which is present, from the subtree of c. Nodes are not
+// merged when they underflow; empty nodes are removed from their parents.
+func (m *Map) delete(c child, k []byte) (child, error) {
+	n, err := m.mutable(c)
+	if err != nil {
+		return c, err
+	}
+
+	if n.leaf {
+		i, _ := n.search(k)
+		n.keys, n.vals = deleteAt(n.keys, i), deleteAt(n.vals, i)
+		return child{n: n}, nil
+	}
+
+	i := n.kid(k)
+	kid, err := m.delete(n.kids[i], k)
+	if err != nil {
+		return c, err
+	}
+
+	if kid.n.empty() {
+		n.kids = deleteAt(n.kids, i)
+		if len(n.keys) > 0 {
+			n.keys = deleteAt(n.keys, max(i-1, 0))
+		}
+	} else {
+		n.kids[i] = kid
+	}
+
+	return child{n: n}, nil
+}
+
+// All returns an iterator over the entries of map m in ascending key
+// order, including uncommitted changes. The yielded slices must not be
+// modified. If reading a page fails the iteration stops early and Err
+// reports the error.
+// The map must not be modified during iteration.
+func (m *Map) All() iter.Seq2[[]byte, []byte] {
+	return m.Ascend(nil, nil)
+}
+
+// Ascend returns an iterator over the entries of map m with keys in
+// [lo, hi), in ascending order. A nil hi means no upper bound.
+// It is otherwise like All.
+func (m *Map) Ascend(lo, hi []byte) iter.Seq2[[]byte, []byte] {
+	return func(yield func([]byte, []byte) bool) {
+		m.ascend(m.root, lo, hi, yield)
+	}
+}
+
+func (m *Map) ascend(c child, lo, hi []byte, yield func([]byte, []byte) bool) bool {
+	n, err := m.load(c)
+	if err != nil {
+		m.err = err
+		return false
+	}
+
+	if n.leaf {
+		i, _ := n.search(lo)
+		for ; i < len(n.keys); i++ {
+			if hi != nil && bytes.Compare(n.keys[i], hi) >= 0 || !yield(n.keys[i], n.vals[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := n.kid(lo); i < len(n.kids); i++ {
+		if i > 0 && hi != nil && bytes.Compare(n.keys[i-1], hi) >= 0 {
+			return false
+		}
+		if !m.ascend(n.kids[i], lo, hi, yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Commit writes the changes made since the last commit to the file and
+// makes them durable. If Commit fails, the changes are kept in memory and
+// the file still holds the last committed version; call Rollback to
+// discard them.
+func (m *Map) Commit() error {
+	b := make([]byte, m.pageSize)
+	root, err := m.flush(&m.root, b)
+	if err != nil {
+		return err
+	}
+	if err := m.f.Sync(); err != nil {
+		return err
+	}
+
+	next := meta{
+		pageSize: m.meta.pageSize,
+		txid:     m.meta.txid + 1,
+		root:     root,
+		npages:   m.npages,
+		count:    uint64(m.count),
+	}
+	if err := m.writeMeta(next); err != nil {
+		return err
+	}
+
+	m.meta = next
+	return nil
+}
+
+// flush writes the modified nodes under *c to new pages, making *c refer
+// to its page, and returns the page.
+func (m *Map) flush(c *child, b []byte) (uint64, error) {
+	n := c.n
+	if n == nil {
+		return c.pg, nil
+	}
+
+	for i := range n.kids {
+		if _, err := m.flush(&n.kids[i], b); err != nil {
+			return 0, err
+		}
+	}
+
+	pg := m.npages
+	n.encode(b)
+	if _, err := m.f.WriteAt(b, int64(pg)*int64(m.pageSize)); err != nil {
+		return 0, err
+	}
+
+	m.npages++
+	m.cache.Put(pg, n)
+	*c = child{pg: pg}
+	return pg, nil
+}
+
+// Rollback discards the changes made since the last commit.
+func (m *Map) Rollback() {
+	if m.npages > m.meta.npages {
+		// Drop the pages of a failed commit, which will be overwritten.
+		m.cache.Clear()
+	}
+
+	m.root = child{pg: m.meta.root}
+	m.count = int(m.meta.count)
+	m.npages = m.meta.npages
+	m.err = nil
+}
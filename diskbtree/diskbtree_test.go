@@ -0,0 +1,248 @@
+package diskbtree
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var _ container.Container = (*Map)(nil)
+
+func key(i int) []byte { return fmt.Appendf(nil, "key%06d", i) }
+
+func (m *Map) verify(t *testing.T) {
+	t.Helper()
+
+	var check func(c child, lo, hi []byte) int
+	check = func(c child, lo, hi []byte) int {
+		n, err := m.load(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n.size() > m.pageSize {
+			t.Fatalf("node of %d bytes exceeds the page size", n.size())
+		}
+		for i, k := range n.keys {
+			if i > 0 && bytes.Compare(n.keys[i-1], k) >= 0 || lo != nil && bytes.Compare(k, lo) < 0 ||
+				hi != nil && bytes.Compare(k, hi) >= 0 {
+				t.Fatalf("key %q out of order", k)
+			}
+		}
+		if n.leaf {
+			return len(n.keys)
+		}
+
+		if len(n.kids) != len(n.keys)+1 {
+			t.Fatalf("branch with %d keys has %d children", len(n.keys), len(n.kids))
+		}
+		count := 0
+		for i, kid := range n.kids {
+			klo, khi := lo, hi
+			if i > 0 {
+				klo = n.keys[i-1]
+			}
+			if i < len(n.keys) {
+				khi = n.keys[i]
+			}
+			count += check(kid, klo, khi)
+		}
+		return count
+	}
+
+	if n := check(m.root, nil, nil); n != m.Len() {
+		t.Fatalf("tree holds %d entries, m.Len() = %d", n, m.Len())
+	}
+}
+
+func checkMap(t *testing.T, m *Map, want map[string]string) {
+	t.Helper()
+
+	m.verify(t)
+
+	var got []string
+	for k, v := range m.All() {
+		if want[string(k)] != string(v) {
+			t.Fatalf("value of %q = %q, want %q", k, v, want[string(k)])
+		}
+		got = append(got, string(k))
+	}
+	if err := m.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if keys := slices.Sorted(maps.Keys(want)); !slices.Equal(got, keys) {
+		t.Fatalf("keys = %d keys, want %d", len(got), len(keys))
+	}
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "map")
+	m, err := Open(name, WithPageSize(1024), WithCachePages(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	r := rand.New(rand.NewSource(1))
+	want, committed := make(map[string]string), make(map[string]string)
+	for i := range 20000 {
+		k := key(r.Intn(3000))
+		switch r.Intn(10) {
+		case 0, 1, 2:
+			ok, err := m.Delete(k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, wok := want[string(k)]; ok != wok {
+				t.Fatalf("m.Delete(%q) = %t, want %t", k, ok, wok)
+			}
+			delete(want, string(k))
+		case 3:
+			if i%7 == 0 {
+				m.Rollback()
+				want = maps.Clone(committed)
+			} else {
+				if err := m.Commit(); err != nil {
+					t.Fatal(err)
+				}
+				committed = maps.Clone(want)
+			}
+		default:
+			v := bytes.Repeat([]byte{'a' + byte(i%26)}, r.Intn(100))
+			if err := m.Set(k, v); err != nil {
+				t.Fatal(err)
+			}
+			want[string(k)] = string(v)
+		}
+	}
+	checkMap(t, m, want)
+
+	if err := m.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	m.Close()
+
+	m, err = Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMap(t, m, want)
+	for k, v := range want {
+		if got, ok, err := m.Get([]byte(k)); err != nil || !ok || string(got) != v {
+			t.Fatalf("m.Get(%q) = %q, %t, %v", k, got, ok, err)
+		}
+	}
+
+	var got []string
+	for k := range m.Ascend(key(100), key(200)) {
+		got = append(got, string(k))
+	}
+	for _, k := range got {
+		if k < string(key(100)) || k >= string(key(200)) {
+			t.Fatalf("m.Ascend yielded %q", k)
+		}
+	}
+	if len(got) == 0 || !slices.IsSorted(got) {
+		t.Errorf("m.Ascend(key(100), key(200)) = %d keys", len(got))
+	}
+}
+
+func TestCrash(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "map")
+	m, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 100 {
+		m.Set(key(i), key(i))
+	}
+	if err := m.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 100 {
+		m.Delete(key(i))
+	}
+	m.Set([]byte("new"), nil)
+	if err := m.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	m.Set([]byte("uncommitted"), nil)
+	m.Close()
+
+	// A torn write of the last meta record reverts to the previous commit.
+	f, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteAt([]byte("garbage"), int64(m.meta.txid%2)*metaSlot+20)
+	f.Close()
+
+	m, err = Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	if m.Len() != 100 {
+		t.Errorf("m.Len() = %d, want 100", m.Len())
+	}
+	if _, ok, _ := m.Get(key(5)); !ok {
+		t.Errorf("entry of the previous commit missing")
+	}
+	if ok, _ := m.Delete([]byte("new")); ok {
+		t.Errorf("entry of the torn commit present")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	m, err := Open(filepath.Join(dir, "map"), WithPageSize(1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	if err := m.Set(make([]byte, 300), nil); err != ErrTooLarge {
+		t.Errorf("m.Set of a large key = %v, want %v", err, ErrTooLarge)
+	}
+
+	m.Set(key(1), nil)
+	m.Clear()
+	if _, ok, _ := m.Get(key(1)); ok || m.Len() != 0 {
+		t.Errorf("map not empty after Clear")
+	}
+
+	name := filepath.Join(dir, "bad")
+	os.WriteFile(name, bytes.Repeat([]byte{1}, 4096), 0o666)
+	if _, err := Open(name); err != ErrCorrupt {
+		t.Errorf("Open of a corrupt file = %v, want %v", err, ErrCorrupt)
+	}
+
+	// meta records with a valid checksum but impossible fields
+	for _, mt := range []meta{
+		{pageSize: 0, npages: 1},
+		{pageSize: 1 << 31, npages: 1},
+		{pageSize: 4096, root: 5, npages: 5},
+	} {
+		b := make([]byte, 4096)
+		mt.encode(b)
+		mt.txid++
+		mt.encode(b[metaSlot:])
+		os.WriteFile(name, b, 0o666)
+		if _, err := Open(name); err != ErrCorrupt {
+			t.Errorf("Open with meta %+v = %v, want %v", mt, err, ErrCorrupt)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package diskbtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"slices"
+	"sort"
+)
+
+const (
+	magic = "CTBTREE1"
+
+	// metaSlot is the offset between the two meta records in page 0. A
+	// commit overwrites the older record, so a torn write leaves the other
+	// one intact.
+	metaSlot = 512
+	metaSize = len(magic) + 4 + 4*8 + 4
+
+	leafPage   = 1
+	branchPage = 2
+)
+
+// meta describes a committed version of the map.
+type meta struct {
+	pageSize uint32
+	txid     uint64
+	root     uint64 // 0 if the map is empty
+	npages   uint64
+	count    uint64
+}
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+func (m *meta) encode(b []byte) {
+	copy(b, magic)
+	b = b[len(magic):]
+	binary.LittleEndian.PutUint32(b, m.pageSize)
+	for i, x := range []uint64{m.txid, m.root, m.npages, m.count} {
+		binary.LittleEndian.PutUint64(b[4+8*i:], x)
+	}
+	binary.LittleEndian.PutUint32(b[4+4*8:], crc32.Checksum(b[:4+4*8], castagnoli))
+}
+
+// decode reports whether b holds a valid meta record. Besides the
+// checksum, which only catches accidental damage, it checks the page size
+// and that the root is one of the pages, so a crafted record cannot make
+// the map misread or allocate without bound.
+func (m *meta) decode(b []byte) bool {
+	if len(b) < metaSize || string(b[:len(magic)]) != magic {
+		return false
+	}
+
+	b = b[len(magic):]
+	if binary.LittleEndian.Uint32(b[4+4*8:]) != crc32.Checksum(b[:4+4*8], castagnoli) {
+		return false
+	}
+
+	m.pageSize = binary.LittleEndian.Uint32(b)
+	m.txid = binary.LittleEndian.Uint64(b[4:])
+	m.root = binary.LittleEndian.Uint64(b[12:])
+	m.npages = binary.LittleEndian.Uint64(b[20:])
+	m.count = binary.LittleEndian.Uint64(b[28:])
+	return minPageSize <= m.pageSize && m.pageSize <= maxPageSize && m.root < m.npages
+}
+
+// child refers to a node either by the page it is stored in or, if it
+// was modified since the last commit, directly.
+type child struct {
+	pg uint64
+	n  *node
+}
+
+// node is a node of the B+tree. Leaves hold the entries; a branch holds
+// len(keys)+1 children, where keys[i] is the smallest key under kids[i+1].
+type node struct {
+	leaf bool
+	keys [][]byte
+	vals [][]byte
+	kids []child
+}
+
+func (n *node) clone() *node {
+	return &node{
+		leaf: n.leaf,
+		keys: slices.Clone(n.keys),
+		vals: slices.Clone(n.vals),
+		kids: slices.Clone(n.kids),
+	}
+}
+
+// search returns the position of k in leaf n and whether it is there.
+func (n *node) search(k []byte) (int, bool) {
+	i := sort.Search(len(n.keys), func(i int) bool { return bytes.Compare(n.keys[i], k) >= 0 })
+	return i, i < len(n.keys) && bytes.Equal(n.keys[i], k)
+}
+
+// kid returns the index of the child of branch n that may hold k.
+func (n *node) kid(k []byte) int {
+	return sort.Search(len(n.keys), func(i int) bool { return bytes.Compare(n.keys[i], k) > 0 })
+}
+
+func (n *node) empty() bool {
+	if n.leaf {
+		return len(n.keys) == 0
+	}
+
+	return len(n.kids) == 0
+}
+
+func uvarintLen(x int) int {
+	var b [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(b[:], uint64(x))
+}
+
+func leafEntrySize(k, v []byte) int {
+	return uvarintLen(len(k)) + len(k) + uvarintLen(len(v)) + len(v)
+}
+
+func branchEntrySize(k []byte) int { return uvarintLen(len(k)) + len(k) + 8 }
+
+func (n *node) entrySize(i int) int {
+	if n.leaf {
+		return leafEntrySize(n.keys[i], n.vals[i])
+	}
+
+	return branchEntrySize(n.keys[i])
+}
+
+// size returns the number of bytes n takes in a page.
+func (n *node) size() int {
+	s := 1 + uvarintLen(len(n.keys))
+	if !n.leaf {
+		s += 8
+	}
+	for i := range n.keys {
+		s += n.entrySize(i)
+	}
+
+	return s
+}
+
+// split moves about half of the bytes of n to a new right sibling and
+// returns the separating key.
+func (n *node) split() (sep []byte, right *node) {
+	half, sum, mid := n.size()/2, 0, 0
+	for mid < len(n.keys)-1 && sum < half {
+		sum += n.entrySize(mid)
+		mid++
+	}
+
+	if n.leaf {
+		mid = max(mid, 1)
+		right = &node{leaf: true, keys: slices.Clone(n.keys[mid:]), vals: slices.Clone(n.vals[mid:])}
+		n.keys, n.vals = slices.Clip(n.keys[:mid]), slices.Clip(n.vals[:mid])
+		return right.keys[0], right
+	}
+
+	sep = n.keys[mid]
+	right = &node{keys: slices.Clone(n.keys[mid+1:]), kids: slices.Clone(n.kids[mid+1:])}
+	n.keys, n.kids = slices.Clip(n.keys[:mid]), slices.Clip(n.kids[:mid+1])
+	return sep, right
+}
+
+// encode writes clean node n into page b.
+func (n *node) encode(b []byte) {
+	clear(b)
+	b[0] = branchPage
+	if n.leaf {
+		b[0] = leafPage
+	}
+	i := 1 + binary.PutUvarint(b[1:], uint64(len(n.keys)))
+
+	if !n.leaf {
+		binary.LittleEndian.PutUint64(b[i:], n.kids[0].pg)
+		i += 8
+	}
+	for j, k := range n.keys {
+		i += binary.PutUvarint(b[i:], uint64(len(k)))
+		i += copy(b[i:], k)
+		if n.leaf {
+			i += binary.PutUvarint(b[i:], uint64(len(n.vals[j])))
+			i += copy(b[i:], n.vals[j])
+		} else {
+			binary.LittleEndian.PutUint64(b[i:], n.kids[j+1].pg)
+			i += 8
+		}
+	}
+}
+
+// decode returns the node stored in page b.
+func decode(b []byte) (*node, error) {
+	d := decoder{b: b}
+	n := &node{leaf: d.byte() == leafPage}
+	if !n.leaf && b[0] != branchPage {
+		return nil, ErrCorrupt
+	}
+
+	count := d.uvarint()
+	if count > uint64(len(b)) {
+		return nil, ErrCorrupt
+	}
+	n.keys = make([][]byte, count)
+	if n.leaf {
+		n.vals = make([][]byte, count)
+	} else {
+		n.kids = make([]child, count+1)
+		n.kids[0].pg = d.uint64()
+	}
+
+	for i := range n.keys {
+		n.keys[i] = d.bytes()
+		if n.leaf {
+			n.vals[i] = d.bytes()
+		} else {
+			n.kids[i+1].pg = d.uint64()
+		}
+	}
+
+	if d.bad {
+		return nil, ErrCorrupt
+	}
+
+	return n, nil
+}
+
+// decoder reads the fields of a page, recording an overrun in bad.
+type decoder struct {
+	b   []byte
+	bad bool
+}
+
+func (d *decoder) byte() byte {
+	if len(d.b) < 1 {
+		d.bad = true
+		return 0
+	}
+
+	x := d.b[0]
+	d.b = d.b[1:]
+	return x
+}
+
+func (d *decoder) uvarint() uint64 {
+	x, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.bad = true
+		return 0
+	}
+
+	d.b = d.b[n:]
+	return x
+}
+
+func (d *decoder) uint64() uint64 {
+	if len(d.b) < 8 {
+		d.bad = true
+		return 0
+	}
+
+	x := binary.LittleEndian.Uint64(d.b)
+	d.b = d.b[8:]
+	return x
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		d.bad = true
+		return nil
+	}
+
+	x := d.b[:n:n]
+	d.b = d.b[n:]
+	return x
+}
@@ -0,0 +1,85 @@
+package encoding
+
+// A Codec encodes and decodes values of type T.
+// Codecs for the basic types are provided by the functions of this
+// package; other types can use a Codec with custom functions.
+type Codec[T any] struct {
+	Encode func(e *Encoder, v T) error
+	Decode func(d *Decoder) (T, error)
+}
+
+// Int returns a codec for signed integers, encoded as varints.
+// Decoding a value that does not fit in T fails with ErrFormat.
+func Int[T ~int | ~int8 | ~int16 | ~int32 | ~int64]() Codec[T] {
+	return Codec[T]{
+		Encode: func(e *Encoder, v T) error { return e.WriteVarint(int64(v)) },
+		Decode: func(d *Decoder) (T, error) {
+			x, err := d.ReadVarint()
+			if err == nil && int64(T(x)) != x {
+				err = ErrFormat
+			}
+
+			return T(x), err
+		},
+	}
+}
+
+// Uint returns a codec for unsigned integers, encoded as uvarints.
+// Decoding a value that does not fit in T fails with ErrFormat.
+func Uint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr]() Codec[T] {
+	return Codec[T]{
+		Encode: func(e *Encoder, v T) error { return e.WriteUvarint(uint64(v)) },
+		Decode: func(d *Decoder) (T, error) {
+			x, err := d.ReadUvarint()
+			if err == nil && uint64(T(x)) != x {
+				err = ErrFormat
+			}
+
+			return T(x), err
+		},
+	}
+}
+
+// Float returns a codec for floating-point numbers, encoded in 8 bytes.
+func Float[T ~float32 | ~float64]() Codec[T] {
+	return Codec[T]{
+		Encode: func(e *Encoder, v T) error { return e.WriteFloat64(float64(v)) },
+		Decode: func(d *Decoder) (T, error) {
+			x, err := d.ReadFloat64()
+			return T(x), err
+		},
+	}
+}
+
+// Bool returns a codec for booleans, encoded in one byte.
+func Bool[T ~bool]() Codec[T] {
+	return Codec[T]{
+		Encode: func(e *Encoder, v T) error { return e.WriteBool(bool(v)) },
+		Decode: func(d *Decoder) (T, error) {
+			x, err := d.ReadBool()
+			return T(x), err
+		},
+	}
+}
+
+// String returns a codec for strings, encoded with a length prefix.
+func String[T ~string]() Codec[T] {
+	return Codec[T]{
+		Encode: func(e *Encoder, v T) error { return e.WriteString(string(v)) },
+		Decode: func(d *Decoder) (T, error) {
+			x, err := d.ReadString()
+			return T(x), err
+		},
+	}
+}
+
+// Bytes returns a codec for byte slices, encoded with a length prefix.
+func Bytes[T ~[]byte]() Codec[T] {
+	return Codec[T]{
+		Encode: func(e *Encoder, v T) error { return e.WriteBytes(v) },
+		Decode: func(d *Decoder) (T, error) {
+			x, err := d.ReadBytes()
+			return T(x), err
+		},
+	}
+}
@@ -0,0 +1,247 @@
+package encoding
+
+import (
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/list"
+	"github.com/weiwenchen2022/container/pvector"
+	"github.com/weiwenchen2022/container/wbtree"
+)
+
+// Container tags written before each encoded container.
+const (
+	tagList    = 'L'
+	tagHeap    = 'H'
+	tagVector  = 'V'
+	tagTreeMap = 'T'
+	tagMap     = 'M'
+	tagSet     = 'S'
+)
+
+// preallocLimit bounds the space reserved from an untrusted element count.
+const preallocLimit = 1024
+
+// EncodeList writes the elements of l from front to back.
+func EncodeList[E any](e *Encoder, l *list.List[E], c Codec[E]) error {
+	if err := e.writeHeader(tagList, l.Len()); err != nil {
+		return err
+	}
+
+	for x := l.Front(); x != nil; x = x.Next() {
+		if err := c.Encode(e, x.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeList reads a list written by EncodeList.
+func DecodeList[E any](d *Decoder, c Codec[E]) (*list.List[E], error) {
+	n, err := d.readHeader(tagList)
+	if err != nil {
+		return nil, err
+	}
+
+	l := list.New[E]()
+	for range n {
+		x, err := c.Decode(d)
+		if err != nil {
+			return nil, unexpected(err)
+		}
+		l.PushBack(x)
+	}
+
+	return l, nil
+}
+
+// EncodeHeap writes the elements of h in its internal order, so decoding
+// does not need to re-sort them.
+func EncodeHeap[E any](e *Encoder, h *heap.Heap[E], c Codec[E]) error {
+	if err := e.writeHeader(tagHeap, h.Len()); err != nil {
+		return err
+	}
+
+	for x := range h.Values() {
+		if err := c.Encode(e, x); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeHeap reads a heap written by EncodeHeap, ordered by less.
+// The heap is re-established in case less differs from the ordering of the
+// encoded heap.
+func DecodeHeap[E any](d *Decoder, c Codec[E], less func(a, b E) bool) (*heap.Heap[E], error) {
+	s, err := decodeSlice(d, tagHeap, c)
+	if err != nil {
+		return nil, err
+	}
+
+	h := heap.New(less, heap.WithData(s))
+	h.Init()
+	return h, nil
+}
+
+// EncodeVector writes the elements of v in index order.
+func EncodeVector[E any](e *Encoder, v pvector.Vector[E], c Codec[E]) error {
+	if err := e.writeHeader(tagVector, v.Len()); err != nil {
+		return err
+	}
+
+	for x := range v.Values() {
+		if err := c.Encode(e, x); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeVector reads a vector written by EncodeVector.
+func DecodeVector[E any](d *Decoder, c Codec[E]) (pvector.Vector[E], error) {
+	s, err := decodeSlice(d, tagVector, c)
+	if err != nil {
+		return pvector.Vector[E]{}, err
+	}
+
+	return pvector.New(s...), nil
+}
+
+func decodeSlice[E any](d *Decoder, tag byte, c Codec[E]) ([]E, error) {
+	n, err := d.readHeader(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make([]E, 0, min(n, preallocLimit))
+	for range n {
+		x, err := c.Decode(d)
+		if err != nil {
+			return nil, unexpected(err)
+		}
+		s = append(s, x)
+	}
+
+	return s, nil
+}
+
+// EncodeTreeMap writes the entries of m in key order.
+func EncodeTreeMap[K, V any](e *Encoder, m *wbtree.Map[K, V], kc Codec[K], vc Codec[V]) error {
+	if err := e.writeHeader(tagTreeMap, m.Len()); err != nil {
+		return err
+	}
+
+	for k, v := range m.All() {
+		if err := kc.Encode(e, k); err != nil {
+			return err
+		}
+		if err := vc.Encode(e, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeTreeMap reads a map written by EncodeTreeMap, ordered by less.
+func DecodeTreeMap[K, V any](d *Decoder, kc Codec[K], vc Codec[V], less func(a, b K) bool) (*wbtree.Map[K, V], error) {
+	n, err := d.readHeader(tagTreeMap)
+	if err != nil {
+		return nil, err
+	}
+
+	m := wbtree.New[K, V](less)
+	for range n {
+		k, v, err := decodeEntry(d, kc, vc)
+		if err != nil {
+			return nil, err
+		}
+		m.Set(k, v)
+	}
+
+	return m, nil
+}
+
+// EncodeMap writes the entries of m in unspecified order.
+func EncodeMap[K comparable, V any](e *Encoder, m map[K]V, kc Codec[K], vc Codec[V]) error {
+	if err := e.writeHeader(tagMap, len(m)); err != nil {
+		return err
+	}
+
+	for k, v := range m {
+		if err := kc.Encode(e, k); err != nil {
+			return err
+		}
+		if err := vc.Encode(e, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeMap reads a map written by EncodeMap.
+func DecodeMap[K comparable, V any](d *Decoder, kc Codec[K], vc Codec[V]) (map[K]V, error) {
+	n, err := d.readHeader(tagMap)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[K]V, min(n, preallocLimit))
+	for range n {
+		k, v, err := decodeEntry(d, kc, vc)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+
+	return m, nil
+}
+
+func decodeEntry[K, V any](d *Decoder, kc Codec[K], vc Codec[V]) (k K, v V, err error) {
+	if k, err = kc.Decode(d); err != nil {
+		return k, v, unexpected(err)
+	}
+	if v, err = vc.Decode(d); err != nil {
+		return k, v, unexpected(err)
+	}
+
+	return k, v, nil
+}
+
+// EncodeSet writes the members of the set s in unspecified order.
+func EncodeSet[K comparable](e *Encoder, s map[K]struct{}, c Codec[K]) error {
+	if err := e.writeHeader(tagSet, len(s)); err != nil {
+		return err
+	}
+
+	for k := range s {
+		if err := c.Encode(e, k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeSet reads a set written by EncodeSet.
+func DecodeSet[K comparable](d *Decoder, c Codec[K]) (map[K]struct{}, error) {
+	n, err := d.readHeader(tagSet)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(map[K]struct{}, min(n, preallocLimit))
+	for range n {
+		k, err := c.Decode(d)
+		if err != nil {
+			return nil, unexpected(err)
+		}
+		s[k] = struct{}{}
+	}
+
+	return s, nil
+}
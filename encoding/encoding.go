@@ -0,0 +1,208 @@
+// Package encoding implements a compact binary format for the containers
+// of this module, for checkpoints where text formats are too slow.
+//
+// Each container is written as a one-byte tag identifying its kind, the
+// number of elements as a uvarint, and the elements in order, each written
+// by a pluggable element Codec. Integers are varint-encoded and strings
+// and byte slices are length-prefixed, so small values take little space.
+// Encoders and Decoders stream to and from an io.Writer or io.Reader, and
+// several containers may be written to one stream in sequence.
+//
+// For example, to checkpoint a list of strings:
+//
+//	enc := encoding.NewEncoder(w)
+//	if err := encoding.EncodeList(enc, l, encoding.String[string]()); err != nil {
+//		return err
+//	}
+//	return enc.Flush()
+package encoding
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrFormat is returned when decoding data that is not in the expected format.
+var ErrFormat = errors.New("encoding: invalid format")
+
+// An Encoder writes values to an output stream.
+// Writes are buffered; call Flush when done.
+type Encoder struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+}
+
+// NewEncoder returns an encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (e *Encoder) Flush() error { return e.w.Flush() }
+
+// WriteUvarint writes x as a uvarint.
+func (e *Encoder) WriteUvarint(x uint64) error {
+	_, err := e.w.Write(binary.AppendUvarint(e.buf[:0], x))
+	return err
+}
+
+// WriteVarint writes x as a zigzag-encoded varint.
+func (e *Encoder) WriteVarint(x int64) error {
+	_, err := e.w.Write(binary.AppendVarint(e.buf[:0], x))
+	return err
+}
+
+// WriteFloat64 writes the IEEE 754 bits of x in little-endian order.
+func (e *Encoder) WriteFloat64(x float64) error {
+	_, err := e.w.Write(binary.LittleEndian.AppendUint64(e.buf[:0], math.Float64bits(x)))
+	return err
+}
+
+// WriteBool writes x as a single byte.
+func (e *Encoder) WriteBool(x bool) error {
+	var b byte
+	if x {
+		b = 1
+	}
+
+	return e.w.WriteByte(b)
+}
+
+// WriteBytes writes the length of b as a uvarint followed by b.
+func (e *Encoder) WriteBytes(b []byte) error {
+	if err := e.WriteUvarint(uint64(len(b))); err != nil {
+		return err
+	}
+
+	_, err := e.w.Write(b)
+	return err
+}
+
+// WriteString writes the length of s as a uvarint followed by s.
+func (e *Encoder) WriteString(s string) error {
+	if err := e.WriteUvarint(uint64(len(s))); err != nil {
+		return err
+	}
+
+	_, err := e.w.WriteString(s)
+	return err
+}
+
+// A Decoder reads values from an input stream.
+// It may read past the values it decodes.
+type Decoder struct {
+	r *bufio.Reader
+}
+
+// NewDecoder returns a decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// unexpected converts io.EOF in the middle of a value to io.ErrUnexpectedEOF.
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// ReadUvarint reads a uvarint.
+func (d *Decoder) ReadUvarint() (uint64, error) {
+	x, err := binary.ReadUvarint(d.r)
+	return x, unexpected(err)
+}
+
+// ReadVarint reads a zigzag-encoded varint.
+func (d *Decoder) ReadVarint() (int64, error) {
+	x, err := binary.ReadVarint(d.r)
+	return x, unexpected(err)
+}
+
+// ReadFloat64 reads a float64 written by WriteFloat64.
+func (d *Decoder) ReadFloat64() (float64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(d.r, b[:]); err != nil {
+		return 0, unexpected(err)
+	}
+
+	return math.Float64frombits(binary.LittleEndian.Uint64(b[:])), nil
+}
+
+// ReadBool reads a bool written by WriteBool.
+func (d *Decoder) ReadBool() (bool, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return false, unexpected(err)
+	}
+	if b > 1 {
+		return false, ErrFormat
+	}
+
+	return b == 1, nil
+}
+
+// ReadBytes reads a length-prefixed byte slice.
+func (d *Decoder) ReadBytes() ([]byte, error) {
+	n, err := d.ReadUvarint()
+	if err != nil {
+		return nil, err
+	}
+
+	// Grow the buffer as data arrives rather than trusting a large length.
+	const chunk = 64 << 10
+	if n <= chunk {
+		b := make([]byte, n)
+		_, err := io.ReadFull(d.r, b)
+		return b, unexpected(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, d.r, int64(n)); err != nil {
+		return nil, unexpected(err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ReadString reads a length-prefixed string.
+func (d *Decoder) ReadString() (string, error) {
+	b, err := d.ReadBytes()
+	return string(b), err
+}
+
+// readHeader reads a container tag, checks it is want, and returns the
+// element count.
+func (d *Decoder) readHeader(want byte) (int, error) {
+	tag, err := d.r.ReadByte()
+	if err != nil {
+		return 0, err // io.EOF at a container boundary is not unexpected
+	}
+	if tag != want {
+		return 0, fmt.Errorf("%w: container tag %q, want %q", ErrFormat, tag, want)
+	}
+
+	n, err := d.ReadUvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: %d elements", ErrFormat, n)
+	}
+
+	return int(n), nil
+}
+
+func (e *Encoder) writeHeader(tag byte, n int) error {
+	if err := e.w.WriteByte(tag); err != nil {
+		return err
+	}
+
+	return e.WriteUvarint(uint64(n))
+}
@@ -0,0 +1,222 @@
+package encoding
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/list"
+	"github.com/weiwenchen2022/container/pvector"
+	"github.com/weiwenchen2022/container/wbtree"
+)
+
+func TestCodecs(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	Int[int8]().Encode(e, -128)
+	Int[int64]().Encode(e, math.MinInt64)
+	Uint[uint64]().Encode(e, math.MaxUint64)
+	Float[float64]().Encode(e, math.Inf(-1))
+	Bool[bool]().Encode(e, true)
+	String[string]().Encode(e, "héllo")
+	Bytes[[]byte]().Encode(e, []byte{0, 1, 2})
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDecoder(&buf)
+	if x, err := Int[int8]().Decode(d); err != nil || x != -128 {
+		t.Errorf("Int[int8] = %d, %v, want -128", x, err)
+	}
+	if x, err := Int[int64]().Decode(d); err != nil || x != math.MinInt64 {
+		t.Errorf("Int[int64] = %d, %v, want %d", x, err, int64(math.MinInt64))
+	}
+	if x, err := Uint[uint64]().Decode(d); err != nil || x != math.MaxUint64 {
+		t.Errorf("Uint[uint64] = %d, %v, want %d", x, err, uint64(math.MaxUint64))
+	}
+	if x, err := Float[float64]().Decode(d); err != nil || !math.IsInf(x, -1) {
+		t.Errorf("Float[float64] = %v, %v, want -Inf", x, err)
+	}
+	if x, err := Bool[bool]().Decode(d); err != nil || !x {
+		t.Errorf("Bool[bool] = %t, %v, want true", x, err)
+	}
+	if x, err := String[string]().Decode(d); err != nil || x != "héllo" {
+		t.Errorf("String[string] = %q, %v, want %q", x, err, "héllo")
+	}
+	if x, err := Bytes[[]byte]().Decode(d); err != nil || !bytes.Equal(x, []byte{0, 1, 2}) {
+		t.Errorf("Bytes[[]byte] = %v, %v, want [0 1 2]", x, err)
+	}
+	if _, err := Int[int]().Decode(d); err != io.ErrUnexpectedEOF {
+		t.Errorf("decoding past the end: err = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+
+	buf.Reset()
+	e = NewEncoder(&buf)
+	Int[int]().Encode(e, 300)
+	e.Flush()
+	if _, err := Int[int8]().Decode(NewDecoder(&buf)); !errors.Is(err, ErrFormat) {
+		t.Errorf("decoding 300 as int8: err = %v, want %v", err, ErrFormat)
+	}
+}
+
+type point struct{ X, Y int }
+
+var pointCodec = Codec[point]{
+	Encode: func(e *Encoder, p point) error {
+		if err := e.WriteVarint(int64(p.X)); err != nil {
+			return err
+		}
+		return e.WriteVarint(int64(p.Y))
+	},
+	Decode: func(d *Decoder) (p point, err error) {
+		x, err := d.ReadVarint()
+		if err != nil {
+			return p, err
+		}
+		y, err := d.ReadVarint()
+		return point{int(x), int(y)}, err
+	},
+}
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	l := list.New[point]()
+	for i := range 100 {
+		l.PushBack(point{i, -i})
+	}
+
+	less := func(a, b int) bool { return a < b }
+	h := heap.New(less)
+	for i := range 100 {
+		h.Push((i * 37) % 100)
+	}
+
+	v := pvector.New("a", "b", "c")
+
+	tm := wbtree.New[string, float64](func(a, b string) bool { return a < b })
+	tm.Set("pi", math.Pi)
+	tm.Set("e", math.E)
+
+	m := map[int]string{1: "one", 2: "two"}
+	s := map[string]struct{}{"x": {}, "y": {}}
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	for _, err := range []error{
+		EncodeList(e, l, pointCodec),
+		EncodeHeap(e, h, Int[int]()),
+		EncodeVector(e, v, String[string]()),
+		EncodeTreeMap(e, tm, String[string](), Float[float64]()),
+		EncodeMap(e, m, Int[int](), String[string]()),
+		EncodeSet(e, s, String[string]()),
+		e.Flush(),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := NewDecoder(&buf)
+	l2, err := DecodeList(d, pointCodec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l2.Len() != 100 {
+		t.Fatalf("l2.Len() = %d, want 100", l2.Len())
+	}
+	for x, y := l.Front(), l2.Front(); x != nil; x, y = x.Next(), y.Next() {
+		if x.Value != y.Value {
+			t.Fatalf("list element = %v, want %v", y.Value, x.Value)
+		}
+	}
+
+	h2, err := DecodeHeap(d, Int[int](), less)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 100 {
+		if x := h2.Pop(); x != i {
+			t.Fatalf("h2.Pop() = %d, want %d", x, i)
+		}
+	}
+
+	v2, err := DecodeVector(d, String[string]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v2.AppendTo(nil); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("vector = %v, want [a b c]", got)
+	}
+
+	tm2, err := DecodeTreeMap(d, String[string](), Float[float64](), func(a, b string) bool { return a < b })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := slices.Collect(tm2.Keys()); !slices.Equal(got, []string{"e", "pi"}) {
+		t.Errorf("tree map keys = %v, want [e pi]", got)
+	}
+	if x, _ := tm2.Get("pi"); x != math.Pi {
+		t.Errorf(`tm2.Get("pi") = %v, want %v`, x, math.Pi)
+	}
+
+	m2, err := DecodeMap(d, Int[int](), String[string]())
+	if err != nil || !maps.Equal(m, m2) {
+		t.Errorf("DecodeMap = %v, %v, want %v", m2, err, m)
+	}
+
+	s2, err := DecodeSet(d, String[string]())
+	if err != nil || !maps.Equal(s, s2) {
+		t.Errorf("DecodeSet = %v, %v, want %v", s2, err, s)
+	}
+
+	if _, err := DecodeList(d, pointCodec); err != io.EOF {
+		t.Errorf("DecodeList at end of stream: err = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	EncodeVector(e, pvector.New(1, 2, 3), Int[int]())
+	e.Flush()
+	data := buf.Bytes()
+
+	if _, err := DecodeList(NewDecoder(bytes.NewReader(data)), Int[int]()); !errors.Is(err, ErrFormat) {
+		t.Errorf("DecodeList of a vector: err = %v, want %v", err, ErrFormat)
+	}
+
+	for n := 1; n < len(data); n++ {
+		if _, err := DecodeVector(NewDecoder(bytes.NewReader(data[:n])), Int[int]()); err != io.ErrUnexpectedEOF {
+			t.Errorf("DecodeVector of %d bytes: err = %v, want %v", n, err, io.ErrUnexpectedEOF)
+		}
+	}
+
+	// A huge element count must not be trusted for allocation.
+	huge := []byte{tagSet, 0xff, 0xff, 0xff, 0xff, 0x07}
+	if _, err := DecodeSet(NewDecoder(bytes.NewReader(huge)), Int[int]()); err != io.ErrUnexpectedEOF {
+		t.Errorf("DecodeSet of a truncated huge set: err = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func BenchmarkEncodeList(b *testing.B) {
+	l := list.New[int]()
+	for i := range 10000 {
+		l.PushBack(i)
+	}
+
+	e := NewEncoder(io.Discard)
+	for i := 0; i < b.N; i++ {
+		EncodeList(e, l, Int[int]())
+	}
+	e.Flush()
+}
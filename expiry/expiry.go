@@ -0,0 +1,167 @@
+// Package expiry adds per-element expiration to any container.
+//
+// A Wrapper tracks an expiration time for each handle registered with
+// Expire. A handle identifies an element of the wrapped container: the
+// element itself for a set, or the *list.Element holding it for a list.
+// Expired elements are removed from the container, through the function
+// given to New, when the wrapper is accessed and by DeleteExpired. The
+// expiration times are kept in a timer heap, so removing them costs
+// O(log n) each and finding none costs O(1).
+//
+// For example, to let the elements of a list used as a queue expire:
+//
+//	w := expiry.ForList(list.New[string]())
+//	w.Expire(w.Unwrap().PushBack("job"), time.Minute)
+//	q := list.AsQueue(w.Unwrap())
+package expiry
+
+import (
+	"time"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/list"
+)
+
+// NoTTL marks an element that never expires.
+const NoTTL time.Duration = 0
+
+type entry[H comparable] struct {
+	h       H
+	expires time.Time
+
+	index int // index in the expiry heap, or -1
+}
+
+// Wrapper is a container of type C whose elements, identified by handles
+// of type H, expire.
+// To create a wrapper use expiry.New or expiry.ForList.
+type Wrapper[C container.Container, H comparable] struct {
+	c      C
+	remove func(C, H)
+
+	items  map[H]*entry[H]
+	expiry *heap.Heap[*entry[H]] // earliest first
+
+	onExpire func(H)
+	now      func() time.Time
+}
+
+type option[C container.Container, H comparable] func(*Wrapper[C, H])
+
+// WithOnExpire sets a function called with the handle of each element
+// after it is removed because it expired.
+func WithOnExpire[C container.Container, H comparable](f func(H)) option[C, H] {
+	return func(w *Wrapper[C, H]) {
+		w.onExpire = f
+	}
+}
+
+// New returns a wrapper of container c that removes an expired element
+// by calling remove with c and the element's handle.
+func New[C container.Container, H comparable](c C, remove func(c C, h H), opts ...option[C, H]) *Wrapper[C, H] {
+	w := &Wrapper[C, H]{
+		c:      c,
+		remove: remove,
+		items:  make(map[H]*entry[H]),
+		expiry: heap.New(func(a, b *entry[H]) bool { return a.expires.Before(b.expires) },
+			heap.WithSetIndex(func(e *entry[H], i int) { e.index = i })),
+		now: time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// ForList returns a wrapper of list l whose handles are the elements of l.
+func ForList[E any](l *list.List[E], opts ...option[*list.List[E], *list.Element[E]]) *Wrapper[*list.List[E], *list.Element[E]] {
+	return New(l, func(l *list.List[E], e *list.Element[E]) { l.Remove(e) }, opts...)
+}
+
+// Unwrap removes the expired elements and returns the wrapped container.
+// Elements removed from the container directly should be reported with
+// Forget.
+func (w *Wrapper[C, H]) Unwrap() C {
+	w.DeleteExpired()
+	return w.c
+}
+
+// Len removes the expired elements and returns the number of elements in
+// the wrapped container.
+func (w *Wrapper[C, H]) Len() int {
+	w.DeleteExpired()
+	return w.c.Len()
+}
+
+// Clear removes all elements from the wrapped container and forgets their
+// expiration times. No expiration callbacks are made.
+func (w *Wrapper[C, H]) Clear() {
+	w.c.Clear()
+	clear(w.items)
+	w.expiry.Clear()
+}
+
+// Expire sets the element identified by h to expire ttl from now,
+// replacing any earlier expiration time. A ttl of NoTTL makes the element
+// never expire.
+func (w *Wrapper[C, H]) Expire(h H, ttl time.Duration) {
+	if ttl == NoTTL {
+		w.Forget(h)
+		return
+	}
+
+	expires := w.now().Add(ttl)
+	if e, ok := w.items[h]; ok {
+		e.expires = expires
+		w.expiry.Fix(e.index)
+		return
+	}
+
+	e := &entry[H]{h: h, expires: expires}
+	w.items[h] = e
+	w.expiry.Push(e)
+}
+
+// Forget stops tracking the expiration of the element identified by h.
+func (w *Wrapper[C, H]) Forget(h H) {
+	if e, ok := w.items[h]; ok {
+		delete(w.items, h)
+		w.expiry.Remove(e.index)
+	}
+}
+
+// TTL returns the time left until the element identified by h expires.
+// The ok result reports whether the element has an expiration time and
+// has not expired; a removed expired element is no longer known.
+func (w *Wrapper[C, H]) TTL(h H) (ttl time.Duration, ok bool) {
+	w.DeleteExpired()
+
+	e, ok := w.items[h]
+	if !ok {
+		return 0, false
+	}
+
+	return e.expires.Sub(w.now()), true
+}
+
+// DeleteExpired removes the expired elements from the wrapped container
+// and returns their number.
+func (w *Wrapper[C, H]) DeleteExpired() int {
+	now := w.now()
+
+	n := 0
+	for w.expiry.Len() > 0 && !now.Before(w.expiry.Peek().expires) {
+		e := w.expiry.Pop()
+		delete(w.items, e.h)
+		w.remove(w.c, e.h)
+		if w.onExpire != nil {
+			w.onExpire(e.h)
+		}
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,91 @@
+package expiry
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/list"
+	"github.com/weiwenchen2022/container/sortedslice"
+)
+
+var _ container.Container = (*Wrapper[*list.List[int], *list.Element[int]])(nil)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(0, 0)}
+
+	var expired []string
+	w := ForList(list.New[string](), WithOnExpire[*list.List[string]](func(e *list.Element[string]) {
+		expired = append(expired, e.Value)
+	}))
+	w.now = clock.now
+
+	l := w.Unwrap()
+	a := l.PushBack("a")
+	b := l.PushBack("b")
+	c := l.PushBack("c")
+	w.Expire(a, time.Second)
+	w.Expire(b, 2*time.Second)
+	w.Expire(c, time.Second)
+	w.Expire(c, NoTTL)
+
+	if ttl, ok := w.TTL(b); !ok || ttl != 2*time.Second {
+		t.Errorf("w.TTL(b) = %v, %t, want %v, true", ttl, ok, 2*time.Second)
+	}
+
+	clock.advance(time.Second)
+	if n := w.Len(); n != 2 {
+		t.Errorf("w.Len() = %d, want 2", n)
+	}
+	if v, _ := list.AsQueue(w.Unwrap()).Peek(); v != "b" {
+		t.Errorf("front = %q, want %q", v, "b")
+	}
+
+	w.Expire(b, time.Hour)
+	clock.advance(time.Minute)
+	if n := w.DeleteExpired(); n != 0 {
+		t.Errorf("w.DeleteExpired() after refreshing = %d, want 0", n)
+	}
+
+	clock.advance(time.Hour)
+	if n := w.DeleteExpired(); n != 1 {
+		t.Errorf("w.DeleteExpired() = %d, want 1", n)
+	}
+	if want := []string{"a", "b"}; !slices.Equal(expired, want) {
+		t.Errorf("expired = %q, want %q", expired, want)
+	}
+
+	w.Clear()
+	if n := w.Len(); n != 0 {
+		t.Errorf("w.Len() after Clear = %d", n)
+	}
+}
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	w := New(sortedslice.New(1, 2, 3), func(s *sortedslice.Slice[int], x int) { s.Delete(x) })
+	w.now = clock.now
+
+	w.Expire(2, time.Second)
+	w.Expire(3, time.Second)
+	w.Forget(3)
+
+	clock.advance(time.Second)
+	if got := slices.Collect(w.Unwrap().Values()); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("set = %v, want [1 3]", got)
+	}
+	if _, ok := w.TTL(2); ok {
+		t.Errorf("w.TTL(2) of an expired element reports ok")
+	}
+}
@@ -0,0 +1,236 @@
+// Package extsort implements external sorting of data larger than memory.
+//
+// A Sorter collects values in memory up to a run size, sorts each full run
+// and spills it to a temporary file using an encoding.Codec, then merges
+// the runs with a k-way merge on a heap when the sorted values are read.
+// Memory use is bounded by the run size plus one buffered value per run.
+//
+//	s := extsort.New(cmp.Compare[string], encoding.String[string]())
+//	defer s.Close()
+//	for line := range lines {
+//		if err := s.Add(line); err != nil {
+//			return err
+//		}
+//	}
+//	for line := range s.Sorted() {
+//		// lines in order
+//	}
+//	if err := s.Err(); err != nil {
+//		return err
+//	}
+package extsort
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"slices"
+
+	"github.com/weiwenchen2022/container/encoding"
+	"github.com/weiwenchen2022/container/heap"
+)
+
+// Merge returns an iterator over the values of seqs, each of which must be
+// sorted by cmp, in sorted order. Equal values are yielded in the order of
+// the sequences they come from, so the merge is stable.
+func Merge[E any](cmp func(a, b E) int, seqs ...iter.Seq[E]) iter.Seq[E] {
+	type head struct {
+		x    E
+		i    int
+		next func() (E, bool)
+	}
+
+	return func(yield func(E) bool) {
+		h := heap.New(func(a, b *head) bool {
+			if c := cmp(a.x, b.x); c != 0 {
+				return c < 0
+			}
+			return a.i < b.i
+		}, heap.WithInitialCap[*head](len(seqs)))
+
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+
+			if x, ok := next(); ok {
+				h.Push(&head{x, i, next})
+			}
+		}
+
+		for h.Len() > 0 {
+			top := h.Peek()
+			if !yield(top.x) {
+				return
+			}
+
+			if x, ok := top.next(); ok {
+				top.x = x
+				h.Fix(0)
+			} else {
+				h.Pop()
+			}
+		}
+	}
+}
+
+// run is a sorted run spilled to a file.
+type run struct {
+	f *os.File
+	n int
+}
+
+// Sorter sorts values that may not fit in memory.
+// To create a sorter use extsort.New.
+type Sorter[E any] struct {
+	cmp     func(a, b E) int
+	codec   encoding.Codec[E]
+	runSize int
+	dir     string
+
+	buf  []E
+	runs []run
+	err  error
+}
+
+type option[E any] func(*Sorter[E])
+
+// WithRunSize sets the number of values sorted in memory before a run is
+// spilled to disk. The default is 65536.
+// It panics if n < 1.
+func WithRunSize[E any](n int) option[E] {
+	if n < 1 {
+		panic("extsort: non-positive run size")
+	}
+
+	return func(s *Sorter[E]) {
+		s.runSize = n
+	}
+}
+
+// WithTempDir sets the directory for the run files. The default is the
+// directory returned by os.TempDir.
+func WithTempDir[E any](dir string) option[E] {
+	return func(s *Sorter[E]) {
+		s.dir = dir
+	}
+}
+
+// New returns a sorter ordering values by cmp, which returns a negative
+// number when a < b, a positive number when a > b and zero when a and b
+// are equal, and spilling them with codec.
+func New[E any](cmp func(a, b E) int, codec encoding.Codec[E], opts ...option[E]) *Sorter[E] {
+	s := &Sorter[E]{cmp: cmp, codec: codec, runSize: 1 << 16}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Add adds x to the values to sort.
+// It returns an error if a run cannot be spilled; the sorter must then be
+// closed.
+func (s *Sorter[E]) Add(x E) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.buf = append(s.buf, x)
+	if len(s.buf) >= s.runSize {
+		s.err = s.spill()
+	}
+
+	return s.err
+}
+
+// spill sorts the buffered values and writes them to a new run file.
+func (s *Sorter[E]) spill() error {
+	slices.SortStableFunc(s.buf, s.cmp)
+
+	f, err := os.CreateTemp(s.dir, "extsort-*")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, run{f, len(s.buf)})
+
+	e := encoding.NewEncoder(f)
+	for _, x := range s.buf {
+		if err := s.codec.Encode(e, x); err != nil {
+			return err
+		}
+	}
+	if err := e.Flush(); err != nil {
+		return err
+	}
+
+	clear(s.buf)
+	s.buf = s.buf[:0]
+	return nil
+}
+
+// Runs returns the number of runs spilled to disk so far.
+func (s *Sorter[E]) Runs() int { return len(s.runs) }
+
+// values returns an iterator over the values of run r, recording a read
+// error in s.err.
+func (s *Sorter[E]) values(r run) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		if _, err := r.f.Seek(0, io.SeekStart); err != nil {
+			s.err = err
+			return
+		}
+
+		d := encoding.NewDecoder(r.f)
+		for range r.n {
+			x, err := s.codec.Decode(d)
+			if err != nil {
+				s.err = err
+				return
+			}
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns an iterator over all values added, in sorted order.
+// Equal values are yielded in the order they were added.
+// If reading a run fails the iteration stops early and Err reports the
+// error. No values may be added after calling Sorted.
+func (s *Sorter[E]) Sorted() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		if s.err != nil {
+			return
+		}
+
+		slices.SortStableFunc(s.buf, s.cmp)
+
+		seqs := make([]iter.Seq[E], 0, len(s.runs)+1)
+		for _, r := range s.runs {
+			seqs = append(seqs, s.values(r))
+		}
+		seqs = append(seqs, slices.Values(s.buf))
+
+		for x := range Merge(s.cmp, seqs...) {
+			if s.err != nil || !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the first error encountered by the sorter.
+func (s *Sorter[E]) Err() error { return s.err }
+
+// Close removes the run files of the sorter.
+func (s *Sorter[E]) Close() error {
+	var errs []error
+	for _, r := range s.runs {
+		errs = append(errs, r.f.Close(), os.Remove(r.f.Name()))
+	}
+
+	s.runs, s.buf = nil, nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,127 @@
+package extsort
+
+import (
+	"cmp"
+	"errors"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container/encoding"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	got := slices.Collect(Merge(cmp.Compare[int],
+		slices.Values([]int{1, 4, 7}),
+		slices.Values([]int{}),
+		slices.Values([]int{2, 4, 8, 9}),
+		slices.Values([]int{0})))
+	if want := []int{0, 1, 2, 4, 4, 7, 8, 9}; !slices.Equal(got, want) {
+		t.Errorf("Merge = %v, want %v", got, want)
+	}
+
+	var first []int
+	for x := range Merge(cmp.Compare[int], slices.Values([]int{1, 3}), slices.Values([]int{2})) {
+		if first = append(first, x); len(first) == 2 {
+			break
+		}
+	}
+	if !slices.Equal(first, []int{1, 2}) {
+		t.Errorf("Merge stopped early = %v, want [1 2]", first)
+	}
+}
+
+type rec struct{ key, seq int }
+
+var recCodec = encoding.Codec[rec]{
+	Encode: func(e *encoding.Encoder, r rec) error {
+		if err := e.WriteVarint(int64(r.key)); err != nil {
+			return err
+		}
+		return e.WriteVarint(int64(r.seq))
+	},
+	Decode: func(d *encoding.Decoder) (rec, error) {
+		k, err := d.ReadVarint()
+		if err != nil {
+			return rec{}, err
+		}
+		s, err := d.ReadVarint()
+		return rec{int(k), int(s)}, err
+	},
+}
+
+func TestSorter(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	dir := t.TempDir()
+
+	s := New(func(a, b rec) int { return cmp.Compare(a.key, b.key) }, recCodec,
+		WithRunSize[rec](100), WithTempDir[rec](dir))
+
+	const n = 10050
+	var want []rec
+	for i := range n {
+		x := rec{r.Intn(500), i}
+		want = append(want, x)
+		if err := s.Add(x); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if runs := s.Runs(); runs != n/100 {
+		t.Errorf("s.Runs() = %d, want %d", runs, n/100)
+	}
+
+	slices.SortStableFunc(want, func(a, b rec) int { return cmp.Compare(a.key, b.key) })
+	got := slices.Collect(s.Sorted())
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("sorted values differ from a stable in-memory sort")
+	}
+
+	// the result can be read again
+	if again := slices.Collect(s.Sorted()); !slices.Equal(again, want) {
+		t.Errorf("second read of sorted values differs")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("%d run files left after Close", len(entries))
+	}
+}
+
+func TestSorterError(t *testing.T) {
+	t.Parallel()
+
+	errBad := errors.New("bad value")
+	codec := encoding.Int[int]()
+	decode := codec.Decode
+	codec.Decode = func(d *encoding.Decoder) (int, error) {
+		x, err := decode(d)
+		if err == nil && x == 13 {
+			err = errBad
+		}
+		return x, err
+	}
+
+	s := New(cmp.Compare[int], codec, WithRunSize[int](4), WithTempDir[int](t.TempDir()))
+	defer s.Close()
+	for i := range 20 {
+		s.Add(i)
+	}
+
+	got := slices.Collect(s.Sorted())
+	if !errors.Is(s.Err(), errBad) {
+		t.Errorf("s.Err() = %v, want %v", s.Err(), errBad)
+	}
+	if len(got) >= 20 {
+		t.Errorf("iteration continued after a read error")
+	}
+}
@@ -0,0 +1,471 @@
+// Package fingertree implements a persistent 2-3 finger tree.
+//
+// A finger tree is a sequence annotated with a measurement from a monoid.
+// Adding or removing an element at either end is O(1) amortized, and
+// concatenating two trees or splitting a tree at the point where a
+// predicate on the accumulated measure flips is O(log n). By choosing the
+// measure, the same structure can serve as an indexed sequence (measure
+// by count), a priority queue (measure by maximum priority), an ordered
+// sequence (measure by last key), an interval map, and so on.
+//
+// Trees are immutable: every modifying operation returns a new Tree and
+// shares structure with the receiver.
+//
+// To iterate over a tree (where t is a Tree):
+//
+//	for v := range t.All() {
+//		// do something with v
+//	}
+package fingertree
+
+import "iter"
+
+// Monoid describes an associative operation with an identity element,
+// used to combine the measures of adjacent elements.
+// Combine must satisfy Combine(Identity, v) == Combine(v, Identity) == v and
+// Combine(Combine(a, b), c) == Combine(a, Combine(b, c)).
+type Monoid[V any] struct {
+	Identity V
+	Combine  func(a, b V) V
+}
+
+// item is either an element (a leaf) or a 2-3 node of items one level down.
+// Using a single type for every level avoids the polymorphic recursion
+// that a textbook definition requires.
+type item[E, V any] struct {
+	v    V
+	elem E
+	kids []*item[E, V]
+}
+
+// ftree is a finger tree of items; the nil *ftree is the empty tree.
+type ftree[E, V any] struct {
+	v V
+
+	single *item[E, V]
+
+	prefix, suffix []*item[E, V] // 1 to 4 items each
+	middle         *ftree[E, V]
+}
+
+type ops[E, V any] struct {
+	measure func(E) V
+	Monoid[V]
+}
+
+func (o *ops[E, V]) leaf(e E) *item[E, V] {
+	return &item[E, V]{v: o.measure(e), elem: e}
+}
+
+func (o *ops[E, V]) node(kids ...*item[E, V]) *item[E, V] {
+	return &item[E, V]{v: o.measureItems(kids), kids: kids}
+}
+
+func (o *ops[E, V]) measureItems(items []*item[E, V]) V {
+	v := o.Identity
+	for _, x := range items {
+		v = o.Combine(v, x.v)
+	}
+
+	return v
+}
+
+func (o *ops[E, V]) measureTree(t *ftree[E, V]) V {
+	if t == nil {
+		return o.Identity
+	}
+
+	return t.v
+}
+
+func (o *ops[E, V]) single(x *item[E, V]) *ftree[E, V] {
+	return &ftree[E, V]{v: x.v, single: x}
+}
+
+func (o *ops[E, V]) deep(prefix []*item[E, V], middle *ftree[E, V], suffix []*item[E, V]) *ftree[E, V] {
+	v := o.Combine(o.measureItems(prefix), o.Combine(o.measureTree(middle), o.measureItems(suffix)))
+	return &ftree[E, V]{v: v, prefix: prefix, middle: middle, suffix: suffix}
+}
+
+// digit returns a fresh slice holding xs, so that digits never share
+// backing arrays and may be appended to freely.
+func digit[E, V any](xs ...*item[E, V]) []*item[E, V] {
+	return append(make([]*item[E, V], 0, 4), xs...)
+}
+
+func (o *ops[E, V]) fromItems(xs []*item[E, V]) *ftree[E, V] {
+	var t *ftree[E, V]
+	for _, x := range xs {
+		t = o.pushBack(t, x)
+	}
+
+	return t
+}
+
+func (o *ops[E, V]) pushFront(t *ftree[E, V], x *item[E, V]) *ftree[E, V] {
+	switch {
+	case t == nil:
+		return o.single(x)
+	case t.single != nil:
+		return o.deep(digit(x), nil, digit(t.single))
+	case len(t.prefix) == 4:
+		p := t.prefix
+		return o.deep(digit(x, p[0]), o.pushFront(t.middle, o.node(p[1], p[2], p[3])), t.suffix)
+	default:
+		return o.deep(digit(append([]*item[E, V]{x}, t.prefix...)...), t.middle, t.suffix)
+	}
+}
+
+func (o *ops[E, V]) pushBack(t *ftree[E, V], x *item[E, V]) *ftree[E, V] {
+	switch {
+	case t == nil:
+		return o.single(x)
+	case t.single != nil:
+		return o.deep(digit(t.single), nil, digit(x))
+	case len(t.suffix) == 4:
+		s := t.suffix
+		return o.deep(t.prefix, o.pushBack(t.middle, o.node(s[0], s[1], s[2])), digit(s[3], x))
+	default:
+		return o.deep(t.prefix, t.middle, digit(append(digit(t.suffix...), x)...))
+	}
+}
+
+// deepL is deep for a possibly empty prefix.
+func (o *ops[E, V]) deepL(prefix []*item[E, V], middle *ftree[E, V], suffix []*item[E, V]) *ftree[E, V] {
+	if len(prefix) > 0 {
+		return o.deep(digit(prefix...), middle, suffix)
+	}
+
+	if middle == nil {
+		return o.fromItems(suffix)
+	}
+
+	x, rest := o.viewFront(middle)
+	return o.deep(digit(x.kids...), rest, suffix)
+}
+
+// deepR is deep for a possibly empty suffix.
+func (o *ops[E, V]) deepR(prefix []*item[E, V], middle *ftree[E, V], suffix []*item[E, V]) *ftree[E, V] {
+	if len(suffix) > 0 {
+		return o.deep(prefix, middle, digit(suffix...))
+	}
+
+	if middle == nil {
+		return o.fromItems(prefix)
+	}
+
+	x, rest := o.viewBack(middle)
+	return o.deep(prefix, rest, digit(x.kids...))
+}
+
+func (o *ops[E, V]) viewFront(t *ftree[E, V]) (*item[E, V], *ftree[E, V]) {
+	if t.single != nil {
+		return t.single, nil
+	}
+
+	return t.prefix[0], o.deepL(t.prefix[1:], t.middle, t.suffix)
+}
+
+func (o *ops[E, V]) viewBack(t *ftree[E, V]) (*item[E, V], *ftree[E, V]) {
+	if t.single != nil {
+		return t.single, nil
+	}
+
+	n := len(t.suffix) - 1
+	return t.suffix[n], o.deepR(t.prefix, t.middle, t.suffix[:n])
+}
+
+// nodes groups 2 or more items into 2-3 nodes.
+func (o *ops[E, V]) nodes(xs []*item[E, V]) []*item[E, V] {
+	var ns []*item[E, V]
+	for {
+		switch len(xs) {
+		case 2:
+			return append(ns, o.node(xs[0], xs[1]))
+		case 3:
+			return append(ns, o.node(xs[0], xs[1], xs[2]))
+		case 4:
+			return append(ns, o.node(xs[0], xs[1]), o.node(xs[2], xs[3]))
+		}
+
+		ns = append(ns, o.node(xs[0], xs[1], xs[2]))
+		xs = xs[3:]
+	}
+}
+
+// app3 concatenates t1, the items xs, and t2.
+func (o *ops[E, V]) app3(t1 *ftree[E, V], xs []*item[E, V], t2 *ftree[E, V]) *ftree[E, V] {
+	switch {
+	case t1 == nil:
+		for i := len(xs) - 1; i >= 0; i-- {
+			t2 = o.pushFront(t2, xs[i])
+		}
+		return t2
+	case t2 == nil:
+		for _, x := range xs {
+			t1 = o.pushBack(t1, x)
+		}
+		return t1
+	case t1.single != nil:
+		return o.pushFront(o.app3(nil, xs, t2), t1.single)
+	case t2.single != nil:
+		return o.pushBack(o.app3(t1, xs, nil), t2.single)
+	}
+
+	mid := make([]*item[E, V], 0, len(t1.suffix)+len(xs)+len(t2.prefix))
+	mid = append(append(append(mid, t1.suffix...), xs...), t2.prefix...)
+	return o.deep(t1.prefix, o.app3(t1.middle, o.nodes(mid), t2.middle), t2.suffix)
+}
+
+// splitDigit splits xs around the first item at which pred of the
+// accumulated measure, starting from i, becomes true.
+func (o *ops[E, V]) splitDigit(pred func(V) bool, i V, xs []*item[E, V]) ([]*item[E, V], *item[E, V], []*item[E, V]) {
+	for k, x := range xs[:len(xs)-1] {
+		i = o.Combine(i, x.v)
+		if pred(i) {
+			return xs[:k:k], x, xs[k+1:]
+		}
+	}
+
+	n := len(xs) - 1
+	return xs[:n:n], xs[n], nil
+}
+
+// splitTree splits non-empty t into l, x, r such that pred is false for the
+// accumulated measure through l and true through x.
+func (o *ops[E, V]) splitTree(pred func(V) bool, i V, t *ftree[E, V]) (*ftree[E, V], *item[E, V], *ftree[E, V]) {
+	if t.single != nil {
+		return nil, t.single, nil
+	}
+
+	vpr := o.Combine(i, o.measureItems(t.prefix))
+	if pred(vpr) {
+		l, x, r := o.splitDigit(pred, i, t.prefix)
+		return o.fromItems(l), x, o.deepL(r, t.middle, t.suffix)
+	}
+
+	vm := o.Combine(vpr, o.measureTree(t.middle))
+	if t.middle != nil && pred(vm) {
+		ml, xs, mr := o.splitTree(pred, vpr, t.middle)
+		l, x, r := o.splitDigit(pred, o.Combine(vpr, o.measureTree(ml)), xs.kids)
+		return o.deepR(t.prefix, ml, l), x, o.deepL(r, mr, t.suffix)
+	}
+
+	l, x, r := o.splitDigit(pred, vm, t.suffix)
+	return o.deepR(t.prefix, t.middle, l), x, o.fromItems(r)
+}
+
+func (x *item[E, V]) all(yield func(E) bool) bool {
+	if x.kids == nil {
+		return yield(x.elem)
+	}
+
+	for _, k := range x.kids {
+		if !k.all(yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (x *item[E, V]) backward(yield func(E) bool) bool {
+	if x.kids == nil {
+		return yield(x.elem)
+	}
+
+	for i := len(x.kids) - 1; i >= 0; i-- {
+		if !x.kids[i].backward(yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *ftree[E, V]) all(yield func(E) bool) bool {
+	switch {
+	case t == nil:
+		return true
+	case t.single != nil:
+		return t.single.all(yield)
+	}
+
+	for _, x := range t.prefix {
+		if !x.all(yield) {
+			return false
+		}
+	}
+
+	if !t.middle.all(yield) {
+		return false
+	}
+
+	for _, x := range t.suffix {
+		if !x.all(yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *ftree[E, V]) backward(yield func(E) bool) bool {
+	switch {
+	case t == nil:
+		return true
+	case t.single != nil:
+		return t.single.backward(yield)
+	}
+
+	for i := len(t.suffix) - 1; i >= 0; i-- {
+		if !t.suffix[i].backward(yield) {
+			return false
+		}
+	}
+
+	if !t.middle.backward(yield) {
+		return false
+	}
+
+	for i := len(t.prefix) - 1; i >= 0; i-- {
+		if !t.prefix[i].backward(yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Tree is a persistent finger tree of elements of type E measured by values of type V.
+// To create a tree use fingertree.New.
+type Tree[E, V any] struct {
+	ops  *ops[E, V]
+	root *ftree[E, V]
+}
+
+// New returns an empty tree whose elements are measured by measure and whose
+// measures are combined by m.
+// Only trees derived from the same New call may be concatenated.
+func New[E, V any](measure func(E) V, m Monoid[V]) Tree[E, V] {
+	return Tree[E, V]{ops: &ops[E, V]{measure: measure, Monoid: m}}
+}
+
+func (t Tree[E, V]) with(root *ftree[E, V]) Tree[E, V] {
+	return Tree[E, V]{ops: t.ops, root: root}
+}
+
+// Empty reports whether tree t has no elements.
+func (t Tree[E, V]) Empty() bool { return t.root == nil }
+
+// Measure returns the combined measure of all elements of tree t.
+// The complexity is O(1).
+func (t Tree[E, V]) Measure() V { return t.ops.measureTree(t.root) }
+
+// PushFront returns a tree with e added at the front of t.
+// The complexity is O(1) amortized.
+func (t Tree[E, V]) PushFront(e E) Tree[E, V] {
+	return t.with(t.ops.pushFront(t.root, t.ops.leaf(e)))
+}
+
+// PushBack returns a tree with e added at the back of t.
+// The complexity is O(1) amortized.
+func (t Tree[E, V]) PushBack(e E) Tree[E, V] {
+	return t.with(t.ops.pushBack(t.root, t.ops.leaf(e)))
+}
+
+// Front returns the first element of tree t.
+// The ok result reports whether t is non-empty.
+func (t Tree[E, V]) Front() (e E, ok bool) {
+	if t.root == nil {
+		return e, false
+	}
+
+	x, _ := t.ops.viewFront(t.root)
+	for x.kids != nil {
+		x = x.kids[0]
+	}
+
+	return x.elem, true
+}
+
+// Back returns the last element of tree t.
+// The ok result reports whether t is non-empty.
+func (t Tree[E, V]) Back() (e E, ok bool) {
+	if t.root == nil {
+		return e, false
+	}
+
+	x, _ := t.ops.viewBack(t.root)
+	for x.kids != nil {
+		x = x.kids[len(x.kids)-1]
+	}
+
+	return x.elem, true
+}
+
+// PopFront returns the first element of tree t and a tree of the remaining elements.
+// The tree must not be empty.
+// The complexity is O(1) amortized.
+func (t Tree[E, V]) PopFront() (E, Tree[E, V]) {
+	x, rest := t.ops.viewFront(t.root)
+	return x.elem, t.with(rest)
+}
+
+// PopBack returns the last element of tree t and a tree of the remaining elements.
+// The tree must not be empty.
+// The complexity is O(1) amortized.
+func (t Tree[E, V]) PopBack() (E, Tree[E, V]) {
+	x, rest := t.ops.viewBack(t.root)
+	return x.elem, t.with(rest)
+}
+
+// Concat returns the concatenation of trees t and u.
+// The complexity is O(log(min(n, m))).
+func (t Tree[E, V]) Concat(u Tree[E, V]) Tree[E, V] {
+	return t.with(t.ops.app3(t.root, nil, u.root))
+}
+
+// Split splits tree t into two trees l and r such that l is the longest
+// prefix of t for which pred of its measure is false.
+// pred must be monotonic: once true for a prefix, it is true for every longer prefix.
+// If pred is false for the whole tree, r is empty.
+// The complexity is O(log(min(i, n-i))) where i is the split point.
+func (t Tree[E, V]) Split(pred func(V) bool) (l, r Tree[E, V]) {
+	if t.root == nil || !pred(t.root.v) {
+		return t, t.with(nil)
+	}
+
+	left, x, right := t.ops.splitTree(pred, t.ops.Identity, t.root)
+	return t.with(left), t.with(t.ops.pushFront(right, x))
+}
+
+// All returns an iterator over the elements of tree t from front to back.
+func (t Tree[E, V]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		t.root.all(yield)
+	}
+}
+
+// Backward returns an iterator over the elements of tree t from back to front.
+func (t Tree[E, V]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		t.root.backward(yield)
+	}
+}
+
+// Clone returns t. Trees are persistent, so t is already independent of
+// later changes; Clone exists for uniformity with the other containers.
+func (t Tree[E, V]) Clone() Tree[E, V] { return t }
+
+// CloneFunc returns a tree of the elements of t copied by f, which allows
+// a deep clone. The result may be concatenated with t.
+// The complexity is O(n).
+func (t Tree[E, V]) CloneFunc(f func(E) E) Tree[E, V] {
+	c := t.with(nil)
+	for e := range t.All() {
+		c = c.PushBack(f(e))
+	}
+
+	return c
+}
@@ -0,0 +1,160 @@
+package fingertree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+var count = Monoid[int]{Identity: 0, Combine: func(a, b int) int { return a + b }}
+
+func newSeq() Tree[int, int] {
+	return New(func(int) int { return 1 }, count)
+}
+
+// splitAt splits t after the first i elements, assuming a count measure.
+func splitAt(t Tree[int, int], i int) (Tree[int, int], Tree[int, int]) {
+	return t.Split(func(n int) bool { return n > i })
+}
+
+func checkTree(t *testing.T, tr Tree[int, int], want []int) {
+	t.Helper()
+
+	if m := tr.Measure(); len(want) != m {
+		t.Fatalf("tr.Measure() = %d, want %d", m, len(want))
+	}
+
+	if e := tr.Empty(); e != (len(want) == 0) {
+		t.Fatalf("tr.Empty() = %t, want %t", e, len(want) == 0)
+	}
+
+	if got := slices.Collect(tr.All()); !slices.Equal(want, got) {
+		t.Fatalf("tr.All() = %v, want %v", got, want)
+	}
+
+	got := slices.Collect(tr.Backward())
+	slices.Reverse(got)
+	if !slices.Equal(want, got) {
+		t.Fatalf("tr.Backward() reversed = %v, want %v", got, want)
+	}
+
+	if len(want) > 0 {
+		if v, ok := tr.Front(); !ok || want[0] != v {
+			t.Fatalf("tr.Front() = %d, %t, want %d, true", v, ok, want[0])
+		}
+
+		if v, ok := tr.Back(); !ok || want[len(want)-1] != v {
+			t.Fatalf("tr.Back() = %d, %t, want %d, true", v, ok, want[len(want)-1])
+		}
+	} else if _, ok := tr.Front(); ok {
+		t.Fatalf("tr.Front() ok on empty tree")
+	}
+}
+
+func TestDeque(t *testing.T) {
+	t.Parallel()
+
+	tr := newSeq()
+	var want []int
+	checkTree(t, tr, want)
+
+	for i := 0; i < 100; i++ {
+		tr = tr.PushBack(i)
+		want = append(want, i)
+		tr = tr.PushFront(-i)
+		want = append([]int{-i}, want...)
+	}
+	checkTree(t, tr, want)
+
+	for len(want) > 0 {
+		var v int
+		if len(want)%2 == 0 {
+			v, tr = tr.PopFront()
+			if want[0] != v {
+				t.Fatalf("PopFront() = %d, want %d", v, want[0])
+			}
+			want = want[1:]
+		} else {
+			v, tr = tr.PopBack()
+			if want[len(want)-1] != v {
+				t.Fatalf("PopBack() = %d, want %d", v, want[len(want)-1])
+			}
+			want = want[:len(want)-1]
+		}
+	}
+	checkTree(t, tr, nil)
+}
+
+func TestSplitConcat(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 80; n++ {
+		tr := newSeq()
+		var want []int
+		for i := 0; i < n; i++ {
+			tr = tr.PushBack(i)
+			want = append(want, i)
+		}
+
+		for i := 0; i <= n; i++ {
+			l, rt := splitAt(tr, i)
+			checkTree(t, l, want[:i])
+			checkTree(t, rt, want[i:])
+			checkTree(t, l.Concat(rt), want)
+		}
+
+		// the original is unchanged
+		checkTree(t, tr, want)
+
+		m := r.Intn(50)
+		other := newSeq()
+		var more []int
+		for i := 0; i < m; i++ {
+			other = other.PushFront(i)
+			more = append([]int{i}, more...)
+		}
+		checkTree(t, tr.Concat(other), append(want[:n:n], more...))
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	t.Parallel()
+
+	maxMonoid := Monoid[int]{Identity: -1, Combine: func(a, b int) int { return max(a, b) }}
+	tr := New(func(p int) int { return p }, maxMonoid)
+
+	for _, p := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		tr = tr.PushBack(p)
+	}
+
+	var got []int
+	for !tr.Empty() {
+		m := tr.Measure()
+		l, r := tr.Split(func(v int) bool { return v >= m })
+		var x int
+		x, r = r.PopFront()
+		got = append(got, x)
+		tr = l.Concat(r)
+	}
+
+	if want := []int{9, 6, 5, 4, 3, 2, 1, 1}; !slices.Equal(want, got) {
+		t.Errorf("extract max order = %v, want %v", got, want)
+	}
+}
+
+func TestCloneFunc(t *testing.T) {
+	t.Parallel()
+
+	tr := newSeq()
+	var want []int
+	for i := range 50 {
+		tr = tr.PushBack(i)
+		want = append(want, -i)
+	}
+
+	c := tr.CloneFunc(func(x int) int { return -x })
+	checkTree(t, c, want)
+	checkTree(t, c.Concat(tr.Clone()), append(want, slices.Collect(tr.All())...))
+}
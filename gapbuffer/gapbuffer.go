@@ -0,0 +1,221 @@
+// Package gapbuffer implements a gap buffer.
+//
+// A gap buffer stores its elements in a single slice with an unused
+// region (the gap) positioned at the cursor. Insertions and deletions at
+// the cursor are O(1) amortized; moving the cursor by d positions costs
+// O(d). This makes a gap buffer a simple and efficient sequence for
+// single-cursor editing, where most changes are localized.
+//
+// To iterate over a buffer (where b is a *Buffer):
+//
+//	before, after := b.Slices()
+//	for _, v := range before {
+//		// do something with v
+//	}
+//	for _, v := range after {
+//		// do something with v
+//	}
+package gapbuffer
+
+import (
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+	"github.com/weiwenchen2022/container/sortx"
+)
+
+const minGap = 16
+
+// Buffer represents a gap buffer.
+// The zero value for Buffer is an empty buffer ready to use.
+type Buffer[E any] struct {
+	s []E
+
+	// the gap is s[start:end], the cursor is at start
+	start, end int
+}
+
+type option[E any] func(*Buffer[E])
+
+// WithData sets buffer using s as its initial contents, with the cursor at the end.
+// The buffer takes ownership of s, and the caller should not use s after this call.
+func WithData[E any](s []E) option[E] {
+	return func(b *Buffer[E]) {
+		b.s = s[:cap(s)]
+		b.start, b.end = len(s), cap(s)
+	}
+}
+
+// WithInitialCap sets buffer's initial space to hold n elements.
+func WithInitialCap[E any](n int) option[E] {
+	return func(b *Buffer[E]) {
+		b.s = make([]E, n)
+		b.start, b.end = 0, n
+	}
+}
+
+// New returns an initialized buffer.
+func New[E any](opts ...option[E]) *Buffer[E] {
+	b := new(Buffer[E])
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Len returns the number of elements of buffer b.
+// The complexity is O(1).
+func (b *Buffer[E]) Len() int { return len(b.s) - (b.end - b.start) }
+
+// Cap returns the number of elements buffer b can hold without reallocating.
+func (b *Buffer[E]) Cap() int { return len(b.s) }
+
+// Cursor returns the position of the cursor, in the range [0, b.Len()].
+func (b *Buffer[E]) Cursor() int { return b.start }
+
+// Clear removes all elements from buffer b, retaining the allocated space.
+func (b *Buffer[E]) Clear() {
+	clear(b.s) // avoid memory leak
+	b.start, b.end = 0, len(b.s)
+}
+
+// index translates a logical position to an index into b.s.
+func (b *Buffer[E]) index(i int) int {
+	if i < 0 || i >= b.Len() {
+		panic("gapbuffer: index out of range")
+	}
+
+	if i < b.start {
+		return i
+	}
+
+	return i + (b.end - b.start)
+}
+
+// At returns the element at position i.
+func (b *Buffer[E]) At(i int) E { return b.s[b.index(i)] }
+
+// Set sets the element at position i to v.
+func (b *Buffer[E]) Set(i int, v E) { b.s[b.index(i)] = v }
+
+// Seek moves the cursor to position i.
+// The complexity is O(d) where d is the distance moved.
+func (b *Buffer[E]) Seek(i int) {
+	if i < 0 || i > b.Len() {
+		panic("gapbuffer: cursor out of range")
+	}
+
+	switch {
+	case i < b.start:
+		// move s[i:start] to the end of the gap
+		n := copy(b.s[b.end-(b.start-i):b.end], b.s[i:b.start])
+		clear(b.s[i:min(b.start, b.end-n)])
+		b.start, b.end = i, b.end-n
+	case i > b.start:
+		// move s[end:end+d] to the start of the gap
+		d := i - b.start
+		n := copy(b.s[b.start:], b.s[b.end:b.end+d])
+		clear(b.s[max(b.end, b.start+n) : b.end+d])
+		b.start, b.end = b.start+n, b.end+n
+	}
+}
+
+// Grow grows the buffer's capacity, if necessary, to guarantee space for
+// another n elements. After Grow(n), at least n elements can be inserted
+// at the cursor without another allocation.
+// If n is negative, Grow will panic.
+func (b *Buffer[E]) Grow(n int) {
+	if n < 0 {
+		panic("gapbuffer: negative count")
+	}
+
+	if b.end-b.start >= n {
+		return
+	}
+
+	size := max(2*len(b.s), b.Len()+n+minGap)
+	s := make([]E, size)
+	copy(s, b.s[:b.start])
+	tail := len(b.s) - b.end
+	copy(s[size-tail:], b.s[b.end:])
+	b.s, b.end = s, size-tail
+}
+
+// Insert inserts the values vs at the cursor and advances the cursor past them.
+func (b *Buffer[E]) Insert(vs ...E) {
+	b.Grow(len(vs))
+	b.start += copy(b.s[b.start:], vs)
+}
+
+// Delete removes up to n elements after the cursor and returns the number removed.
+func (b *Buffer[E]) Delete(n int) int {
+	n = max(0, min(n, len(b.s)-b.end))
+	clear(b.s[b.end : b.end+n]) // avoid memory leak
+	b.end += n
+	return n
+}
+
+// Backspace removes up to n elements before the cursor and returns the number removed.
+func (b *Buffer[E]) Backspace(n int) int {
+	n = max(0, min(n, b.start))
+	clear(b.s[b.start-n : b.start]) // avoid memory leak
+	b.start -= n
+	return n
+}
+
+// Slices returns the elements of buffer b as two slices: the elements
+// before the cursor and the elements after it. The slices alias the buffer
+// and are valid only until the next modification of b.
+func (b *Buffer[E]) Slices() (before, after []E) {
+	return b.s[:b.start:b.start], b.s[b.end:]
+}
+
+// Sort sorts the elements of buffer b in ascending order as determined by
+// less, leaving the cursor at the same position.
+// The sort is not guaranteed to be stable.
+// The complexity is O(n log n).
+func (b *Buffer[E]) Sort(less func(a, b E) bool) {
+	cursor := b.start
+	b.Seek(b.Len())
+	sortx.Sort(b.s[:b.start], less)
+	b.Seek(cursor)
+}
+
+// AppendTo appends the elements of buffer b to dst in order and returns the extended slice.
+func (b *Buffer[E]) AppendTo(dst []E) []E {
+	before, after := b.Slices()
+	return append(append(dst, before...), after...)
+}
+
+// Clone returns a copy of buffer b with the same cursor. The elements are
+// copied by assignment, so this is a shallow clone.
+func (b *Buffer[E]) Clone() *Buffer[E] {
+	c := *b
+	c.s = append([]E(nil), b.s...)
+	return &c
+}
+
+// CloneFunc returns a copy of buffer b with the same cursor and each
+// element copied by f, which allows a deep clone.
+func (b *Buffer[E]) CloneFunc(f func(E) E) *Buffer[E] {
+	c := *b
+	c.s = make([]E, len(b.s))
+	for i := range b.start {
+		c.s[i] = f(b.s[i])
+	}
+	for i := b.end; i < len(b.s); i++ {
+		c.s[i] = f(b.s[i])
+	}
+
+	return &c
+}
+
+// MemStats returns statistics about the memory used by buffer b.
+func (b *Buffer[E]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len:   b.Len(),
+		Cap:   cap(b.s),
+		Bytes: memsize.Of[Buffer[E]]() + memsize.Slice[E](cap(b.s)),
+	}
+}
@@ -0,0 +1,170 @@
+package gapbuffer
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container = (*Buffer[int])(nil)
+	_ container.Sizer     = (*Buffer[int])(nil)
+)
+
+func checkBuffer[E comparable](t *testing.T, b *Buffer[E], want []E) {
+	t.Helper()
+
+	if n := b.Len(); len(want) != n {
+		t.Errorf("b.Len() = %d, want %d", n, len(want))
+	}
+
+	if got := b.AppendTo(nil); !slices.Equal(want, got) {
+		t.Errorf("contents = %v, want %v", got, want)
+	}
+
+	for i, v := range want {
+		if x := b.At(i); v != x {
+			t.Errorf("b.At(%d) = %v, want %v", i, x, v)
+		}
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	t.Parallel()
+
+	var b Buffer[byte]
+	checkBuffer(t, &b, nil)
+
+	b.Insert([]byte("hello world")...)
+	checkBuffer(t, &b, []byte("hello world"))
+	if c := b.Cursor(); c != 11 {
+		t.Errorf("b.Cursor() = %d, want %d", c, 11)
+	}
+
+	b.Seek(5)
+	b.Insert([]byte(",")...)
+	checkBuffer(t, &b, []byte("hello, world"))
+
+	if n := b.Delete(1); n != 1 {
+		t.Errorf("b.Delete(1) = %d, want %d", n, 1)
+	}
+	b.Insert([]byte(" big ")...)
+	checkBuffer(t, &b, []byte("hello, big world"))
+
+	if n := b.Backspace(100); n != 11 {
+		t.Errorf("b.Backspace(100) = %d, want %d", n, 11)
+	}
+	checkBuffer(t, &b, []byte("world"))
+
+	b.Seek(5)
+	if n := b.Delete(1); n != 0 {
+		t.Errorf("b.Delete(1) at end = %d, want %d", n, 0)
+	}
+
+	b.Set(0, 'W')
+	before, after := b.Slices()
+	if string(before) != "World" || len(after) != 0 {
+		t.Errorf("b.Slices() = %q, %q, want %q, %q", before, after, "World", "")
+	}
+
+	b.Clear()
+	checkBuffer(t, &b, nil)
+}
+
+func TestWithData(t *testing.T) {
+	t.Parallel()
+
+	b := New(WithData([]int{1, 2, 3}))
+	checkBuffer(t, b, []int{1, 2, 3})
+	if c := b.Cursor(); c != 3 {
+		t.Errorf("b.Cursor() = %d, want %d", c, 3)
+	}
+
+	b.Seek(0)
+	b.Insert(0)
+	checkBuffer(t, b, []int{0, 1, 2, 3})
+}
+
+func TestGrow(t *testing.T) {
+	t.Parallel()
+
+	b := New(WithInitialCap[int](4))
+	b.Insert(1, 2)
+	b.Seek(1)
+	b.Grow(100)
+	if c := b.Cap(); c < 102 {
+		t.Errorf("b.Cap() = %d, want >= %d", c, 102)
+	}
+
+	c := b.Cap()
+	for i := 0; i < 100; i++ {
+		b.Insert(0)
+	}
+	if c != b.Cap() {
+		t.Errorf("Insert after Grow reallocated: cap %d, want %d", b.Cap(), c)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	var b Buffer[int]
+	var want []int
+	for i := 0; i < 2000; i++ {
+		switch c := b.Cursor(); r.Intn(4) {
+		case 0:
+			b.Insert(i)
+			want = slices.Insert(want, c, i)
+		case 1:
+			n := b.Delete(r.Intn(4))
+			want = slices.Delete(want, c, c+n)
+		case 2:
+			n := b.Backspace(r.Intn(4))
+			want = slices.Delete(want, c-n, c)
+		case 3:
+			b.Seek(r.Intn(b.Len() + 1))
+		}
+	}
+	checkBuffer(t, &b, want)
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	b := New(WithData([]rune("held")))
+	b.Seek(2)
+
+	c := b.Clone()
+	if c.Cursor() != 2 {
+		t.Errorf("c.Cursor() = %d, want 2", c.Cursor())
+	}
+	c.Insert('l', 'o', ' ', 'w')
+	checkBuffer(t, c, []rune("helo wld"))
+	checkBuffer(t, b, []rune("held"))
+
+	upper := b.CloneFunc(func(r rune) rune { return r - 'a' + 'A' })
+	checkBuffer(t, upper, []rune("HELD"))
+	if upper.Cursor() != 2 {
+		t.Errorf("upper.Cursor() = %d, want 2", upper.Cursor())
+	}
+}
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	b := New(WithData([]int{5, 2, 8, 1}))
+	b.Seek(2)
+	b.Insert(7, 3)
+	b.Sort(func(a, b int) bool { return a < b })
+
+	if got, want := b.AppendTo(nil), []int{1, 2, 3, 5, 7, 8}; !slices.Equal(got, want) {
+		t.Errorf("b = %v, want %v", got, want)
+	}
+	if c := b.Cursor(); c != 4 {
+		t.Errorf("b.Cursor() = %d, want 4", c)
+	}
+}
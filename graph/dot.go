@@ -0,0 +1,379 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Attrs is a set of DOT attributes, such as label or color.
+// A graph whose node or edge payloads are Attrs is written with them as
+// attribute lists, and ReadDOT returns graphs with Attrs payloads.
+type Attrs map[string]string
+
+// WriteDOT writes graph g to w in the Graphviz DOT language.
+// Nodes and edges are written in the order Nodes and Edges yield them.
+// Payloads of type Attrs are written as attribute lists, payloads of type
+// struct{} are omitted, and other payloads are written as labels
+// formatted with %v.
+func (g *Graph[K, N, E]) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	kind, op := "graph", "--"
+	if g.directed {
+		kind, op = "digraph", "->"
+	}
+
+	fmt.Fprintf(bw, "%s {\n", kind)
+	for u := range g.Nodes() {
+		fmt.Fprintf(bw, "\t%s%s;\n", dotQuote(fmt.Sprint(u)), dotAttrs(g.nodes[u].data))
+	}
+	for e := range g.Edges() {
+		fmt.Fprintf(bw, "\t%s %s %s%s;\n", dotQuote(fmt.Sprint(e.From)), op, dotQuote(fmt.Sprint(e.To)), dotAttrs(e.Value))
+	}
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+func dotAttrs(v any) string {
+	var attrs Attrs
+	switch v := v.(type) {
+	case struct{}:
+		return ""
+	case Attrs:
+		attrs = v
+	default:
+		attrs = Attrs{"label": fmt.Sprint(v)}
+	}
+
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(" [")
+	for i, k := range slices.Sorted(maps.Keys(attrs)) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s=%s", dotQuote(k), dotQuote(attrs[k]))
+	}
+	b.WriteString("]")
+
+	return b.String()
+}
+
+// dotQuote returns s as a DOT identifier, quoted unless it is a plain
+// alphanumeric identifier other than a keyword.
+func dotQuote(s string) string {
+	plain := s != "" && !unicode.IsDigit(rune(s[0])) && strings.IndexFunc(s, func(r rune) bool {
+		return !(r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) < 0
+	switch strings.ToLower(s) {
+	case "node", "edge", "graph", "digraph", "subgraph", "strict":
+		plain = false
+	}
+	if plain {
+		return s
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// ReadDOT parses a graph in the Graphviz DOT language from r.
+// Node identifiers become string keys and attribute lists become Attrs
+// payloads; a node or edge without attributes has a nil payload.
+//
+// ReadDOT supports node and edge statements, including edge chains such
+// as a -> b -> c, and ignores graph attributes and default attribute
+// statements. Subgraphs, ports and HTML strings are not supported.
+func ReadDOT(r io.Reader) (*Graph[string, Attrs, Attrs], error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &dotParser{lex: dotLexer{src: string(src), line: 1}}
+	p.next()
+	g, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("graph: DOT line %d: %w", p.tok.line, err)
+	}
+
+	return g, nil
+}
+
+type dotToken struct {
+	kind  byte // 'i' for identifiers, 0 at EOF, else the punctuation
+	text  string
+	line  int
+	arrow string // "->" or "--" for edge operators
+}
+
+type dotLexer struct {
+	src  string
+	pos  int
+	line int
+}
+
+var errDOTSyntax = errors.New("syntax error")
+
+func (l *dotLexer) skip() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			l.pos++
+		case c == '#' || strings.HasPrefix(l.src[l.pos:], "//"):
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				end = len(l.src) - l.pos - 2
+			}
+			l.line += strings.Count(l.src[l.pos:l.pos+2+end], "\n")
+			l.pos = min(l.pos+2+end+2, len(l.src))
+		default:
+			return
+		}
+	}
+}
+
+func (l *dotLexer) next() (dotToken, error) {
+	l.skip()
+	t := dotToken{line: l.line}
+	if l.pos >= len(l.src) {
+		return t, nil
+	}
+
+	s := l.src[l.pos:]
+	switch c := s[0]; {
+	case strings.HasPrefix(s, "->") || strings.HasPrefix(s, "--"):
+		l.pos += 2
+		t.kind, t.arrow = '-', s[:2]
+	case strings.ContainsRune("{}[]=;,", rune(c)):
+		l.pos++
+		t.kind = c
+	case c == '"':
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				if i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+					i++
+				}
+			case '"':
+				l.pos += i + 1
+				t.kind, t.text = 'i', b.String()
+				return t, nil
+			case '\n':
+				l.line++
+			}
+			b.WriteByte(s[i])
+		}
+		return t, errors.New("unterminated string")
+	default:
+		// identifiers and numerals, which may have a leading minus sign
+		r, size := utf8.DecodeRuneInString(s)
+		if !dotIdent(r) && !(r == '-' && len(s) > 1 && dotIdent(rune(s[1]))) {
+			return t, fmt.Errorf("unexpected %q", r)
+		}
+
+		i := strings.IndexFunc(s[size:], func(r rune) bool { return !dotIdent(r) })
+		if i < 0 {
+			i = len(s) - size
+		}
+		i += size
+
+		l.pos += i
+		t.kind, t.text = 'i', s[:i]
+	}
+
+	return t, nil
+}
+
+func dotIdent(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+type dotParser struct {
+	lex dotLexer
+	tok dotToken
+	err error
+}
+
+func (p *dotParser) next() {
+	if p.err == nil {
+		p.tok, p.err = p.lex.next()
+	}
+}
+
+func (p *dotParser) keyword(kw string) bool {
+	return p.tok.kind == 'i' && strings.EqualFold(p.tok.text, kw)
+}
+
+func (p *dotParser) expect(kind byte) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	if p.tok.kind != kind {
+		if p.tok.kind == 0 {
+			return "", io.ErrUnexpectedEOF
+		}
+		text := p.tok.text
+		switch {
+		case p.tok.arrow != "":
+			text = p.tok.arrow
+		case p.tok.kind != 'i':
+			text = string(p.tok.kind)
+		}
+		return "", fmt.Errorf("%w: unexpected %q", errDOTSyntax, text)
+	}
+
+	text := p.tok.text
+	p.next()
+	return text, nil
+}
+
+func (p *dotParser) parse() (*Graph[string, Attrs, Attrs], error) {
+	if p.keyword("strict") {
+		p.next()
+	}
+
+	var g *Graph[string, Attrs, Attrs]
+	switch {
+	case p.keyword("graph"):
+		g = NewUndirected[string, Attrs, Attrs]()
+	case p.keyword("digraph"):
+		g = NewDirected[string, Attrs, Attrs]()
+	default:
+		return nil, fmt.Errorf("%w: expected graph or digraph", errDOTSyntax)
+	}
+	p.next()
+
+	if p.tok.kind == 'i' {
+		p.next() // graph name
+	}
+	if _, err := p.expect('{'); err != nil {
+		return nil, err
+	}
+
+	for p.err == nil && p.tok.kind != '}' && p.tok.kind != 0 {
+		if err := p.statement(g); err != nil {
+			return nil, err
+		}
+
+		if p.tok.kind == ';' || p.tok.kind == ',' {
+			p.next()
+		}
+	}
+
+	if _, err := p.expect('}'); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != 0 {
+		return nil, fmt.Errorf("%w: content after graph", errDOTSyntax)
+	}
+
+	return g, p.err
+}
+
+func (p *dotParser) statement(g *Graph[string, Attrs, Attrs]) error {
+	if p.keyword("subgraph") || p.tok.kind == '{' {
+		return errors.New("subgraphs are not supported")
+	}
+
+	id, err := p.expect('i')
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case p.tok.kind == '=': // graph attribute
+		p.next()
+		_, err := p.expect('i')
+		return err
+	case p.tok.kind == '[' && (strings.EqualFold(id, "graph") || strings.EqualFold(id, "node") || strings.EqualFold(id, "edge")):
+		_, err := p.attrs()
+		return err
+	}
+
+	nodes := []string{id}
+	for p.tok.kind == '-' {
+		if (p.tok.arrow == "->") != g.directed {
+			return fmt.Errorf("%w: %s in %s", errDOTSyntax, p.tok.arrow, map[bool]string{true: "digraph", false: "graph"}[g.directed])
+		}
+
+		p.next()
+		id, err := p.expect('i')
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, id)
+	}
+
+	var attrs Attrs
+	if p.tok.kind == '[' {
+		if attrs, err = p.attrs(); err != nil {
+			return err
+		}
+	}
+
+	if len(nodes) == 1 {
+		data, _ := g.Node(id)
+		if data == nil && attrs != nil {
+			data = make(Attrs)
+		}
+		maps.Copy(data, attrs)
+		g.SetNode(id, data)
+		return nil
+	}
+
+	for i := 1; i < len(nodes); i++ {
+		g.AddEdge(nodes[i-1], nodes[i], maps.Clone(attrs))
+	}
+
+	return nil
+}
+
+func (p *dotParser) attrs() (Attrs, error) {
+	attrs := make(Attrs)
+	for p.tok.kind == '[' {
+		p.next()
+		for p.err == nil && p.tok.kind != ']' {
+			k, err := p.expect('i')
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect('='); err != nil {
+				return nil, err
+			}
+			v, err := p.expect('i')
+			if err != nil {
+				return nil, err
+			}
+			attrs[k] = v
+
+			if p.tok.kind == ';' || p.tok.kind == ',' {
+				p.next()
+			}
+		}
+
+		if _, err := p.expect(']'); err != nil {
+			return nil, err
+		}
+	}
+
+	return attrs, p.err
+}
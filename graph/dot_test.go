@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	t.Parallel()
+
+	g := NewDirected[string, struct{}, int]()
+	g.AddEdge("a", "b", 3)
+	g.AddEdge("b", `say "hi"`, 4)
+	g.AddNode("c")
+	g.AddEdge("c", "node", 5)
+
+	var b strings.Builder
+	if err := g.WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `digraph {
+	a;
+	b;
+	"say \"hi\"";
+	c;
+	"node";
+	a -> b [label="3"];
+	b -> "say \"hi\"" [label="4"];
+	c -> "node" [label="5"];
+}
+`
+	if b.String() != want {
+		t.Errorf("WriteDOT =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestDOTRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	g := NewUndirected[string, Attrs, Attrs]()
+	g.SetNode("x", Attrs{"color": "red", "label": `back\slash`})
+	g.AddEdge("x", "y", Attrs{"weight": "2"})
+	g.AddEdge("y", "z", nil)
+	g.AddEdge("z", "z", Attrs{})
+
+	var b strings.Builder
+	if err := g.WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := ReadDOT(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("ReadDOT(%s): %v", b.String(), err)
+	}
+
+	if h.Directed() || h.Order() != g.Order() || h.Size() != g.Size() {
+		t.Fatalf("round trip of\n%s\ngave directed %t, order %d, size %d", b.String(), h.Directed(), h.Order(), h.Size())
+	}
+
+	for u := range g.Nodes() {
+		want, _ := g.Node(u)
+		if got, _ := h.Node(u); !maps.Equal(want, got) {
+			t.Errorf("node %s = %v, want %v", u, got, want)
+		}
+	}
+	for e := range g.Edges() {
+		if got, ok := h.Edge(e.From, e.To); !ok || !maps.Equal(e.Value, got) {
+			t.Errorf("edge %s -- %s = %v, %t, want %v", e.From, e.To, got, ok, e.Value)
+		}
+	}
+}
+
+func TestReadDOT(t *testing.T) {
+	t.Parallel()
+
+	const src = `/* build graph */
+strict digraph deps {
+	rankdir = LR
+	node [shape=box]
+	# comment
+	main -> util -> "fmt" [style=dashed; color=blue] // chain
+	main -> -1.5
+	util [label="Util
+package"]
+}`
+
+	g, err := ReadDOT(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !g.Directed() || g.Order() != 4 || g.Size() != 3 {
+		t.Fatalf("directed %t, order %d, size %d, want true, 4, 3", g.Directed(), g.Order(), g.Size())
+	}
+
+	if a, _ := g.Edge("util", "fmt"); a["style"] != "dashed" || a["color"] != "blue" {
+		t.Errorf("edge util -> fmt = %v", a)
+	}
+	if a, _ := g.Node("util"); a["label"] != "Util\npackage" {
+		t.Errorf("node util = %v", a)
+	}
+	if !g.HasEdge("main", "-1.5") {
+		t.Errorf("missing edge main -> -1.5")
+	}
+
+	for _, bad := range []string{
+		"",
+		"digraph { a -- b }",
+		"graph { a -> b }",
+		"digraph { a -> }",
+		`digraph { "a }`,
+		"digraph { subgraph s { a } }",
+		"digraph { a [color] }",
+		"digraph { a } b",
+		"digraph { a $ b }",
+	} {
+		if _, err := ReadDOT(strings.NewReader(bad)); err == nil {
+			t.Errorf("ReadDOT(%q) succeeded", bad)
+		}
+	}
+}
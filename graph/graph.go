@@ -0,0 +1,456 @@
+// Package graph implements directed and undirected graphs stored as
+// adjacency lists.
+//
+// Nodes are identified by values of a comparable type. Each node carries a
+// payload of type N and each edge a payload of type E, typically a weight;
+// use struct{} for either when no data is needed. Nodes and the neighbors
+// of each node are kept in insertion order, so iteration over a graph is
+// deterministic.
+//
+// To iterate over the neighbors of a node (where g is a *Graph):
+//
+//	for v, e := range g.Neighbors(u) {
+//		// do something with the edge u -> v and its payload e
+//	}
+package graph
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/weiwenchen2022/container/internal/mem"
+)
+
+// Edge is an edge from node From to node To carrying payload Value.
+type Edge[K comparable, E any] struct {
+	From, To K
+	Value    E
+}
+
+// adjacency is an insertion-ordered set of neighbors with edge payloads.
+type adjacency[K comparable, E any] struct {
+	order []K
+	edge  map[K]E
+}
+
+func (a *adjacency[K, E]) set(v K, e E) (added bool) {
+	if a.edge == nil {
+		a.edge = make(map[K]E)
+	}
+
+	if _, ok := a.edge[v]; !ok {
+		a.order = append(a.order, v)
+		added = true
+	}
+
+	a.edge[v] = e
+	return added
+}
+
+func (a *adjacency[K, E]) remove(v K) bool {
+	if _, ok := a.edge[v]; !ok {
+		return false
+	}
+
+	delete(a.edge, v)
+	for i, x := range a.order {
+		if x == v {
+			a.order = append(a.order[:i], a.order[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+func (a *adjacency[K, E]) all(yield func(K, E) bool) {
+	for _, v := range a.order {
+		if !yield(v, a.edge[v]) {
+			return
+		}
+	}
+}
+
+type vertex[K comparable, N, E any] struct {
+	data N
+	out  adjacency[K, E]
+	in   adjacency[K, E] // only used by directed graphs
+}
+
+// Graph is a directed or undirected graph.
+// To create a graph use graph.NewDirected or graph.NewUndirected.
+type Graph[K comparable, N, E any] struct {
+	directed bool
+
+	order []K
+	nodes map[K]*vertex[K, N, E]
+	size  int
+
+	arena *mem.Arena[vertex[K, N, E]]
+}
+
+type option[K comparable, N, E any] func(*Graph[K, N, E])
+
+// WithArena makes the graph allocate its nodes in chunks of chunkSize,
+// which makes building a large graph cheaper. The memory of removed nodes
+// is only reclaimed by Clear, so this suits graphs that are built, used and
+// discarded or cleared as a whole.
+// It panics if chunkSize < 1.
+func WithArena[K comparable, N, E any](chunkSize int) option[K, N, E] {
+	return func(g *Graph[K, N, E]) {
+		g.arena = mem.NewArena[vertex[K, N, E]](chunkSize)
+	}
+}
+
+func newGraph[K comparable, N, E any](directed bool, opts []option[K, N, E]) *Graph[K, N, E] {
+	g := &Graph[K, N, E]{directed: directed, nodes: make(map[K]*vertex[K, N, E])}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// NewDirected returns an empty directed graph.
+func NewDirected[K comparable, N, E any](opts ...option[K, N, E]) *Graph[K, N, E] {
+	return newGraph(true, opts)
+}
+
+// NewUndirected returns an empty undirected graph.
+func NewUndirected[K comparable, N, E any](opts ...option[K, N, E]) *Graph[K, N, E] {
+	return newGraph(false, opts)
+}
+
+// Directed reports whether graph g is directed.
+func (g *Graph[K, N, E]) Directed() bool { return g.directed }
+
+// Order returns the number of nodes of graph g.
+func (g *Graph[K, N, E]) Order() int { return len(g.order) }
+
+// Size returns the number of edges of graph g.
+func (g *Graph[K, N, E]) Size() int { return g.size }
+
+// Clear removes all nodes and edges from graph g.
+func (g *Graph[K, N, E]) Clear() {
+	clear(g.order)
+	g.order = g.order[:0]
+	clear(g.nodes)
+	g.size = 0
+	g.arena.Reset()
+}
+
+// Clone returns a copy of graph g with the same iteration order.
+// The payloads are copied by assignment, so this is a shallow clone.
+func (g *Graph[K, N, E]) Clone() *Graph[K, N, E] {
+	return g.CloneFunc(func(n N) N { return n }, func(e E) E { return e })
+}
+
+// CloneFunc is like Clone but copies each node payload with node and each
+// edge payload with edge, which allows a deep clone.
+// In an undirected graph edge is called once per edge.
+func (g *Graph[K, N, E]) CloneFunc(node func(N) N, edge func(E) E) *Graph[K, N, E] {
+	c := &Graph[K, N, E]{
+		directed: g.directed,
+		order:    slices.Clone(g.order),
+		nodes:    make(map[K]*vertex[K, N, E], len(g.nodes)),
+		size:     g.size,
+		arena:    g.arena.Empty(),
+	}
+
+	type pair struct{ u, v K }
+	edges := make(map[pair]E, g.size)
+	copyEdge := func(u, v K, e E) E {
+		if !g.directed {
+			if ce, ok := edges[pair{v, u}]; ok {
+				return ce
+			}
+		}
+
+		ce := edge(e)
+		edges[pair{u, v}] = ce
+		return ce
+	}
+
+	for _, u := range g.order {
+		cu := c.arena.New()
+		cu.data = node(g.nodes[u].data)
+		c.nodes[u] = cu
+	}
+	for _, u := range g.order {
+		vu, cu := g.nodes[u], c.nodes[u]
+		for _, v := range vu.out.order {
+			cu.out.set(v, copyEdge(u, v, vu.out.edge[v]))
+		}
+	}
+	if g.directed {
+		for _, u := range g.order {
+			for _, v := range g.nodes[u].in.order {
+				c.nodes[u].in.set(v, edges[pair{v, u}])
+			}
+		}
+	}
+
+	return c
+}
+
+// AddNode adds node u with a zero payload to graph g and reports whether
+// it was not already present.
+func (g *Graph[K, N, E]) AddNode(u K) bool {
+	if _, ok := g.nodes[u]; ok {
+		return false
+	}
+
+	g.nodes[u] = g.arena.New()
+	g.order = append(g.order, u)
+	return true
+}
+
+// SetNode sets the payload of node u to data, adding the node if
+// necessary, and reports whether it was not already present.
+func (g *Graph[K, N, E]) SetNode(u K, data N) bool {
+	added := g.AddNode(u)
+	g.nodes[u].data = data
+	return added
+}
+
+// Node returns the payload of node u.
+// The ok result reports whether the node is in graph g.
+func (g *Graph[K, N, E]) Node(u K) (data N, ok bool) {
+	if vu, ok := g.nodes[u]; ok {
+		return vu.data, true
+	}
+
+	return data, false
+}
+
+// HasNode reports whether node u is in graph g.
+func (g *Graph[K, N, E]) HasNode(u K) bool {
+	_, ok := g.nodes[u]
+	return ok
+}
+
+// RemoveNode removes node u and its edges from graph g and reports whether it was present.
+// The complexity is O(n + d) where d is the degree of u.
+func (g *Graph[K, N, E]) RemoveNode(u K) bool {
+	vu, ok := g.nodes[u]
+	if !ok {
+		return false
+	}
+
+	for _, v := range append([]K(nil), vu.out.order...) {
+		g.RemoveEdge(u, v)
+	}
+	for _, v := range append([]K(nil), vu.in.order...) {
+		g.RemoveEdge(v, u)
+	}
+
+	delete(g.nodes, u)
+	for i, x := range g.order {
+		if x == u {
+			g.order = append(g.order[:i], g.order[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// AddEdge adds an edge from node u to node v with payload e, adding the
+// nodes if necessary. If the edge already exists its payload is replaced.
+// In an undirected graph the edge also connects v to u.
+func (g *Graph[K, N, E]) AddEdge(u, v K, e E) {
+	g.AddNode(u)
+	g.AddNode(v)
+
+	if g.nodes[u].out.set(v, e) {
+		g.size++
+	}
+
+	if g.directed {
+		g.nodes[v].in.set(u, e)
+	} else {
+		g.nodes[v].out.set(u, e)
+	}
+}
+
+// RemoveEdge removes the edge from node u to node v and reports whether it was present.
+func (g *Graph[K, N, E]) RemoveEdge(u, v K) bool {
+	vu, ok := g.nodes[u]
+	if !ok || !vu.out.remove(v) {
+		return false
+	}
+
+	g.size--
+	if g.directed {
+		g.nodes[v].in.remove(u)
+	} else {
+		g.nodes[v].out.remove(u)
+	}
+
+	return true
+}
+
+// HasEdge reports whether graph g has an edge from node u to node v.
+func (g *Graph[K, N, E]) HasEdge(u, v K) bool {
+	_, ok := g.Edge(u, v)
+	return ok
+}
+
+// Edge returns the payload of the edge from node u to node v.
+// The ok result reports whether the edge exists.
+func (g *Graph[K, N, E]) Edge(u, v K) (e E, ok bool) {
+	if vu, ok := g.nodes[u]; ok {
+		e, ok = vu.out.edge[v]
+		return e, ok
+	}
+
+	return e, false
+}
+
+// OutDegree returns the number of edges leaving node u.
+// In an undirected graph it is the degree of u.
+func (g *Graph[K, N, E]) OutDegree(u K) int {
+	if vu, ok := g.nodes[u]; ok {
+		return len(vu.out.order)
+	}
+
+	return 0
+}
+
+// InDegree returns the number of edges entering node u.
+// In an undirected graph it is the degree of u.
+func (g *Graph[K, N, E]) InDegree(u K) int {
+	if !g.directed {
+		return g.OutDegree(u)
+	}
+
+	if vu, ok := g.nodes[u]; ok {
+		return len(vu.in.order)
+	}
+
+	return 0
+}
+
+// Nodes returns an iterator over the nodes of graph g in insertion order.
+func (g *Graph[K, N, E]) Nodes() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for _, u := range g.order {
+			if !yield(u) {
+				return
+			}
+		}
+	}
+}
+
+// Edges returns an iterator over the edges of graph g.
+// Each edge of an undirected graph is yielded once.
+func (g *Graph[K, N, E]) Edges() iter.Seq[Edge[K, E]] {
+	return func(yield func(Edge[K, E]) bool) {
+		var seen map[K]bool
+		if !g.directed {
+			seen = make(map[K]bool)
+		}
+
+		for _, u := range g.order {
+			for _, v := range g.nodes[u].out.order {
+				if seen[v] {
+					continue
+				}
+
+				if !yield(Edge[K, E]{u, v, g.nodes[u].out.edge[v]}) {
+					return
+				}
+			}
+
+			if seen != nil {
+				seen[u] = true
+			}
+		}
+	}
+}
+
+// Neighbors returns an iterator over the successors of node u and the
+// payloads of the edges to them.
+// In an undirected graph these are all nodes adjacent to u.
+func (g *Graph[K, N, E]) Neighbors(u K) iter.Seq2[K, E] {
+	return func(yield func(K, E) bool) {
+		if vu, ok := g.nodes[u]; ok {
+			vu.out.all(yield)
+		}
+	}
+}
+
+// Predecessors returns an iterator over the nodes with an edge to node u
+// and the payloads of those edges.
+// In an undirected graph these are all nodes adjacent to u.
+func (g *Graph[K, N, E]) Predecessors(u K) iter.Seq2[K, E] {
+	if !g.directed {
+		return g.Neighbors(u)
+	}
+
+	return func(yield func(K, E) bool) {
+		if vu, ok := g.nodes[u]; ok {
+			vu.in.all(yield)
+		}
+	}
+}
+
+// BFS returns an iterator over the nodes reachable from node start in
+// breadth-first order.
+func (g *Graph[K, N, E]) BFS(start K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if !g.HasNode(start) {
+			return
+		}
+
+		visited := map[K]bool{start: true}
+		queue := []K{start}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			if !yield(u) {
+				return
+			}
+
+			for _, v := range g.nodes[u].out.order {
+				if !visited[v] {
+					visited[v] = true
+					queue = append(queue, v)
+				}
+			}
+		}
+	}
+}
+
+// DFS returns an iterator over the nodes reachable from node start in
+// depth-first preorder.
+func (g *Graph[K, N, E]) DFS(start K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if !g.HasNode(start) {
+			return
+		}
+
+		visited := make(map[K]bool)
+		stack := []K{start}
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if visited[u] {
+				continue
+			}
+
+			visited[u] = true
+			if !yield(u) {
+				return
+			}
+
+			// push in reverse so neighbors are visited in insertion order
+			out := g.nodes[u].out.order
+			for i := len(out) - 1; i >= 0; i-- {
+				if !visited[out[i]] {
+					stack = append(stack, out[i])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+func neighbors[K comparable, N, E any](g *Graph[K, N, E], u K) []K {
+	var vs []K
+	for v := range g.Neighbors(u) {
+		vs = append(vs, v)
+	}
+
+	return vs
+}
+
+func TestDirected(t *testing.T) {
+	t.Parallel()
+
+	g := NewDirected[string, struct{}, int]()
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 2)
+	g.AddEdge("c", "b", 3)
+	g.AddNode("d")
+
+	if g.Order() != 4 || g.Size() != 3 {
+		t.Fatalf("order, size = %d, %d, want 4, 3", g.Order(), g.Size())
+	}
+
+	if want, got := []string{"a", "b", "c", "d"}, slices.Collect(g.Nodes()); !slices.Equal(want, got) {
+		t.Errorf("nodes = %v, want %v", got, want)
+	}
+
+	if !g.HasEdge("a", "b") || g.HasEdge("b", "a") {
+		t.Errorf("HasEdge does not respect direction")
+	}
+
+	if w, ok := g.Edge("c", "b"); !ok || w != 3 {
+		t.Errorf("g.Edge(c, b) = %d, %t, want 3, true", w, ok)
+	}
+
+	if want, got := []string{"b", "c"}, neighbors(g, "a"); !slices.Equal(want, got) {
+		t.Errorf("neighbors(a) = %v, want %v", got, want)
+	}
+
+	var preds []string
+	for u := range g.Predecessors("b") {
+		preds = append(preds, u)
+	}
+	if want := []string{"a", "c"}; !slices.Equal(want, preds) {
+		t.Errorf("predecessors(b) = %v, want %v", preds, want)
+	}
+
+	if g.InDegree("b") != 2 || g.OutDegree("b") != 0 {
+		t.Errorf("degrees of b = %d, %d, want 2, 0", g.InDegree("b"), g.OutDegree("b"))
+	}
+
+	g.AddEdge("a", "b", 5)
+	if w, _ := g.Edge("a", "b"); w != 5 || g.Size() != 3 {
+		t.Errorf("replacing an edge: weight %d, size %d, want 5, 3", w, g.Size())
+	}
+
+	if !g.RemoveNode("c") || g.RemoveNode("c") {
+		t.Fatalf("RemoveNode(c) did not report presence correctly")
+	}
+	if g.Size() != 1 || g.InDegree("b") != 1 {
+		t.Errorf("after RemoveNode: size %d, indegree(b) %d, want 1, 1", g.Size(), g.InDegree("b"))
+	}
+}
+
+func TestUndirected(t *testing.T) {
+	t.Parallel()
+
+	g := NewUndirected[int, struct{}, float64]()
+	g.AddEdge(1, 2, 0.5)
+	g.AddEdge(2, 3, 1.5)
+	g.AddEdge(3, 3, 2)
+
+	if g.Size() != 3 {
+		t.Errorf("size = %d, want 3", g.Size())
+	}
+
+	if !g.HasEdge(2, 1) {
+		t.Errorf("undirected edge is not symmetric")
+	}
+
+	var edges []Edge[int, float64]
+	for e := range g.Edges() {
+		edges = append(edges, e)
+	}
+	want := []Edge[int, float64]{{1, 2, 0.5}, {2, 3, 1.5}, {3, 3, 2}}
+	if !slices.Equal(want, edges) {
+		t.Errorf("edges = %v, want %v", edges, want)
+	}
+
+	if !g.RemoveEdge(2, 1) || g.HasEdge(1, 2) || g.Size() != 2 {
+		t.Errorf("RemoveEdge(2, 1) did not remove both directions")
+	}
+}
+
+func TestTraversal(t *testing.T) {
+	t.Parallel()
+
+	//   1 -> 2 -> 4
+	//   |         ^
+	//   v         |
+	//   3 --------+
+	//   5 (unreachable)
+	g := NewDirected[int, struct{}, struct{}]()
+	for _, e := range [][2]int{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {4, 1}} {
+		g.AddEdge(e[0], e[1], struct{}{})
+	}
+	g.AddNode(5)
+
+	if want, got := []int{1, 2, 3, 4}, slices.Collect(g.BFS(1)); !slices.Equal(want, got) {
+		t.Errorf("BFS = %v, want %v", got, want)
+	}
+
+	if want, got := []int{1, 2, 4, 3}, slices.Collect(g.DFS(1)); !slices.Equal(want, got) {
+		t.Errorf("DFS = %v, want %v", got, want)
+	}
+
+	if got := slices.Collect(g.BFS(6)); len(got) != 0 {
+		t.Errorf("BFS from a missing node = %v, want none", got)
+	}
+
+	for v := range g.DFS(1) {
+		if v != 1 {
+			t.Fatalf("DFS did not stop")
+		}
+		break
+	}
+}
+
+func TestPayloads(t *testing.T) {
+	t.Parallel()
+
+	type city struct{ population int }
+	type road struct {
+		name string
+		km   float64
+	}
+
+	g := NewUndirected[string, city, road]()
+	if !g.SetNode("paris", city{2100000}) || g.SetNode("paris", city{2200000}) {
+		t.Errorf("SetNode did not report additions correctly")
+	}
+	g.AddEdge("paris", "lyon", road{"A6", 465})
+
+	if c, ok := g.Node("paris"); !ok || c.population != 2200000 {
+		t.Errorf("g.Node(paris) = %v, %t", c, ok)
+	}
+	if c, ok := g.Node("lyon"); !ok || c != (city{}) {
+		t.Errorf("g.Node(lyon) = %v, %t, want zero payload, true", c, ok)
+	}
+	if _, ok := g.Node("nice"); ok {
+		t.Errorf("g.Node(nice) found a missing node")
+	}
+
+	if r, ok := g.Edge("lyon", "paris"); !ok || r.name != "A6" {
+		t.Errorf("g.Edge(lyon, paris) = %v, %t", r, ok)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	for _, g := range []*Graph[int, string, []int]{NewDirected[int, string, []int](), NewUndirected[int, string, []int]()} {
+		g.SetNode(1, "one")
+		g.AddEdge(1, 2, []int{12})
+		g.AddEdge(3, 1, []int{31})
+		g.AddEdge(2, 2, []int{22})
+
+		c := g.Clone()
+		if !slices.Equal(slices.Collect(c.Nodes()), slices.Collect(g.Nodes())) ||
+			!slices.EqualFunc(slices.Collect(c.Edges()), slices.Collect(g.Edges()), func(a, b Edge[int, []int]) bool {
+				return a.From == b.From && a.To == b.To && slices.Equal(a.Value, b.Value)
+			}) {
+			t.Errorf("directed %t: clone differs from the graph", g.Directed())
+		}
+		keys := func(seq iter.Seq2[int, []int]) (ks []int) {
+			for k := range seq {
+				ks = append(ks, k)
+			}
+			return ks
+		}
+		for _, u := range []int{1, 2, 3} {
+			if want, got := keys(g.Predecessors(u)), keys(c.Predecessors(u)); !slices.Equal(want, got) {
+				t.Errorf("directed %t: clone predecessors of %d = %v, want %v", g.Directed(), u, got, want)
+			}
+		}
+
+		c.RemoveNode(1)
+		if g.Order() != 3 || g.Size() != 3 || g.InDegree(2) != 2 {
+			t.Errorf("directed %t: changing the clone changed the graph", g.Directed())
+		}
+
+		calls := 0
+		d := g.CloneFunc(func(s string) string { return s + "!" }, func(e []int) []int {
+			calls++
+			return slices.Clone(e)
+		})
+		if calls != g.Size() {
+			t.Errorf("directed %t: edge copy called %d times, want %d", g.Directed(), calls, g.Size())
+		}
+		if n, _ := d.Node(1); n != "one!" {
+			t.Errorf("directed %t: d.Node(1) = %q, want %q", g.Directed(), n, "one!")
+		}
+
+		e, _ := d.Edge(1, 2)
+		e[0] = 0
+		if e, _ := g.Edge(1, 2); e[0] != 12 {
+			t.Errorf("directed %t: changing a copied payload changed the graph", g.Directed())
+		}
+		if !g.Directed() {
+			if e, _ := d.Edge(2, 1); e[0] != 0 {
+				t.Errorf("undirected edge payload is not shared between directions in the clone")
+			}
+		}
+	}
+}
+
+func TestArena(t *testing.T) {
+	t.Parallel()
+
+	g := NewDirected(WithArena[int, string, int](8))
+	for i := range 20 {
+		g.AddEdge(i, i+1, i)
+	}
+	g.SetNode(0, "zero")
+	if g.Order() != 21 || g.Size() != 20 || g.arena.Chunks() != 3 {
+		t.Errorf("g.Order(), g.Size(), chunks = %d, %d, %d, want 21, 20, 3", g.Order(), g.Size(), g.arena.Chunks())
+	}
+
+	c := g.Clone()
+	if n, _ := c.Node(0); n != "zero" || c.arena == g.arena || c.arena.Chunks() != 3 {
+		t.Errorf("clone does not use its own arena")
+	}
+
+	g.Clear()
+	if g.Order() != 0 || g.Size() != 0 || g.HasNode(0) || slices.Collect(g.Edges()) != nil {
+		t.Errorf("graph not empty after Clear")
+	}
+	for i := range 20 {
+		g.AddEdge(i, i+1, i)
+	}
+	if n, ok := g.Node(0); !ok || n != "" || g.arena.Chunks() != 3 {
+		t.Errorf("g.Node(0) = %q, %t, chunks = %d after reuse, want \"\", true, 3", n, ok, g.arena.Chunks())
+	}
+	if c.Order() != 21 || c.Size() != 20 {
+		t.Errorf("clearing the graph changed the clone")
+	}
+}
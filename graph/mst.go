@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/unionfind"
+)
+
+// Kruskal returns the edges of a minimum spanning forest of undirected
+// graph g and their total weight, using Kruskal's algorithm.
+// The edges are in order of increasing weight.
+// It panics if g is directed.
+// The complexity is O(m log m).
+func Kruskal[K comparable, N any, W Weight](g *Graph[K, N, W]) (edges []Edge[K, W], total W) {
+	if g.directed {
+		panic("graph: spanning tree of a directed graph")
+	}
+
+	all := slices.Collect(g.Edges())
+	slices.SortStableFunc(all, func(a, b Edge[K, W]) int { return cmp.Compare(a.Value, b.Value) })
+
+	sets := unionfind.New[K]()
+	for _, e := range all {
+		if sets.Union(e.From, e.To) {
+			edges = append(edges, e)
+			total += e.Value
+		}
+	}
+
+	return edges, total
+}
+
+// Prim returns the edges of a minimum spanning forest of undirected graph g
+// and their total weight, using Prim's algorithm.
+// The edges are in the order they join the forest, each from a node
+// already in its tree to the node it adds.
+// It panics if g is directed.
+// The complexity is O(m log n).
+func Prim[K comparable, N any, W Weight](g *Graph[K, N, W]) (edges []Edge[K, W], total W) {
+	if g.directed {
+		panic("graph: spanning tree of a directed graph")
+	}
+
+	pq := heap.New(func(a, b *pqItem[K, W]) bool { return a.priority < b.priority },
+		heap.WithSetIndex(func(it *pqItem[K, W], i int) { it.index = i }))
+	open := make(map[K]*pqItem[K, W])
+	parent := make(map[K]K)
+	inTree := make(map[K]bool, len(g.order))
+
+	for _, root := range g.order {
+		if inTree[root] {
+			continue
+		}
+
+		pq.Push(&pqItem[K, W]{node: root})
+		for pq.Len() > 0 {
+			it := pq.Pop()
+			u := it.node
+			inTree[u] = true
+			if u != root {
+				edges = append(edges, Edge[K, W]{parent[u], u, it.priority})
+				total += it.priority
+			}
+			delete(open, u)
+
+			for v, w := range g.nodes[u].out.all {
+				if inTree[v] {
+					continue
+				}
+
+				if o, ok := open[v]; ok {
+					if w < o.priority {
+						o.priority = w
+						parent[v] = u
+						pq.Fix(o.index)
+					}
+
+					continue
+				}
+
+				o := &pqItem[K, W]{node: v, priority: w}
+				open[v] = o
+				parent[v] = u
+				pq.Push(o)
+			}
+		}
+	}
+
+	return edges, total
+}
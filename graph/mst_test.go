@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestMinimumSpanningTree(t *testing.T) {
+	t.Parallel()
+
+	g := NewUndirected[string, struct{}, int]()
+	for _, e := range []Edge[string, int]{
+		{"a", "b", 4}, {"a", "h", 8}, {"b", "c", 8}, {"b", "h", 11},
+		{"c", "d", 7}, {"c", "f", 4}, {"c", "i", 2}, {"d", "e", 9},
+		{"d", "f", 14}, {"e", "f", 10}, {"f", "g", 2}, {"g", "h", 1},
+		{"g", "i", 6}, {"h", "i", 7},
+		{"x", "y", 3}, // second tree
+	} {
+		g.AddEdge(e.From, e.To, e.Value)
+	}
+
+	for name, mst := range map[string]func(*Graph[string, struct{}, int]) ([]Edge[string, int], int){
+		"Kruskal": Kruskal[string, struct{}, int],
+		"Prim":    Prim[string, struct{}, int],
+	} {
+		edges, total := mst(g)
+		if total != 37+3 || len(edges) != g.Order()-2 {
+			t.Errorf("%s: total %d with %d edges, want 40 with %d", name, total, len(edges), g.Order()-2)
+		}
+
+		sum := 0
+		for _, e := range edges {
+			if w, ok := g.Edge(e.From, e.To); !ok || w != e.Value {
+				t.Errorf("%s: edge %v not in graph", name, e)
+			}
+			sum += e.Value
+		}
+		if sum != total {
+			t.Errorf("%s: edge weights sum to %d, total %d", name, sum, total)
+		}
+	}
+}
+
+func TestMinimumSpanningTreeRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 20 {
+		g := NewUndirected[int, struct{}, float64]()
+		n := 1 + r.IntN(50)
+		for i := range n {
+			g.AddNode(i)
+		}
+		for range r.IntN(n * 4) {
+			g.AddEdge(r.IntN(n), r.IntN(n), r.Float64())
+		}
+
+		ke, kt := Kruskal(g)
+		pe, pt := Prim(g)
+		if len(ke) != len(pe) || kt-pt > 1e-9 || pt-kt > 1e-9 {
+			t.Fatalf("Kruskal = %d edges %v, Prim = %d edges %v", len(ke), kt, len(pe), pt)
+		}
+	}
+}
+
+func TestSpanningTreeDirected(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Kruskal did not panic on a directed graph")
+		}
+	}()
+	Kruskal(NewDirected[int, struct{}, int]())
+}
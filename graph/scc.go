@@ -0,0 +1,107 @@
+package graph
+
+import "slices"
+
+// StronglyConnectedComponents returns the strongly connected components of
+// graph g using Tarjan's algorithm. The components are in topological order:
+// no edge leads from a component to an earlier one. The nodes of each
+// component are in the order Tarjan's algorithm discovers them.
+// In an undirected graph the components are the connected components.
+// The complexity is O(n + m).
+func StronglyConnectedComponents[K comparable, N, E any](g *Graph[K, N, E]) [][]K {
+	type frame struct {
+		node K
+		next int // index of the next neighbor to visit
+	}
+
+	index := make(map[K]int, len(g.order))
+	low := make(map[K]int, len(g.order))
+	onStack := make(map[K]bool)
+	var stack []K
+	var comps [][]K
+
+	for _, root := range g.order {
+		if _, ok := index[root]; ok {
+			continue
+		}
+
+		call := []frame{{node: root}}
+		index[root], low[root] = len(index), len(index)
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(call) > 0 {
+			f := &call[len(call)-1]
+			out := g.nodes[f.node].out.order
+			if f.next < len(out) {
+				v := out[f.next]
+				f.next++
+				if _, ok := index[v]; !ok {
+					index[v], low[v] = len(index), len(index)
+					stack = append(stack, v)
+					onStack[v] = true
+					call = append(call, frame{node: v})
+				} else if onStack[v] {
+					low[f.node] = min(low[f.node], index[v])
+				}
+
+				continue
+			}
+
+			u := f.node
+			call = call[:len(call)-1]
+			if len(call) > 0 {
+				p := call[len(call)-1].node
+				low[p] = min(low[p], low[u])
+			}
+
+			if low[u] == index[u] {
+				i := len(stack) - 1
+				for stack[i] != u {
+					i--
+				}
+
+				comp := slices.Clone(stack[i:])
+				for _, v := range comp {
+					onStack[v] = false
+				}
+				stack = stack[:i]
+				comps = append(comps, comp)
+			}
+		}
+	}
+
+	// Tarjan's algorithm emits components in reverse topological order.
+	slices.Reverse(comps)
+	return comps
+}
+
+// Condensation returns the condensation of graph g: the directed acyclic
+// graph with a node for each strongly connected component of g and an edge
+// between two components if g has an edge between their nodes.
+// Node i of dag has payload comps[i], as returned by
+// StronglyConnectedComponents, so the nodes are in topological order;
+// the payload of each edge is the number of edges of g it stands for.
+func Condensation[K comparable, N, E any](g *Graph[K, N, E]) (dag *Graph[int, []K, int], comps [][]K) {
+	comps = StronglyConnectedComponents(g)
+	of := make(map[K]int, len(g.order))
+	dag = NewDirected[int, []K, int]()
+	for i, comp := range comps {
+		dag.SetNode(i, comp)
+		for _, u := range comp {
+			of[u] = i
+		}
+	}
+
+	for e := range g.Edges() {
+		i, j := of[e.From], of[e.To]
+		if i == j {
+			continue
+		}
+
+		n, _ := dag.Edge(i, j)
+		dag.AddEdge(i, j, n+1)
+	}
+
+	return dag, comps
+}
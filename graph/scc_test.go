@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	t.Parallel()
+
+	g := NewDirected[int, struct{}, struct{}]()
+	for _, e := range [][2]int{
+		{1, 2}, {2, 3}, {3, 1}, // {1, 2, 3}
+		{3, 4}, {4, 5}, {5, 4}, // {4, 5}
+		{5, 6}, // {6}
+		{2, 6},
+		{7, 7}, // {7} with a self-loop
+	} {
+		g.AddEdge(e[0], e[1], struct{}{})
+	}
+
+	comps := StronglyConnectedComponents(g)
+	if len(comps) != 4 {
+		t.Fatalf("components = %v, want 4", comps)
+	}
+
+	of := make(map[int]int)
+	for i, comp := range comps {
+		for _, u := range comp {
+			of[u] = i
+		}
+	}
+
+	if of[1] != of[2] || of[2] != of[3] || of[4] != of[5] || of[1] == of[4] || of[4] == of[6] {
+		t.Errorf("components = %v", comps)
+	}
+
+	// topological order
+	for e := range g.Edges() {
+		if of[e.From] > of[e.To] {
+			t.Errorf("edge %d -> %d goes backward in %v", e.From, e.To, comps)
+		}
+	}
+
+	dag, dcomps := Condensation(g)
+	if !slices.EqualFunc(comps, dcomps, slices.Equal) {
+		t.Errorf("Condensation components = %v, want %v", dcomps, comps)
+	}
+
+	if comp, _ := dag.Node(of[4]); !slices.Equal(comp, comps[of[4]]) {
+		t.Errorf("dag node payload = %v, want %v", comp, comps[of[4]])
+	}
+
+	if dag.Order() != 4 || dag.Size() != 3 {
+		t.Errorf("dag order, size = %d, %d, want 4, 3", dag.Order(), dag.Size())
+	}
+
+	if n, ok := dag.Edge(of[1], of[6]); !ok || n != 1 {
+		t.Errorf("dag weight {1,2,3} -> {6} = %d, %t, want 1, true", n, ok)
+	}
+	if n, ok := dag.Edge(of[1], of[4]); !ok || n != 1 {
+		t.Errorf("dag weight {1,2,3} -> {4,5} = %d, %t, want 1, true", n, ok)
+	}
+	if got := len(StronglyConnectedComponents(dag)); got != dag.Order() {
+		t.Errorf("condensation has a cycle")
+	}
+}
+
+func TestStronglyConnectedComponentsDeep(t *testing.T) {
+	t.Parallel()
+
+	// a long cycle must not overflow the stack
+	const n = 100000
+	g := NewDirected[int, struct{}, struct{}]()
+	for i := range n {
+		g.AddEdge(i, (i+1)%n, struct{}{})
+	}
+
+	if comps := StronglyConnectedComponents(g); len(comps) != 1 || len(comps[0]) != n {
+		t.Errorf("got %d components, want 1 of %d nodes", len(comps), n)
+	}
+}
@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"slices"
+
+	"github.com/weiwenchen2022/container/heap"
+)
+
+// Weight is a constraint permitting numeric edge payloads, which the
+// shortest path and spanning tree algorithms interpret as weights.
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// pqItem is a node in the priority queue of a search. index is maintained
+// by the heap so the priority can be decreased in place.
+type pqItem[K comparable, W Weight] struct {
+	node     K
+	dist     W // distance from the source
+	priority W // dist plus the heuristic estimate for A*
+	index    int
+}
+
+// search runs Dijkstra's algorithm from src, ordering the frontier by the
+// distance plus the estimate h, until dst is settled (if stop is true) or
+// every reachable node is. It returns the distances and predecessors of
+// the settled nodes.
+func search[K comparable, N any, W Weight](g *Graph[K, N, W], src, dst K, stop bool, h func(K) W) (dist map[K]W, prev map[K]K) {
+	dist, prev = make(map[K]W), make(map[K]K)
+	if !g.HasNode(src) {
+		return dist, prev
+	}
+
+	pq := heap.New(func(a, b *pqItem[K, W]) bool { return a.priority < b.priority },
+		heap.WithSetIndex(func(it *pqItem[K, W], i int) { it.index = i }))
+	open := make(map[K]*pqItem[K, W])
+	settled := make(map[K]bool)
+
+	var zero W
+	start := &pqItem[K, W]{node: src, priority: h(src)}
+	open[src] = start
+	pq.Push(start)
+
+	for pq.Len() > 0 {
+		it := pq.Pop()
+		delete(open, it.node)
+		settled[it.node] = true
+		dist[it.node] = it.dist
+		if stop && it.node == dst {
+			break
+		}
+
+		for v, w := range g.Neighbors(it.node) {
+			if w < zero {
+				panic("graph: negative edge weight")
+			}
+			if settled[v] {
+				continue
+			}
+
+			d := it.dist + w
+			if o, ok := open[v]; ok {
+				if d < o.dist {
+					o.priority -= o.dist - d
+					o.dist = d
+					prev[v] = it.node
+					pq.Fix(o.index)
+				}
+
+				continue
+			}
+
+			o := &pqItem[K, W]{node: v, dist: d, priority: d + h(v)}
+			open[v] = o
+			prev[v] = it.node
+			pq.Push(o)
+		}
+	}
+
+	// drop predecessors of nodes left on the frontier
+	for v := range open {
+		delete(prev, v)
+	}
+
+	return dist, prev
+}
+
+// ShortestPaths runs Dijkstra's algorithm on graph g from node src.
+// It returns the distance of every node reachable from src and the
+// predecessor of each such node, other than src, on a shortest path.
+// It panics if it encounters an edge of negative weight.
+// The complexity is O((n + m) log n).
+func ShortestPaths[K comparable, N any, W Weight](g *Graph[K, N, W], src K) (dist map[K]W, prev map[K]K) {
+	return search(g, src, src, false, func(K) W { return 0 })
+}
+
+// ShortestPath returns a shortest path in graph g from node src to node
+// dst and its length, using Dijkstra's algorithm.
+// The ok result reports whether dst is reachable from src.
+// It panics if it encounters an edge of negative weight.
+func ShortestPath[K comparable, N any, W Weight](g *Graph[K, N, W], src, dst K) (path []K, dist W, ok bool) {
+	return AStar(g, src, dst, func(K) W { return 0 })
+}
+
+// AStar returns a shortest path in graph g from node src to node dst and
+// its length, using the A* algorithm guided by the heuristic h.
+// h(u) estimates the distance from u to dst and must be consistent:
+// h(dst) is zero and h(u) <= w + h(v) for every edge u -> v of weight w.
+// The ok result reports whether dst is reachable from src.
+// It panics if it encounters an edge of negative weight.
+func AStar[K comparable, N any, W Weight](g *Graph[K, N, W], src, dst K, h func(K) W) (path []K, dist W, ok bool) {
+	d, prev := search(g, src, dst, true, h)
+	if dist, ok = d[dst]; !ok {
+		return nil, dist, false
+	}
+
+	return PathTo(prev, src, dst), dist, true
+}
+
+// PathTo returns the path from node src to node dst following the
+// predecessor map prev, as returned by ShortestPaths, or nil if dst is
+// not reachable from src.
+func PathTo[K comparable](prev map[K]K, src, dst K) []K {
+	path := []K{dst}
+	for v := dst; v != src; {
+		u, ok := prev[v]
+		if !ok {
+			return nil
+		}
+
+		path = append(path, u)
+		v = u
+	}
+
+	slices.Reverse(path)
+	return path
+}
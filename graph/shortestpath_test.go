@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestShortestPaths(t *testing.T) {
+	t.Parallel()
+
+	g := NewDirected[string, struct{}, int]()
+	g.AddEdge("s", "a", 7)
+	g.AddEdge("s", "b", 2)
+	g.AddEdge("b", "a", 3)
+	g.AddEdge("a", "t", 1)
+	g.AddEdge("b", "t", 8)
+	g.AddNode("x")
+
+	dist, prev := ShortestPaths(g, "s")
+	want := map[string]int{"s": 0, "a": 5, "b": 2, "t": 6}
+	if len(dist) != len(want) {
+		t.Errorf("dist = %v, want %v", dist, want)
+	}
+	for k, d := range want {
+		if dist[k] != d {
+			t.Errorf("dist[%s] = %d, want %d", k, dist[k], d)
+		}
+	}
+
+	if want, got := []string{"s", "b", "a", "t"}, PathTo(prev, "s", "t"); !slices.Equal(want, got) {
+		t.Errorf("PathTo(t) = %v, want %v", got, want)
+	}
+	if got := PathTo(prev, "s", "x"); got != nil {
+		t.Errorf("PathTo(x) = %v, want nil", got)
+	}
+
+	if path, d, ok := ShortestPath(g, "s", "t"); !ok || d != 6 || len(path) != 4 {
+		t.Errorf("ShortestPath(s, t) = %v, %d, %t", path, d, ok)
+	}
+	if _, _, ok := ShortestPath(g, "t", "s"); ok {
+		t.Errorf("ShortestPath(t, s) found a path")
+	}
+	if path, d, ok := ShortestPath(g, "s", "s"); !ok || d != 0 || !slices.Equal(path, []string{"s"}) {
+		t.Errorf("ShortestPath(s, s) = %v, %d, %t", path, d, ok)
+	}
+}
+
+func TestNegativeWeight(t *testing.T) {
+	t.Parallel()
+
+	g := NewDirected[int, struct{}, int]()
+	g.AddEdge(0, 1, -1)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("ShortestPaths did not panic on a negative weight")
+		}
+	}()
+	ShortestPaths(g, 0)
+}
+
+func TestAStar(t *testing.T) {
+	t.Parallel()
+
+	type point struct{ x, y int }
+	const n = 20
+
+	// grid with random walls and unit edges
+	r := rand.New(rand.NewPCG(1, 2))
+	g := NewUndirected[point, struct{}, int]()
+	wall := func(p point) bool { return p != (point{}) && p != (point{n - 1, n - 1}) && r.IntN(4) == 0 }
+	walls := make(map[point]bool)
+	for x := range n {
+		for y := range n {
+			walls[point{x, y}] = wall(point{x, y})
+		}
+	}
+	for x := range n {
+		for y := range n {
+			p := point{x, y}
+			if walls[p] {
+				continue
+			}
+			if q := (point{x + 1, y}); x+1 < n && !walls[q] {
+				g.AddEdge(p, q, 1)
+			}
+			if q := (point{x, y + 1}); y+1 < n && !walls[q] {
+				g.AddEdge(p, q, 1)
+			}
+		}
+	}
+
+	src, dst := point{}, point{n - 1, n - 1}
+	manhattan := func(p point) int { return dst.x - p.x + dst.y - p.y }
+
+	wantPath, wantDist, wantOK := ShortestPath(g, src, dst)
+	path, dist, ok := AStar(g, src, dst, manhattan)
+	if ok != wantOK || dist != wantDist {
+		t.Fatalf("AStar = %d, %t, want %d, %t", dist, ok, wantDist, wantOK)
+	}
+	if !ok {
+		t.Fatalf("no path through the grid")
+	}
+
+	if len(path) != len(wantPath) || path[0] != src || path[len(path)-1] != dst {
+		t.Errorf("AStar path = %v", path)
+	}
+	for i := 1; i < len(path); i++ {
+		if !g.HasEdge(path[i-1], path[i]) {
+			t.Errorf("AStar path uses missing edge %v -> %v", path[i-1], path[i])
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Package grid implements a two-dimensional grid of cells.
+//
+// The cells are stored in row-major order in a single slice, so a row is
+// contiguous in memory. Sub returns a view of a rectangle of a grid that
+// shares its storage, which suits game boards, images of cells and
+// dynamic-programming tables.
+//
+// To iterate over a grid (where g is a *Grid):
+//
+//	for p, v := range g.All() {
+//		// do something with p.X, p.Y and v
+//	}
+package grid
+
+import (
+	"iter"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+// Point is the position of a cell: column X and row Y.
+type Point struct {
+	X, Y int
+}
+
+// Grid is a grid of cells of type E.
+// To create a grid use grid.New or grid.FromSlice.
+type Grid[E any] struct {
+	w, h   int
+	stride int // distance between the starts of consecutive rows
+	s      []E
+}
+
+// New returns a grid of w columns and h rows of zero values.
+// It panics if w or h is negative.
+func New[E any](w, h int) *Grid[E] {
+	if w < 0 || h < 0 {
+		panic("grid: negative dimension")
+	}
+
+	return &Grid[E]{w: w, h: h, stride: w, s: make([]E, w*h)}
+}
+
+// FromSlice returns a grid of w columns using s, in row-major order, as its
+// storage, without copying it. It panics if w is not positive or len(s) is
+// not a multiple of w.
+func FromSlice[E any](w int, s []E) *Grid[E] {
+	if w <= 0 || len(s)%w != 0 {
+		panic("grid: slice length not a multiple of width")
+	}
+
+	return &Grid[E]{w: w, h: len(s) / w, stride: w, s: s}
+}
+
+// Width returns the number of columns of grid g.
+func (g *Grid[E]) Width() int { return g.w }
+
+// Height returns the number of rows of grid g.
+func (g *Grid[E]) Height() int { return g.h }
+
+// Len returns the number of cells of grid g.
+func (g *Grid[E]) Len() int { return g.w * g.h }
+
+// In reports whether the cell at column x and row y is in grid g.
+func (g *Grid[E]) In(x, y int) bool {
+	return uint(x) < uint(g.w) && uint(y) < uint(g.h)
+}
+
+func (g *Grid[E]) index(x, y int) int {
+	if !g.In(x, y) {
+		panic("grid: index out of range")
+	}
+
+	return y*g.stride + x
+}
+
+// At returns the value of the cell at column x and row y.
+// It panics if the cell is not in grid g.
+func (g *Grid[E]) At(x, y int) E { return g.s[g.index(x, y)] }
+
+// Set sets the value of the cell at column x and row y to v.
+// It panics if the cell is not in grid g.
+func (g *Grid[E]) Set(x, y int, v E) { g.s[g.index(x, y)] = v }
+
+func (g *Grid[E]) row(y int) []E {
+	i := y * g.stride
+	return g.s[i : i+g.w]
+}
+
+// Row returns an iterator over the columns and values of the cells of row
+// y. It panics if the row is not in grid g.
+func (g *Grid[E]) Row(y int) iter.Seq2[int, E] {
+	if uint(y) >= uint(g.h) {
+		panic("grid: row out of range")
+	}
+
+	return func(yield func(int, E) bool) {
+		for x, v := range g.row(y) {
+			if !yield(x, v) {
+				return
+			}
+		}
+	}
+}
+
+// Col returns an iterator over the rows and values of the cells of column
+// x. It panics if the column is not in grid g.
+func (g *Grid[E]) Col(x int) iter.Seq2[int, E] {
+	if uint(x) >= uint(g.w) {
+		panic("grid: column out of range")
+	}
+
+	return func(yield func(int, E) bool) {
+		for y := range g.h {
+			if !yield(y, g.s[y*g.stride+x]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the positions and values of the cells of
+// grid g in row-major order.
+func (g *Grid[E]) All() iter.Seq2[Point, E] {
+	return func(yield func(Point, E) bool) {
+		for y := range g.h {
+			for x, v := range g.row(y) {
+				if !yield(Point{x, y}, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values of the cells of grid g in
+// row-major order.
+func (g *Grid[E]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for y := range g.h {
+			for _, v := range g.row(y) {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+var (
+	orthogonal = []Point{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+	diagonal   = []Point{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+)
+
+// Neighbors returns an iterator over the positions and values of the cells
+// in grid g next to the cell at column x and row y, in row-major order.
+// If diagonals is false these are the four cells sharing an edge with it,
+// otherwise the eight cells surrounding it. Positions outside g are
+// skipped.
+func (g *Grid[E]) Neighbors(x, y int, diagonals bool) iter.Seq2[Point, E] {
+	ds := orthogonal
+	if diagonals {
+		ds = diagonal
+	}
+
+	return func(yield func(Point, E) bool) {
+		for _, d := range ds {
+			p := Point{x + d.X, y + d.Y}
+			if g.In(p.X, p.Y) && !yield(p, g.s[p.Y*g.stride+p.X]) {
+				return
+			}
+		}
+	}
+}
+
+// Sub returns a view of the w by h rectangle of grid g whose top left cell
+// is at column x and row y. The view shares the cells of g: setting a cell
+// in either is visible through the other.
+// It panics if the rectangle is not within g.
+func (g *Grid[E]) Sub(x, y, w, h int) *Grid[E] {
+	if x < 0 || y < 0 || w < 0 || h < 0 || x+w > g.w || y+h > g.h {
+		panic("grid: sub-grid out of range")
+	}
+
+	v := &Grid[E]{w: w, h: h, stride: g.stride}
+	if w > 0 && h > 0 {
+		i := y*g.stride + x
+		v.s = g.s[i : i+(h-1)*g.stride+w]
+	}
+
+	return v
+}
+
+// Fill sets every cell of grid g to v.
+func (g *Grid[E]) Fill(v E) {
+	for y := range g.h {
+		r := g.row(y)
+		for x := range r {
+			r[x] = v
+		}
+	}
+}
+
+// Map returns a new grid of the same size as g whose cells are the results
+// of f applied to the positions and values of the cells of g.
+func Map[E, F any](g *Grid[E], f func(Point, E) F) *Grid[F] {
+	m := New[F](g.w, g.h)
+	for p, v := range g.All() {
+		m.s[p.Y*m.stride+p.X] = f(p, v)
+	}
+
+	return m
+}
+
+// Clone returns a copy of grid g with its own storage. The values are
+// copied by assignment, so this is a shallow clone. The clone of a view
+// does not share cells with the grid it views.
+func (g *Grid[E]) Clone() *Grid[E] {
+	return Map(g, func(_ Point, v E) E { return v })
+}
+
+// MemStats returns statistics about the memory used by grid g. A view
+// reports the storage it shares.
+func (g *Grid[E]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len:   g.Len(),
+		Cap:   cap(g.s),
+		Bytes: memsize.Of[Grid[E]]() + memsize.Slice[E](cap(g.s)),
+	}
+}
@@ -0,0 +1,106 @@
+package grid
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Iterable[int]      = (*Grid[int])(nil)
+	_ container.Cloner[*Grid[int]] = (*Grid[int])(nil)
+	_ container.Sizer              = (*Grid[int])(nil)
+)
+
+func TestGrid(t *testing.T) {
+	t.Parallel()
+
+	g := FromSlice(3, []int{
+		0, 1, 2,
+		3, 4, 5,
+	})
+	if g.Width() != 3 || g.Height() != 2 || g.Len() != 6 {
+		t.Fatalf("g is %dx%d, want 3x2", g.Width(), g.Height())
+	}
+
+	g.Set(2, 1, 50)
+	if v := g.At(2, 1); v != 50 {
+		t.Errorf("g.At(2, 1) = %d, want 50", v)
+	}
+	if g.In(3, 0) || g.In(0, -1) || !g.In(2, 1) {
+		t.Errorf("g.In results wrong")
+	}
+
+	var row, col []int
+	for _, v := range g.Row(1) {
+		row = append(row, v)
+	}
+	for _, v := range g.Col(1) {
+		col = append(col, v)
+	}
+	if !slices.Equal(row, []int{3, 4, 50}) || !slices.Equal(col, []int{1, 4}) {
+		t.Errorf("g.Row(1) = %v, g.Col(1) = %v", row, col)
+	}
+
+	var ns []Point
+	for p := range g.Neighbors(0, 0, false) {
+		ns = append(ns, p)
+	}
+	if want := []Point{{1, 0}, {0, 1}}; !slices.Equal(ns, want) {
+		t.Errorf("g.Neighbors(0, 0, false) = %v, want %v", ns, want)
+	}
+	n := 0
+	for range g.Neighbors(1, 0, true) {
+		n++
+	}
+	if n != 5 {
+		t.Errorf("g.Neighbors(1, 0, true) yielded %d cells, want 5", n)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("g.At(3, 0) did not panic")
+		}
+	}()
+	g.At(3, 0)
+}
+
+func TestSub(t *testing.T) {
+	t.Parallel()
+
+	g := New[int](4, 4)
+	s := g.Sub(1, 1, 2, 3)
+	s.Fill(1)
+	s.Set(1, 2, 2)
+
+	want := []int{
+		0, 0, 0, 0,
+		0, 1, 1, 0,
+		0, 1, 1, 0,
+		0, 1, 2, 0,
+	}
+	if got := slices.Collect(g.Values()); !slices.Equal(got, want) {
+		t.Errorf("g = %v, want %v", got, want)
+	}
+
+	ss := s.Sub(1, 1, 1, 2)
+	if got := slices.Collect(ss.Values()); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("sub-grid of sub-grid = %v, want [1 2]", got)
+	}
+
+	c := s.Clone()
+	c.Set(0, 0, 9)
+	if g.At(1, 1) != 1 || c.Width() != 2 || c.Height() != 3 {
+		t.Errorf("clone of a view shares cells")
+	}
+
+	sums := Map(g, func(p Point, v int) int { return p.X + p.Y + v })
+	if v := sums.At(2, 3); v != 7 {
+		t.Errorf("sums.At(2, 3) = %d, want 7", v)
+	}
+
+	if e := g.Sub(4, 4, 0, 0); e.Len() != 0 {
+		t.Errorf("empty sub-grid has %d cells", e.Len())
+	}
+}
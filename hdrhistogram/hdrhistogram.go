@@ -0,0 +1,330 @@
+// Package hdrhistogram implements a high dynamic range histogram of
+// integer values.
+//
+// A Histogram (Tene, "HdrHistogram") records values between 0 and a fixed
+// maximum with a fixed number of significant decimal digits of precision:
+// with 3 digits every recorded value is reproduced within 0.1%, whether it
+// is a microsecond or an hour. Values are counted in buckets whose width
+// doubles with each power of two, so the memory needed depends only on the
+// range and precision, not on the number of values. Recording is O(1),
+// histograms can be merged, and MarshalBinary exports a compact encoding.
+//
+// Histograms suit latency tracking, where the tail of the distribution
+// matters and values span many orders of magnitude.
+package hdrhistogram
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// ErrOutOfRange is returned when recording a value outside the range of a
+// histogram.
+var ErrOutOfRange = errors.New("hdrhistogram: value out of range")
+
+// Histogram is an HDR histogram.
+// To create a histogram use hdrhistogram.New.
+type Histogram struct {
+	highest int64
+	digits  int
+
+	subBucketMag       int // log2 of the number of sub-buckets
+	subBucketHalfMag   int
+	subBucketHalf      int
+	subBucketMask      uint64
+	subBucketCount     int
+	bucketCount        int
+	counts             []int64
+	total              int64
+	minValue, maxValue int64
+}
+
+// New returns an empty histogram recording values in [0, highest] with the
+// given number of significant decimal digits, between 1 and 5.
+// It panics if highest < 2 or digits is out of range.
+func New(highest int64, digits int) *Histogram {
+	if digits < 1 || digits > 5 {
+		panic("hdrhistogram: significant digits out of range")
+	}
+	if highest < 2 {
+		panic("hdrhistogram: highest value too small")
+	}
+
+	h := &Histogram{highest: highest, digits: digits}
+
+	// The sub-buckets of the first bucket count single units up to
+	// 2×10^digits, which gives the requested precision everywhere else.
+	single := 2 * int64(math.Pow10(digits))
+	h.subBucketMag = bits.Len64(uint64(single - 1))
+	h.subBucketHalfMag = h.subBucketMag - 1
+	h.subBucketCount = 1 << h.subBucketMag
+	h.subBucketHalf = h.subBucketCount / 2
+	h.subBucketMask = uint64(h.subBucketCount - 1)
+
+	// Each bucket doubles the range covered.
+	untrackable := int64(h.subBucketCount)
+	h.bucketCount = 1
+	for untrackable <= highest {
+		if untrackable > math.MaxInt64/2 {
+			h.bucketCount++
+			break
+		}
+		untrackable <<= 1
+		h.bucketCount++
+	}
+
+	h.counts = make([]int64, (h.bucketCount+1)*h.subBucketHalf)
+	h.Reset()
+	return h
+}
+
+// Highest returns the largest value histogram h can record.
+func (h *Histogram) Highest() int64 { return h.highest }
+
+// SignificantDigits returns the precision of histogram h.
+func (h *Histogram) SignificantDigits() int { return h.digits }
+
+// Count returns the number of values recorded in histogram h.
+func (h *Histogram) Count() int64 { return h.total }
+
+// Min returns the smallest value recorded in histogram h, or 0 if it is
+// empty. The value is exact, not rounded to the precision of h.
+func (h *Histogram) Min() int64 {
+	if h.total == 0 {
+		return 0
+	}
+
+	return h.minValue
+}
+
+// Max returns the largest value recorded in histogram h, or 0 if it is
+// empty. The value is exact, not rounded to the precision of h.
+func (h *Histogram) Max() int64 {
+	if h.total == 0 {
+		return 0
+	}
+
+	return h.maxValue
+}
+
+// Reset removes all values from histogram h.
+func (h *Histogram) Reset() {
+	clear(h.counts)
+	h.total = 0
+	h.minValue, h.maxValue = math.MaxInt64, 0
+}
+
+// Clone returns a copy of histogram h.
+func (h *Histogram) Clone() *Histogram {
+	c := *h
+	c.counts = slices.Clone(h.counts)
+	return &c
+}
+
+func (h *Histogram) bucketIndex(v int64) int {
+	return bits.Len64(uint64(v)|h.subBucketMask) - h.subBucketMag
+}
+
+func (h *Histogram) countsIndex(v int64) int {
+	b := h.bucketIndex(v)
+	sb := int(v >> b)
+	return (b+1)<<h.subBucketHalfMag + sb - h.subBucketHalf
+}
+
+// valueAt returns the lowest value counted at index i of h.counts.
+func (h *Histogram) valueAt(i int) int64 {
+	b := i>>h.subBucketHalfMag - 1
+	sb := i&(h.subBucketHalf-1) + h.subBucketHalf
+	if b < 0 {
+		sb -= h.subBucketHalf
+		b = 0
+	}
+
+	return int64(sb) << b
+}
+
+// equivalentRange returns the width of the range of values counted
+// together with v.
+func (h *Histogram) equivalentRange(v int64) int64 {
+	return 1 << h.bucketIndex(v)
+}
+
+// Lowest returns the smallest value counted together with v.
+func (h *Histogram) Lowest(v int64) int64 {
+	return v &^ (h.equivalentRange(v) - 1)
+}
+
+// HighestEquivalent returns the largest value counted together with v.
+func (h *Histogram) HighestEquivalent(v int64) int64 {
+	return h.Lowest(v) + h.equivalentRange(v) - 1
+}
+
+// Record records value v in histogram h.
+// It returns ErrOutOfRange if v is negative or larger than h.Highest().
+func (h *Histogram) Record(v int64) error { return h.RecordN(v, 1) }
+
+// RecordN records n occurrences of value v in histogram h.
+// It returns ErrOutOfRange if v is negative or larger than h.Highest().
+// Non-positive n are ignored.
+func (h *Histogram) RecordN(v, n int64) error {
+	if v < 0 || v > h.highest {
+		return ErrOutOfRange
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	h.counts[h.countsIndex(v)] += n
+	h.total += n
+	h.minValue, h.maxValue = min(h.minValue, v), max(h.maxValue, v)
+	return nil
+}
+
+// ValueAtQuantile returns the value at quantile q, in [0, 1], of the
+// values recorded in histogram h: the largest value counted together with
+// the value of that rank. It returns 0 if h is empty.
+func (h *Histogram) ValueAtQuantile(q float64) int64 {
+	if h.total == 0 {
+		return 0
+	}
+
+	q = min(max(q, 0), 1)
+	rank := max(int64(q*float64(h.total)+0.5), 1)
+
+	var seen int64
+	for i, c := range h.counts {
+		if seen += c; seen >= rank {
+			return min(h.HighestEquivalent(h.valueAt(i)), h.maxValue)
+		}
+	}
+
+	return h.maxValue
+}
+
+// Mean returns the mean of the values recorded in histogram h, each taken
+// as the middle of its equivalent range, or 0 if h is empty.
+func (h *Histogram) Mean() float64 {
+	if h.total == 0 {
+		return 0
+	}
+
+	var sum float64
+	for i, c := range h.counts {
+		if c != 0 {
+			v := h.valueAt(i)
+			sum += float64(c) * float64(v+h.equivalentRange(v)/2)
+		}
+	}
+
+	return sum / float64(h.total)
+}
+
+// Merge adds the values recorded in histogram other to histogram h, which
+// may have a different range and precision. Values of other beyond the
+// range of h are dropped and reported by ErrOutOfRange.
+func (h *Histogram) Merge(other *Histogram) error {
+	var err error
+	for i, c := range other.counts {
+		if c == 0 {
+			continue
+		}
+
+		v := other.valueAt(i)
+		switch {
+		case other.minValue >= v && other.minValue <= other.HighestEquivalent(v):
+			v = other.minValue // keep the exact extremes
+		case other.maxValue >= v && other.maxValue <= other.HighestEquivalent(v):
+			v = other.maxValue
+		}
+		if e := h.RecordN(v, c); e != nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// MarshalBinary encodes histogram h compactly: its configuration followed
+// by the counts, with runs of empty buckets collapsed.
+func (h *Histogram) MarshalBinary() ([]byte, error) {
+	b := binary.AppendUvarint(nil, uint64(h.highest))
+	b = binary.AppendUvarint(b, uint64(h.digits))
+	b = binary.AppendUvarint(b, uint64(h.minValue))
+	b = binary.AppendUvarint(b, uint64(h.maxValue))
+
+	// Trailing empty buckets are implied.
+	n := len(h.counts)
+	for n > 0 && h.counts[n-1] == 0 {
+		n--
+	}
+
+	// A count is written as a positive varint, a run of k zero counts as -k.
+	for i := 0; i < n; {
+		if h.counts[i] != 0 {
+			b = binary.AppendVarint(b, h.counts[i])
+			i++
+			continue
+		}
+
+		j := i
+		for h.counts[j] == 0 {
+			j++
+		}
+		b = binary.AppendVarint(b, -int64(j-i))
+		i = j
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary decodes a histogram encoded by MarshalBinary into h,
+// replacing its configuration and values.
+func (h *Histogram) UnmarshalBinary(data []byte) error {
+	errFormat := errors.New("hdrhistogram: invalid encoding")
+
+	var hdr [4]uint64
+	for i := range hdr {
+		x, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errFormat
+		}
+		hdr[i], data = x, data[n:]
+	}
+	if hdr[0] < 2 || hdr[0] > math.MaxInt64 || hdr[1] < 1 || hdr[1] > 5 || hdr[3] > hdr[0] {
+		return errFormat
+	}
+
+	c := New(int64(hdr[0]), int(hdr[1]))
+	for i := 0; len(data) > 0; {
+		x, n := binary.Varint(data)
+		if n <= 0 {
+			return errFormat
+		}
+		data = data[n:]
+
+		if x < 0 {
+			if -x > int64(len(c.counts)-i) {
+				return errFormat
+			}
+			i += int(-x)
+			continue
+		}
+		if i >= len(c.counts) {
+			return errFormat
+		}
+
+		c.counts[i] = x
+		c.total += x
+		i++
+	}
+
+	if c.total > 0 {
+		c.minValue, c.maxValue = int64(hdr[2]), int64(hdr[3])
+	}
+
+	*h = *c
+	return nil
+}
@@ -0,0 +1,139 @@
+package hdrhistogram
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	t.Parallel()
+
+	h := New(1<<40, 3)
+	prev := -1
+	for _, v := range []int64{0, 1, 2047, 2048, 2049, 4095, 4096, 1 << 20, 1<<40 - 1, 1 << 40} {
+		i := h.countsIndex(v)
+		if i < prev {
+			t.Errorf("countsIndex(%d) = %d decreasing", v, i)
+		}
+		if i >= len(h.counts) {
+			t.Fatalf("countsIndex(%d) = %d out of range %d", v, i, len(h.counts))
+		}
+		if lo := h.valueAt(i); lo != h.Lowest(v) || lo > v || h.HighestEquivalent(v) < v {
+			t.Errorf("value %d: valueAt = %d, Lowest = %d, HighestEquivalent = %d", v, lo, h.Lowest(v), h.HighestEquivalent(v))
+		}
+		prev = i
+	}
+}
+
+func TestQuantiles(t *testing.T) {
+	t.Parallel()
+
+	const n = 1000000
+	h := New(3600*1000*1000, 3)
+	for v := int64(1); v <= n; v++ {
+		if err := h.Record(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if h.Count() != n || h.Min() != 1 || h.Max() != n {
+		t.Errorf("Count, Min, Max = %d, %d, %d, want %d, 1, %d", h.Count(), h.Min(), h.Max(), n, n)
+	}
+
+	for _, q := range []float64{0, 0.1, 0.5, 0.9, 0.99, 0.999, 1} {
+		want := math.Max(q*n, 1)
+		got := float64(h.ValueAtQuantile(q))
+		if math.Abs(got-want)/want > 0.001 {
+			t.Errorf("h.ValueAtQuantile(%v) = %v, want %v within 0.1%%", q, got, want)
+		}
+	}
+
+	if m := h.Mean(); math.Abs(m-(n+1)/2.0)/(n/2) > 0.001 {
+		t.Errorf("h.Mean() = %v, want %v", m, (n+1)/2.0)
+	}
+
+	if err := h.Record(-1); err != ErrOutOfRange {
+		t.Errorf("h.Record(-1) = %v, want %v", err, ErrOutOfRange)
+	}
+	if err := h.Record(h.Highest() + 1); err != ErrOutOfRange {
+		t.Errorf("h.Record(highest+1) = %v, want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	a, b := New(1e9, 3), New(1e6, 2)
+	all := New(1e9, 3)
+	for range 10000 {
+		v := int64(r.ExpFloat64() * 1000)
+		a.Record(v)
+		all.Record(v)
+		w := int64(r.ExpFloat64() * 5000)
+		b.Record(w)
+		all.Record(w)
+	}
+
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if a.Count() != all.Count() || a.Min() != all.Min() || a.Max() != all.Max() {
+		t.Errorf("merged Count, Min, Max = %d, %d, %d, want %d, %d, %d",
+			a.Count(), a.Min(), a.Max(), all.Count(), all.Min(), all.Max())
+	}
+	for _, q := range []float64{0.5, 0.99} {
+		got, want := float64(a.ValueAtQuantile(q)), float64(all.ValueAtQuantile(q))
+		if math.Abs(got-want)/want > 0.01 {
+			t.Errorf("merged quantile %v = %v, want %v within 1%%", q, got, want)
+		}
+	}
+
+	small := New(100, 2)
+	if err := small.Merge(all); err != ErrOutOfRange {
+		t.Errorf("small.Merge(all) = %v, want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	h := New(1e12, 4)
+	for _, v := range []int64{0, 5, 5, 1000, 123456789, 1e12} {
+		h.Record(v)
+	}
+
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > 100 {
+		t.Errorf("encoding takes %d bytes for 6 values", len(data))
+	}
+
+	var g Histogram
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(g.counts, h.counts) || g.Count() != h.Count() || g.Min() != 0 || g.Max() != 1e12 ||
+		g.Highest() != h.Highest() || g.SignificantDigits() != 4 {
+		t.Errorf("decoded histogram differs")
+	}
+
+	// the highest value alone takes 6 bytes
+	for n := range 6 {
+		var g Histogram
+		if g.UnmarshalBinary(data[:n]) == nil {
+			t.Errorf("UnmarshalBinary of a %d-byte header succeeded", n)
+		}
+	}
+
+	c := h.Clone()
+	c.Reset()
+	if c.Count() != 0 || h.Count() != 6 {
+		t.Errorf("resetting the clone changed the histogram")
+	}
+}
@@ -16,6 +16,13 @@
 // implementation; the file example_pq_test.go has the complete source.
 package heap
 
+import (
+	"iter"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
 // The Heap type implements a min-heap with the following invariants (established after
 // Init has been called or if the data is empty or sorted):
 //
@@ -67,9 +74,45 @@ func New[E any](less func(i, j E) bool, opts ...option[E]) *Heap[E] {
 	return h
 }
 
+// WrapSlice returns a heap using s as its storage, without copying it.
+// It reorders s in place to establish the heap invariants.
+// The heap takes ownership of s, and the caller should not use s after this call.
+// The complexity is O(n) where n = len(s).
+func WrapSlice[E any](s []E, less func(i, j E) bool) *Heap[E] {
+	h := &Heap[E]{less: less, s: s}
+	h.Init()
+	return h
+}
+
 // Len reports the number of elements in the heap.
 func (h *Heap[E]) Len() int { return len(h.s) }
 
+// Clear removes all elements from the heap, keeping its allocated space.
+// Like Pop, it sets the index of each element to -1.
+func (h *Heap[E]) Clear() {
+	if h.setIndex != nil {
+		for _, x := range h.s {
+			h.setIndex(x, -1)
+		}
+	}
+
+	clear(h.s)
+	h.s = h.s[:0]
+}
+
+// Values returns an iterator over the elements of the heap in their
+// internal array order, which is not sorted. The heap must not be
+// modified during iteration.
+func (h *Heap[E]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, x := range h.s {
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
+
 func (h *Heap[E]) swap(i, j int) {
 	h.s[i], h.s[j] = h.s[j], h.s[i]
 
@@ -193,3 +236,39 @@ func (h *Heap[E]) down(i0, n int) bool {
 
 	return i > i0
 }
+
+// Clone returns a copy of the heap. The elements are copied by assignment,
+// so this is a shallow clone.
+// If the heap has a setIndex function and its elements are pointers, the
+// clone shares them with the heap and the index each records is only valid
+// for one of the two; use CloneFunc to copy the elements instead.
+func (h *Heap[E]) Clone() *Heap[E] {
+	c := *h
+	c.s = append([]E(nil), h.s...)
+	return &c
+}
+
+// CloneFunc returns a copy of the heap with each element copied by f,
+// which allows a deep clone. The setIndex function, if any, is called for
+// each copied element.
+func (h *Heap[E]) CloneFunc(f func(E) E) *Heap[E] {
+	c := *h
+	c.s = make([]E, len(h.s))
+	for i, x := range h.s {
+		c.s[i] = f(x)
+		if c.setIndex != nil {
+			c.setIndex(c.s[i], i)
+		}
+	}
+
+	return &c
+}
+
+// MemStats returns statistics about the memory used by heap h.
+func (h *Heap[E]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len:   len(h.s),
+		Cap:   cap(h.s),
+		Bytes: memsize.Of[Heap[E]]() + memsize.Slice[E](cap(h.s)),
+	}
+}
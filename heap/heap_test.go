@@ -9,8 +9,17 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.PriorityQueue[int] = (*Heap[int])(nil)
+	_ container.Iterable[int]      = (*Heap[int])(nil)
+	_ container.Sizer              = (*Heap[int])(nil)
 )
 
 type myHeap struct {
@@ -145,6 +154,40 @@ func TestRemove0(t *testing.T) {
 	}
 }
 
+func TestClear(t *testing.T) {
+	t.Parallel()
+
+	h := &myHeap{New(less)}
+	for i := 0; i < 10; i++ {
+		h.Push(i)
+	}
+
+	h.Clear()
+	if h.Len() != 0 {
+		t.Errorf("Len() after Clear = %d; want 0", h.Len())
+	}
+
+	h.Push(3)
+	h.Push(1)
+	h.verify(t, 0)
+	if x := h.Pop(); x != 1 {
+		t.Errorf("Pop() after Clear got %d; want 1", x)
+	}
+
+	type item struct{ v, index int }
+	ih := New(func(a, b *item) bool { return a.v < b.v }, WithSetIndex(func(x *item, i int) { x.index = i }))
+	items := []*item{{v: 2}, {v: 1}, {v: 3}}
+	for _, x := range items {
+		ih.Push(x)
+	}
+	ih.Clear()
+	for _, x := range items {
+		if x.index != -1 {
+			t.Errorf("index of %d after Clear = %d; want -1", x.v, x.index)
+		}
+	}
+}
+
 func TestRemove1(t *testing.T) {
 	t.Parallel()
 
@@ -298,3 +341,71 @@ func TestFix(t *testing.T) {
 		h.verify(t, 0)
 	}
 }
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	h := &myHeap{New(less)}
+	for i := 10; i > 0; i-- {
+		h.Push(i)
+	}
+
+	c := &myHeap{h.Clone()}
+	c.Push(0)
+	c.verify(t, 0)
+	if x := c.Pop(); x != 0 {
+		t.Errorf("clone Pop() got %d; want 0", x)
+	}
+	if h.Len() != 10 || h.Peek() != 1 {
+		t.Errorf("changing the clone changed the heap")
+	}
+
+	type item struct{ v, index int }
+	ih := New(func(a, b *item) bool { return a.v < b.v }, WithSetIndex(func(x *item, i int) { x.index = i }))
+	for i := range 5 {
+		ih.Push(&item{v: 5 - i})
+	}
+
+	var copies []*item
+	ic := ih.CloneFunc(func(x *item) *item {
+		y := *x
+		copies = append(copies, &y)
+		return &y
+	})
+	for _, x := range copies {
+		if y := ic.Remove(x.index); y != x {
+			t.Errorf("Remove(%d) got %v; want %v", x.index, y, x)
+		}
+	}
+	if ih.Len() != 5 || ih.Peek().index != 0 {
+		t.Errorf("CloneFunc modified the original elements")
+	}
+}
+
+func TestMemStats(t *testing.T) {
+	t.Parallel()
+
+	h := New(func(a, b int32) bool { return a < b }, WithInitialCap[int32](10))
+	h.Push(1)
+	if s := h.MemStats(); s.Len != 1 || s.Cap != 10 || s.Bytes < 40 {
+		t.Errorf("h.MemStats() = %+v", s)
+	}
+}
+
+func TestWrapSlice(t *testing.T) {
+	t.Parallel()
+
+	s := []int{5, 3, 8, 1, 9, 2}
+	h := WrapSlice(s, func(a, b int) bool { return a < b })
+	if &h.s[0] != &s[0] {
+		t.Errorf("WrapSlice copied the slice")
+	}
+
+	var got []int
+	for h.Len() > 0 {
+		got = append(got, h.Pop())
+	}
+	if want := []int{1, 2, 3, 5, 8, 9}; !slices.Equal(got, want) {
+		t.Errorf("popped %v, want %v", got, want)
+	}
+}
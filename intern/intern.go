@@ -0,0 +1,116 @@
+// Package intern implements tables that map values to small integer IDs.
+//
+// Interning stores each distinct value once and refers to it by a dense
+// ID, so large numbers of duplicates, such as the node names of a graph or
+// the symbols of a parser, share one copy and compare as integers. IDs are
+// assigned in order from 0 and stay valid until the table is cleared.
+//
+// A Table is not safe for concurrent use; Sync is.
+package intern
+
+import (
+	"iter"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+// ID identifies a value interned in a table.
+type ID uint32
+
+// Table is a table of interned values.
+// To create a table use intern.New.
+type Table[T comparable] struct {
+	ids    map[T]ID
+	values []T
+}
+
+// New returns an empty table.
+func New[T comparable]() *Table[T] {
+	return &Table[T]{ids: make(map[T]ID)}
+}
+
+// Len returns the number of values in table t.
+func (t *Table[T]) Len() int { return len(t.values) }
+
+// Clear removes all values from table t. IDs returned earlier become
+// invalid and are reused for the values interned next.
+func (t *Table[T]) Clear() {
+	clear(t.ids)
+	clear(t.values)
+	t.values = t.values[:0]
+}
+
+// Intern returns the ID of v, adding v to table t if it is not there.
+// It panics if t already holds the maximum number of IDs.
+func (t *Table[T]) Intern(v T) ID {
+	if id, ok := t.ids[v]; ok {
+		return id
+	}
+
+	if len(t.values) > math.MaxUint32 {
+		panic("intern: too many values")
+	}
+
+	id := ID(len(t.values))
+	t.ids[v] = id
+	t.values = append(t.values, v)
+	return id
+}
+
+// Lookup returns the ID of v without adding it.
+// The ok result reports whether v is in table t.
+func (t *Table[T]) Lookup(v T) (id ID, ok bool) {
+	id, ok = t.ids[v]
+	return id, ok
+}
+
+// Value returns the value with the given ID.
+// It panics if id was not returned by Intern since the last Clear.
+func (t *Table[T]) Value(id ID) T {
+	if int(id) >= len(t.values) {
+		panic("intern: unknown ID")
+	}
+
+	return t.values[id]
+}
+
+// All returns an iterator over the IDs and values of table t in ID order.
+func (t *Table[T]) All() iter.Seq2[ID, T] {
+	return func(yield func(ID, T) bool) {
+		for i, v := range t.values {
+			if !yield(ID(i), v) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values of table t in ID order.
+func (t *Table[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range t.values {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a copy of table t, which assigns the same IDs.
+func (t *Table[T]) Clone() *Table[T] {
+	return &Table[T]{ids: maps.Clone(t.ids), values: slices.Clone(t.values)}
+}
+
+// MemStats returns statistics about the memory used by table t.
+// The values are counted twice, once for each direction of lookup.
+func (t *Table[T]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len:   len(t.values),
+		Cap:   cap(t.values),
+		Bytes: memsize.Of[Table[T]]() + memsize.Slice[T](cap(t.values)) + memsize.Map[T, ID](len(t.ids)),
+	}
+}
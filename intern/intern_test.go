@@ -0,0 +1,91 @@
+package intern
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container           = (*Table[string])(nil)
+	_ container.Iterable[string]    = (*Table[string])(nil)
+	_ container.Cloner[*Table[int]] = (*Table[int])(nil)
+	_ container.Sizer               = (*Table[string])(nil)
+	_ container.Container           = (*Sync[string])(nil)
+)
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	tab := New[string]()
+	words := []string{"a", "b", "a", "c", "b", "a"}
+	var ids []ID
+	for _, w := range words {
+		ids = append(ids, tab.Intern(w))
+	}
+
+	if want := []ID{0, 1, 0, 2, 1, 0}; !slices.Equal(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+	for i, id := range ids {
+		if v := tab.Value(id); v != words[i] {
+			t.Errorf("tab.Value(%d) = %q, want %q", id, v, words[i])
+		}
+	}
+	if id, ok := tab.Lookup("c"); !ok || id != 2 {
+		t.Errorf(`tab.Lookup("c") = %d, %t, want 2, true`, id, ok)
+	}
+	if _, ok := tab.Lookup("d"); ok || tab.Len() != 3 {
+		t.Errorf("Lookup added a value")
+	}
+	if got := slices.Collect(tab.Values()); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("tab.Values() = %q", got)
+	}
+
+	c := tab.Clone()
+	tab.Clear()
+	if id := tab.Intern("c"); id != 0 {
+		t.Errorf(`tab.Intern("c") after Clear = %d, want 0`, id)
+	}
+	if id, _ := c.Lookup("c"); id != 2 || c.Len() != 3 {
+		t.Errorf("clearing the table changed the clone")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("tab.Value(5) did not panic")
+		}
+	}()
+	tab.Value(5)
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	s := NewSync[string]()
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				w := strconv.Itoa(i % 100)
+				if v := s.Value(s.Intern(w)); v != w {
+					t.Errorf("s.Value(s.Intern(%q)) = %q", w, v)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := s.Len(); n != 100 {
+		t.Errorf("s.Len() = %d, want 100", n)
+	}
+	if n := s.Snapshot().Len(); n != 100 {
+		t.Errorf("s.Snapshot().Len() = %d, want 100", n)
+	}
+}
@@ -0,0 +1,71 @@
+package intern
+
+import "sync"
+
+// Sync is a table of interned values safe for concurrent use.
+// Interning a value already in the table only takes a shared lock, so
+// concurrent lookups of known values do not contend.
+// To create a table use intern.NewSync.
+type Sync[T comparable] struct {
+	mu sync.RWMutex
+	t  *Table[T]
+}
+
+// NewSync returns an empty concurrent table.
+func NewSync[T comparable]() *Sync[T] {
+	return &Sync[T]{t: New[T]()}
+}
+
+// Len returns the number of values in table s.
+func (s *Sync[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.t.Len()
+}
+
+// Clear removes all values from table s, like Table.Clear.
+func (s *Sync[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.t.Clear()
+}
+
+// Intern returns the ID of v, adding v to table s if it is not there.
+func (s *Sync[T]) Intern(v T) ID {
+	if id, ok := s.Lookup(v); ok {
+		return id
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.t.Intern(v)
+}
+
+// Lookup returns the ID of v without adding it.
+// The ok result reports whether v is in table s.
+func (s *Sync[T]) Lookup(v T) (id ID, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.t.Lookup(v)
+}
+
+// Value returns the value with the given ID.
+// It panics if id was not returned by Intern since the last Clear.
+func (s *Sync[T]) Value(id ID) T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.t.Value(id)
+}
+
+// Snapshot returns a copy of table s as a Table.
+func (s *Sync[T]) Snapshot() *Table[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.t.Clone()
+}
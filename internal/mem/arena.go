@@ -0,0 +1,99 @@
+// Package mem provides typed arenas for allocating the nodes of linked
+// data structures in bulk.
+package mem
+
+// An Arena allocates values of type T from chunks of a fixed number of
+// values, so building a structure of n nodes costs O(n/chunk) allocations
+// instead of O(n). Values are not freed individually; Reset recycles all of
+// them at once.
+//
+// A nil *Arena is valid and allocates each value separately with new, so
+// containers can allocate through an optional arena unconditionally.
+//
+// An Arena is not safe for concurrent use.
+type Arena[T any] struct {
+	chunkSize int
+	chunks    [][]T
+
+	// the next value is chunks[chunk][off]
+	chunk, off int
+}
+
+// NewArena returns an arena allocating chunks of chunkSize values.
+// It panics if chunkSize < 1.
+func NewArena[T any](chunkSize int) *Arena[T] {
+	if chunkSize < 1 {
+		panic("mem: non-positive chunk size")
+	}
+
+	return &Arena[T]{chunkSize: chunkSize}
+}
+
+// Empty returns a new arena with the same chunk size as a, or nil if a is nil.
+func (a *Arena[T]) Empty() *Arena[T] {
+	if a == nil {
+		return nil
+	}
+
+	return NewArena[T](a.chunkSize)
+}
+
+// New returns a pointer to a new zero value of type T.
+func (a *Arena[T]) New() *T {
+	if a == nil {
+		return new(T)
+	}
+
+	if a.chunk < len(a.chunks) && a.off == a.chunkSize {
+		a.chunk, a.off = a.chunk+1, 0
+	}
+	if a.chunk == len(a.chunks) {
+		a.chunks = append(a.chunks, make([]T, a.chunkSize))
+	}
+
+	p := &a.chunks[a.chunk][a.off]
+	a.off++
+	return p
+}
+
+// Reset makes all values allocated by a available for reuse, keeping the
+// chunks. Pointers returned by New before Reset must no longer be used.
+func (a *Arena[T]) Reset() {
+	if a == nil {
+		return
+	}
+
+	for i := 0; i <= a.chunk && i < len(a.chunks); i++ {
+		clear(a.chunks[i])
+	}
+	a.chunk, a.off = 0, 0
+}
+
+// Len returns the number of values allocated by a since it was created or
+// last reset.
+func (a *Arena[T]) Len() int {
+	if a == nil {
+		return 0
+	}
+
+	return a.chunk*a.chunkSize + a.off
+}
+
+// Cap returns the number of values a can allocate without allocating
+// another chunk, including those already allocated.
+func (a *Arena[T]) Cap() int {
+	if a == nil {
+		return 0
+	}
+
+	return len(a.chunks) * a.chunkSize
+}
+
+// Chunks returns the number of chunks allocated by a.
+func (a *Arena[T]) Chunks() int {
+	if a == nil {
+		return 0
+	}
+
+	return len(a.chunks)
+}
@@ -0,0 +1,65 @@
+package mem
+
+import "testing"
+
+func TestArena(t *testing.T) {
+	t.Parallel()
+
+	a := NewArena[int](4)
+	ps := make(map[*int]bool)
+	for i := range 10 {
+		p := a.New()
+		if *p != 0 {
+			t.Fatalf("a.New() = %d, want 0", *p)
+		}
+		if ps[p] {
+			t.Fatalf("a.New() returned %p twice", p)
+		}
+		ps[p] = true
+		*p = i + 1
+	}
+	if n := a.Chunks(); n != 3 {
+		t.Errorf("a.Chunks() = %d, want 3", n)
+	}
+	if n, c := a.Len(), a.Cap(); n != 10 || c != 12 {
+		t.Errorf("a.Len(), a.Cap() = %d, %d, want 10, 12", n, c)
+	}
+
+	a.Reset()
+	for range 10 {
+		p := a.New()
+		if *p != 0 {
+			t.Fatalf("a.New() after Reset = %d, want 0", *p)
+		}
+		if !ps[p] {
+			t.Fatalf("a.New() after Reset did not reuse memory")
+		}
+	}
+	a.New()
+	a.New()
+	if n := a.Chunks(); n != 3 {
+		t.Errorf("a.Chunks() after reuse = %d, want 3", n)
+	}
+
+	a.New()
+	if n := a.Chunks(); n != 4 {
+		t.Errorf("a.Chunks() = %d, want 4", n)
+	}
+
+	if e := a.Empty(); e.Chunks() != 0 || e.chunkSize != 4 {
+		t.Errorf("a.Empty() = %+v", e)
+	}
+}
+
+func TestNilArena(t *testing.T) {
+	t.Parallel()
+
+	var a *Arena[int]
+	if p, q := a.New(), a.New(); p == q {
+		t.Errorf("nil arena returned the same pointer twice")
+	}
+	a.Reset()
+	if a.Empty() != nil || a.Chunks() != 0 || a.Len() != 0 || a.Cap() != 0 {
+		t.Errorf("nil arena Empty or Chunks wrong")
+	}
+}
@@ -0,0 +1,29 @@
+// Package memsize estimates the memory used by Go values, for the MemStats
+// methods of the containers.
+//
+// The estimates are shallow: they count the memory of the data structures
+// themselves, including elements stored inline, but not memory that
+// elements point to.
+package memsize
+
+import "unsafe"
+
+// Of returns the size in bytes of a value of type T.
+func Of[T any]() int64 {
+	var z T
+	return int64(unsafe.Sizeof(z))
+}
+
+// Slice returns the size of the backing array of a slice of capacity n.
+func Slice[E any](n int) int64 { return int64(n) * Of[E]() }
+
+// mapHeader approximates the fixed size of a map.
+const mapHeader = 48
+
+// Map estimates the size of a map with n entries. Maps store entries in
+// groups of eight slots with a control word, kept at most 7/8 full; since
+// maps do not shrink, a map that once held more entries uses more.
+func Map[K comparable, V any](n int) int64 {
+	groups := int64((n*8/7 + 7) / 8)
+	return mapHeader + groups*(8+8*(Of[K]()+Of[V]()))
+}
@@ -0,0 +1,25 @@
+package memsize
+
+import "testing"
+
+func TestSize(t *testing.T) {
+	t.Parallel()
+
+	if n := Of[int64](); n != 8 {
+		t.Errorf("Of[int64]() = %d, want 8", n)
+	}
+	if n := Of[struct{ a, b int32 }](); n != 8 {
+		t.Errorf("Of[struct{a, b int32}]() = %d, want 8", n)
+	}
+	if n := Slice[int32](10); n != 40 {
+		t.Errorf("Slice[int32](10) = %d, want 40", n)
+	}
+
+	if n := Map[int64, int64](0); n != mapHeader {
+		t.Errorf("Map(0) = %d, want %d", n, mapHeader)
+	}
+	small, large := Map[int64, int64](7), Map[int64, int64](7000)
+	if small != mapHeader+8+8*16 || large < 7000*16 || large > 7000*16*2 {
+		t.Errorf("Map(7), Map(7000) = %d, %d", small, large)
+	}
+}
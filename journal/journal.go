@@ -0,0 +1,120 @@
+// Package journal implements undo and redo for containers.
+//
+// A History records the mutations made through the wrappers of this
+// package, Stack and Deque, together with how to revert them. Mutations
+// between two checkpoints form one step that Undo reverts and Redo
+// reapplies as a whole, as in the history of an editor. Several wrappers
+// may share one History, so a step can span containers.
+//
+// The wrapped containers must only be changed through their wrappers while
+// the history is in use; changes made directly are not recorded and make
+// undoing unreliable.
+package journal
+
+// An action is a recorded mutation.
+type action struct {
+	undo, redo func()
+}
+
+// A History records steps of mutations that can be undone and redone.
+// The zero value is an empty history without limit.
+type History struct {
+	done   [][]action // completed steps, oldest first
+	cur    []action   // mutations since the last checkpoint
+	undone [][]action // undone steps, most recently undone last
+	limit  int
+}
+
+type option func(*History)
+
+// WithLimit limits the history to the n most recent steps.
+// It panics if n is not positive.
+func WithLimit(n int) option {
+	if n <= 0 {
+		panic("journal: non-positive limit")
+	}
+
+	return func(h *History) {
+		h.limit = n
+	}
+}
+
+// New returns an empty history.
+func New(opts ...option) *History {
+	h := &History{}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// record adds a mutation to the current step. Recording forgets the
+// undone steps, which can no longer be redone.
+func (h *History) record(undo, redo func()) {
+	h.cur = append(h.cur, action{undo, redo})
+	clear(h.undone)
+	h.undone = h.undone[:0]
+}
+
+// Checkpoint ends the current step. It does nothing if no mutations were
+// recorded since the last checkpoint.
+func (h *History) Checkpoint() {
+	if len(h.cur) == 0 {
+		return
+	}
+
+	h.done = append(h.done, h.cur)
+	h.cur = nil
+	if h.limit > 0 && len(h.done) > h.limit {
+		n := len(h.done) - h.limit
+		clear(h.done[:n])
+		h.done = h.done[n:]
+	}
+}
+
+// Undo ends the current step and reverts the most recent step.
+// It reports whether there was a step to undo.
+func (h *History) Undo() bool {
+	h.Checkpoint()
+	if len(h.done) == 0 {
+		return false
+	}
+
+	step := h.done[len(h.done)-1]
+	h.done[len(h.done)-1] = nil
+	h.done = h.done[:len(h.done)-1]
+	for i := len(step) - 1; i >= 0; i-- {
+		step[i].undo()
+	}
+	h.undone = append(h.undone, step)
+	return true
+}
+
+// Redo reapplies the most recently undone step.
+// It reports whether there was a step to redo.
+func (h *History) Redo() bool {
+	if len(h.undone) == 0 {
+		return false
+	}
+
+	step := h.undone[len(h.undone)-1]
+	h.undone[len(h.undone)-1] = nil
+	h.undone = h.undone[:len(h.undone)-1]
+	for _, a := range step {
+		a.redo()
+	}
+	h.done = append(h.done, step)
+	return true
+}
+
+// CanUndo reports whether Undo would revert a step.
+func (h *History) CanUndo() bool { return len(h.done) > 0 || len(h.cur) > 0 }
+
+// CanRedo reports whether Redo would reapply a step.
+func (h *History) CanRedo() bool { return len(h.undone) > 0 }
+
+// Reset forgets all recorded steps without changing the containers.
+func (h *History) Reset() {
+	h.done, h.cur, h.undone = nil, nil, nil
+}
@@ -0,0 +1,140 @@
+package journal
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Stack[int] = (*Stack[int])(nil)
+	_ container.Deque[int] = (*Deque[int])(nil)
+)
+
+// deque is a slice-backed container.Deque and container.Stack.
+type deque []int
+
+func (d *deque) Len() int          { return len(*d) }
+func (d *deque) Clear()            { *d = nil }
+func (d *deque) PushFront(v int)   { *d = slices.Insert(*d, 0, v) }
+func (d *deque) PushBack(v int)    { *d = append(*d, v) }
+func (d *deque) Push(v int)        { d.PushBack(v) }
+func (d *deque) Pop() (int, bool)  { return d.PopBack() }
+func (d *deque) Peek() (int, bool) { return d.PeekBack() }
+
+func (d *deque) PopFront() (int, bool) {
+	v, ok := d.PeekFront()
+	if ok {
+		*d = (*d)[1:]
+	}
+	return v, ok
+}
+
+func (d *deque) PopBack() (int, bool) {
+	v, ok := d.PeekBack()
+	if ok {
+		*d = (*d)[:len(*d)-1]
+	}
+	return v, ok
+}
+
+func (d *deque) PeekFront() (int, bool) {
+	if len(*d) == 0 {
+		return 0, false
+	}
+	return (*d)[0], true
+}
+
+func (d *deque) PeekBack() (int, bool) {
+	if len(*d) == 0 {
+		return 0, false
+	}
+	return (*d)[len(*d)-1], true
+}
+
+func TestUndoRedo(t *testing.T) {
+	t.Parallel()
+
+	h := New()
+	var raw deque
+	d := NewDeque(h, &raw)
+
+	check := func(want ...int) {
+		t.Helper()
+		if !slices.Equal(raw, want) {
+			t.Fatalf("deque = %v, want %v", raw, want)
+		}
+	}
+
+	d.PushBack(1)
+	d.PushBack(2)
+	h.Checkpoint()
+	d.PushFront(0)
+	d.PopBack()
+	h.Checkpoint()
+	d.Clear()
+	check()
+
+	if !h.Undo() {
+		t.Fatal("h.Undo() = false")
+	}
+	check(0, 1)
+	h.Undo()
+	check(1, 2)
+	h.Undo()
+	check()
+	if h.Undo() || h.CanUndo() {
+		t.Errorf("undo of empty history succeeded")
+	}
+
+	h.Redo()
+	check(1, 2)
+	h.Redo()
+	check(0, 1)
+
+	// a new mutation forgets the undone steps
+	d.PopFront()
+	if h.CanRedo() || h.Redo() {
+		t.Errorf("redo after a new mutation succeeded")
+	}
+	check(1)
+	h.Undo()
+	check(0, 1)
+}
+
+func TestShared(t *testing.T) {
+	t.Parallel()
+
+	h := New(WithLimit(2))
+	var a, b deque
+	s := NewStack(h, &a)
+	d := NewDeque(h, &b)
+
+	for i := range 4 {
+		s.Push(i)
+		d.PushFront(i)
+		h.Checkpoint()
+	}
+	if v, _ := s.Pop(); v != 3 {
+		t.Errorf("s.Pop() = %d, want 3", v)
+	}
+
+	// the Pop and the last Push are kept, the older steps are forgotten
+	for h.Undo() {
+	}
+	if want := []int{0, 1, 2}; !slices.Equal(a, want) || !slices.Equal(b, []int{2, 1, 0}) {
+		t.Errorf("after undoing all = %v, %v, want %v, [2 1 0]", a, b, want)
+	}
+
+	s.Clear()
+	h.Undo()
+	if want := []int{0, 1, 2}; !slices.Equal(a, want) {
+		t.Errorf("undo of Clear = %v, want %v", a, want)
+	}
+
+	h.Reset()
+	if h.CanUndo() || h.CanRedo() {
+		t.Errorf("history not empty after Reset")
+	}
+}
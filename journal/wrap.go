@@ -0,0 +1,122 @@
+package journal
+
+import "github.com/weiwenchen2022/container"
+
+// Stack is a container.Stack whose mutations are recorded in a History.
+type Stack[E any] struct {
+	s container.Stack[E]
+	h *History
+}
+
+// NewStack returns a stack that changes s and records the changes in h.
+func NewStack[E any](h *History, s container.Stack[E]) *Stack[E] {
+	return &Stack[E]{s: s, h: h}
+}
+
+// Len returns the number of elements in the stack.
+func (s *Stack[E]) Len() int { return s.s.Len() }
+
+// Peek returns the element at the top of the stack.
+func (s *Stack[E]) Peek() (E, bool) { return s.s.Peek() }
+
+// Push adds v at the top of the stack.
+func (s *Stack[E]) Push(v E) {
+	s.s.Push(v)
+	s.h.record(func() { s.s.Pop() }, func() { s.s.Push(v) })
+}
+
+// Pop removes and returns the element at the top of the stack.
+func (s *Stack[E]) Pop() (E, bool) {
+	v, ok := s.s.Pop()
+	if ok {
+		s.h.record(func() { s.s.Push(v) }, func() { s.s.Pop() })
+	}
+
+	return v, ok
+}
+
+// Clear removes all elements from the stack.
+func (s *Stack[E]) Clear() {
+	var vs []E
+	for v, ok := s.s.Pop(); ok; v, ok = s.s.Pop() {
+		vs = append(vs, v)
+	}
+	if len(vs) == 0 {
+		return
+	}
+
+	s.h.record(func() {
+		for i := len(vs) - 1; i >= 0; i-- {
+			s.s.Push(vs[i])
+		}
+	}, s.s.Clear)
+}
+
+// Deque is a container.Deque whose mutations are recorded in a History.
+type Deque[E any] struct {
+	d container.Deque[E]
+	h *History
+}
+
+// NewDeque returns a deque that changes d and records the changes in h.
+func NewDeque[E any](h *History, d container.Deque[E]) *Deque[E] {
+	return &Deque[E]{d: d, h: h}
+}
+
+// Len returns the number of elements in the deque.
+func (d *Deque[E]) Len() int { return d.d.Len() }
+
+// PeekFront returns the element at the front of the deque.
+func (d *Deque[E]) PeekFront() (E, bool) { return d.d.PeekFront() }
+
+// PeekBack returns the element at the back of the deque.
+func (d *Deque[E]) PeekBack() (E, bool) { return d.d.PeekBack() }
+
+// PushFront adds v at the front of the deque.
+func (d *Deque[E]) PushFront(v E) {
+	d.d.PushFront(v)
+	d.h.record(func() { d.d.PopFront() }, func() { d.d.PushFront(v) })
+}
+
+// PushBack adds v at the back of the deque.
+func (d *Deque[E]) PushBack(v E) {
+	d.d.PushBack(v)
+	d.h.record(func() { d.d.PopBack() }, func() { d.d.PushBack(v) })
+}
+
+// PopFront removes and returns the element at the front of the deque.
+func (d *Deque[E]) PopFront() (E, bool) {
+	v, ok := d.d.PopFront()
+	if ok {
+		d.h.record(func() { d.d.PushFront(v) }, func() { d.d.PopFront() })
+	}
+
+	return v, ok
+}
+
+// PopBack removes and returns the element at the back of the deque.
+func (d *Deque[E]) PopBack() (E, bool) {
+	v, ok := d.d.PopBack()
+	if ok {
+		d.h.record(func() { d.d.PushBack(v) }, func() { d.d.PopBack() })
+	}
+
+	return v, ok
+}
+
+// Clear removes all elements from the deque.
+func (d *Deque[E]) Clear() {
+	var vs []E
+	for v, ok := d.d.PopFront(); ok; v, ok = d.d.PopFront() {
+		vs = append(vs, v)
+	}
+	if len(vs) == 0 {
+		return
+	}
+
+	d.h.record(func() {
+		for _, v := range vs {
+			d.d.PushBack(v)
+		}
+	}, d.d.Clear)
+}
@@ -0,0 +1,119 @@
+package list
+
+import "github.com/weiwenchen2022/container"
+
+// AsDeque returns a view of list l as a container.Deque, with the front of
+// the deque at the front of the list. Changes through the view change l
+// and changes to l are visible through the view.
+func AsDeque[E any](l *List[E]) container.Deque[E] { return deque[E]{l} }
+
+// AsQueue returns a view of list l as a queue, with the front of the
+// queue at the front of the list.
+func AsQueue[E any](l *List[E]) *Queue[E] { return &Queue[E]{l} }
+
+// AsStack returns a view of list l as a stack, with the top of the stack
+// at the back of the list.
+func AsStack[E any](l *List[E]) *Stack[E] { return &Stack[E]{l} }
+
+// popFront removes and returns the value at the front of l, if any.
+func (l *List[E]) popFront() (v E, ok bool) {
+	if e := l.Front(); e != nil {
+		return l.Remove(e), true
+	}
+
+	return v, false
+}
+
+func (l *List[E]) popBack() (v E, ok bool) {
+	if e := l.Back(); e != nil {
+		return l.Remove(e), true
+	}
+
+	return v, false
+}
+
+func (l *List[E]) peekFront() (v E, ok bool) {
+	if e := l.Front(); e != nil {
+		return e.Value, true
+	}
+
+	return v, false
+}
+
+func (l *List[E]) peekBack() (v E, ok bool) {
+	if e := l.Back(); e != nil {
+		return e.Value, true
+	}
+
+	return v, false
+}
+
+type deque[E any] struct{ l *List[E] }
+
+func (d deque[E]) Len() int                  { return d.l.Len() }
+func (d deque[E]) Clear()                    { d.l.Clear() }
+func (d deque[E]) PushFront(v E)             { d.l.PushFront(v) }
+func (d deque[E]) PushBack(v E)              { d.l.PushBack(v) }
+func (d deque[E]) PopFront() (v E, ok bool)  { return d.l.popFront() }
+func (d deque[E]) PopBack() (v E, ok bool)   { return d.l.popBack() }
+func (d deque[E]) PeekFront() (v E, ok bool) { return d.l.peekFront() }
+func (d deque[E]) PeekBack() (v E, ok bool)  { return d.l.peekBack() }
+
+// Queue is a first-in, first-out queue backed by a List, so its elements
+// keep stable addresses while they are queued. It is a container.Queue.
+// To create a queue use list.NewQueue or list.AsQueue.
+type Queue[E any] struct{ l *List[E] }
+
+// NewQueue returns an empty queue backed by a new list created with the
+// options opts.
+func NewQueue[E any](opts ...option[E]) *Queue[E] { return AsQueue(New(opts...)) }
+
+// List returns the list backing queue q, front first.
+func (q *Queue[E]) List() *List[E] { return q.l }
+
+// Len returns the number of values in queue q.
+func (q *Queue[E]) Len() int { return q.l.Len() }
+
+// Clear removes all values from queue q.
+func (q *Queue[E]) Clear() { q.l.Clear() }
+
+// Enqueue adds v at the back of queue q.
+func (q *Queue[E]) Enqueue(v E) { q.l.PushBack(v) }
+
+// Dequeue removes and returns the value at the front of queue q.
+// The ok result reports whether q was non-empty.
+func (q *Queue[E]) Dequeue() (v E, ok bool) { return q.l.popFront() }
+
+// Peek returns the value at the front of queue q without removing it.
+// The ok result reports whether q is non-empty.
+func (q *Queue[E]) Peek() (v E, ok bool) { return q.l.peekFront() }
+
+// Stack is a last-in, first-out stack backed by a List, so its elements
+// keep stable addresses while they are on the stack. It is a
+// container.Stack.
+// To create a stack use list.NewStack or list.AsStack.
+type Stack[E any] struct{ l *List[E] }
+
+// NewStack returns an empty stack backed by a new list created with the
+// options opts.
+func NewStack[E any](opts ...option[E]) *Stack[E] { return AsStack(New(opts...)) }
+
+// List returns the list backing stack s, top last.
+func (s *Stack[E]) List() *List[E] { return s.l }
+
+// Len returns the number of values on stack s.
+func (s *Stack[E]) Len() int { return s.l.Len() }
+
+// Clear removes all values from stack s.
+func (s *Stack[E]) Clear() { s.l.Clear() }
+
+// Push adds v at the top of stack s.
+func (s *Stack[E]) Push(v E) { s.l.PushBack(v) }
+
+// Pop removes and returns the value at the top of stack s.
+// The ok result reports whether s was non-empty.
+func (s *Stack[E]) Pop() (v E, ok bool) { return s.l.popBack() }
+
+// Peek returns the value at the top of stack s without removing it.
+// The ok result reports whether s is non-empty.
+func (s *Stack[E]) Peek() (v E, ok bool) { return s.l.peekBack() }
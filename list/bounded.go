@@ -0,0 +1,61 @@
+package list
+
+// EvictPolicy selects the end of a list with a maximum length from which
+// elements are evicted when an insertion makes it too long.
+type EvictPolicy int
+
+const (
+	// EvictOpposite evicts from the back after an insertion at the front,
+	// and from the front after any other insertion, so that a list used as
+	// a history keeps the most recent values.
+	EvictOpposite EvictPolicy = iota
+
+	// EvictFront always evicts from the front.
+	EvictFront
+
+	// EvictBack always evicts from the back. Pushing to the back of a full
+	// list then evicts the pushed element itself.
+	EvictBack
+)
+
+// WithMaxLen limits the list to n elements. When an insertion makes the
+// list longer, elements are removed from the end chosen by policy until it
+// is n elements long again. An insertion of several values evicts after
+// inserting all of them, so it may evict some of the inserted elements;
+// an insert method may therefore return an element no longer in the list.
+// It panics if n < 1.
+func WithMaxLen[E any](n int, policy EvictPolicy) option[E] {
+	if n < 1 {
+		panic("list: non-positive max length")
+	}
+
+	return func(l *List[E]) {
+		l.maxLen = n
+		l.evict = policy
+	}
+}
+
+// WithOnEvict sets a function called with the value of each element
+// evicted by a list with a maximum length, after the observer set by
+// WithOnRemove.
+func WithOnEvict[E any](f func(E)) option[E] {
+	return func(l *List[E]) {
+		l.onEvict = f
+	}
+}
+
+// trim evicts elements until list l is no longer than its maximum length.
+// front reports whether the insertion that made it longer was at the front.
+func (l *List[E]) trim(front bool) {
+	for l.maxLen > 0 && l.len > l.maxLen {
+		e := l.root.next
+		if l.evict == EvictBack || l.evict == EvictOpposite && front {
+			e = l.root.prev
+		}
+
+		v := l.Remove(e)
+		if l.onEvict != nil {
+			l.onEvict(v)
+		}
+	}
+}
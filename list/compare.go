@@ -0,0 +1,54 @@
+package list
+
+import "cmp"
+
+// Equal reports whether lists a and b have the same length and equal
+// values in the same order.
+func Equal[E comparable](a, b *List[E]) bool {
+	return EqualFunc(a, b, func(x, y E) bool { return x == y })
+}
+
+// EqualFunc reports whether lists a and b have the same length and values
+// equal in the same order as reported by eq.
+func EqualFunc[E1, E2 any](a *List[E1], b *List[E2], eq func(E1, E2) bool) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+
+	for x, y := a.Front(), b.Front(); x != nil; x, y = x.Next(), y.Next() {
+		if !eq(x.Value, y.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compare compares the values of lists a and b lexicographically, using
+// cmp.Compare on each pair of values, like slices.Compare. The result is 0
+// if a == b, -1 if a < b and +1 if a > b.
+func Compare[E cmp.Ordered](a, b *List[E]) int {
+	return CompareFunc(a, b, cmp.Compare[E])
+}
+
+// CompareFunc is like Compare but uses a custom comparison function on
+// each pair of values. The result is the first non-zero result of cmp; if
+// cmp always returns 0 the result is 0 if the lists have the same length,
+// -1 if a is shorter and +1 if a is longer.
+func CompareFunc[E1, E2 any](a *List[E1], b *List[E2], cmp func(E1, E2) int) int {
+	x, y := a.Front(), b.Front()
+	for ; x != nil && y != nil; x, y = x.Next(), y.Next() {
+		if c := cmp(x.Value, y.Value); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case x != nil:
+		return +1
+	case y != nil:
+		return -1
+	}
+
+	return 0
+}
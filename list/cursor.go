@@ -0,0 +1,188 @@
+package list
+
+// Cursor is a position in a list that stays valid while the list changes.
+//
+// A cursor is either at an element or in the gap between two adjacent
+// positions, such as after its element was removed or after it moved past
+// either end of the list. Elements may be inserted anywhere and removed
+// anywhere but at the cursor through the list while a cursor is in use;
+// the element at the cursor must be removed with Cursor.Remove. If both
+// elements around the gap of a cursor are removed, the cursor loses its
+// position: Next and Prev report false and leave it in place, and
+// InsertBefore and InsertAfter panic.
+//
+// To remove elements while iterating (where l is a *List):
+//
+//	for c := l.CursorFront(); c.Valid(); {
+//		if drop(c.Element().Value) {
+//			c.Remove()
+//		}
+//		c.Next()
+//	}
+type Cursor[E any] struct {
+	l   *List[E]
+	cur *Element[E] // element at the cursor, or nil in a gap
+
+	// In a gap, the elements before and after it; nil stands for the
+	// front or back of the list. Either may have been removed since.
+	prev, next *Element[E]
+}
+
+// CursorFront returns a cursor at the front element of list l, or before
+// the front of l if it is empty.
+func (l *List[E]) CursorFront() *Cursor[E] {
+	return &Cursor[E]{l: l, cur: l.Front()}
+}
+
+// CursorBack returns a cursor at the back element of list l, or after the
+// back of l if it is empty.
+func (l *List[E]) CursorBack() *Cursor[E] {
+	return &Cursor[E]{l: l, cur: l.Back()}
+}
+
+// Valid reports whether cursor c is at an element.
+func (c *Cursor[E]) Valid() bool { return c.cur != nil }
+
+// Element returns the element at cursor c, or nil if c is in a gap.
+func (c *Cursor[E]) Element() *Element[E] { return c.cur }
+
+// valid reports whether e, a neighbour of a gap, is still usable.
+func (c *Cursor[E]) valid(e *Element[E]) bool { return e == nil || e.list() == c.l }
+
+// lost reports whether c is in a gap whose neighbours have both been
+// removed, so that its position is unknown.
+func (c *Cursor[E]) lost() bool {
+	return c.cur == nil && !c.valid(c.prev) && !c.valid(c.next)
+}
+
+// after returns the element following the gap c is in.
+// c must not be lost.
+func (c *Cursor[E]) after() *Element[E] {
+	switch {
+	case c.valid(c.prev) && c.prev == nil:
+		return c.l.Front()
+	case c.valid(c.prev):
+		return c.prev.Next()
+	}
+
+	return c.next
+}
+
+// before returns the element preceding the gap c is in.
+// c must not be lost.
+func (c *Cursor[E]) before() *Element[E] {
+	switch {
+	case c.valid(c.next) && c.next == nil:
+		return c.l.Back()
+	case c.valid(c.next):
+		return c.next.Prev()
+	}
+
+	return c.prev
+}
+
+// Next moves cursor c to the next element and reports whether there is
+// one. Past the back of the list c is in the gap after it.
+func (c *Cursor[E]) Next() bool {
+	if c.lost() {
+		return false
+	}
+
+	var e *Element[E]
+	if c.cur != nil {
+		e = c.cur.Next()
+		if e == nil {
+			c.prev, c.next = c.cur, nil
+		}
+	} else {
+		e = c.after()
+		if e == nil {
+			c.prev, c.next = c.l.Back(), nil
+		}
+	}
+
+	c.cur = e
+	return e != nil
+}
+
+// Prev moves cursor c to the previous element and reports whether there
+// is one. Past the front of the list c is in the gap before it.
+func (c *Cursor[E]) Prev() bool {
+	if c.lost() {
+		return false
+	}
+
+	var e *Element[E]
+	if c.cur != nil {
+		e = c.cur.Prev()
+		if e == nil {
+			c.prev, c.next = nil, c.cur
+		}
+	} else {
+		e = c.before()
+		if e == nil {
+			c.prev, c.next = nil, c.l.Front()
+		}
+	}
+
+	c.cur = e
+	return e != nil
+}
+
+// Remove removes the element at cursor c from the list and returns its
+// value. The cursor is left in the gap where the element was, so Next and
+// Prev move to its former neighbours.
+// It panics if c is not at an element.
+func (c *Cursor[E]) Remove() E {
+	if c.cur == nil {
+		panic("list: cursor not at an element")
+	}
+
+	e := c.cur
+	c.prev, c.next, c.cur = e.Prev(), e.Next(), nil
+	return c.l.Remove(e)
+}
+
+// InsertBefore inserts a new element with value v before cursor c and
+// returns it. Prev moves to the new element next.
+// It panics if c has lost its position.
+func (c *Cursor[E]) InsertBefore(v E) *Element[E] {
+	if c.cur != nil {
+		return c.l.InsertBefore(v, c.cur)
+	}
+	if c.lost() {
+		panic("list: cursor position lost")
+	}
+
+	var e *Element[E]
+	if at := c.after(); at != nil {
+		e = c.l.InsertBefore(v, at)
+	} else {
+		e = c.l.PushBack(v)
+	}
+
+	c.prev = e
+	return e
+}
+
+// InsertAfter inserts a new element with value v after cursor c and
+// returns it. Next moves to the new element next.
+// It panics if c has lost its position.
+func (c *Cursor[E]) InsertAfter(v E) *Element[E] {
+	if c.cur != nil {
+		return c.l.InsertAfter(v, c.cur)
+	}
+	if c.lost() {
+		panic("list: cursor position lost")
+	}
+
+	var e *Element[E]
+	if at := c.before(); at != nil {
+		e = c.l.InsertAfter(v, at)
+	} else {
+		e = c.l.PushFront(v)
+	}
+
+	c.next = e
+	return e
+}
@@ -0,0 +1,15 @@
+package list
+
+import "fmt"
+
+// String returns the values of list l from front to back in the format
+// of a slice, such as "[a b c]".
+func (l List[E]) String() string {
+	return fmt.Sprint(l.ToSlice())
+}
+
+// GoString returns Go syntax that builds a copy of list l, such as
+// `list.NewFromSlice([]string{"a", "b", "c"})`, for the %#v verb.
+func (l List[E]) GoString() string {
+	return fmt.Sprintf("list.NewFromSlice(%#v)", l.ToSlice())
+}
@@ -0,0 +1,37 @@
+package list
+
+// Map returns a new list of the results of f applied to the values of
+// list l, in the same order.
+func Map[In, Out any](l *List[In], f func(In) Out) *List[Out] {
+	m := New[Out]()
+	for e := l.Front(); e != nil; e = e.Next() {
+		m.PushBack(f(e.Value))
+	}
+
+	return m
+}
+
+// Filter returns a new list of the values of list l for which keep
+// returns true, in the same order.
+func Filter[E any](l *List[E], keep func(E) bool) *List[E] {
+	f := New[E]()
+	for e := l.Front(); e != nil; e = e.Next() {
+		if keep(e.Value) {
+			f.PushBack(e.Value)
+		}
+	}
+
+	return f
+}
+
+// Reduce combines the values of list l from front to back, starting from
+// init, by calling f with the accumulated result and each value, and
+// returns the final result.
+func Reduce[E, A any](l *List[E], init A, f func(acc A, v E) A) A {
+	acc := init
+	for e := l.Front(); e != nil; e = e.Next() {
+		acc = f(acc, e.Value)
+	}
+
+	return acc
+}
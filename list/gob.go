@@ -0,0 +1,63 @@
+package list
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+// GobEncode implements the gob.GobEncoder interface. A list is encoded as
+// its length followed by its values from front to back, each encoded by
+// gob, so interface values must be registered with gob.Register.
+func (l List[E]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(l.len); err != nil {
+		return nil, err
+	}
+
+	for e := l.Front(); e != nil; e = e.Next() {
+		if err := enc.Encode(e.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface. It replaces the
+// contents of list l with the values of a list encoded by GobEncode.
+// If decoding fails l is not modified.
+func (l *List[E]) GobDecode(data []byte) error {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+
+	var n int
+	if err := dec.Decode(&n); err != nil {
+		return err
+	}
+	// Every value takes at least one byte, which bounds a valid length.
+	if n < 0 || n > len(data) {
+		return errors.New("list: invalid gob length")
+	}
+
+	var vs []E
+	for range n {
+		var v E
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		vs = append(vs, v)
+	}
+
+	l.Clear()
+	l.PushBackSlice(vs)
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, with
+// the encoding of GobEncode.
+func (l List[E]) MarshalBinary() ([]byte, error) { return l.GobEncode() }
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// like GobDecode.
+func (l *List[E]) UnmarshalBinary(data []byte) error { return l.GobDecode(data) }
@@ -0,0 +1,228 @@
+package list
+
+import "iter"
+
+// IndexedList is a list with an index from the key of each value to its
+// element, so elements can be found, moved and removed by key in O(1)
+// time. It offers the methods of List that keep the index up to date.
+//
+// Keys are unique: inserting a value whose key is already in the list
+// removes the element that had it, once the insertion is complete. The key
+// of a value must not change while it is in the list.
+// To create an indexed list use list.NewIndexed.
+type IndexedList[K comparable, E any] struct {
+	l *List[E]
+
+	key   func(E) K
+	index map[K]*Element[E]
+
+	// elements whose key was taken by an inserted element, to be removed
+	// by resolve
+	stale []*Element[E]
+}
+
+// NewIndexed returns an empty indexed list keying values by key, whose
+// list is created with the options opts. Observers set by WithOnInsert and
+// WithOnRemove are called after the index is updated.
+func NewIndexed[K comparable, E any](key func(E) K, opts ...option[E]) *IndexedList[K, E] {
+	l := &IndexedList[K, E]{l: New(opts...), key: key, index: make(map[K]*Element[E])}
+
+	onInsert, onRemove := l.l.onInsert, l.l.onRemove
+	l.l.onInsert = func(e *Element[E]) {
+		// Removing the old element now could pull it from under the
+		// method inserting e, so it is only noted here.
+		k := key(e.Value)
+		if old, ok := l.index[k]; ok {
+			l.stale = append(l.stale, old)
+		}
+		l.index[k] = e
+
+		if onInsert != nil {
+			onInsert(e)
+		}
+	}
+	l.l.onRemove = func(v E) {
+		// The key may already index a newer element.
+		k := key(v)
+		if e, ok := l.index[k]; ok && e.list() != l.l {
+			delete(l.index, k)
+		}
+
+		if onRemove != nil {
+			onRemove(v)
+		}
+	}
+
+	return l
+}
+
+// resolve removes the elements whose keys were taken by later insertions.
+func (l *IndexedList[K, E]) resolve() {
+	for _, e := range l.stale {
+		if e.list() == l.l && l.index[l.key(e.Value)] != e {
+			l.l.Remove(e)
+		}
+	}
+
+	clear(l.stale)
+	l.stale = l.stale[:0]
+}
+
+// Len returns the number of elements of list l.
+func (l *IndexedList[K, E]) Len() int { return l.l.Len() }
+
+// Init initializes or clears list l.
+func (l *IndexedList[K, E]) Init() *IndexedList[K, E] {
+	l.l.Init()
+	clear(l.index)
+	return l
+}
+
+// Clear removes all elements from list l, like List.Clear.
+func (l *IndexedList[K, E]) Clear() {
+	l.l.Clear()
+	clear(l.index)
+}
+
+// Front returns the first element of list l or nil if the list is empty.
+func (l *IndexedList[K, E]) Front() *Element[E] { return l.l.Front() }
+
+// Back returns the last element of list l or nil if the list is empty.
+func (l *IndexedList[K, E]) Back() *Element[E] { return l.l.Back() }
+
+// All returns an iterator over the elements of list l from front to back,
+// like List.All.
+func (l *IndexedList[K, E]) All() iter.Seq[*Element[E]] { return l.l.All() }
+
+// Backward returns an iterator over the elements of list l from back to
+// front, like List.Backward.
+func (l *IndexedList[K, E]) Backward() iter.Seq[*Element[E]] { return l.l.Backward() }
+
+// Values returns an iterator over the values of list l from front to back.
+func (l *IndexedList[K, E]) Values() iter.Seq[E] { return l.l.Values() }
+
+// ToSlice returns a new slice of the values of list l from front to back.
+func (l *IndexedList[K, E]) ToSlice() []E { return l.l.ToSlice() }
+
+// PushFront inserts a new element e with value v at the front of list l
+// and returns e.
+func (l *IndexedList[K, E]) PushFront(v E) *Element[E] {
+	defer l.resolve()
+	return l.l.PushFront(v)
+}
+
+// PushBack inserts a new element e with value v at the back of list l and
+// returns e.
+func (l *IndexedList[K, E]) PushBack(v E) *Element[E] {
+	defer l.resolve()
+	return l.l.PushBack(v)
+}
+
+// InsertBefore inserts a new element e with value v immediately before
+// mark and returns e, like List.InsertBefore.
+func (l *IndexedList[K, E]) InsertBefore(v E, mark *Element[E]) *Element[E] {
+	defer l.resolve()
+	return l.l.InsertBefore(v, mark)
+}
+
+// InsertAfter inserts a new element e with value v immediately after mark
+// and returns e, like List.InsertAfter.
+func (l *IndexedList[K, E]) InsertAfter(v E, mark *Element[E]) *Element[E] {
+	defer l.resolve()
+	return l.l.InsertAfter(v, mark)
+}
+
+// PushBackSlice inserts the values vs at the back of list l.
+func (l *IndexedList[K, E]) PushBackSlice(vs []E) {
+	defer l.resolve()
+	l.l.PushBackSlice(vs)
+}
+
+// PushFrontSlice inserts the values vs at the front of list l.
+func (l *IndexedList[K, E]) PushFrontSlice(vs []E) {
+	defer l.resolve()
+	l.l.PushFrontSlice(vs)
+}
+
+// PushBackList inserts a copy of list other at the back of list l.
+func (l *IndexedList[K, E]) PushBackList(other *List[E]) {
+	defer l.resolve()
+	l.l.PushBackList(other)
+}
+
+// PushFrontList inserts a copy of list other at the front of list l.
+func (l *IndexedList[K, E]) PushFrontList(other *List[E]) {
+	defer l.resolve()
+	l.l.PushFrontList(other)
+}
+
+// MergeSorted moves all elements of list other into list l, like
+// List.MergeSorted, and then removes the elements whose keys the moved
+// elements took.
+func (l *IndexedList[K, E]) MergeSorted(other *List[E], less func(a, b E) bool) {
+	defer l.resolve()
+	l.l.MergeSorted(other, less)
+}
+
+// Remove removes e from l if e is an element of list l and returns its
+// value, like List.Remove.
+func (l *IndexedList[K, E]) Remove(e *Element[E]) E { return l.l.Remove(e) }
+
+// RemoveFunc removes the elements of list l whose values satisfy pred and
+// returns the number removed.
+func (l *IndexedList[K, E]) RemoveFunc(pred func(E) bool) int { return l.l.RemoveFunc(pred) }
+
+// MoveToFront moves element e to the front of list l.
+func (l *IndexedList[K, E]) MoveToFront(e *Element[E]) { l.l.MoveToFront(e) }
+
+// MoveToBack moves element e to the back of list l.
+func (l *IndexedList[K, E]) MoveToBack(e *Element[E]) { l.l.MoveToBack(e) }
+
+// MoveBefore moves element e to its new position before mark.
+func (l *IndexedList[K, E]) MoveBefore(e, mark *Element[E]) { l.l.MoveBefore(e, mark) }
+
+// MoveAfter moves element e to its new position after mark.
+func (l *IndexedList[K, E]) MoveAfter(e, mark *Element[E]) { l.l.MoveAfter(e, mark) }
+
+// GetByKey returns the element whose value has key k, or nil if there is
+// none.
+func (l *IndexedList[K, E]) GetByKey(k K) *Element[E] { return l.index[k] }
+
+// ContainsKey reports whether list l has a value with key k.
+func (l *IndexedList[K, E]) ContainsKey(k K) bool {
+	_, ok := l.index[k]
+	return ok
+}
+
+// MoveToFrontByKey moves the element whose value has key k to the front of
+// list l and reports whether there was one.
+func (l *IndexedList[K, E]) MoveToFrontByKey(k K) bool {
+	e, ok := l.index[k]
+	if ok {
+		l.MoveToFront(e)
+	}
+
+	return ok
+}
+
+// MoveToBackByKey moves the element whose value has key k to the back of
+// list l and reports whether there was one.
+func (l *IndexedList[K, E]) MoveToBackByKey(k K) bool {
+	e, ok := l.index[k]
+	if ok {
+		l.MoveToBack(e)
+	}
+
+	return ok
+}
+
+// RemoveByKey removes the element whose value has key k and returns its
+// value. The ok result reports whether there was one.
+func (l *IndexedList[K, E]) RemoveByKey(k K) (v E, ok bool) {
+	e, ok := l.index[k]
+	if !ok {
+		return v, false
+	}
+
+	return l.Remove(e), true
+}
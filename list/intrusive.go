@@ -0,0 +1,195 @@
+package list
+
+import "iter"
+
+// Hook links the value containing it into an Intrusive list. A value type
+// embeds one Hook for each Intrusive list the value can be in at the same
+// time. The zero value is an unlinked hook.
+// A Hook must not be copied while it is linked.
+type Hook[T any] struct {
+	next, prev *Hook[T]
+	value      *T
+	list       *Intrusive[T]
+}
+
+// Linked reports whether the value containing hook h is in a list.
+func (h *Hook[T]) Linked() bool { return h.list != nil }
+
+// Intrusive is a doubly linked list of values of type *T that links them
+// through a Hook inside each value, so inserting a value allocates nothing
+// and a value can be in several lists through several hooks.
+//
+// For example, an item that is both in a queue and in an LRU list:
+//
+//	type item struct {
+//		queue, lru list.Hook[item]
+//		key        string
+//	}
+//
+//	q := list.NewIntrusive(func(x *item) *list.Hook[item] { return &x.queue })
+//	r := list.NewIntrusive(func(x *item) *list.Hook[item] { return &x.lru })
+//
+// To create an intrusive list use list.NewIntrusive.
+type Intrusive[T any] struct {
+	root Hook[T] // sentinel, as in List
+	len  int
+	hook func(*T) *Hook[T]
+}
+
+// NewIntrusive returns an empty intrusive list that links values through
+// the hook returned by hook.
+func NewIntrusive[T any](hook func(*T) *Hook[T]) *Intrusive[T] {
+	l := &Intrusive[T]{hook: hook}
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	return l
+}
+
+// Len returns the number of values in list l.
+// The complexity is O(1).
+func (l *Intrusive[T]) Len() int { return l.len }
+
+// Clear removes all values from list l, unlinking their hooks so that the
+// values can be inserted again. The complexity is O(n).
+func (l *Intrusive[T]) Clear() {
+	for h := l.root.next; h != &l.root; {
+		next := h.next
+		*h = Hook[T]{}
+		h = next
+	}
+
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	l.len = 0
+}
+
+// Contains reports whether x is in list l.
+func (l *Intrusive[T]) Contains(x *T) bool { return l.hook(x).list == l }
+
+// value returns the value containing h, or nil if h is the sentinel.
+func (l *Intrusive[T]) value(h *Hook[T]) *T {
+	if h == &l.root {
+		return nil
+	}
+
+	return h.value
+}
+
+// Front returns the first value of list l or nil if the list is empty.
+func (l *Intrusive[T]) Front() *T { return l.value(l.root.next) }
+
+// Back returns the last value of list l or nil if the list is empty.
+func (l *Intrusive[T]) Back() *T { return l.value(l.root.prev) }
+
+// Next returns the value after x in list l or nil.
+// x must be in l.
+func (l *Intrusive[T]) Next(x *T) *T { return l.value(l.hook(x).next) }
+
+// Prev returns the value before x in list l or nil.
+// x must be in l.
+func (l *Intrusive[T]) Prev(x *T) *T { return l.value(l.hook(x).prev) }
+
+// link inserts x after at.
+// It panics if x is already in a list through the hook.
+func (l *Intrusive[T]) link(x *T, at *Hook[T]) {
+	h := l.hook(x)
+	if h.list != nil {
+		panic("list: value already in a list")
+	}
+
+	h.value = x
+	h.list = l
+	h.prev = at
+	h.next = at.next
+	at.next.prev = h
+	at.next = h
+	l.len++
+}
+
+func (l *Intrusive[T]) unlink(h *Hook[T]) {
+	h.prev.next = h.next
+	h.next.prev = h.prev
+	*h = Hook[T]{}
+	l.len--
+}
+
+// PushFront inserts x at the front of list l.
+// It panics if x is already in a list through the hook.
+func (l *Intrusive[T]) PushFront(x *T) { l.link(x, &l.root) }
+
+// PushBack inserts x at the back of list l.
+// It panics if x is already in a list through the hook.
+func (l *Intrusive[T]) PushBack(x *T) { l.link(x, l.root.prev) }
+
+// InsertBefore inserts x immediately before mark.
+// If mark is not in l, the list is not modified.
+// It panics if x is already in a list through the hook.
+func (l *Intrusive[T]) InsertBefore(x, mark *T) {
+	if m := l.hook(mark); m.list == l {
+		l.link(x, m.prev)
+	}
+}
+
+// InsertAfter inserts x immediately after mark.
+// If mark is not in l, the list is not modified.
+// It panics if x is already in a list through the hook.
+func (l *Intrusive[T]) InsertAfter(x, mark *T) {
+	if m := l.hook(mark); m.list == l {
+		l.link(x, m)
+	}
+}
+
+// Remove removes x from list l and reports whether it was in l.
+func (l *Intrusive[T]) Remove(x *T) bool {
+	h := l.hook(x)
+	if h.list != l {
+		return false
+	}
+
+	l.unlink(h)
+	return true
+}
+
+// MoveToFront moves x to the front of list l.
+// If x is not in l, the list is not modified.
+func (l *Intrusive[T]) MoveToFront(x *T) {
+	if l.Remove(x) {
+		l.PushFront(x)
+	}
+}
+
+// MoveToBack moves x to the back of list l.
+// If x is not in l, the list is not modified.
+func (l *Intrusive[T]) MoveToBack(x *T) {
+	if l.Remove(x) {
+		l.PushBack(x)
+	}
+}
+
+// Values returns an iterator over the values of list l from front to
+// back. The value being yielded may be removed from l during iteration.
+func (l *Intrusive[T]) Values() iter.Seq[*T] {
+	return func(yield func(*T) bool) {
+		for h := l.root.next; h != &l.root; {
+			next := h.next
+			if !yield(h.value) {
+				return
+			}
+			h = next
+		}
+	}
+}
+
+// Backward returns an iterator over the values of list l from back to
+// front. The value being yielded may be removed from l during iteration.
+func (l *Intrusive[T]) Backward() iter.Seq[*T] {
+	return func(yield func(*T) bool) {
+		for h := l.root.prev; h != &l.root; {
+			prev := h.prev
+			if !yield(h.value) {
+				return
+			}
+			h = prev
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package list
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalJSON implements the json.Marshaler interface. A list is encoded as
+// a JSON array of its values from front to back.
+//
+// MarshalJSON has a value receiver so that lists held by value in structs
+// are encoded even when the struct is not addressable.
+func (l List[E]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.ToSlice())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It replaces the
+// contents of list l with the values of a JSON array. Decoding null leaves
+// l unchanged.
+func (l *List[E]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var vs []E
+	if err := json.Unmarshal(data, &vs); err != nil {
+		return err
+	}
+
+	l.Clear()
+	l.PushBackSlice(vs)
+	return nil
+}
@@ -9,8 +9,23 @@
 //	for e := l.Front(); e != nil; e = e.Next() {
 //		// do something with e.Value
 //	}
+//
+// or, with range-over-func:
+//
+//	for v := range l.Values() {
+//		// do something with v
+//	}
 package list
 
+import (
+	"iter"
+	"slices"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/mem"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
 // Element is an element of a linked list.
 type Element[E any] struct {
 	// The value stored with this element.
@@ -23,13 +38,62 @@ type Element[E any] struct {
 	// element (l.Front()).
 	next, prev *Element[E]
 
-	// The list to which this element belongs.
-	list *List[E]
+	// The owner of the list to which this element belongs, or nil for a
+	// removed element and the sentinel of a list.
+	own *owner[E]
+}
+
+// owner identifies the list its elements belong to. Splicing one list into
+// another forwards the owner of the first to the owner of the second, so
+// the elements need not be updated one by one.
+type owner[E any] struct {
+	l    *List[E]  // the list, if the owner has not been forwarded
+	next *owner[E] // the owner forwarded to, or nil
+}
+
+// list returns the list to which e belongs, or nil. It only reads, so it
+// is safe for concurrent readers of the list.
+func (e *Element[E]) list() *List[E] {
+	o := e.own
+	if o == nil {
+		return nil
+	}
+
+	for o.next != nil {
+		o = o.next
+	}
+
+	return o.l
+}
+
+// owns reports whether e is an element of l. If it is, owns points e and
+// the forwarded owners it passes straight at the owner of l, so that later
+// lookups are fast; only methods that modify l may call it.
+func (l *List[E]) owns(e *Element[E]) bool {
+	if e.list() != l {
+		return false
+	}
+
+	if o := e.own; o != nil && o.next != nil {
+		root := o
+		for root.next != nil {
+			root = root.next
+		}
+
+		for o.next != root {
+			next := o.next
+			o.next = root
+			o = next
+		}
+		e.own = root
+	}
+
+	return true
 }
 
 // Next returns the next list element or nil.
 func (e *Element[E]) Next() *Element[E] {
-	if p := e.next; e.list != nil && &e.list.root != p {
+	if p := e.next; p != nil && p.own != nil {
 		return p
 	}
 
@@ -38,7 +102,7 @@ func (e *Element[E]) Next() *Element[E] {
 
 // Prev returns the previous list element or nil.
 func (e *Element[E]) Prev() *Element[E] {
-	if p := e.prev; e.list != nil && &e.list.root != p {
+	if p := e.prev; p != nil && p.own != nil {
 		return p
 	}
 
@@ -53,13 +117,106 @@ type List[E any] struct {
 
 	// current list length excluding (this) sentinel element
 	len int
+
+	// owner of the elements, created on the first insertion
+	own *owner[E]
+
+	arena *mem.Arena[Element[E]]
+
+	// removed elements kept for reuse, linked by next
+	free           *Element[E]
+	nfree, maxFree int
+
+	maxLen  int // 0 if unbounded
+	evict   EvictPolicy
+	onEvict func(E)
+
+	onInsert func(*Element[E])
+	onRemove func(E)
+}
+
+type option[E any] func(*List[E])
+
+// WithArena makes the list allocate its elements in chunks of chunkSize,
+// which makes building a large list much cheaper. The memory of removed
+// elements is only reclaimed by Clear, so this suits lists that are built,
+// used and discarded or cleared as a whole.
+// It panics if chunkSize < 1.
+func WithArena[E any](chunkSize int) option[E] {
+	return func(l *List[E]) {
+		l.arena = mem.NewArena[Element[E]](chunkSize)
+	}
+}
+
+// WithFreeList makes the list keep up to max removed elements and reuse
+// them for inserted values, which avoids allocating in workloads that
+// insert and remove at a steady rate. An element removed from the list
+// must then no longer be used, since it may come back with another value.
+// It panics if max < 1.
+func WithFreeList[E any](max int) option[E] {
+	if max < 1 {
+		panic("list: non-positive free list size")
+	}
+
+	return func(l *List[E]) {
+		l.maxFree = max
+	}
+}
+
+// WithOnInsert sets a function called with each element inserted into the
+// list, after it is linked in. Moving an element within the list does not
+// call it.
+func WithOnInsert[E any](f func(*Element[E])) option[E] {
+	return func(l *List[E]) {
+		l.onInsert = f
+	}
+}
+
+// WithOnRemove sets a function called with the value of each element
+// removed from the list by Remove or Clear.
+func WithOnRemove[E any](f func(E)) option[E] {
+	return func(l *List[E]) {
+		l.onRemove = f
+	}
 }
 
 // New returns an initialized list.
-func New[E any]() *List[E] { return new(List[E]).Init() }
+func New[E any](opts ...option[E]) *List[E] {
+	l := new(List[E]).Init()
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// NewFromSlice returns a list of the values vs, in order, created with the
+// options opts.
+func NewFromSlice[E any](vs []E, opts ...option[E]) *List[E] {
+	l := New(opts...)
+	l.PushBackSlice(vs)
+	return l
+}
+
+// NewFromSeq returns a list of the values of seq, in order, created with
+// the options opts.
+func NewFromSeq[E any](seq iter.Seq[E], opts ...option[E]) *List[E] {
+	l := New(opts...)
+	for v := range seq {
+		l.insertValue(v, l.root.prev)
+	}
+
+	l.trim(false)
+	return l
+}
 
 // Init initializes or clears list l.
 func (l *List[E]) Init() *List[E] {
+	if l.own != nil {
+		// the elements no longer belong to l
+		l.own.l = nil
+		l.own = nil
+	}
 	l.root.next = &l.root
 	l.root.prev = &l.root
 	l.len = 0
@@ -67,8 +224,21 @@ func (l *List[E]) Init() *List[E] {
 }
 
 // Clear clears list l.
+// If l allocates from an arena, Clear recycles the memory of all its
+// elements, so elements removed from l earlier must no longer be used.
 func (l *List[E]) Clear() {
+	if l.onRemove != nil {
+		for e := l.Front(); e != nil; e = e.Next() {
+			l.onRemove(e.Value)
+		}
+	}
+
 	l.Init()
+	if l.arena != nil {
+		// the arena reuses the memory of the free elements too
+		l.free, l.nfree = nil, 0
+	}
+	l.arena.Reset()
 }
 
 // Len returns the number of elements of list l.
@@ -93,6 +263,56 @@ func (l *List[E]) Back() *Element[E] {
 	return l.root.prev
 }
 
+// All returns an iterator over the elements of list l from front to back.
+// The element being yielded may be removed from l during iteration;
+// other changes to l during iteration have unspecified effects on it.
+func (l *List[E]) All() iter.Seq[*Element[E]] {
+	return func(yield func(*Element[E]) bool) {
+		for e := l.Front(); e != nil; {
+			next := e.Next()
+			if !yield(e) {
+				return
+			}
+			e = next
+		}
+	}
+}
+
+// Backward returns an iterator over the elements of list l from back to
+// front. As with All, the element being yielded may be removed from l.
+func (l *List[E]) Backward() iter.Seq[*Element[E]] {
+	return func(yield func(*Element[E]) bool) {
+		for e := l.Back(); e != nil; {
+			prev := e.Prev()
+			if !yield(e) {
+				return
+			}
+			e = prev
+		}
+	}
+}
+
+// Values returns an iterator over the values of list l from front to back.
+func (l *List[E]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for e := l.Front(); e != nil; e = e.Next() {
+			if !yield(e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Do calls f on the value of each element of list l, from front to back,
+// like the Do method of container/ring. It does the work of ranging over
+// Values without creating an iterator. The behavior of Do is undefined if
+// f changes l.
+func (l *List[E]) Do(f func(E)) {
+	for e := l.Front(); e != nil; e = e.Next() {
+		f(e.Value)
+	}
+}
+
 // lazyInit lazily initializes a zero List value.
 func (l *List[E]) lazyInit() {
 	if l.root.next == nil {
@@ -106,14 +326,51 @@ func (l *List[E]) insert(e, at *Element[E]) *Element[E] {
 	e.next = at.next
 	at.next.prev = e
 	at.next = e
-	e.list = l
+	e.own = l.owner()
 	l.len++
 	return e
 }
 
+// owner returns the owner of the elements of l, creating it if need be.
+func (l *List[E]) owner() *owner[E] {
+	if l.own == nil {
+		l.own = &owner[E]{l: l}
+	}
+
+	return l.own
+}
+
 // insertValue is a convenience wrapper for insert(&Element{Value: v}, at).
 func (l *List[E]) insertValue(v E, at *Element[E]) *Element[E] {
-	return l.insert(&Element[E]{Value: v}, at)
+	e := l.newElement()
+	e.Value = v
+	l.insert(e, at)
+	if l.onInsert != nil {
+		l.onInsert(e)
+	}
+
+	return e
+}
+
+// newElement returns a zero element, reusing a free one if there is one.
+func (l *List[E]) newElement() *Element[E] {
+	if e := l.free; e != nil {
+		l.free = e.next
+		l.nfree--
+		e.next = nil
+		return e
+	}
+
+	return l.arena.New()
+}
+
+// recycle adds the removed element e to the free list if there is room.
+func (l *List[E]) recycle(e *Element[E]) {
+	if l.nfree < l.maxFree {
+		*e = Element[E]{next: l.free}
+		l.free = e
+		l.nfree++
+	}
 }
 
 // remove removes e from its list, decrements l.len
@@ -122,7 +379,7 @@ func (l *List[E]) remove(e *Element[E]) {
 	e.next.prev = e.prev
 	e.next = nil // avoid memory leaks
 	e.prev = nil // avoid memory leaks
-	e.list = nil
+	e.own = nil
 	l.len--
 }
 
@@ -145,56 +402,147 @@ func (l *List[E]) move(e, at *Element[E]) {
 // It returns the element value e.Value.
 // The element must not be nil.
 func (l *List[E]) Remove(e *Element[E]) E {
-	if l == e.list {
-		// if e.list == l, l must have been initialized when e was inserted
+	if l.owns(e) {
+		// if e.list() == l, l must have been initialized when e was inserted
 		// in l or l == nil (e is a zero Element) and l.remove will crash
 		l.remove(e)
+		if l.onRemove != nil {
+			l.onRemove(e.Value)
+		}
+
+		if l.maxFree > 0 {
+			v := e.Value
+			l.recycle(e)
+			return v
+		}
 	}
 
 	return e.Value
 }
 
+// RemoveFunc removes the elements of list l whose values satisfy pred, in
+// a single pass from front to back, and returns the number removed.
+func (l *List[E]) RemoveFunc(pred func(E) bool) int {
+	n := 0
+	for e := l.Front(); e != nil; {
+		next := e.Next()
+		if pred(e.Value) {
+			l.Remove(e)
+			n++
+		}
+		e = next
+	}
+
+	return n
+}
+
+// Compact replaces each run of consecutive elements of list l whose values
+// are equal as reported by eq with the first element of the run, like
+// slices.CompactFunc, and returns the number of elements removed.
+func (l *List[E]) Compact(eq func(E, E) bool) int {
+	n := 0
+	for e := l.Front(); e != nil; {
+		next := e.Next()
+		for next != nil && eq(e.Value, next.Value) {
+			dup := next
+			next = next.Next()
+			l.Remove(dup)
+			n++
+		}
+		e = next
+	}
+
+	return n
+}
+
 // PushFront inserts a new element e with value v at the front of list l and returns e.
 func (l *List[E]) PushFront(v E) *Element[E] {
 	l.lazyInit()
-	return l.insertValue(v, &l.root)
+	e := l.insertValue(v, &l.root)
+	l.trim(true)
+	return e
 }
 
 // PushBack inserts a new element e with value v at the back of list l and returns e.
 func (l *List[E]) PushBack(v E) *Element[E] {
 	l.lazyInit()
-	return l.insertValue(v, l.root.prev)
+	e := l.insertValue(v, l.root.prev)
+	l.trim(false)
+	return e
 }
 
 // InsertBefore inserts a new element e with value v immediately before mark and returns e.
 // If mark is not an element of l, the list is not modified.
 // The mark must not be nil.
 func (l *List[E]) InsertBefore(v E, mark *Element[E]) *Element[E] {
-	if l != mark.list {
+	if !l.owns(mark) {
 		return nil
 	}
 
 	// see comment in List.Remove about initialization of l
-	return l.insertValue(v, mark.prev)
+	e := l.insertValue(v, mark.prev)
+	l.trim(e == l.root.next)
+	return e
 }
 
 // InsertAfter inserts a new element e with value v immediately after mark and returns e.
 // If mark is not an element of l, the list is not modified.
 // The mark must not be nil.
 func (l *List[E]) InsertAfter(v E, mark *Element[E]) *Element[E] {
-	if l != mark.list {
+	if !l.owns(mark) {
 		return nil
 	}
 
 	// see comment in List.Remove about initialization of l
-	return l.insertValue(v, mark)
+	e := l.insertValue(v, mark)
+	l.trim(e == l.root.next)
+	return e
+}
+
+// InsertSliceBefore inserts new elements with the values vs immediately
+// before mark, in the order of vs, and returns the first and last of them.
+// If mark is not an element of l or vs is empty, the list is not modified
+// and both results are nil.
+// The mark must not be nil.
+func (l *List[E]) InsertSliceBefore(vs []E, mark *Element[E]) (first, last *Element[E]) {
+	if !l.owns(mark) || len(vs) == 0 {
+		return nil, nil
+	}
+
+	return l.insertSlice(vs, mark.prev)
+}
+
+// InsertSliceAfter inserts new elements with the values vs immediately
+// after mark, in the order of vs, and returns the first and last of them.
+// If mark is not an element of l or vs is empty, the list is not modified
+// and both results are nil.
+// The mark must not be nil.
+func (l *List[E]) InsertSliceAfter(vs []E, mark *Element[E]) (first, last *Element[E]) {
+	if !l.owns(mark) || len(vs) == 0 {
+		return nil, nil
+	}
+
+	return l.insertSlice(vs, mark)
+}
+
+// insertSlice inserts the values vs after at and returns the first and
+// last elements inserted. vs must not be empty.
+func (l *List[E]) insertSlice(vs []E, at *Element[E]) (first, last *Element[E]) {
+	last = at
+	for _, v := range vs {
+		last = l.insertValue(v, last)
+	}
+
+	first = at.next
+	l.trim(at == &l.root)
+	return first, last
 }
 
 // MoveToFront moves element e to the front of list l.
 // If e is not an element of l, the list is not modified.
 // The element must not be nil.
 func (l *List[E]) MoveToFront(e *Element[E]) {
-	if l != e.list || l.root.next == e {
+	if !l.owns(e) || l.root.next == e {
 		return
 	}
 
@@ -206,7 +554,7 @@ func (l *List[E]) MoveToFront(e *Element[E]) {
 // If e is not an element of l, the list is not modified.
 // The element must not be nil.
 func (l *List[E]) MoveToBack(e *Element[E]) {
-	if l != e.list || l.root.prev == e {
+	if !l.owns(e) || l.root.prev == e {
 		return
 	}
 
@@ -218,7 +566,7 @@ func (l *List[E]) MoveToBack(e *Element[E]) {
 // If e or mark is not an element of l, or e == mark, the list is not modified.
 // The element and mark must not be nil.
 func (l *List[E]) MoveBefore(e, mark *Element[E]) {
-	if l != e.list || l != mark.list || e == mark {
+	if !l.owns(e) || !l.owns(mark) || e == mark {
 		return
 	}
 
@@ -229,13 +577,96 @@ func (l *List[E]) MoveBefore(e, mark *Element[E]) {
 // If e or mark is not an element of l, or e == mark, the list is not modified.
 // The element and mark must not be nil.
 func (l *List[E]) MoveAfter(e, mark *Element[E]) {
-	if l != e.list || l != mark.list || e == mark {
+	if !l.owns(e) || !l.owns(mark) || e == mark {
 		return
 	}
 
 	l.move(e, mark)
 }
 
+// Swap exchanges the positions of elements a and b in list l by relinking
+// them, so each keeps its value.
+// If a or b is not an element of l, or a == b, the list is not modified.
+// The elements must not be nil.
+func (l *List[E]) Swap(a, b *Element[E]) {
+	if !l.owns(a) || !l.owns(b) || a == b {
+		return
+	}
+
+	if at := a.prev; at == b {
+		l.move(b, a)
+	} else {
+		l.move(a, b)
+		l.move(b, at)
+	}
+}
+
+// MoveRangeBefore moves the elements from first to last, inclusive, to
+// their new position before mark, keeping their order, in O(1) time.
+// If first, last or mark is not an element of l, the list is not modified.
+// first must not come after last, and mark must not be in the range; these
+// are not checked, as that would take O(n) time.
+// The elements and mark must not be nil.
+func (l *List[E]) MoveRangeBefore(first, last, mark *Element[E]) {
+	if !l.owns(first) || !l.owns(last) || !l.owns(mark) || last.next == mark {
+		return
+	}
+
+	first.prev.next = last.next
+	last.next.prev = first.prev
+
+	at := mark.prev
+	first.prev = at
+	last.next = mark
+	at.next = first
+	mark.prev = last
+}
+
+// Rotate rotates list l left by n positions, so the element at position n
+// becomes the front, or right by -n positions if n is negative. It relinks
+// the list in O(min(k, len-k)) time, where k is n modulo the length, like
+// At.
+func (l *List[E]) Rotate(n int) {
+	if l.len < 2 {
+		return
+	}
+
+	k := n % l.len
+	if k < 0 {
+		k += l.len
+	}
+	if k == 0 {
+		return
+	}
+
+	// Move the sentinel of the ring before the new front.
+	front := l.At(k)
+	root := &l.root
+	root.prev.next = root.next
+	root.next.prev = root.prev
+
+	root.prev = front.prev
+	root.next = front
+	front.prev.next = root
+	front.prev = root
+}
+
+// Reverse reverses the order of the elements of list l in place by
+// swapping their next and prev links. The complexity is O(n).
+func (l *List[E]) Reverse() {
+	if l.len < 2 {
+		return
+	}
+
+	e := &l.root
+	for {
+		e.next, e.prev = e.prev, e.next
+		if e = e.prev; e == &l.root {
+			return
+		}
+	}
+}
+
 // PushBackList inserts a copy of another list at the back of list l.
 // The lists l and other may be the same. They must not be nil.
 func (l *List[E]) PushBackList(other *List[E]) {
@@ -244,6 +675,8 @@ func (l *List[E]) PushBackList(other *List[E]) {
 	for n, e := other.Len(), other.Front(); n > 0; n, e = n-1, e.Next() {
 		l.insertValue(e.Value, l.root.prev)
 	}
+
+	l.trim(false)
 }
 
 // PushBackSlice inserts all elements of the slice es at the back of list l.
@@ -254,6 +687,8 @@ func (l *List[E]) PushBackSlice(vs []E) {
 	for _, v := range vs {
 		l.insertValue(v, l.root.prev)
 	}
+
+	l.trim(false)
 }
 
 // PushFrontList inserts a copy of another list at the front of list l.
@@ -264,6 +699,8 @@ func (l *List[E]) PushFrontList(other *List[E]) {
 	for n, e := other.Len(), other.Back(); n > 0; n, e = n-1, e.Prev() {
 		l.insertValue(e.Value, &l.root)
 	}
+
+	l.trim(true)
 }
 
 // PushFrontSlice inserts all elements of the slice vs at the front of list l.
@@ -274,4 +711,191 @@ func (l *List[E]) PushFrontSlice(vs []E) {
 	for i := len(vs) - 1; i > -1; i-- {
 		l.insertValue(vs[i], &l.root)
 	}
+
+	l.trim(true)
+}
+
+// SpliceBack moves all elements of list other to the back of list l,
+// leaving other empty. The elements are relinked rather than copied, so
+// they stay valid, in O(1) time. The observers of l see the elements
+// inserted and those of other see them removed, which takes O(n) time in
+// the length of other if either list has observers.
+// If l and other are the same list, SpliceBack does nothing.
+func (l *List[E]) SpliceBack(other *List[E]) {
+	l.lazyInit()
+	l.splice(other, l.root.prev)
+	l.trim(false)
+}
+
+// SpliceFront moves all elements of list other to the front of list l,
+// leaving other empty, like SpliceBack.
+func (l *List[E]) SpliceFront(other *List[E]) {
+	l.lazyInit()
+	l.splice(other, &l.root)
+	l.trim(true)
+}
+
+// splice moves the elements of other after at.
+func (l *List[E]) splice(other *List[E], at *Element[E]) {
+	if l == other || other.len == 0 {
+		return
+	}
+
+	n := other.len
+	first, last := l.take(other)
+	first.prev = at
+	last.next = at.next
+	at.next.prev = last
+	at.next = first
+	l.len += n
+
+	if l.onInsert != nil {
+		for e := first; ; e = e.next {
+			l.onInsert(e)
+			if e == last {
+				break
+			}
+		}
+	}
+}
+
+// take makes the elements of the non-empty list other elements of l,
+// leaving other empty, and returns the first and last of them, still
+// linked to each other but not yet to l. The observers of other see the
+// elements removed.
+func (l *List[E]) take(other *List[E]) (first, last *Element[E]) {
+	first, last = other.root.next, other.root.prev
+	if other.onRemove != nil {
+		for e := first; ; e = e.next {
+			other.onRemove(e.Value)
+			if e == last {
+				break
+			}
+		}
+	}
+
+	// Forward the owner of the elements to l instead of updating them.
+	other.own.l, other.own.next = nil, l.owner()
+	other.own = nil
+
+	// The elements may live in other's arena, which must no longer be
+	// recycled by other.Clear.
+	other.Init()
+	other.arena = other.arena.Empty()
+	return first, last
+}
+
+// MergeSorted moves all elements of list other into list l, leaving other
+// empty. If l and other are both sorted by less, l is sorted afterwards.
+// The merge is stable: elements of l come before equal elements of other.
+// The elements are relinked rather than copied, in O(n+m) time, and the
+// observers are called as by SpliceBack. If l and other are the same list,
+// MergeSorted does nothing.
+func (l *List[E]) MergeSorted(other *List[E], less func(a, b E) bool) {
+	if l == other || other.len == 0 {
+		return
+	}
+
+	l.lazyInit()
+	first, last := l.take(other)
+	p := l.root.next
+	for e := first; ; {
+		next := e.next
+		for p != &l.root && !less(e.Value, p.Value) {
+			p = p.next
+		}
+
+		l.insert(e, p.prev)
+		if l.onInsert != nil {
+			l.onInsert(e)
+		}
+
+		if e == last {
+			break
+		}
+		e = next
+	}
+
+	l.trim(false)
+}
+
+// Partition moves the elements of list l whose values satisfy pred to a
+// new list match and the others to a new list rest, keeping their order,
+// and leaves l empty. The elements are relinked rather than copied, so
+// they stay valid. The observers of l see the elements removed; the new
+// lists have no options.
+func (l *List[E]) Partition(pred func(E) bool) (match, rest *List[E]) {
+	match, rest = New[E](), New[E]()
+	if l.len == 0 {
+		return match, rest
+	}
+
+	first, last := match.take(l)
+	for e := first; ; {
+		next := e.next
+		if pred(e.Value) {
+			match.insert(e, match.root.prev)
+		} else {
+			rest.insert(e, rest.root.prev)
+		}
+
+		if e == last {
+			return match, rest
+		}
+		e = next
+	}
+}
+
+// AppendTo appends the values of list l to dst from front to back and
+// returns the extended slice. It grows dst at most once.
+func (l *List[E]) AppendTo(dst []E) []E {
+	dst = slices.Grow(dst, l.len)
+	for e := l.Front(); e != nil; e = e.Next() {
+		dst = append(dst, e.Value)
+	}
+
+	return dst
+}
+
+// ToSlice returns a new slice of the values of list l from front to back.
+func (l *List[E]) ToSlice() []E {
+	return l.AppendTo(make([]E, 0, l.len))
+}
+
+// Clone returns a copy of list l. The values are copied by assignment,
+// so this is a shallow clone. The copy has no observers.
+func (l *List[E]) Clone() *List[E] {
+	c := &List[E]{arena: l.arena.Empty(), maxFree: l.maxFree, maxLen: l.maxLen, evict: l.evict}
+	c.Init()
+	c.PushBackList(l)
+	return c
+}
+
+// CloneFunc returns a copy of list l with each value copied by f, which
+// allows a deep clone.
+func (l *List[E]) CloneFunc(f func(E) E) *List[E] {
+	c := &List[E]{arena: l.arena.Empty(), maxFree: l.maxFree, maxLen: l.maxLen, evict: l.evict}
+	c.Init()
+	for e := l.Front(); e != nil; e = e.Next() {
+		c.insertValue(f(e.Value), c.root.prev)
+	}
+
+	return c
+}
+
+// MemStats returns statistics about the memory used by list l.
+// With an arena, Cap counts the unused values of the arena's chunks.
+// Cap and Bytes count the elements kept for reuse by a free list.
+func (l *List[E]) MemStats() container.MemStats {
+	elem := memsize.Of[Element[E]]()
+	if l.arena != nil {
+		return container.MemStats{
+			Len:   l.len,
+			Cap:   l.len + l.nfree + l.arena.Cap() - l.arena.Len(),
+			Bytes: memsize.Of[List[E]]() + int64(l.arena.Cap())*elem,
+		}
+	}
+
+	n := l.len + l.nfree
+	return container.MemStats{Len: l.len, Cap: n, Bytes: memsize.Of[List[E]]() + int64(n)*elem}
 }
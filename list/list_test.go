@@ -4,7 +4,43 @@
 
 package list
 
-import "testing"
+import (
+	"bytes"
+	stdlist "container/list"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container          = (*List[int])(nil)
+	_ container.Iterable[int]      = (*List[int])(nil)
+	_ container.Cloner[*List[int]] = (*List[int])(nil)
+	_ container.Sizer              = (*List[int])(nil)
+
+	_ container.Queue[int] = (*Queue[int])(nil)
+	_ container.Stack[int] = (*Stack[int])(nil)
+
+	_ container.Container      = (*Intrusive[int])(nil)
+	_ container.Iterable[*int] = (*Intrusive[int])(nil)
+
+	_ container.Iterable[int] = Persistent[int]{}
+
+	_ container.Deque[int]    = (*SyncList[int])(nil)
+	_ container.Iterable[int] = (*SyncList[int])(nil)
+
+	_ container.Container     = (*Unrolled[int])(nil)
+	_ container.Iterable[int] = (*Unrolled[int])(nil)
+	_ container.Sizer         = (*Unrolled[int])(nil)
+)
 
 func checkListLen[E any](t *testing.T, l *List[E], len int) bool {
 	if l := l.Len(); len != l {
@@ -266,8 +302,8 @@ func TestIssue6349(t *testing.T) {
 		t.Errorf("e.Prev() != nil")
 	}
 
-	if e.list != nil {
-		t.Errorf("e.list != nil")
+	if e.list() != nil {
+		t.Errorf("e.list() != nil")
 	}
 }
 
@@ -369,3 +405,1511 @@ func TestMoveUnknownMark(t *testing.T) {
 	checkList(t, &l1, []int{1})
 	checkList(t, &l2, []int{2})
 }
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	var l List[int]
+	l.PushBack(1)
+	l.PushBack(2)
+	l.PushBack(3)
+
+	c := l.Clone()
+	checkList(t, c, []int{1, 2, 3})
+	c.PushBack(4)
+	c.Front().Value = 0
+	checkList(t, &l, []int{1, 2, 3})
+
+	d := l.CloneFunc(func(v int) int { return v * 10 })
+	checkList(t, d, []int{10, 20, 30})
+
+	// CloneFunc copies values deeply, Clone only shallowly
+	var s List[[]int]
+	s.PushBack([]int{1})
+	shallow, deep := s.Clone(), s.CloneFunc(slices.Clone)
+	s.Front().Value[0] = 2
+	if shallow.Front().Value[0] != 2 || deep.Front().Value[0] != 1 {
+		t.Errorf("shallow clone = %v, deep clone = %v, want [2], [1]", shallow.Front().Value, deep.Front().Value)
+	}
+
+	var empty List[int]
+	checkList(t, empty.Clone(), []int{})
+}
+
+func TestArena(t *testing.T) {
+	t.Parallel()
+
+	l := New(WithArena[int](4))
+	for i := range 10 {
+		l.PushBack(i)
+	}
+	e := l.Front().Next()
+	l.Remove(e)
+	l.InsertBefore(1, l.Front().Next())
+	checkList(t, l, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	if n := l.arena.Chunks(); n != 3 {
+		t.Errorf("l.arena.Chunks() = %d, want 3", n)
+	}
+
+	c := l.Clone()
+	c.PushBack(10)
+	checkList(t, c, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	if c.arena == l.arena || c.arena.Chunks() != 3 {
+		t.Errorf("clone does not use its own arena")
+	}
+
+	l.Clear()
+	for i := range 12 {
+		l.PushFront(i)
+	}
+	if n := l.arena.Chunks(); n != 3 {
+		t.Errorf("l.arena.Chunks() after Clear = %d, want 3", n)
+	}
+	checkList(t, l, []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0})
+}
+
+func BenchmarkPushBack(b *testing.B) {
+	for _, bb := range []struct {
+		name string
+		new  func() *List[int]
+	}{
+		{"Heap", func() *List[int] { return New[int]() }},
+		{"Arena", func() *List[int] { return New(WithArena[int](1024)) }},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l := bb.new()
+				for j := range 10000 {
+					l.PushBack(j)
+				}
+			}
+		})
+	}
+}
+
+func TestMemStats(t *testing.T) {
+	t.Parallel()
+
+	l := New[int64]()
+	l.PushBackSlice([]int64{1, 2, 3})
+	s := l.MemStats()
+	if s.Len != 3 || s.Cap != 3 || s.Bytes < 3*(8+3*8) {
+		t.Errorf("l.MemStats() = %+v", s)
+	}
+
+	a := New(WithArena[int64](100))
+	a.PushBackSlice([]int64{1, 2, 3})
+	if s := a.MemStats(); s.Len != 3 || s.Cap != 100 || s.Bytes < 100*(8+3*8) {
+		t.Errorf("a.MemStats() = %+v", s)
+	}
+}
+
+func TestObservers(t *testing.T) {
+	t.Parallel()
+
+	var inserted, removed []int
+	l := New(WithOnInsert(func(e *Element[int]) { inserted = append(inserted, e.Value) }),
+		WithOnRemove(func(v int) { removed = append(removed, v) }))
+
+	e := l.PushBack(1)
+	l.PushFront(0)
+	l.InsertAfter(2, e)
+	l.PushBackSlice([]int{3, 4})
+	l.MoveToFront(e)
+	l.Remove(e)
+	l.Remove(e) // no longer in the list
+	l.Clear()
+
+	if want := []int{1, 0, 2, 3, 4}; !slices.Equal(inserted, want) {
+		t.Errorf("inserted = %v, want %v", inserted, want)
+	}
+	if want := []int{1, 0, 2, 3, 4}; !slices.Equal(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+}
+
+func TestAdapters(t *testing.T) {
+	t.Parallel()
+
+	l := New[int]()
+	d := AsDeque(l)
+	d.PushBack(2)
+	d.PushFront(1)
+	l.PushBack(3)
+	if v, _ := d.PeekBack(); v != 3 || d.Len() != 3 {
+		t.Errorf("change to the list not visible through the deque")
+	}
+	if v, ok := d.PopFront(); !ok || v != 1 {
+		t.Errorf("d.PopFront() = %d, %t, want 1, true", v, ok)
+	}
+
+	q := AsQueue(l)
+	q.Enqueue(4)
+	if v, _ := q.Dequeue(); v != 2 {
+		t.Errorf("q.Dequeue() = %d, want 2", v)
+	}
+
+	s := AsStack(l)
+	s.Push(5)
+	if v, _ := s.Pop(); v != 5 {
+		t.Errorf("s.Pop() = %d, want 5", v)
+	}
+	if v, _ := s.Peek(); v != 4 {
+		t.Errorf("s.Peek() = %d, want 4", v)
+	}
+
+	s.Clear()
+	if _, ok := d.PopBack(); ok || l.Len() != 0 {
+		t.Errorf("list not empty after Clear through the stack")
+	}
+	if _, ok := q.Peek(); ok {
+		t.Errorf("q.Peek() of an empty list reports ok")
+	}
+}
+
+func TestIterators(t *testing.T) {
+	t.Parallel()
+
+	l := New[int]()
+	l.PushBackSlice([]int{1, 2, 3, 4})
+
+	if got := slices.Collect(l.Values()); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("l.Values() = %v, want [1 2 3 4]", got)
+	}
+
+	var back []int
+	for e := range l.Backward() {
+		back = append(back, e.Value)
+		if e.Value == 3 {
+			break
+		}
+	}
+	if !slices.Equal(back, []int{4, 3}) {
+		t.Errorf("l.Backward() up to 3 = %v, want [4 3]", back)
+	}
+
+	// removing the yielded element does not stop the iteration
+	for e := range l.All() {
+		if e.Value%2 == 0 {
+			l.Remove(e)
+		}
+	}
+	if got := slices.Collect(l.Values()); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("l after removing even values = %v, want [1 3]", got)
+	}
+
+	for range New[int]().All() {
+		t.Errorf("All of an empty list yielded an element")
+	}
+	var zero List[int]
+	for range zero.Values() {
+		t.Errorf("Values of a zero list yielded a value")
+	}
+}
+
+func TestCursor(t *testing.T) {
+	t.Parallel()
+
+	l := New[int]()
+	l.PushBackSlice([]int{1, 2, 3, 4, 5, 6})
+
+	for c := l.CursorFront(); c.Valid(); c.Next() {
+		switch v := c.Element().Value; {
+		case v%2 == 0:
+			c.Remove()
+		case v == 3:
+			c.InsertBefore(30)
+			c.InsertAfter(31)
+		}
+	}
+	if got := slices.Collect(l.Values()); !slices.Equal(got, []int{1, 30, 3, 31, 5}) {
+		t.Errorf("l = %v, want [1 30 3 31 5]", got)
+	}
+
+	// the gap left by Remove survives removing and inserting around it
+	c := l.CursorFront()
+	c.Next()
+	c.Next()
+	if v := c.Remove(); v != 3 {
+		t.Errorf("c.Remove() = %d, want 3", v)
+	}
+	l.Remove(l.Front().Next()) // 30, before the gap
+	c.InsertAfter(32)
+	c.InsertBefore(29)
+	if got := slices.Collect(l.Values()); !slices.Equal(got, []int{1, 29, 32, 31, 5}) {
+		t.Errorf("l = %v, want [1 29 32 31 5]", got)
+	}
+	if !c.Prev() || c.Element().Value != 29 {
+		t.Errorf("c.Prev() did not move to 29")
+	}
+	if !c.Next() || c.Element().Value != 32 {
+		t.Errorf("c.Next() did not move to 32")
+	}
+
+	// past the back the cursor sees elements pushed later
+	for c.Next() {
+	}
+	l.PushBack(6)
+	if !c.Next() || c.Element().Value != 6 {
+		t.Errorf("c.Next() did not move to the pushed element")
+	}
+
+	var empty List[int]
+	e := empty.CursorBack()
+	if e.Valid() || e.Next() || e.Prev() {
+		t.Errorf("cursor of an empty list is at an element")
+	}
+	e.InsertAfter(1)
+	e.InsertBefore(0)
+	if got := slices.Collect(empty.Values()); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("empty after inserts = %v, want [0 1]", got)
+	}
+
+	// removing both neighbours of a gap loses the cursor's position
+	g := NewFromSlice([]int{1, 2, 3, 4})
+	lc := g.CursorFront()
+	lc.Next()
+	lc.Remove()
+	g.Remove(g.Front())
+	g.Remove(g.Front())
+	if lc.Next() || lc.Prev() || lc.Valid() {
+		t.Errorf("cursor with both neighbours removed moved")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("InsertAfter on a lost cursor did not panic")
+			}
+		}()
+		lc.InsertAfter(0)
+	}()
+	checkList(t, g, []int{4})
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Remove in a gap did not panic")
+		}
+	}()
+	e.Remove()
+}
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	type pair struct{ k, i int }
+	byKey := func(a, b pair) bool { return a.k < b.k }
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 3, 7, 64, 1000} {
+		l := New[pair]()
+		var want []pair
+		for i := range n {
+			p := pair{r.Intn(n/2 + 1), i}
+			l.PushBack(p)
+			want = append(want, p)
+		}
+		front := l.Front()
+
+		l.Sort(byKey)
+		slices.SortStableFunc(want, func(a, b pair) int { return a.k - b.k })
+
+		checkListPointers(t, l, slices.Collect(l.All()))
+		if got := slices.Collect(l.Values()); !slices.Equal(got, want) {
+			t.Errorf("sorted list of %d = %v, want %v", n, got, want)
+		}
+		if n > 0 && front.list() != l {
+			t.Errorf("element no longer in the list after Sort")
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	t.Parallel()
+
+	for n := range 4 {
+		l := New[int]()
+		var es []*Element[int]
+		for i := range n {
+			es = append(es, l.PushFront(i))
+		}
+
+		// pushing to the front built the list in reverse
+		l.Reverse()
+		checkListPointers(t, l, es)
+	}
+
+	var zero List[int]
+	zero.Reverse()
+	checkListLen(t, &zero, 0)
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	l := New[string]()
+	l.PushBackSlice([]string{"a", "B", "b"})
+
+	if e := l.Find("b", strings.EqualFold); e != l.Front().Next() {
+		t.Errorf(`l.Find("b", strings.EqualFold) = %v, want the second element`, e)
+	}
+	if e := l.FindFunc(func(s string) bool { return s > "a" }); e != l.Back() {
+		t.Errorf(`l.FindFunc(> "a") = %v, want the last element`, e)
+	}
+	if e := l.Find("c", strings.EqualFold); e != nil {
+		t.Errorf(`l.Find("c", strings.EqualFold) = %v, want nil`, e)
+	}
+
+	var zero List[string]
+	if e := zero.FindFunc(func(string) bool { return true }); e != nil {
+		t.Errorf("zero.FindFunc = %v, want nil", e)
+	}
+}
+
+func TestIndexFunc(t *testing.T) {
+	t.Parallel()
+
+	l := New[int]()
+	l.PushBackSlice([]int{1, 4, 6})
+
+	even := func(v int) bool { return v%2 == 0 }
+	negative := func(v int) bool { return v < 0 }
+	if i := l.IndexFunc(even); i != 1 {
+		t.Errorf("l.IndexFunc(even) = %d, want 1", i)
+	}
+	if i := l.IndexFunc(negative); i != -1 {
+		t.Errorf("l.IndexFunc(negative) = %d, want -1", i)
+	}
+	if !l.ContainsFunc(even) || l.ContainsFunc(negative) {
+		t.Errorf("l.ContainsFunc results wrong")
+	}
+}
+
+func TestRemoveFunc(t *testing.T) {
+	t.Parallel()
+
+	var removed []int
+	l := New(WithOnRemove(func(v int) { removed = append(removed, v) }))
+	l.PushBackSlice([]int{2, 1, 4, 3, 6})
+	es := slices.Collect(l.All())
+
+	if n := l.RemoveFunc(func(v int) bool { return v%2 == 0 }); n != 3 {
+		t.Errorf("l.RemoveFunc(even) = %d, want 3", n)
+	}
+	checkListPointers(t, l, []*Element[int]{es[1], es[3]})
+	if !slices.Equal(removed, []int{2, 4, 6}) {
+		t.Errorf("removed = %v, want [2 4 6]", removed)
+	}
+
+	if n := l.RemoveFunc(func(int) bool { return false }); n != 0 {
+		t.Errorf("l.RemoveFunc(false) = %d, want 0", n)
+	}
+}
+
+func TestToSlice(t *testing.T) {
+	t.Parallel()
+
+	l := New[int]()
+	if s := l.ToSlice(); s == nil || len(s) != 0 {
+		t.Errorf("l.ToSlice() of an empty list = %#v, want []int{}", s)
+	}
+
+	l.PushBackSlice([]int{1, 2, 3})
+	if s := l.ToSlice(); !slices.Equal(s, []int{1, 2, 3}) || cap(s) != 3 {
+		t.Errorf("l.ToSlice() = %v with cap %d, want [1 2 3] with cap 3", s, cap(s))
+	}
+
+	dst := []int{0}
+	if s := l.AppendTo(dst); !slices.Equal(s, []int{0, 1, 2, 3}) {
+		t.Errorf("l.AppendTo([0]) = %v, want [0 1 2 3]", s)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	var a, b, c List[int]
+	a.PushBackSlice([]int{1, 2, 3})
+	b.PushBackSlice([]int{1, 2, 3})
+	c.PushBackSlice([]int{1, 2})
+
+	if !Equal(&a, &b) || Equal(&a, &c) {
+		t.Errorf("Equal results wrong")
+	}
+	b.Back().Value = 4
+	if Equal(&a, &b) {
+		t.Errorf("Equal(a, b) with different values = true")
+	}
+
+	var s List[string]
+	s.PushBackSlice([]string{"1", "2", "3"})
+	if !EqualFunc(&a, &s, func(x int, y string) bool { return strconv.Itoa(x) == y }) {
+		t.Errorf("EqualFunc(a, s, Itoa) = false")
+	}
+
+	var empty List[int]
+	if !Equal(&empty, New[int]()) {
+		t.Errorf("Equal of empty lists = false")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Hosts List[string]
+		Ports *List[int]
+	}
+
+	var c config
+	c.Hosts.PushBackSlice([]string{"a", "b"})
+	c.Ports = New[int]()
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("json.Marshal(c) = %v", err)
+	}
+	if want := `{"Hosts":["a","b"],"Ports":[]}`; string(data) != want {
+		t.Errorf("json.Marshal(c) = %s, want %s", data, want)
+	}
+
+	var d config
+	d.Hosts.PushBack("old")
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatalf("json.Unmarshal = %v", err)
+	}
+	checkList(t, &d.Hosts, []string{"a", "b"})
+	if d.Ports == nil || d.Ports.Len() != 0 {
+		t.Errorf("d.Ports = %v, want an empty list", d.Ports)
+	}
+
+	if err := json.Unmarshal([]byte(`{"Hosts":null}`), &d); err != nil || d.Hosts.Len() != 2 {
+		t.Errorf("decoding null changed the list or failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"Hosts":[1]}`), &d); err == nil || d.Hosts.Len() != 2 {
+		t.Errorf("decoding a bad array succeeded or changed the list")
+	}
+}
+
+func TestGob(t *testing.T) {
+	t.Parallel()
+
+	type state struct {
+		Queue List[string]
+		Seen  *List[int]
+	}
+
+	var s state
+	s.Queue.PushBackSlice([]string{"a", "", "c"})
+	s.Seen = New[int]()
+	s.Seen.PushBack(7)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		t.Fatalf("gob Encode = %v", err)
+	}
+
+	var d state
+	if err := gob.NewDecoder(&buf).Decode(&d); err != nil {
+		t.Fatalf("gob Decode = %v", err)
+	}
+	checkList(t, &d.Queue, []string{"a", "", "c"})
+	checkList(t, d.Seen, []int{7})
+
+	data, err := s.Queue.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary = %v", err)
+	}
+	l := New[string]()
+	l.PushBack("old")
+	if err := l.UnmarshalBinary(data[:len(data)-1]); err == nil || l.Len() != 1 {
+		t.Errorf("UnmarshalBinary of truncated data succeeded or changed the list")
+	}
+	if err := l.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary = %v", err)
+	}
+	checkList(t, l, []string{"a", "", "c"})
+
+	for _, n := range []int{-1, 1 << 40} {
+		var bad bytes.Buffer
+		gob.NewEncoder(&bad).Encode(n)
+		if err := l.GobDecode(bad.Bytes()); err == nil || l.Len() != 3 {
+			t.Errorf("GobDecode of length %d succeeded or changed the list", n)
+		}
+	}
+}
+
+func TestSyncList(t *testing.T) {
+	t.Parallel()
+
+	s := NewSyncList(-1)
+
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				s.PushBack(w*100 + i)
+				if i%10 == 0 {
+					s.PopFront()
+				}
+				s.ContainsFunc(func(v int) bool { return v == i })
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := s.Len(); n != 4*90+1 {
+		t.Errorf("s.Len() = %d, want %d", n, 4*90+1)
+	}
+
+	// the loop body may use s during snapshot iteration
+	n := 0
+	for v := range s.Values() {
+		s.PushFront(v)
+		n++
+	}
+	if n != 4*90+1 || s.Len() != 2*n {
+		t.Errorf("iterated over %d values, s.Len() = %d", n, s.Len())
+	}
+
+	s.Do(func(l *List[int]) {
+		l.Clear()
+		l.PushBackSlice([]int{1, 2, 3})
+		l.Reverse()
+	})
+	if got := s.ToSlice(); !slices.Equal(got, []int{3, 2, 1}) {
+		t.Errorf("s.ToSlice() = %v, want [3 2 1]", got)
+	}
+	if n := s.RemoveFunc(func(v int) bool { return v > 1 }); n != 2 {
+		t.Errorf("s.RemoveFunc(> 1) = %d, want 2", n)
+	}
+	if v, ok := s.PeekBack(); !ok || v != 1 {
+		t.Errorf("s.PeekBack() = %d, %t, want 1, true", v, ok)
+	}
+}
+
+func TestSplice(t *testing.T) {
+	t.Parallel()
+
+	var inserted, removed []int
+	l := New(WithOnInsert(func(e *Element[int]) { inserted = append(inserted, e.Value) }))
+	other := New(WithArena[int](4), WithOnRemove(func(v int) { removed = append(removed, v) }))
+
+	l.PushBack(0)
+	inserted = nil
+	es := []*Element[int]{other.PushBack(1), other.PushBack(2)}
+	l.SpliceBack(other)
+	checkListLen(t, other, 0)
+	checkList(t, l, []int{0, 1, 2})
+	if es[0].list() != l || !slices.Equal(inserted, []int{1, 2}) || !slices.Equal(removed, []int{1, 2}) {
+		t.Errorf("inserted %v, removed %v, want [1 2] for both", inserted, removed)
+	}
+
+	// other can be reused without disturbing the moved elements
+	other.Clear()
+	other.PushBack(-1)
+	other.PushBack(-2)
+	l.SpliceFront(other)
+	checkList(t, l, []int{-1, -2, 0, 1, 2})
+	checkListLen(t, other, 0)
+
+	l.SpliceBack(l)
+	l.SpliceBack(other)
+	var zero List[int]
+	zero.SpliceFront(l)
+	checkList(t, &zero, []int{-1, -2, 0, 1, 2})
+	checkListLen(t, l, 0)
+
+	// elements moved by several splices resolve to their current list
+	l.PushBack(3)
+	if l.Remove(es[0]); zero.Len() != 5 {
+		t.Errorf("l.Remove removed an element of another list")
+	}
+	if v := zero.Remove(es[0]); v != 1 || es[0].list() != nil {
+		t.Errorf("zero.Remove(es[0]) = %d, want 1", v)
+	}
+	zero.Clear()
+	if es[1].list() != nil || zero.Remove(es[1]) != 2 || zero.Len() != 0 {
+		t.Errorf("element of a cleared list still belongs to it")
+	}
+	checkList(t, l, []int{3})
+}
+
+func TestMoveRangeBefore(t *testing.T) {
+	t.Parallel()
+
+	l := New[int]()
+	var es []*Element[int]
+	for i := range 6 {
+		es = append(es, l.PushBack(i))
+	}
+
+	l.MoveRangeBefore(es[3], es[4], es[1])
+	checkListPointers(t, l, []*Element[int]{es[0], es[3], es[4], es[1], es[2], es[5]})
+
+	l.MoveRangeBefore(es[0], es[4], es[5])
+	checkListPointers(t, l, []*Element[int]{es[1], es[2], es[0], es[3], es[4], es[5]})
+
+	// a range already before mark, and a mark from another list
+	l.MoveRangeBefore(es[1], es[2], es[0])
+	l.MoveRangeBefore(es[1], es[1], New[int]().PushBack(9))
+	checkListPointers(t, l, []*Element[int]{es[1], es[2], es[0], es[3], es[4], es[5]})
+
+	l.MoveRangeBefore(es[5], es[5], es[1])
+	checkListPointers(t, l, []*Element[int]{es[5], es[1], es[2], es[0], es[3], es[4]})
+}
+
+func TestInsertSlice(t *testing.T) {
+	t.Parallel()
+
+	l := New[int]()
+	mark := l.PushBack(0)
+
+	first, last := l.InsertSliceBefore([]int{-3, -2, -1}, mark)
+	if first.Value != -3 || last.Value != -1 {
+		t.Errorf("InsertSliceBefore returned %d, %d, want -3, -1", first.Value, last.Value)
+	}
+	first, last = l.InsertSliceAfter([]int{1, 2}, mark)
+	if first.Value != 1 || last.Value != 2 {
+		t.Errorf("InsertSliceAfter returned %d, %d, want 1, 2", first.Value, last.Value)
+	}
+	checkList(t, l, []int{-3, -2, -1, 0, 1, 2})
+
+	if first, last := l.InsertSliceAfter(nil, mark); first != nil || last != nil {
+		t.Errorf("InsertSliceAfter(nil) returned elements")
+	}
+	if first, _ := l.InsertSliceBefore([]int{9}, New[int]().PushBack(0)); first != nil {
+		t.Errorf("InsertSliceBefore with a foreign mark returned an element")
+	}
+	checkList(t, l, []int{-3, -2, -1, 0, 1, 2})
+}
+
+func TestRotate(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		n    int
+		want []int
+	}{
+		{0, []int{0, 1, 2, 3, 4}},
+		{1, []int{1, 2, 3, 4, 0}},
+		{4, []int{4, 0, 1, 2, 3}},
+		{-1, []int{4, 0, 1, 2, 3}},
+		{-7, []int{3, 4, 0, 1, 2}},
+		{12, []int{2, 3, 4, 0, 1}},
+	} {
+		l := New[int]()
+		l.PushBackSlice([]int{0, 1, 2, 3, 4})
+		l.Rotate(tt.n)
+		checkList(t, l, tt.want)
+		checkListPointers(t, l, slices.Collect(l.All()))
+	}
+
+	var zero List[int]
+	zero.Rotate(3)
+	checkListLen(t, &zero, 0)
+}
+
+func TestMapFilterReduce(t *testing.T) {
+	t.Parallel()
+
+	l := New[int]()
+	l.PushBackSlice([]int{1, 2, 3, 4})
+
+	checkList(t, Map(l, strconv.Itoa), []string{"1", "2", "3", "4"})
+	checkList(t, Filter(l, func(v int) bool { return v%2 == 0 }), []int{2, 4})
+	if sum := Reduce(l, 10, func(acc, v int) int { return acc + v }); sum != 20 {
+		t.Errorf("Reduce(l, 10, +) = %d, want 20", sum)
+	}
+
+	var zero List[int]
+	checkList(t, Map(&zero, strconv.Itoa), []string{})
+	if s := Reduce(&zero, "x", func(acc string, v int) string { return acc + strconv.Itoa(v) }); s != "x" {
+		t.Errorf("Reduce of an empty list = %q, want %q", s, "x")
+	}
+}
+
+func TestSwap(t *testing.T) {
+	t.Parallel()
+
+	l := New[int]()
+	var es []*Element[int]
+	for i := range 4 {
+		es = append(es, l.PushBack(i))
+	}
+
+	l.Swap(es[0], es[3])
+	checkListPointers(t, l, []*Element[int]{es[3], es[1], es[2], es[0]})
+
+	l.Swap(es[1], es[2])
+	checkListPointers(t, l, []*Element[int]{es[3], es[2], es[1], es[0]})
+
+	l.Swap(es[1], es[2])
+	checkListPointers(t, l, []*Element[int]{es[3], es[1], es[2], es[0]})
+
+	l.Swap(es[1], es[1])
+	l.Swap(es[1], New[int]().PushBack(9))
+	checkListPointers(t, l, []*Element[int]{es[3], es[1], es[2], es[0]})
+}
+
+func TestAt(t *testing.T) {
+	t.Parallel()
+
+	l := New[int]()
+	var es []*Element[int]
+	for i := range 5 {
+		es = append(es, l.PushBack(i))
+	}
+
+	for i, e := range es {
+		if got := l.At(i); got != e {
+			t.Errorf("l.At(%d) = %v, want %v", i, got, e)
+		}
+		if got := l.IndexOf(e); got != i {
+			t.Errorf("l.IndexOf(es[%d]) = %d, want %d", i, got, i)
+		}
+	}
+
+	if l.At(-1) != nil || l.At(5) != nil {
+		t.Errorf("l.At out of range returned an element")
+	}
+	if i := l.IndexOf(New[int]().PushBack(0)); i != -1 {
+		t.Errorf("l.IndexOf(foreign element) = %d, want -1", i)
+	}
+	l.Remove(es[2])
+	if i := l.IndexOf(es[2]); i != -1 {
+		t.Errorf("l.IndexOf(removed element) = %d, want -1", i)
+	}
+}
+
+func TestNewFrom(t *testing.T) {
+	t.Parallel()
+
+	checkList(t, NewFromSlice([]int{1, 2, 3}), []int{1, 2, 3})
+	checkList(t, NewFromSlice[int](nil), []int{})
+
+	l := NewFromSeq(slices.Values([]string{"a", "b"}), WithArena[string](8))
+	checkList(t, l, []string{"a", "b"})
+	if l.arena == nil {
+		t.Errorf("NewFromSeq ignored the options")
+	}
+	checkList(t, NewFromSeq(NewFromSlice([]int{3, 4}).Values()), []int{3, 4})
+}
+
+func TestMergeSorted(t *testing.T) {
+	t.Parallel()
+
+	type pair struct{ k, src int }
+	byKey := func(a, b pair) bool { return a.k < b.k }
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 5, 50} {
+		var as, bs []pair
+		for range n {
+			as = append(as, pair{r.Intn(n + 1), 0})
+		}
+		for range r.Intn(n + 1) {
+			bs = append(bs, pair{r.Intn(n + 1), 1})
+		}
+		less := func(a, b pair) int { return a.k - b.k }
+		slices.SortFunc(as, less)
+		slices.SortFunc(bs, less)
+
+		a, b := NewFromSlice(as), NewFromSlice(bs)
+		a.MergeSorted(b, byKey)
+
+		want := append(slices.Clone(as), bs...)
+		slices.SortStableFunc(want, less)
+		checkList(t, a, want)
+		checkListPointers(t, a, slices.Collect(a.All()))
+		checkListLen(t, b, 0)
+	}
+
+	var zero List[int]
+	zero.MergeSorted(NewFromSlice([]int{1, 2}), func(a, b int) bool { return a < b })
+	checkList(t, &zero, []int{1, 2})
+}
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	l := NewFromSlice([]string{"a", "A", "b", "b", "B", "a", "c", "c"})
+	if n := l.Compact(strings.EqualFold); n != 4 {
+		t.Errorf("l.Compact(EqualFold) = %d, want 4", n)
+	}
+	checkList(t, l, []string{"a", "b", "a", "c"})
+	checkListPointers(t, l, slices.Collect(l.All()))
+
+	if n := l.Compact(strings.EqualFold); n != 0 {
+		t.Errorf("second l.Compact(EqualFold) = %d, want 0", n)
+	}
+}
+
+func TestFreeList(t *testing.T) {
+	l := New(WithFreeList[int](2))
+	a, b, c := l.PushBack(1), l.PushBack(2), l.PushBack(3)
+	l.Remove(a)
+	if v := l.Remove(b); v != 2 {
+		t.Errorf("l.Remove(b) = %d, want 2", v)
+	}
+	l.Remove(c)
+	if l.nfree != 2 {
+		t.Errorf("l.nfree = %d, want 2", l.nfree)
+	}
+	if s := l.MemStats(); s.Len != 0 || s.Cap != 2 {
+		t.Errorf("l.MemStats() = %+v, want Len 0, Cap 2", s)
+	}
+
+	if e := l.PushBack(4); e != b || e.Value != 4 {
+		t.Errorf("l.PushBack(4) did not reuse the last removed element")
+	}
+	l.PushFront(5)
+	checkList(t, l, []int{5, 4})
+	checkListPointers(t, l, slices.Collect(l.All()))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Remove(l.PushBack(0))
+	})
+	if allocs != 0 {
+		t.Errorf("push and remove allocated %v times with a free list", allocs)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		a, b []int
+		want int
+	}{
+		{nil, nil, 0},
+		{[]int{1, 2}, []int{1, 2}, 0},
+		{[]int{1, 2}, []int{1, 3}, -1},
+		{[]int{2}, []int{1, 3}, +1},
+		{[]int{1}, []int{1, 0}, -1},
+		{[]int{1, 0}, []int{1}, +1},
+	} {
+		if got := Compare(NewFromSlice(tt.a), NewFromSlice(tt.b)); got != tt.want {
+			t.Errorf("Compare(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	strs := NewFromSlice([]string{"1", "10"})
+	byNum := func(x int, y string) int {
+		n, _ := strconv.Atoi(y)
+		return x - n
+	}
+	if c := CompareFunc(NewFromSlice([]int{1, 9}), strs, byNum); c >= 0 {
+		t.Errorf("CompareFunc([1 9], [1 10]) = %d, want < 0", c)
+	}
+}
+
+func TestMaxLen(t *testing.T) {
+	t.Parallel()
+
+	var evicted []int
+	onEvict := WithOnEvict(func(v int) { evicted = append(evicted, v) })
+
+	l := New(WithMaxLen[int](3, EvictOpposite), onEvict)
+	l.PushBackSlice([]int{1, 2, 3, 4})
+	checkList(t, l, []int{2, 3, 4})
+	l.PushFront(0)
+	checkList(t, l, []int{0, 2, 3})
+	l.InsertAfter(9, l.Front())
+	checkList(t, l, []int{9, 2, 3})
+	if !slices.Equal(evicted, []int{1, 4, 0}) {
+		t.Errorf("evicted = %v, want [1 4 0]", evicted)
+	}
+
+	// EvictOpposite evicts from the end away from where the element landed
+	o := New(WithMaxLen[int](3, EvictOpposite))
+	o.PushBackSlice([]int{1, 2, 3})
+	if e := o.InsertBefore(0, o.Front()); e.list() != o {
+		t.Errorf("inserting before the front evicted the new element")
+	}
+	checkList(t, o, []int{0, 1, 2})
+	if e := o.InsertAfter(9, o.Back()); e.list() != o {
+		t.Errorf("inserting after the back evicted the new element")
+	}
+	checkList(t, o, []int{1, 2, 9})
+	o.InsertSliceBefore([]int{7, 8}, o.Front())
+	checkList(t, o, []int{7, 8, 1})
+
+	b := New(WithMaxLen[int](2, EvictBack))
+	b.PushBack(1)
+	b.PushBack(2)
+	if e := b.PushBack(3); e.list() != nil {
+		t.Errorf("pushing to the back of a full list with EvictBack kept the element")
+	}
+	b.PushFront(0)
+	checkList(t, b, []int{0, 1})
+
+	f := New(WithMaxLen[int](2, EvictFront))
+	f.PushFrontSlice([]int{1, 2, 3})
+	checkList(t, f, []int{2, 3})
+	f.SpliceFront(NewFromSlice([]int{0}))
+	checkList(t, f, []int{2, 3})
+	c := f.Clone()
+	c.PushBack(4)
+	checkList(t, c, []int{3, 4})
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	l := NewFromSlice([]int{1, 2, 3})
+	sum := 0
+	l.Do(func(v int) { sum += v })
+	if sum != 6 {
+		t.Errorf("sum over l.Do = %d, want 6", sum)
+	}
+
+	var zero List[int]
+	zero.Do(func(int) { t.Errorf("Do on a zero list called f") })
+}
+
+func BenchmarkDo(b *testing.B) {
+	l := NewFromSlice(make([]int, 1000))
+	for b.Loop() {
+		n := 0
+		l.Do(func(v int) { n += v })
+	}
+}
+
+func BenchmarkValues(b *testing.B) {
+	l := NewFromSlice(make([]int, 1000))
+	for b.Loop() {
+		n := 0
+		for v := range l.Values() {
+			n += v
+		}
+	}
+}
+
+func TestStackQueue(t *testing.T) {
+	t.Parallel()
+
+	q := NewQueue(WithMaxLen[int](2, EvictOpposite))
+	q.Enqueue(1)
+	q.Enqueue(2)
+	q.Enqueue(3)
+	e := q.List().Front()
+	if v, ok := q.Peek(); !ok || v != 2 || e.Value != 2 {
+		t.Errorf("q.Peek() = %d, %t, want 2, true", v, ok)
+	}
+	if v, _ := q.Dequeue(); v != 2 || q.Len() != 1 {
+		t.Errorf("q.Dequeue() = %d, want 2", v)
+	}
+
+	s := NewStack[string]()
+	s.Push("a")
+	top := s.List().Back()
+	s.Push("b")
+	if v, _ := s.Pop(); v != "b" {
+		t.Errorf("s.Pop() = %q, want %q", v, "b")
+	}
+	if v, ok := s.Peek(); !ok || v != "a" || s.List().Back() != top {
+		t.Errorf("s.Peek() = %q, %t, want %q, true on the same element", v, ok, "a")
+	}
+	s.Clear()
+	if _, ok := s.Pop(); ok || s.Len() != 0 {
+		t.Errorf("s.Pop() after Clear reports ok")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	l := NewFromSlice([]string{"a", "b", "c"})
+	for _, tt := range []struct {
+		format string
+		want   string
+	}{
+		{"%v", "[a b c]"},
+		{"%s", "[a b c]"},
+		{"%#v", `list.NewFromSlice([]string{"a", "b", "c"})`},
+	} {
+		if got := fmt.Sprintf(tt.format, l); got != tt.want {
+			t.Errorf("Sprintf(%q, l) = %s, want %s", tt.format, got, tt.want)
+		}
+	}
+
+	var zero List[int]
+	if got := fmt.Sprint(zero); got != "[]" {
+		t.Errorf("Sprint(zero) = %s, want []", got)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	t.Parallel()
+
+	removed := 0
+	l := NewFromSlice([]int{1, 2, 3, 4, 5}, WithOnRemove(func(int) { removed++ }))
+	es := slices.Collect(l.All())
+
+	even, odd := l.Partition(func(v int) bool { return v%2 == 0 })
+	checkListPointers(t, even, []*Element[int]{es[1], es[3]})
+	checkListPointers(t, odd, []*Element[int]{es[0], es[2], es[4]})
+	checkListLen(t, l, 0)
+	if removed != 5 {
+		t.Errorf("onRemove called %d times, want 5", removed)
+	}
+
+	all, none := odd.Partition(func(int) bool { return true })
+	checkList(t, all, []int{1, 3, 5})
+	checkList(t, none, []int{})
+
+	var zero List[int]
+	a, b := zero.Partition(func(int) bool { return true })
+	checkListLen(t, a, 0)
+	checkListLen(t, b, 0)
+}
+
+func TestMinMaxFunc(t *testing.T) {
+	t.Parallel()
+
+	type pair struct{ k, i int }
+	byKey := func(a, b pair) int { return a.k - b.k }
+
+	l := NewFromSlice([]pair{{3, 0}, {1, 1}, {5, 2}, {1, 3}, {5, 4}})
+	if e := MinFunc(l, byKey); e != l.At(1) {
+		t.Errorf("MinFunc = %v, want the first minimum", e.Value)
+	}
+	if e := MaxFunc(l, byKey); e != l.At(2) {
+		t.Errorf("MaxFunc = %v, want the first maximum", e.Value)
+	}
+
+	if MinFunc(New[pair](), byKey) != nil || MaxFunc(New[pair](), byKey) != nil {
+		t.Errorf("MinFunc or MaxFunc of an empty list returned an element")
+	}
+}
+
+type hooked struct {
+	a, b Hook[hooked]
+	v    int
+}
+
+func TestIntrusive(t *testing.T) {
+	t.Parallel()
+
+	la := NewIntrusive(func(x *hooked) *Hook[hooked] { return &x.a })
+	lb := NewIntrusive(func(x *hooked) *Hook[hooked] { return &x.b })
+	values := func(l *Intrusive[hooked]) (vs []int) {
+		for x := range l.Values() {
+			vs = append(vs, x.v)
+		}
+		return vs
+	}
+
+	xs := make([]hooked, 4)
+	for i := range xs {
+		xs[i].v = i
+		la.PushBack(&xs[i])
+		lb.PushFront(&xs[i])
+	}
+	if got := values(la); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("la = %v, want [0 1 2 3]", got)
+	}
+	if got := values(lb); !slices.Equal(got, []int{3, 2, 1, 0}) {
+		t.Errorf("lb = %v, want [3 2 1 0]", got)
+	}
+
+	la.MoveToFront(&xs[2])
+	la.Remove(&xs[0])
+	la.InsertAfter(&xs[0], &xs[3])
+	if got := values(la); !slices.Equal(got, []int{2, 1, 3, 0}) {
+		t.Errorf("la = %v, want [2 1 3 0]", got)
+	}
+	if la.Front() != &xs[2] || la.Next(&xs[3]) != &xs[0] || la.Next(&xs[0]) != nil || la.Prev(&xs[2]) != nil {
+		t.Errorf("la navigation wrong")
+	}
+	if la.Remove(&hooked{}) || !lb.Contains(&xs[0]) || la.Len() != 4 {
+		t.Errorf("la.Remove of a foreign value or lb.Contains wrong")
+	}
+
+	// removing during iteration
+	for x := range lb.Backward() {
+		if x.v%2 == 0 {
+			lb.Remove(x)
+		}
+	}
+	if got := values(lb); !slices.Equal(got, []int{3, 1}) || xs[0].b.Linked() {
+		t.Errorf("lb = %v, want [3 1]", got)
+	}
+
+	la.Clear()
+	if la.Len() != 0 || xs[1].a.Linked() {
+		t.Errorf("la not empty after Clear")
+	}
+	la.PushBack(&xs[1])
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("pushing a linked value did not panic")
+		}
+	}()
+	la.PushFront(&xs[1])
+}
+
+func BenchmarkIntrusivePushRemove(b *testing.B) {
+	l := NewIntrusive(func(x *hooked) *Hook[hooked] { return &x.a })
+	var x hooked
+	for b.Loop() {
+		l.PushBack(&x)
+		l.Remove(&x)
+	}
+}
+
+func TestPersistent(t *testing.T) {
+	t.Parallel()
+
+	var empty Persistent[int]
+	if _, ok := empty.Head(); ok || empty.Len() != 0 || empty.Tail().Len() != 0 {
+		t.Errorf("zero Persistent is not empty")
+	}
+
+	p := PersistentOf(2, 3)
+	q := p.Cons(1)
+	r := p.Cons(0)
+	if got := q.AppendTo(nil); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("q = %v, want [1 2 3]", got)
+	}
+	if got := slices.Collect(r.Values()); !slices.Equal(got, []int{0, 2, 3}) {
+		t.Errorf("r = %v, want [0 2 3]", got)
+	}
+	if got := p.AppendTo(nil); !slices.Equal(got, []int{2, 3}) || p.Len() != 2 {
+		t.Errorf("p changed by Cons: %v", got)
+	}
+	if q.Tail().c != p.c || r.Tail().c != p.c {
+		t.Errorf("tails are not shared")
+	}
+
+	if v, ok := q.Head(); !ok || v != 1 {
+		t.Errorf("q.Head() = %d, %t, want 1, true", v, ok)
+	}
+	if got := q.Reverse().AppendTo(nil); !slices.Equal(got, []int{3, 2, 1}) || q.Reverse().Len() != 3 {
+		t.Errorf("q.Reverse() = %v, want [3 2 1]", got)
+	}
+}
+
+func TestIndexedList(t *testing.T) {
+	t.Parallel()
+
+	type entry struct {
+		k string
+		v int
+	}
+
+	var removed []string
+	l := NewIndexed(func(x entry) string { return x.k },
+		WithOnRemove(func(x entry) { removed = append(removed, x.k) }))
+	checkIndex := func() {
+		t.Helper()
+		if len(l.index) != l.Len() {
+			t.Fatalf("index has %d keys, list %d elements", len(l.index), l.Len())
+		}
+		for e := range l.All() {
+			if l.GetByKey(e.Value.k) != e {
+				t.Fatalf("index of %q wrong", e.Value.k)
+			}
+		}
+	}
+
+	l.PushBackSlice([]entry{{"a", 1}, {"b", 2}, {"c", 3}})
+	checkIndex()
+
+	if !l.MoveToFrontByKey("c") || l.Front().Value.k != "c" {
+		t.Errorf(`l.MoveToFrontByKey("c") did not move c to the front`)
+	}
+	if !l.MoveToBackByKey("c") || l.Back().Value.k != "c" || l.MoveToBackByKey("x") {
+		t.Errorf(`l.MoveToBackByKey results wrong`)
+	}
+
+	// a value with a present key replaces the element that had it
+	l.PushFront(entry{"b", 20})
+	checkIndex()
+	if e := l.GetByKey("b"); e != l.Front() || e.Value.v != 20 || l.Len() != 3 {
+		t.Errorf(`l.GetByKey("b") = %v, want the new front`, e.Value)
+	}
+
+	if x, ok := l.RemoveByKey("a"); !ok || x.v != 1 || l.ContainsKey("a") {
+		t.Errorf(`l.RemoveByKey("a") = %v, %t, want {a 1}, true`, x, ok)
+	}
+	if _, ok := l.RemoveByKey("a"); ok {
+		t.Errorf(`second l.RemoveByKey("a") reports ok`)
+	}
+
+	l.RemoveFunc(func(x entry) bool { return x.v == 3 })
+	checkIndex()
+	l.Clear()
+	checkIndex()
+	if want := []string{"b", "a", "c", "b"}; !slices.Equal(removed, want) {
+		t.Errorf("removed = %q, want %q", removed, want)
+	}
+
+	// bulk insertions of present keys replace the old elements afterwards
+	n := NewIndexed(func(v int) int { return v })
+	n.PushBack(1)
+	n.PushBack(2)
+	n.PushBackSlice(n.ToSlice())
+	n.PushBackList(NewFromSlice([]int{2, 3, 3}))
+	if got := n.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) || len(n.index) != 3 {
+		t.Errorf("after pushing present keys n = %v with %d keys, want [1 2 3]", got, len(n.index))
+	}
+	for e := range n.All() {
+		if n.GetByKey(e.Value) != e {
+			t.Errorf("index of %d wrong after bulk pushes", e.Value)
+		}
+	}
+
+	m := NewIndexed(func(x entry) string { return x.k })
+	m.PushBack(entry{"a", 1})
+	byV := func(a, b entry) bool { return a.v < b.v }
+	m.MergeSorted(NewFromSlice([]entry{{"a", 1}, {"b", 2}}), byV)
+	if got := m.ToSlice(); len(got) != 2 || got[0].k != "a" || got[1].k != "b" || m.GetByKey("a") != m.Front() {
+		t.Errorf("m after MergeSorted with a present key = %v", got)
+	}
+
+	if m.Init().Len() != 0 || m.ContainsKey("a") || m.GetByKey("b") != nil {
+		t.Errorf("m.Init() left keys in the index")
+	}
+	m.PushBack(entry{"a", 3})
+	if e := m.GetByKey("a"); e == nil || e.Value.v != 3 || m.Len() != 1 {
+		t.Errorf("m after Init and PushBack = %v", m.ToSlice())
+	}
+}
+
+func (u *Unrolled[E]) verify(t *testing.T) {
+	t.Helper()
+
+	n, nodes := 0, 0
+	var prev *unode[E]
+	for x := u.head; x != nil; x = x.next {
+		if x.prev != prev {
+			t.Fatalf("node %d has a wrong prev pointer", nodes)
+		}
+		if len(x.vals) == 0 || len(x.vals) > u.size() {
+			t.Fatalf("node %d holds %d values", nodes, len(x.vals))
+		}
+		n += len(x.vals)
+		nodes++
+		prev = x
+	}
+	if u.tail != prev || u.len != n || u.nodes != nodes {
+		t.Fatalf("u.len, u.nodes = %d, %d, want %d, %d", u.len, u.nodes, n, nodes)
+	}
+}
+
+func TestUnrolled(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	u := NewUnrolled[int](4)
+	var want []int
+	for i := range 2000 {
+		switch op := r.Intn(8); {
+		case op < 2:
+			u.PushBack(i)
+			want = append(want, i)
+		case op < 3:
+			u.PushFront(i)
+			want = slices.Insert(want, 0, i)
+		case op < 5:
+			j := r.Intn(len(want) + 1)
+			u.Insert(j, i)
+			want = slices.Insert(want, j, i)
+		case len(want) == 0:
+		case op < 6:
+			v, _ := u.PopFront()
+			if v != want[0] {
+				t.Fatalf("u.PopFront() = %d, want %d", v, want[0])
+			}
+			want = want[1:]
+		default:
+			j := r.Intn(len(want))
+			if v := u.Delete(j); v != want[j] {
+				t.Fatalf("u.Delete(%d) = %d, want %d", j, v, want[j])
+			}
+			want = slices.Delete(want, j, j+1)
+		}
+		u.verify(t)
+	}
+
+	if got := slices.Collect(u.Values()); !slices.Equal(got, want) {
+		t.Fatalf("u.Values() = %v, want %v", got, want)
+	}
+	for i, v := range u.All() {
+		if want[i] != v || u.At(i) != v {
+			t.Fatalf("u.All() yielded %d, %d, want %d", i, v, want[i])
+		}
+	}
+	for i, v := range u.Backward() {
+		if want[i] != v {
+			t.Fatalf("u.Backward() yielded %d, %d, want %d", i, v, want[i])
+		}
+	}
+
+	u.Set(0, -1)
+	if v, ok := u.Front(); !ok || v != -1 {
+		t.Errorf("u.Front() = %d, %t, want -1, true", v, ok)
+	}
+	if v, ok := u.Back(); !ok || v != want[len(want)-1] {
+		t.Errorf("u.Back() = %d, %t, want %d, true", v, ok, want[len(want)-1])
+	}
+
+	for u.Len() > 0 {
+		u.PopBack()
+	}
+	u.verify(t)
+	if _, ok := u.PopBack(); ok {
+		t.Errorf("u.PopBack() on an empty list reports ok")
+	}
+
+	var z Unrolled[string]
+	z.PushBack("b")
+	z.PushFront("a")
+	if got := slices.Collect(z.Values()); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("zero Unrolled holds %q, want [a b]", got)
+	}
+	if s := z.MemStats(); s.Len != 2 || s.Cap != defaultNodeSize {
+		t.Errorf("z.MemStats() = %+v", s)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("z.At(2) did not panic")
+		}
+	}()
+	z.At(2)
+}
+
+func BenchmarkUnrolledValues(b *testing.B) {
+	var u Unrolled[int]
+	for range 1000 {
+		u.PushBack(0)
+	}
+	for b.Loop() {
+		n := 0
+		for v := range u.Values() {
+			n += v
+		}
+	}
+}
+
+func BenchmarkUnrolledPushBack(b *testing.B) {
+	for b.Loop() {
+		var u Unrolled[int]
+		for i := range 1000 {
+			u.PushBack(i)
+		}
+	}
+}
+
+func TestView(t *testing.T) {
+	t.Parallel()
+
+	l := NewFromSlice([]int{1, 2, 3, 4, 5})
+	second, fourth := l.Front().Next(), l.Back().Prev()
+
+	v := l.Range(second, fourth)
+	if got := v.ToSlice(); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("v.ToSlice() = %v, want [2 3 4]", got)
+	}
+	var back []int
+	for e := range v.Backward() {
+		back = append(back, e.Value)
+	}
+	if !slices.Equal(back, []int{4, 3, 2}) {
+		t.Errorf("v.Backward() yielded %v, want [4 3 2]", back)
+	}
+	for x := range v.Values() {
+		if x == 3 {
+			break
+		}
+		if x != 2 {
+			t.Errorf("v.Values() yielded %d before 3", x)
+		}
+	}
+
+	if got := l.Range(second, second).ToSlice(); !slices.Equal(got, []int{2}) {
+		t.Errorf("single element view = %v, want [2]", got)
+	}
+	if got := l.Range(second, New[int]().PushBack(0)).ToSlice(); got != nil {
+		t.Errorf("view ending in another list = %v, want empty", got)
+	}
+	var zero View[int]
+	if zero.Front() != nil || zero.RemoveAll() != 0 {
+		t.Errorf("zero View is not empty")
+	}
+
+	if n := v.RemoveAll(); n != 3 {
+		t.Errorf("v.RemoveAll() = %d, want 3", n)
+	}
+	checkList(t, l, []int{1, 5})
+}
+
+func TestStd(t *testing.T) {
+	t.Parallel()
+
+	sl := stdlist.New()
+	for _, v := range []int{1, 2, 3} {
+		sl.PushBack(v)
+	}
+
+	l := FromStd[int](sl)
+	checkList(t, l, []int{1, 2, 3})
+
+	back := l.ToStd()
+	var got []int
+	for e := back.Front(); e != nil; e = e.Next() {
+		got = append(got, e.Value.(int))
+	}
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("l.ToStd() holds %v, want [1 2 3]", got)
+	}
+
+	sl.PushBack("4")
+	s := FromStdFunc(sl, func(v any) string { return fmt.Sprint(v) })
+	checkList(t, s, []string{"1", "2", "3", "4"})
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("FromStd of a mistyped value did not panic")
+		}
+	}()
+	FromStd[int](sl)
+}
+
+func TestConcurrentReaders(t *testing.T) {
+	t.Parallel()
+
+	// elements moved by splices reach their list through forwarded owners
+	l := New[int]()
+	for i := range 4 {
+		other := NewFromSlice([]int{i, i})
+		l.SpliceBack(other)
+	}
+	front, back := l.Front(), l.Back()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if i := l.IndexOf(back); i != 7 {
+					t.Errorf("l.IndexOf(back) = %d, want 7", i)
+				}
+				if n := len(l.Range(front, back).ToSlice()); n != 8 {
+					t.Errorf("len(l.Range(front, back).ToSlice()) = %d, want 8", n)
+				}
+				for c := l.CursorFront(); c.Next(); {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
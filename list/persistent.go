@@ -0,0 +1,99 @@
+package list
+
+import "iter"
+
+// cell is a node of a Persistent list. Cells are never modified after
+// construction, so tails are shared between lists.
+type cell[E any] struct {
+	head E
+	tail *cell[E]
+	len  int
+}
+
+// Persistent is an immutable singly linked list: a cons list. Cons returns
+// a new list that shares all the cells of the receiver, so keeping old
+// versions costs nothing, and a Persistent list is safe for concurrent use
+// by multiple goroutines without locking.
+// The zero value is an empty list ready to use.
+type Persistent[E any] struct {
+	c *cell[E]
+}
+
+// PersistentOf returns a persistent list of the values vs, with vs[0] at
+// its head.
+func PersistentOf[E any](vs ...E) Persistent[E] {
+	var p Persistent[E]
+	for i := len(vs) - 1; i >= 0; i-- {
+		p = p.Cons(vs[i])
+	}
+
+	return p
+}
+
+// Len returns the number of values of list p.
+// The complexity is O(1).
+func (p Persistent[E]) Len() int {
+	if p.c == nil {
+		return 0
+	}
+
+	return p.c.len
+}
+
+// Cons returns a list with v at its head and the values of p after it.
+// p is not modified.
+func (p Persistent[E]) Cons(v E) Persistent[E] {
+	return Persistent[E]{&cell[E]{head: v, tail: p.c, len: p.Len() + 1}}
+}
+
+// Head returns the first value of list p.
+// The ok result reports whether p is non-empty.
+func (p Persistent[E]) Head() (v E, ok bool) {
+	if p.c == nil {
+		return v, false
+	}
+
+	return p.c.head, true
+}
+
+// Tail returns the list of the values of p after its head, which shares
+// the cells of p, or an empty list if p is empty.
+func (p Persistent[E]) Tail() Persistent[E] {
+	if p.c == nil {
+		return p
+	}
+
+	return Persistent[E]{p.c.tail}
+}
+
+// Reverse returns a list of the values of p in reverse order. It copies
+// every value, so the complexity is O(n).
+func (p Persistent[E]) Reverse() Persistent[E] {
+	var r Persistent[E]
+	for c := p.c; c != nil; c = c.tail {
+		r = r.Cons(c.head)
+	}
+
+	return r
+}
+
+// Values returns an iterator over the values of list p from head to end.
+func (p Persistent[E]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for c := p.c; c != nil; c = c.tail {
+			if !yield(c.head) {
+				return
+			}
+		}
+	}
+}
+
+// AppendTo appends the values of list p to dst from head to end and
+// returns the extended slice.
+func (p Persistent[E]) AppendTo(dst []E) []E {
+	for c := p.c; c != nil; c = c.tail {
+		dst = append(dst, c.head)
+	}
+
+	return dst
+}
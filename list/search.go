@@ -0,0 +1,109 @@
+package list
+
+// Find returns the first element of list l whose value is equal to v as
+// reported by eq, or nil if there is none.
+func (l *List[E]) Find(v E, eq func(E, E) bool) *Element[E] {
+	return l.FindFunc(func(x E) bool { return eq(x, v) })
+}
+
+// FindFunc returns the first element of list l whose value satisfies pred,
+// or nil if there is none.
+func (l *List[E]) FindFunc(pred func(E) bool) *Element[E] {
+	for e := l.Front(); e != nil; e = e.Next() {
+		if pred(e.Value) {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// ContainsFunc reports whether at least one value of list l satisfies
+// pred.
+func (l *List[E]) ContainsFunc(pred func(E) bool) bool {
+	return l.FindFunc(pred) != nil
+}
+
+// IndexFunc returns the position, counting from the front, of the first
+// value of list l satisfying pred, or -1 if none do.
+func (l *List[E]) IndexFunc(pred func(E) bool) int {
+	i := 0
+	for e := l.Front(); e != nil; e = e.Next() {
+		if pred(e.Value) {
+			return i
+		}
+		i++
+	}
+
+	return -1
+}
+
+// At returns the element at position i of list l, counting from the
+// front, or nil if i is out of range. It walks from the closer end of l,
+// so the complexity is O(min(i, l.Len()-i)).
+func (l *List[E]) At(i int) *Element[E] {
+	if i < 0 || i >= l.len {
+		return nil
+	}
+
+	if i <= l.len-1-i {
+		e := l.root.next
+		for range i {
+			e = e.next
+		}
+		return e
+	}
+
+	e := l.root.prev
+	for range l.len - 1 - i {
+		e = e.prev
+	}
+	return e
+}
+
+// IndexOf returns the position of element e in list l, counting from the
+// front, or -1 if e is not an element of l.
+// The element must not be nil.
+func (l *List[E]) IndexOf(e *Element[E]) int {
+	if l != e.list() {
+		return -1
+	}
+
+	i := 0
+	for p := e.prev; p != &l.root; p = p.prev {
+		i++
+	}
+
+	return i
+}
+
+// MinFunc returns the element of list l with the minimal value as
+// determined by cmp, or nil if l is empty. If there are several minimal
+// values, MinFunc returns the first, like slices.MinFunc.
+func MinFunc[E any](l *List[E], cmp func(a, b E) int) *Element[E] {
+	return extreme(l, func(a, b E) bool { return cmp(a, b) < 0 })
+}
+
+// MaxFunc returns the element of list l with the maximal value as
+// determined by cmp, or nil if l is empty. If there are several maximal
+// values, MaxFunc returns the first, like slices.MaxFunc.
+func MaxFunc[E any](l *List[E], cmp func(a, b E) int) *Element[E] {
+	return extreme(l, func(a, b E) bool { return cmp(a, b) > 0 })
+}
+
+// extreme returns the first element of l whose value no later value is
+// better than, or nil if l is empty.
+func extreme[E any](l *List[E], better func(a, b E) bool) *Element[E] {
+	m := l.Front()
+	if m == nil {
+		return nil
+	}
+
+	for e := m.Next(); e != nil; e = e.Next() {
+		if better(e.Value, m.Value) {
+			m = e
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,66 @@
+package list
+
+// Sort sorts list l in ascending order as determined by less, which must
+// be a strict weak ordering. Sort is stable: equal elements keep their
+// original order.
+//
+// Sort is a bottom-up merge sort that relinks the elements in place, so it
+// allocates nothing, runs in O(n log n) time, and elements held by the
+// caller stay valid and keep their values.
+func (l *List[E]) Sort(less func(a, b E) bool) {
+	if l.len < 2 {
+		return
+	}
+
+	// Sort the elements as a nil-terminated chain of next links, merging
+	// runs of width 1, 2, 4, ... until a single run is left.
+	head := l.root.next
+	l.root.prev.next = nil
+	for width := 1; ; width *= 2 {
+		var first, tail *Element[E]
+		merges := 0
+		for p := head; p != nil; merges++ {
+			q, plen := p, 0
+			for plen < width && q != nil {
+				q = q.next
+				plen++
+			}
+
+			qlen := width
+			for plen > 0 || qlen > 0 && q != nil {
+				var e *Element[E]
+				if plen == 0 || qlen > 0 && q != nil && less(q.Value, p.Value) {
+					e, q = q, q.next
+					qlen--
+				} else {
+					e, p = p, p.next
+					plen--
+				}
+
+				if tail == nil {
+					first = e
+				} else {
+					tail.next = e
+				}
+				tail = e
+			}
+
+			p = q
+		}
+
+		tail.next = nil
+		head = first
+		if merges <= 1 {
+			break
+		}
+	}
+
+	prev := &l.root
+	for e := head; e != nil; e = e.next {
+		e.prev = prev
+		prev.next = e
+		prev = e
+	}
+	prev.next = &l.root
+	l.root.prev = prev
+}
@@ -0,0 +1,31 @@
+package list
+
+import stdlist "container/list"
+
+// FromStd returns a new list of the values of the standard library list
+// sl, from front to back, with the options opts. It panics if a value is
+// not of type E; use FromStdFunc to convert the values otherwise.
+func FromStd[E any](sl *stdlist.List, opts ...option[E]) *List[E] {
+	return FromStdFunc(sl, func(v any) E { return v.(E) }, opts...)
+}
+
+// FromStdFunc is like FromStd but converts each value of sl with conv.
+func FromStdFunc[E any](sl *stdlist.List, conv func(any) E, opts ...option[E]) *List[E] {
+	l := New(opts...)
+	for e := sl.Front(); e != nil; e = e.Next() {
+		l.PushBack(conv(e.Value))
+	}
+
+	return l
+}
+
+// ToStd returns a new standard library list of the values of list l, from
+// front to back.
+func (l *List[E]) ToStd() *stdlist.List {
+	sl := stdlist.New()
+	for e := l.Front(); e != nil; e = e.Next() {
+		sl.PushBack(e.Value)
+	}
+
+	return sl
+}
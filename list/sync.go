@@ -0,0 +1,142 @@
+package list
+
+import (
+	"iter"
+	"sync"
+)
+
+// SyncList is a list safe for concurrent use by multiple goroutines.
+// It works with values rather than elements, since an element could be
+// changed by another goroutine as soon as it is returned; Do runs a
+// sequence of operations on the underlying list as a unit.
+// SyncList is a container.Deque.
+// The zero value is an empty list ready to use.
+// A SyncList must not be copied after first use.
+type SyncList[E any] struct {
+	mu sync.RWMutex
+	l  List[E]
+}
+
+// NewSyncList returns a concurrent list of the values vs.
+func NewSyncList[E any](vs ...E) *SyncList[E] {
+	s := &SyncList[E]{}
+	s.l.PushBackSlice(vs)
+	return s
+}
+
+// Len returns the number of values of list s.
+func (s *SyncList[E]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.l.Len()
+}
+
+// Clear removes all values from list s.
+func (s *SyncList[E]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.l.Clear()
+}
+
+// PushFront inserts v at the front of list s.
+func (s *SyncList[E]) PushFront(v E) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.l.PushFront(v)
+}
+
+// PushBack inserts v at the back of list s.
+func (s *SyncList[E]) PushBack(v E) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.l.PushBack(v)
+}
+
+// PopFront removes and returns the value at the front of list s.
+// The ok result reports whether s was non-empty.
+func (s *SyncList[E]) PopFront() (v E, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.l.popFront()
+}
+
+// PopBack removes and returns the value at the back of list s.
+// The ok result reports whether s was non-empty.
+func (s *SyncList[E]) PopBack() (v E, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.l.popBack()
+}
+
+// PeekFront returns the value at the front of list s.
+// The ok result reports whether s is non-empty.
+func (s *SyncList[E]) PeekFront() (v E, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.l.peekFront()
+}
+
+// PeekBack returns the value at the back of list s.
+// The ok result reports whether s is non-empty.
+func (s *SyncList[E]) PeekBack() (v E, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.l.peekBack()
+}
+
+// RemoveFunc removes the values of list s satisfying pred and returns the
+// number removed. pred is called with the lock held and must not use s.
+func (s *SyncList[E]) RemoveFunc(pred func(E) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.l.RemoveFunc(pred)
+}
+
+// ContainsFunc reports whether at least one value of list s satisfies
+// pred. pred is called with the lock held and must not use s.
+func (s *SyncList[E]) ContainsFunc(pred func(E) bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.l.ContainsFunc(pred)
+}
+
+// ToSlice returns a snapshot of the values of list s from front to back.
+func (s *SyncList[E]) ToSlice() []E {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.l.ToSlice()
+}
+
+// Values returns an iterator over a snapshot of the values of list s from
+// front to back, taken when iteration starts. The lock is not held while
+// the values are yielded, so the loop body may use s.
+func (s *SyncList[E]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, v := range s.ToSlice() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Do calls f with the underlying list while holding the write lock, so a
+// sequence of operations runs as a unit. Neither the list nor its elements
+// may be retained after f returns.
+func (s *SyncList[E]) Do(f func(l *List[E])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f(&s.l)
+}
@@ -0,0 +1,311 @@
+package list
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+// defaultNodeSize is the number of values per node of a zero Unrolled.
+const defaultNodeSize = 64
+
+// unode is a node of an Unrolled list, holding between 1 and the node size
+// of its list values.
+type unode[E any] struct {
+	next, prev *unode[E]
+	vals       []E
+}
+
+// Unrolled is a doubly linked list that stores several values per node,
+// which saves most of the pointers and allocations of List and keeps
+// neighbouring values together in memory. Values do not have stable
+// elements; they are addressed by position instead.
+// The zero value is an empty list, with nodes of 64 values, ready to use.
+// To choose the node size use list.NewUnrolled.
+type Unrolled[E any] struct {
+	head, tail *unode[E]
+	len        int
+	nodes      int
+	nodeSize   int
+}
+
+// NewUnrolled returns an empty unrolled list whose nodes hold up to
+// nodeSize values. It panics if nodeSize < 2.
+func NewUnrolled[E any](nodeSize int) *Unrolled[E] {
+	if nodeSize < 2 {
+		panic("list: node size less than 2")
+	}
+
+	return &Unrolled[E]{nodeSize: nodeSize}
+}
+
+func (u *Unrolled[E]) size() int {
+	if u.nodeSize == 0 {
+		return defaultNodeSize
+	}
+
+	return u.nodeSize
+}
+
+// Len returns the number of values of list u.
+// The complexity is O(1).
+func (u *Unrolled[E]) Len() int { return u.len }
+
+// Clear removes all values from list u.
+func (u *Unrolled[E]) Clear() {
+	u.head, u.tail = nil, nil
+	u.len, u.nodes = 0, 0
+}
+
+// Front returns the first value of list u.
+// The ok result reports whether u is non-empty.
+func (u *Unrolled[E]) Front() (v E, ok bool) {
+	if u.head == nil {
+		return v, false
+	}
+
+	return u.head.vals[0], true
+}
+
+// Back returns the last value of list u.
+// The ok result reports whether u is non-empty.
+func (u *Unrolled[E]) Back() (v E, ok bool) {
+	if u.tail == nil {
+		return v, false
+	}
+
+	return u.tail.vals[len(u.tail.vals)-1], true
+}
+
+// newNode links a new empty node after at, or at the front if at is nil.
+func (u *Unrolled[E]) newNode(at *unode[E]) *unode[E] {
+	n := &unode[E]{vals: make([]E, 0, u.size())}
+	if at == nil {
+		n.next = u.head
+		u.head = n
+	} else {
+		n.next = at.next
+		n.prev = at
+		at.next = n
+	}
+
+	if n.next != nil {
+		n.next.prev = n
+	} else {
+		u.tail = n
+	}
+
+	u.nodes++
+	return n
+}
+
+// unlink removes the empty node n.
+func (u *Unrolled[E]) unlink(n *unode[E]) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		u.head = n.next
+	}
+
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		u.tail = n.prev
+	}
+
+	u.nodes--
+}
+
+// PushFront inserts v at the front of list u.
+func (u *Unrolled[E]) PushFront(v E) {
+	n := u.head
+	if n == nil || len(n.vals) == cap(n.vals) {
+		n = u.newNode(nil)
+	}
+
+	n.vals = slices.Insert(n.vals, 0, v)
+	u.len++
+}
+
+// PushBack inserts v at the back of list u.
+func (u *Unrolled[E]) PushBack(v E) {
+	n := u.tail
+	if n == nil || len(n.vals) == cap(n.vals) {
+		n = u.newNode(u.tail)
+	}
+
+	n.vals = append(n.vals, v)
+	u.len++
+}
+
+// PopFront removes and returns the first value of list u.
+// The ok result reports whether u was non-empty.
+func (u *Unrolled[E]) PopFront() (v E, ok bool) {
+	if u.head == nil {
+		return v, false
+	}
+
+	return u.delete(u.head, 0), true
+}
+
+// PopBack removes and returns the last value of list u.
+// The ok result reports whether u was non-empty.
+func (u *Unrolled[E]) PopBack() (v E, ok bool) {
+	if u.tail == nil {
+		return v, false
+	}
+
+	return u.delete(u.tail, len(u.tail.vals)-1), true
+}
+
+// find returns the node holding the value at position i and the index of
+// the value in it, walking from the closer end of list u.
+// It panics if i is out of range.
+func (u *Unrolled[E]) find(i int) (*unode[E], int) {
+	if i < 0 || i >= u.len {
+		panic("list: index out of range")
+	}
+
+	if i < u.len/2 {
+		n := u.head
+		for i >= len(n.vals) {
+			i -= len(n.vals)
+			n = n.next
+		}
+		return n, i
+	}
+
+	n, j := u.tail, u.len-1-i // j counts from the back
+	for j >= len(n.vals) {
+		j -= len(n.vals)
+		n = n.prev
+	}
+	return n, len(n.vals) - 1 - j
+}
+
+// At returns the value at position i of list u.
+// It panics if i is out of range.
+func (u *Unrolled[E]) At(i int) E {
+	n, j := u.find(i)
+	return n.vals[j]
+}
+
+// Set sets the value at position i of list u to v.
+// It panics if i is out of range.
+func (u *Unrolled[E]) Set(i int, v E) {
+	n, j := u.find(i)
+	n.vals[j] = v
+}
+
+// Insert inserts v at position i of list u, so that At(i) returns v.
+// It panics if i < 0 or i > u.Len().
+func (u *Unrolled[E]) Insert(i int, v E) {
+	switch {
+	case i == u.len:
+		u.PushBack(v)
+		return
+	case i == 0:
+		u.PushFront(v)
+		return
+	}
+
+	n, j := u.find(i)
+	if len(n.vals) == cap(n.vals) {
+		// Split the full node in halves.
+		m := u.newNode(n)
+		h := len(n.vals) / 2
+		m.vals = append(m.vals, n.vals[h:]...)
+		clear(n.vals[h:])
+		n.vals = n.vals[:h]
+		if j >= h {
+			n, j = m, j-h
+		}
+	}
+
+	n.vals = slices.Insert(n.vals, j, v)
+	u.len++
+}
+
+// Delete removes and returns the value at position i of list u.
+// It panics if i is out of range.
+func (u *Unrolled[E]) Delete(i int) E {
+	n, j := u.find(i)
+	return u.delete(n, j)
+}
+
+// delete removes value j of node n, freeing or merging the node when it
+// becomes sparse.
+func (u *Unrolled[E]) delete(n *unode[E], j int) E {
+	v := n.vals[j]
+	n.vals = slices.Delete(n.vals, j, j+1)
+	u.len--
+
+	switch {
+	case len(n.vals) == 0:
+		u.unlink(n)
+	case len(n.vals) < cap(n.vals)/4 && n.next != nil && len(n.vals)+len(n.next.vals) <= cap(n.vals)/2:
+		m := n.next
+		n.vals = append(n.vals, m.vals...)
+		m.vals = m.vals[:0]
+		u.unlink(m)
+	}
+
+	return v
+}
+
+// All returns an iterator over the positions and values of list u from
+// front to back. u must not be modified during iteration.
+func (u *Unrolled[E]) All() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		i := 0
+		for n := u.head; n != nil; n = n.next {
+			for _, v := range n.vals {
+				if !yield(i, v) {
+					return
+				}
+				i++
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values of list u from front to back.
+// u must not be modified during iteration.
+func (u *Unrolled[E]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for n := u.head; n != nil; n = n.next {
+			for _, v := range n.vals {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the positions and values of list u
+// from back to front. u must not be modified during iteration.
+func (u *Unrolled[E]) Backward() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		i := u.len - 1
+		for n := u.tail; n != nil; n = n.prev {
+			for j := len(n.vals) - 1; j >= 0; j-- {
+				if !yield(i, n.vals[j]) {
+					return
+				}
+				i--
+			}
+		}
+	}
+}
+
+// MemStats returns statistics about the memory used by list u.
+func (u *Unrolled[E]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len:   u.len,
+		Cap:   u.nodes * u.size(),
+		Bytes: memsize.Of[Unrolled[E]]() + int64(u.nodes)*(memsize.Of[unode[E]]()+memsize.Slice[E](u.size())),
+	}
+}
@@ -0,0 +1,107 @@
+package list
+
+import "iter"
+
+// View is a contiguous span of a List, from a first to a last element
+// inclusive. It does not copy the elements, so changes to the values are
+// visible through the view. It has no Len, as that would take O(n) time;
+// iterate over it instead. The zero View is empty.
+// To create a view use List.Range.
+type View[E any] struct {
+	l           *List[E]
+	first, last *Element[E]
+}
+
+// Range returns a view of the elements of list l from first to last,
+// inclusive. If first or last is not an element of l, the view is empty.
+// first must not come after last; this is not checked, as that would take
+// O(n) time. The view stays valid while first and last are in l and
+// between each other.
+func (l *List[E]) Range(first, last *Element[E]) View[E] {
+	if first == nil || last == nil || l != first.list() || l != last.list() {
+		return View[E]{}
+	}
+
+	return View[E]{l, first, last}
+}
+
+// Front returns the first element of view v or nil if v is empty.
+func (v View[E]) Front() *Element[E] { return v.first }
+
+// Back returns the last element of view v or nil if v is empty.
+func (v View[E]) Back() *Element[E] { return v.last }
+
+// All returns an iterator over the elements of view v from front to back.
+// The element being yielded may be removed from the list, but no other
+// element of v may be.
+func (v View[E]) All() iter.Seq[*Element[E]] {
+	return func(yield func(*Element[E]) bool) {
+		if v.first == nil {
+			return
+		}
+
+		for e := v.first; ; {
+			last, next := e == v.last, e.next
+			if !yield(e) || last {
+				return
+			}
+			e = next
+		}
+	}
+}
+
+// Backward returns an iterator over the elements of view v from back to
+// front. As with All, the element being yielded may be removed from the
+// list.
+func (v View[E]) Backward() iter.Seq[*Element[E]] {
+	return func(yield func(*Element[E]) bool) {
+		if v.last == nil {
+			return
+		}
+
+		for e := v.last; ; {
+			first, prev := e == v.first, e.prev
+			if !yield(e) || first {
+				return
+			}
+			e = prev
+		}
+	}
+}
+
+// Values returns an iterator over the values of view v from front to back.
+func (v View[E]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for e := range v.All() {
+			if !yield(e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// AppendTo appends the values of view v to dst from front to back and
+// returns the extended slice.
+func (v View[E]) AppendTo(dst []E) []E {
+	for e := range v.All() {
+		dst = append(dst, e.Value)
+	}
+
+	return dst
+}
+
+// ToSlice returns a new slice of the values of view v from front to back.
+func (v View[E]) ToSlice() []E { return v.AppendTo(nil) }
+
+// RemoveAll removes the elements of view v from the list, as if by
+// List.Remove, and returns the number removed. The view must not be used
+// afterwards.
+func (v View[E]) RemoveAll() int {
+	n := 0
+	for e := range v.All() {
+		v.l.Remove(e)
+		n++
+	}
+
+	return n
+}
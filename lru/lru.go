@@ -0,0 +1,360 @@
+// Package lru implements a fixed-capacity least-recently-used cache.
+//
+// A cache is bounded by the number of entries and, optionally, by the total
+// cost of its entries as computed by a caller-supplied cost function, such
+// as the size in bytes of each value.
+//
+// A Cache is not safe for concurrent use by multiple goroutines.
+package lru
+
+import (
+	"iter"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/cachestats"
+	"github.com/weiwenchen2022/container/internal/memsize"
+	"github.com/weiwenchen2022/container/list"
+)
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	cost  int64
+}
+
+// Cache is an LRU cache holding at most a fixed number of entries.
+// To create a cache use lru.New.
+type Cache[K comparable, V any] struct {
+	capacity int
+
+	costOf  func(K, V) int64
+	maxCost int64 // 0 means unbounded
+	cost    int64
+
+	ll    *list.List[entry[K, V]] // front is most recently used
+	items map[K]*list.Element[entry[K, V]]
+
+	onEvict  func(K, V)
+	onHit    func(K, V)
+	onInsert func(K, V)
+	onUpdate func(k K, old, new V)
+	onRemove func(K, V)
+
+	stats cachestats.Recorder
+}
+
+type option[K comparable, V any] func(*Cache[K, V])
+
+// WithOnEvict sets a function called with the key and value of each entry
+// evicted to make room for another or by Resize.
+// It is not called for entries removed with Remove or Clear.
+func WithOnEvict[K comparable, V any](f func(K, V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onEvict = f
+	}
+}
+
+// WithCost sets the function computing the cost of an entry, such as the
+// size in bytes of its value. The default cost of every entry is 1.
+func WithCost[K comparable, V any](f func(K, V) int64) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.costOf = f
+	}
+}
+
+// WithMaxCost bounds the total cost of the entries in the cache to n,
+// in addition to the bound on their number.
+// It panics if n is not positive.
+func WithMaxCost[K comparable, V any](n int64) option[K, V] {
+	if n <= 0 {
+		panic("lru: non-positive max cost")
+	}
+
+	return func(c *Cache[K, V]) {
+		c.maxCost = n
+	}
+}
+
+// WithOnHit sets a function called with the key and value of each entry
+// found by Get.
+func WithOnHit[K comparable, V any](f func(K, V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onHit = f
+	}
+}
+
+// WithOnInsert sets a function called with the key and value of each entry
+// added to the cache by Put.
+func WithOnInsert[K comparable, V any](f func(K, V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onInsert = f
+	}
+}
+
+// WithOnUpdate sets a function called with the key and the old and new
+// values of each entry whose value Put replaces.
+func WithOnUpdate[K comparable, V any](f func(k K, old, new V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onUpdate = f
+	}
+}
+
+// WithOnRemove sets a function called with the key and value of each entry
+// that leaves the cache, whether by Remove, Clear or eviction.
+// For evicted entries it is called before the function set by WithOnEvict.
+func WithOnRemove[K comparable, V any](f func(K, V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onRemove = f
+	}
+}
+
+// New returns an empty cache holding at most capacity entries.
+// It panics if capacity is not positive.
+func New[K comparable, V any](capacity int, opts ...option[K, V]) *Cache[K, V] {
+	if capacity <= 0 {
+		panic("lru: non-positive capacity")
+	}
+
+	c := &Cache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element[entry[K, V]]),
+	}
+	c.ll = c.newList()
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// newList returns an empty recency list that keeps the index and total
+// cost of cache c up to date as entries enter and leave it.
+func (c *Cache[K, V]) newList() *list.List[entry[K, V]] {
+	return list.New(
+		list.WithOnInsert(func(e *list.Element[entry[K, V]]) {
+			c.items[e.Value.key] = e
+			c.cost += e.Value.cost
+		}),
+		list.WithOnRemove(func(x entry[K, V]) {
+			delete(c.items, x.key)
+			c.cost -= x.cost
+			if c.onRemove != nil {
+				c.onRemove(x.key, x.value)
+			}
+		}))
+}
+
+// Len returns the number of entries in cache c.
+func (c *Cache[K, V]) Len() int { return c.ll.Len() }
+
+// Cap returns the maximum number of entries cache c holds.
+func (c *Cache[K, V]) Cap() int { return c.capacity }
+
+// Cost returns the total cost of the entries in cache c.
+func (c *Cache[K, V]) Cost() int64 { return c.cost }
+
+// MaxCost returns the bound on the total cost of the entries in cache c,
+// or 0 if it is unbounded.
+func (c *Cache[K, V]) MaxCost() int64 { return c.maxCost }
+
+func (c *Cache[K, V]) costOfEntry(k K, v V) int64 {
+	if c.costOf == nil {
+		return 1
+	}
+
+	return c.costOf(k, v)
+}
+
+// full reports whether cache c exceeds its bounds.
+func (c *Cache[K, V]) full() bool {
+	return c.ll.Len() > c.capacity || c.maxCost > 0 && c.cost > c.maxCost
+}
+
+// Hits returns the number of calls to Get that found their key.
+func (c *Cache[K, V]) Hits() uint64 { return c.stats.Hits() }
+
+// Misses returns the number of calls to Get that did not find their key.
+func (c *Cache[K, V]) Misses() uint64 { return c.stats.Misses() }
+
+// Stats returns a snapshot of the statistics of cache c.
+func (c *Cache[K, V]) Stats() cachestats.Stats { return c.stats.Stats() }
+
+// Get returns the value stored for key k and marks it as most recently used.
+// The ok result reports whether the key was present.
+func (c *Cache[K, V]) Get(k K) (v V, ok bool) {
+	e, ok := c.items[k]
+	if !ok {
+		c.stats.Miss()
+		return v, false
+	}
+
+	c.stats.Hit()
+	c.ll.MoveToFront(e)
+	if c.onHit != nil {
+		c.onHit(k, e.Value.value)
+	}
+
+	return e.Value.value, true
+}
+
+// Peek returns the value stored for key k without updating its recency or
+// the hit and miss counters.
+// The ok result reports whether the key was present.
+func (c *Cache[K, V]) Peek(k K) (v V, ok bool) {
+	if e, ok := c.items[k]; ok {
+		return e.Value.value, true
+	}
+
+	return v, false
+}
+
+// Contains reports whether key k is in cache c, without updating its recency.
+func (c *Cache[K, V]) Contains(k K) bool {
+	_, ok := c.items[k]
+	return ok
+}
+
+// Put stores value v for key k and marks it as most recently used,
+// evicting least recently used entries until the cache is within its
+// bounds. An entry whose cost alone exceeds the maximum cost is evicted
+// immediately, before any other entry, and an entry already stored for k
+// is removed.
+// It reports whether any entry was evicted.
+func (c *Cache[K, V]) Put(k K, v V) (evicted bool) {
+	cost := c.costOfEntry(k, v)
+	if c.maxCost > 0 && cost > c.maxCost {
+		// Evicting others first could not make room for it.
+		if e, ok := c.items[k]; ok {
+			c.ll.Remove(e)
+		}
+
+		c.stats.Evict()
+		if c.onEvict != nil {
+			c.onEvict(k, v)
+		}
+
+		return true
+	}
+
+	if e, ok := c.items[k]; ok {
+		old := e.Value.value
+		c.cost += cost - e.Value.cost
+		e.Value.value, e.Value.cost = v, cost
+		c.ll.MoveToFront(e)
+		if c.onUpdate != nil {
+			c.onUpdate(k, old, v)
+		}
+	} else {
+		c.ll.PushFront(entry[K, V]{k, v, cost})
+		if c.onInsert != nil {
+			c.onInsert(k, v)
+		}
+	}
+
+	for c.full() {
+		c.evict()
+		evicted = true
+	}
+
+	return evicted
+}
+
+// Remove removes the entry for key k and reports whether it was present.
+func (c *Cache[K, V]) Remove(k K) bool {
+	e, ok := c.items[k]
+	if ok {
+		c.ll.Remove(e)
+	}
+
+	return ok
+}
+
+// Oldest returns the least recently used entry without updating its recency.
+// The ok result reports whether the cache is non-empty.
+func (c *Cache[K, V]) Oldest() (k K, v V, ok bool) {
+	if e := c.ll.Back(); e != nil {
+		return e.Value.key, e.Value.value, true
+	}
+
+	return k, v, false
+}
+
+// Resize changes the capacity of cache c to capacity, evicting least
+// recently used entries as needed, and returns the number evicted.
+// It panics if capacity is not positive.
+func (c *Cache[K, V]) Resize(capacity int) (evicted int) {
+	if capacity <= 0 {
+		panic("lru: non-positive capacity")
+	}
+
+	c.capacity = capacity
+	for ; c.full(); evicted++ {
+		c.evict()
+	}
+
+	return evicted
+}
+
+// Clear removes all entries from cache c.
+// The statistics are not reset.
+func (c *Cache[K, V]) Clear() {
+	c.ll.Clear()
+}
+
+// Clone returns a copy of cache c with the same entries, recency order,
+// options and statistics. The values are copied by assignment, so this is
+// a shallow clone.
+func (c *Cache[K, V]) Clone() *Cache[K, V] {
+	return c.CloneFunc(func(v V) V { return v })
+}
+
+// CloneFunc is like Clone but copies each value with f, which allows a
+// deep clone.
+func (c *Cache[K, V]) CloneFunc(f func(V) V) *Cache[K, V] {
+	d := *c
+	d.items = make(map[K]*list.Element[entry[K, V]], len(c.items))
+	d.cost = 0
+	d.ll = d.newList()
+	d.stats = c.stats.Clone()
+	for e := c.ll.Front(); e != nil; e = e.Next() {
+		x := e.Value
+		x.value = f(x.value)
+		d.ll.PushBack(x)
+	}
+
+	return &d
+}
+
+func (c *Cache[K, V]) evict() {
+	x := c.ll.Remove(c.ll.Back())
+	c.stats.Evict()
+
+	if c.onEvict != nil {
+		c.onEvict(x.key, x.value)
+	}
+}
+
+// All returns an iterator over the entries of cache c from most to least
+// recently used, without updating their recency.
+// The cache must not be modified during iteration.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := c.ll.Front(); e != nil; e = e.Next() {
+			if !yield(e.Value.key, e.Value.value) {
+				return
+			}
+		}
+	}
+}
+
+// MemStats returns statistics about the memory used by cache c.
+// Cap is the capacity of the cache.
+func (c *Cache[K, V]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len: c.Len(),
+		Cap: c.capacity,
+		Bytes: memsize.Of[Cache[K, V]]() + c.ll.MemStats().Bytes +
+			memsize.Map[K, *list.Element[entry[K, V]]](len(c.items)),
+	}
+}
@@ -0,0 +1,259 @@
+package lru
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container = (*Cache[int, int])(nil)
+	_ container.Sizer     = (*Cache[int, int])(nil)
+)
+
+func keys[K comparable, V any](c *Cache[K, V]) []K {
+	var ks []K
+	for k := range c.All() {
+		ks = append(ks, k)
+	}
+
+	return ks
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	var evicted []int
+	c := New(3, WithOnEvict(func(k int, v string) {
+		evicted = append(evicted, k)
+	}))
+
+	for i, s := range []string{"a", "b", "c"} {
+		if c.Put(i, s) {
+			t.Errorf("c.Put(%d, %q) evicted", i, s)
+		}
+	}
+
+	if v, ok := c.Get(0); !ok || v != "a" {
+		t.Errorf("c.Get(0) = %q, %t, want %q, true", v, ok, "a")
+	}
+
+	if !c.Put(3, "d") {
+		t.Errorf("c.Put(3) did not evict")
+	}
+
+	if want := []int{1}; !slices.Equal(want, evicted) {
+		t.Errorf("evicted = %v, want %v", evicted, want)
+	}
+
+	if want, got := []int{3, 0, 2}, keys(c); !slices.Equal(want, got) {
+		t.Errorf("keys = %v, want %v", got, want)
+	}
+
+	if _, ok := c.Get(1); ok {
+		t.Errorf("c.Get(1) found evicted key")
+	}
+
+	if h, m := c.Hits(), c.Misses(); h != 1 || m != 1 {
+		t.Errorf("hits, misses = %d, %d, want 1, 1", h, m)
+	}
+
+	// Peek doesn't change recency or counters.
+	if v, ok := c.Peek(2); !ok || v != "c" {
+		t.Errorf("c.Peek(2) = %q, %t, want %q, true", v, ok, "c")
+	}
+	if k, _, _ := c.Oldest(); k != 2 {
+		t.Errorf("c.Oldest() = %d, want %d", k, 2)
+	}
+	if h, m := c.Hits(), c.Misses(); h != 1 || m != 1 {
+		t.Errorf("after Peek hits, misses = %d, %d, want 1, 1", h, m)
+	}
+
+	if !c.Remove(2) || c.Remove(2) {
+		t.Errorf("c.Remove(2) results wrong")
+	}
+
+	if n := c.Resize(1); n != 1 {
+		t.Errorf("c.Resize(1) = %d, want %d", n, 1)
+	}
+	if want, got := []int{3}, keys(c); !slices.Equal(want, got) {
+		t.Errorf("keys after Resize = %v, want %v", got, want)
+	}
+	if want := []int{1, 0}; !slices.Equal(want, evicted) {
+		t.Errorf("evicted = %v, want %v", evicted, want)
+	}
+
+	c.Clear()
+	if n := c.Len(); n != 0 {
+		t.Errorf("c.Len() after Clear = %d, want 0", n)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	c := New[string, int](2)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("a", 3)
+	c.Put("c", 4)
+
+	if _, ok := c.Peek("b"); ok {
+		t.Errorf("b should have been evicted")
+	}
+	if v, _ := c.Peek("a"); v != 3 {
+		t.Errorf(`c.Peek("a") = %d, want %d`, v, 3)
+	}
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	var hit []int
+	c := New(1, WithOnHit(func(k, v int) { hit = append(hit, k) }))
+	c.Put(1, 1)
+	c.Get(1)
+	c.Get(2)
+	c.Put(2, 2)
+
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.Evictions != 1 {
+		t.Errorf("c.Stats() = %+v", s)
+	}
+	if want := []int{1}; !slices.Equal(want, hit) {
+		t.Errorf("hit = %v, want %v", hit, want)
+	}
+}
+
+func TestCost(t *testing.T) {
+	t.Parallel()
+
+	var evicted []string
+	c := New(100,
+		WithCost(func(k string, v []byte) int64 { return int64(len(v)) }),
+		WithMaxCost[string, []byte](10),
+		WithOnEvict(func(k string, v []byte) { evicted = append(evicted, k) }))
+
+	c.Put("a", make([]byte, 4))
+	c.Put("b", make([]byte, 4))
+	if n := c.Cost(); n != 8 {
+		t.Errorf("c.Cost() = %d, want 8", n)
+	}
+
+	if !c.Put("c", make([]byte, 4)) {
+		t.Errorf(`c.Put("c") did not evict`)
+	}
+	if want, got := []string{"c", "b"}, keys(c); !slices.Equal(want, got) {
+		t.Errorf("keys = %v, want %v", got, want)
+	}
+
+	// growing an existing entry evicts others
+	c.Put("c", make([]byte, 9))
+	if want, got := []string{"c"}, keys(c); !slices.Equal(want, got) {
+		t.Errorf("keys = %v, want %v", got, want)
+	}
+	if n := c.Cost(); n != 9 {
+		t.Errorf("c.Cost() = %d, want 9", n)
+	}
+
+	// an entry larger than the budget is not retained and evicts nothing else
+	if !c.Put("d", make([]byte, 11)) {
+		t.Errorf(`c.Put("d") of an oversized entry did not report an eviction`)
+	}
+	if want, got := []string{"c"}, keys(c); !slices.Equal(want, got) || c.Cost() != 9 {
+		t.Errorf("keys = %v with cost %d, want %v with cost 9", got, c.Cost(), want)
+	}
+
+	// nor is an oversized update, which drops the stale value
+	c.Put("c", make([]byte, 12))
+	if c.Len() != 0 || c.Cost() != 0 {
+		t.Errorf("c.Len(), c.Cost() = %d, %d, want 0, 0", c.Len(), c.Cost())
+	}
+
+	if want := []string{"a", "b", "d", "c"}; !slices.Equal(want, evicted) {
+		t.Errorf("evicted = %v, want %v", evicted, want)
+	}
+
+	c.Put("e", make([]byte, 3))
+	c.Remove("e")
+	if n := c.Cost(); n != 0 {
+		t.Errorf("c.Cost() after Remove = %d, want 0", n)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	c := New[int, []int](3)
+	for i := range 3 {
+		c.Put(i, []int{i})
+	}
+	c.Get(0)
+
+	d := c.Clone()
+	if want, got := keys(c), keys(d); !slices.Equal(want, got) {
+		t.Errorf("clone keys = %v, want %v", got, want)
+	}
+	if d.Hits() != 1 || d.Cost() != c.Cost() {
+		t.Errorf("clone hits = %d, cost = %d, want 1, %d", d.Hits(), d.Cost(), c.Cost())
+	}
+
+	d.Put(3, nil)
+	if c.Len() != 3 || !c.Contains(1) {
+		t.Errorf("changing the clone changed the cache")
+	}
+
+	deep := c.CloneFunc(slices.Clone)
+	v, _ := deep.Peek(0)
+	v[0] = 9
+	if v, _ := c.Peek(0); v[0] != 0 {
+		t.Errorf("changing a deep-cloned value changed the cache")
+	}
+}
+
+func TestMemStats(t *testing.T) {
+	t.Parallel()
+
+	c := New[int, int](100)
+	empty := c.MemStats()
+	for i := range 50 {
+		c.Put(i, i)
+	}
+
+	s := c.MemStats()
+	if s.Len != 50 || s.Cap != 100 {
+		t.Errorf("c.MemStats() = %+v, want Len 50, Cap 100", s)
+	}
+	if grown := s.Bytes - empty.Bytes; grown < 50*(16+16) {
+		t.Errorf("50 entries take %d bytes, want at least %d", grown, 50*(16+16))
+	}
+}
+
+func TestObservers(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+	c := New(2,
+		WithOnInsert(func(k, v int) { events = append(events, fmt.Sprint("insert ", k, v)) }),
+		WithOnUpdate(func(k, old, new int) { events = append(events, fmt.Sprint("update ", k, old, new)) }),
+		WithOnRemove(func(k, v int) { events = append(events, fmt.Sprint("remove ", k, v)) }),
+		WithOnEvict(func(k, v int) { events = append(events, fmt.Sprint("evict ", k, v)) }))
+
+	c.Put(1, 1)
+	c.Put(1, 10)
+	c.Put(2, 2)
+	c.Put(3, 3)
+	c.Remove(2)
+	c.Remove(2)
+	c.Clear()
+
+	want := []string{
+		"insert 1 1", "update 1 1 10", "insert 2 2", "insert 3 3",
+		"remove 1 10", "evict 1 10", "remove 2 2", "remove 3 3",
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+}
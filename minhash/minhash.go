@@ -0,0 +1,159 @@
+// Package minhash implements MinHash signatures for estimating the Jaccard
+// similarity of sets.
+//
+// A MinHash keeps, for each of a number of hash functions simulating random
+// permutations, the minimum hash value over the elements added. The
+// fraction of positions at which two signatures agree is an unbiased
+// estimate of the Jaccard similarity of the underlying sets, with standard
+// error about 1/sqrt(number of permutations).
+//
+// Signatures are deterministic for a given seed and number of permutations,
+// so they can be stored and compared across processes.
+package minhash
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+)
+
+const prime = 1<<61 - 1 // Mersenne prime
+
+// mulmod returns a*b mod prime.
+func mulmod(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	// a*b = hi*2^64 + lo; 2^64 = 8 (mod prime)
+	r := (lo & prime) + (lo >> 61) + (hi << 3 & prime) + (hi >> 58)
+	for r >= prime {
+		r -= prime
+	}
+
+	return r
+}
+
+// MinHash computes a MinHash signature.
+// To create a MinHash use minhash.New.
+type MinHash struct {
+	a, b []uint64 // permutation coefficients
+	mins []uint64
+}
+
+type option func(*options)
+
+type options struct {
+	seed uint64
+}
+
+// WithSeed sets the seed from which the permutations are derived.
+// Only signatures computed with the same seed and number of permutations
+// are comparable. The default seed is 1.
+func WithSeed(seed uint64) option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// New returns an empty MinHash using numPerm permutations.
+// It panics if numPerm is not positive.
+func New(numPerm int, opts ...option) *MinHash {
+	if numPerm <= 0 {
+		panic("minhash: non-positive number of permutations")
+	}
+
+	o := options{seed: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	r := rand.New(rand.NewPCG(o.seed, o.seed^0x9e3779b97f4a7c15))
+	m := &MinHash{
+		a:    make([]uint64, numPerm),
+		b:    make([]uint64, numPerm),
+		mins: make([]uint64, numPerm),
+	}
+	for i := range m.mins {
+		m.a[i] = 1 + r.Uint64N(prime-1)
+		m.b[i] = r.Uint64N(prime)
+	}
+	m.Reset()
+
+	return m
+}
+
+// Reset removes all elements from m.
+func (m *MinHash) Reset() {
+	for i := range m.mins {
+		m.mins[i] = math.MaxUint64
+	}
+}
+
+// Clone returns a copy of m with the same permutations, so the two may
+// be compared and merged.
+func (m *MinHash) Clone() *MinHash {
+	return &MinHash{a: m.a, b: m.b, mins: append([]uint64(nil), m.mins...)}
+}
+
+// Add adds the element with encoding p to m.
+func (m *MinHash) Add(p []byte) {
+	h := fnv.New64a()
+	h.Write(p)
+	m.AddHash(h.Sum64())
+}
+
+// AddString adds the element s to m.
+func (m *MinHash) AddString(s string) { m.Add([]byte(s)) }
+
+// AddHash adds an element given by a 64-bit hash of it.
+func (m *MinHash) AddHash(x uint64) {
+	x %= prime
+	for i, a := range m.a {
+		if h := (mulmod(a, x) + m.b[i]) % prime; h < m.mins[i] {
+			m.mins[i] = h
+		}
+	}
+}
+
+// Merge updates m to the signature of the union of the sets summarized by
+// m and other, which must use the same permutations.
+func (m *MinHash) Merge(other *MinHash) {
+	if len(m.mins) != len(other.mins) {
+		panic("minhash: merging signatures of different lengths")
+	}
+
+	for i, v := range other.mins {
+		m.mins[i] = min(m.mins[i], v)
+	}
+}
+
+// Signature returns a copy of the current signature of m.
+func (m *MinHash) Signature() []uint64 {
+	return append([]uint64(nil), m.mins...)
+}
+
+// Jaccard returns the estimated Jaccard similarity of the sets summarized by m and other.
+func (m *MinHash) Jaccard(other *MinHash) float64 {
+	return Similarity(m.mins, other.mins)
+}
+
+// Similarity returns the estimated Jaccard similarity of the sets with
+// signatures a and b, the fraction of positions at which they agree.
+// It panics if the signatures have different lengths.
+func Similarity(a, b []uint64) float64 {
+	if len(a) != len(b) {
+		panic("minhash: comparing signatures of different lengths")
+	}
+
+	if len(a) == 0 {
+		return 0
+	}
+
+	n := 0
+	for i := range a {
+		if a[i] == b[i] {
+			n++
+		}
+	}
+
+	return float64(n) / float64(len(a))
+}
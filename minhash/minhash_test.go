@@ -0,0 +1,97 @@
+package minhash
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestMulmod(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	p := new(big.Int).SetUint64(prime)
+	for i := 0; i < 1000; i++ {
+		a, b := r.Uint64()%prime, r.Uint64()%prime
+		want := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+		want.Mod(want, p)
+		if got := mulmod(a, b); got != want.Uint64() {
+			t.Fatalf("mulmod(%d, %d) = %d, want %d", a, b, got, want)
+		}
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	t.Parallel()
+
+	// A = [0, 1000), B = [500, 1500): J = 500 / 1500
+	a, b := New(256), New(256)
+	for i := 0; i < 1000; i++ {
+		a.AddString(strconv.Itoa(i))
+		b.AddString(strconv.Itoa(i + 500))
+	}
+
+	if j := a.Jaccard(b); math.Abs(j-1.0/3) > 0.1 {
+		t.Errorf("a.Jaccard(b) = %v, want ~0.333", j)
+	}
+
+	if j := Similarity(a.Signature(), a.Signature()); j != 1 {
+		t.Errorf("self similarity = %v, want 1", j)
+	}
+
+	// the union contains both sets
+	u := New(256)
+	u.Merge(a)
+	u.Merge(b)
+	c := New(256)
+	for i := 0; i < 1500; i++ {
+		c.AddString(strconv.Itoa(i))
+	}
+	if !slices.Equal(u.Signature(), c.Signature()) {
+		t.Errorf("merged signature differs from signature of union")
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	t.Parallel()
+
+	a, b := New(16, WithSeed(7)), New(16, WithSeed(7))
+	a.AddString("x")
+	b.AddString("x")
+	if !slices.Equal(a.Signature(), b.Signature()) {
+		t.Errorf("same seed produced different signatures")
+	}
+
+	c := New(16, WithSeed(8))
+	c.AddString("x")
+	if slices.Equal(a.Signature(), c.Signature()) {
+		t.Errorf("different seeds produced identical signatures")
+	}
+
+	a.Reset()
+	if a.Signature()[0] != math.MaxUint64 {
+		t.Errorf("signature not reset")
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	a := New(64)
+	a.AddString("x")
+
+	b := a.Clone()
+	if j := a.Jaccard(b); j != 1 {
+		t.Errorf("a.Jaccard(clone) = %v, want 1", j)
+	}
+
+	for i := 0; i < 100; i++ {
+		b.AddString(strconv.Itoa(i))
+	}
+	if j := a.Jaccard(b); j == 1 {
+		t.Errorf("adding to the clone changed the original")
+	}
+}
@@ -0,0 +1,173 @@
+// Package packed implements arrays of integers stored at a fixed bit width.
+//
+// An IntArray of width w stores each value in w bits, packed across 64-bit
+// words, so n values take about n·w/64 words instead of n. This suits
+// dense indexes whose values are known to be small, such as offsets or
+// ranks, and building blocks of succinct data structures.
+//
+// To iterate over an array (where a is an *IntArray):
+//
+//	for i, v := range a.All() {
+//		// do something with i and v
+//	}
+package packed
+
+import (
+	"iter"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+// IntArray is an array of unsigned integers of a fixed bit width.
+// To create an array use packed.NewIntArray.
+type IntArray struct {
+	words []uint64
+	width uint
+	mask  uint64
+	n     int
+}
+
+// NewIntArray returns an array of n zeros of the given bit width.
+// It panics if width is not in [1, 64] or n is negative.
+func NewIntArray(width, n int) *IntArray {
+	if width < 1 || width > 64 {
+		panic("packed: width out of range [1, 64]")
+	}
+	if n < 0 {
+		panic("packed: negative length")
+	}
+
+	a := &IntArray{width: uint(width), mask: 1<<width - 1, n: n}
+	if width == 64 {
+		a.mask = ^uint64(0)
+	}
+	a.words = make([]uint64, a.wordsFor(n))
+	return a
+}
+
+func (a *IntArray) wordsFor(n int) int { return (n*int(a.width) + 63) / 64 }
+
+// Len returns the number of values of array a.
+func (a *IntArray) Len() int { return a.n }
+
+// Width returns the bit width of the values of array a.
+func (a *IntArray) Width() int { return int(a.width) }
+
+// Max returns the largest value array a can store.
+func (a *IntArray) Max() uint64 { return a.mask }
+
+// Clear removes all values from array a.
+func (a *IntArray) Clear() {
+	clear(a.words)
+	a.words = a.words[:0]
+	a.n = 0
+}
+
+func (a *IntArray) checkIndex(i int) {
+	if i < 0 || i >= a.n {
+		panic("packed: index out of range")
+	}
+}
+
+func (a *IntArray) get(i int) uint64 {
+	bit := uint(i) * a.width
+	w, off := bit/64, bit%64
+	v := a.words[w] >> off
+	if off+a.width > 64 {
+		v |= a.words[w+1] << (64 - off)
+	}
+
+	return v & a.mask
+}
+
+func (a *IntArray) set(i int, v uint64) {
+	bit := uint(i) * a.width
+	w, off := bit/64, bit%64
+	a.words[w] = a.words[w]&^(a.mask<<off) | v<<off
+	if off+a.width > 64 {
+		rest := 64 - off
+		a.words[w+1] = a.words[w+1]&^(a.mask>>rest) | v>>rest
+	}
+}
+
+// Get returns the value at index i.
+// It panics if i is out of range.
+func (a *IntArray) Get(i int) uint64 {
+	a.checkIndex(i)
+	return a.get(i)
+}
+
+// Set sets the value at index i to v.
+// It panics if i is out of range or v does not fit in the width of a.
+func (a *IntArray) Set(i int, v uint64) {
+	a.checkIndex(i)
+	if v > a.mask {
+		panic("packed: value too wide")
+	}
+
+	a.set(i, v)
+}
+
+// Append adds v at the end of array a.
+// It panics if v does not fit in the width of a.
+func (a *IntArray) Append(v uint64) {
+	if v > a.mask {
+		panic("packed: value too wide")
+	}
+
+	if n := a.wordsFor(a.n + 1); n > len(a.words) {
+		a.words = append(a.words, 0)
+	}
+	a.n++
+	a.set(a.n-1, v)
+}
+
+// All returns an iterator over the indexes and values of array a.
+func (a *IntArray) All() iter.Seq2[int, uint64] {
+	return func(yield func(int, uint64) bool) {
+		for i := range a.n {
+			if !yield(i, a.get(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values of array a.
+func (a *IntArray) Values() iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for i := range a.n {
+			if !yield(a.get(i)) {
+				return
+			}
+		}
+	}
+}
+
+// AppendTo appends the values of array a to s and returns the extended
+// slice.
+func (a *IntArray) AppendTo(s []uint64) []uint64 {
+	for i := range a.n {
+		s = append(s, a.get(i))
+	}
+
+	return s
+}
+
+// Clone returns a copy of array a.
+func (a *IntArray) Clone() *IntArray {
+	c := *a
+	c.words = append([]uint64(nil), a.words...)
+	return &c
+}
+
+// MemStats returns statistics about the memory used by array a.
+// Cap is the number of values the allocated words can hold.
+func (a *IntArray) MemStats() container.MemStats {
+	return container.MemStats{
+		Len:   a.n,
+		Cap:   cap(a.words) * 64 / int(a.width),
+		Bytes: memsize.Of[IntArray]() + memsize.Slice[uint64](cap(a.words)),
+	}
+}
@@ -0,0 +1,94 @@
+package packed
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container         = (*IntArray)(nil)
+	_ container.Iterable[uint64]  = (*IntArray)(nil)
+	_ container.Cloner[*IntArray] = (*IntArray)(nil)
+	_ container.Sizer             = (*IntArray)(nil)
+)
+
+func TestIntArray(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for _, width := range []int{1, 3, 7, 13, 32, 63, 64} {
+		a := NewIntArray(width, 100)
+		want := make([]uint64, 100)
+		for range 1000 {
+			i, v := r.Intn(len(want)), r.Uint64()&a.Max()
+			if r.Intn(4) == 0 {
+				a.Append(v)
+				want = append(want, v)
+			} else {
+				a.Set(i, v)
+				want[i] = v
+			}
+		}
+
+		if a.Len() != len(want) {
+			t.Fatalf("width %d: a.Len() = %d, want %d", width, a.Len(), len(want))
+		}
+		for i, v := range want {
+			if got := a.Get(i); got != v {
+				t.Fatalf("width %d: a.Get(%d) = %d, want %d", width, i, got, v)
+			}
+		}
+		if got := slices.Collect(a.Values()); !slices.Equal(got, want) {
+			t.Fatalf("width %d: a.Values() differ", width)
+		}
+		if words := len(a.words); words != (len(want)*width+63)/64 {
+			t.Errorf("width %d: %d words for %d values", width, words, len(want))
+		}
+	}
+}
+
+func TestPanics(t *testing.T) {
+	t.Parallel()
+
+	a := NewIntArray(4, 2)
+	for name, f := range map[string]func(){
+		"Set too wide":     func() { a.Set(0, 16) },
+		"Append too wide":  func() { a.Append(16) },
+		"Get out of range": func() { a.Get(2) },
+		"width 0":          func() { NewIntArray(0, 1) },
+		"width 65":         func() { NewIntArray(65, 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	a := NewIntArray(5, 0)
+	for i := range 20 {
+		a.Append(uint64(i))
+	}
+
+	c := a.Clone()
+	c.Set(0, 31)
+	c.Append(1)
+	if a.Get(0) != 0 || a.Len() != 20 {
+		t.Errorf("changing the clone changed the array")
+	}
+
+	a.Clear()
+	if a.Len() != 0 || c.Len() != 21 {
+		t.Errorf("a.Len(), c.Len() after Clear = %d, %d, want 0, 21", a.Len(), c.Len())
+	}
+}
@@ -0,0 +1,180 @@
+// Package pool implements a typed pool of reusable objects.
+//
+// Unlike sync.Pool, a Pool is typed, resets objects when they are returned,
+// may be bounded, and never drops idle objects behind the caller's back, so
+// its behavior is predictable: an object Put is handed out again by the
+// next Get. In debug mode it also tracks the objects handed out, to find
+// objects that are never returned and objects returned twice.
+//
+// A typical use recycles scratch buffers:
+//
+//	p := pool.New(func() *bytes.Buffer { return new(bytes.Buffer) },
+//		pool.WithReset((*bytes.Buffer).Reset), pool.WithMaxIdle[*bytes.Buffer](64))
+//	b := p.Get()
+//	defer p.Put(b)
+package pool
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+)
+
+// A Pool is a set of idle objects of type T that may be reused.
+// A Pool is safe for concurrent use by multiple goroutines.
+// To create a pool use pool.New.
+type Pool[T any] struct {
+	newFn   func() T
+	reset   func(T)
+	maxIdle int // 0 means unbounded
+	debug   bool
+
+	mu   sync.Mutex
+	idle []T
+
+	// the stack trace of each object handed out, in debug mode
+	out map[any]string
+}
+
+type option[T any] func(*Pool[T])
+
+// WithReset sets a function called on each object returned by Put, before
+// it becomes idle.
+func WithReset[T any](f func(T)) option[T] {
+	return func(p *Pool[T]) {
+		p.reset = f
+	}
+}
+
+// WithMaxIdle bounds the number of idle objects kept by the pool to n.
+// Objects returned to a full pool are dropped.
+// It panics if n < 1.
+func WithMaxIdle[T any](n int) option[T] {
+	if n < 1 {
+		panic("pool: non-positive max idle")
+	}
+
+	return func(p *Pool[T]) {
+		p.maxIdle = n
+	}
+}
+
+// WithDebug enables leak detection: the pool records where each object was
+// obtained until it is returned, and panics if an object is returned that
+// is not outstanding. The objects must be of comparable types, typically
+// pointers. Debug mode is slow and meant for tests.
+func WithDebug[T any]() option[T] {
+	return func(p *Pool[T]) {
+		p.debug = true
+		p.out = make(map[any]string)
+	}
+}
+
+// New returns an empty pool that creates objects with newFn when no idle
+// object is available.
+func New[T any](newFn func() T, opts ...option[T]) *Pool[T] {
+	p := &Pool[T]{newFn: newFn}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Get returns an idle object, most recently returned first, or a new one.
+func (p *Pool[T]) Get() T {
+	var x T
+	ok := false
+
+	p.mu.Lock()
+	if n := len(p.idle); n > 0 {
+		x, ok = p.idle[n-1], true
+		clear(p.idle[n-1:])
+		p.idle = p.idle[:n-1]
+	}
+	p.mu.Unlock()
+
+	if !ok {
+		x = p.newFn()
+	}
+	if p.debug {
+		p.track(x)
+	}
+
+	return x
+}
+
+func (p *Pool[T]) track(x T) {
+	k, stack := key(x), string(debug.Stack())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.out[k]; ok {
+		panic(fmt.Sprintf("pool: object %v handed out twice", x))
+	}
+	p.out[k] = stack
+}
+
+// Put resets x and adds it to the idle objects of the pool, or drops it if
+// the pool is full.
+func (p *Pool[T]) Put(x T) {
+	if p.reset != nil {
+		p.reset(x)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.debug {
+		k := key(x)
+		if _, ok := p.out[k]; !ok {
+			panic(fmt.Sprintf("pool: Put of object %v not obtained from Get", x))
+		}
+		delete(p.out, k)
+	}
+
+	if p.maxIdle == 0 || len(p.idle) < p.maxIdle {
+		p.idle = append(p.idle, x)
+	}
+}
+
+func key(x any) any {
+	if t := reflect.TypeOf(x); t != nil && !t.Comparable() {
+		panic("pool: debug mode with uncomparable object type " + t.String())
+	}
+
+	return x
+}
+
+// Len returns the number of idle objects in the pool.
+func (p *Pool[T]) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.idle)
+}
+
+// Clear drops all idle objects.
+func (p *Pool[T]) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clear(p.idle)
+	p.idle = p.idle[:0]
+}
+
+// Leaks returns the stack traces of the Get calls whose objects have not
+// been returned. It returns nil unless the pool is in debug mode.
+func (p *Pool[T]) Leaks() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var leaks []string
+	for _, stack := range p.out {
+		leaks = append(leaks, stack)
+	}
+
+	return leaks
+}
@@ -0,0 +1,98 @@
+package pool
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var _ container.Container = (*Pool[int])(nil)
+
+type obj struct{ n int }
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	created := 0
+	p := New(func() *obj { created++; return new(obj) },
+		WithReset(func(o *obj) { o.n = 0 }), WithMaxIdle[*obj](2))
+
+	a, b, c := p.Get(), p.Get(), p.Get()
+	if created != 3 {
+		t.Errorf("created = %d, want 3", created)
+	}
+
+	a.n, b.n, c.n = 1, 2, 3
+	p.Put(a)
+	p.Put(b)
+	p.Put(c) // dropped
+	if n := p.Len(); n != 2 {
+		t.Errorf("p.Len() = %d, want 2", n)
+	}
+
+	if x := p.Get(); x != b || x.n != 0 {
+		t.Errorf("p.Get() = %p with n = %d, want %p with n = 0", x, x.n, b)
+	}
+	if x := p.Get(); x != a {
+		t.Errorf("p.Get() = %p, want %p", x, a)
+	}
+	p.Get()
+	if created != 4 {
+		t.Errorf("created = %d, want 4", created)
+	}
+
+	p.Put(a)
+	p.Clear()
+	if n := p.Len(); n != 0 {
+		t.Errorf("p.Len() after Clear = %d, want 0", n)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	t.Parallel()
+
+	p := New(func() []byte { return make([]byte, 0, 64) })
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				b := p.Get()
+				b = append(b[:0], 'x')
+				p.Put(b)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := p.Len(); n < 1 || n > 8 {
+		t.Errorf("p.Len() = %d, want in [1, 8]", n)
+	}
+}
+
+func TestDebug(t *testing.T) {
+	t.Parallel()
+
+	p := New(func() *obj { return new(obj) }, WithDebug[*obj]())
+
+	a := p.Get()
+	b := p.Get()
+	p.Put(a)
+
+	leaks := p.Leaks()
+	if len(leaks) != 1 || !strings.Contains(leaks[0], "TestDebug") {
+		t.Errorf("p.Leaks() = %q, want one stack from TestDebug", leaks)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Put of a returned object did not panic")
+		}
+	}()
+	p.Put(b)
+	p.Put(b)
+}
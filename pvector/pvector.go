@@ -0,0 +1,350 @@
+// Package pvector implements a persistent (immutable) vector.
+//
+// Every modifying operation returns a new Vector and leaves the receiver
+// unchanged; unmodified parts are shared between versions. This makes it
+// cheap to keep old versions around, for example as undo history, and safe
+// to share a Vector between goroutines without locking.
+//
+// A Vector is a relaxed, height-balanced tree whose leaves hold up to 32
+// elements, plus a tail chunk that absorbs appends. Index, Set and Append
+// are effectively O(1) for practical sizes (O(log n) with a large base),
+// while Slice and Concat are O(log n).
+//
+// To iterate over a vector (where v is a Vector):
+//
+//	for i, x := range v.All() {
+//		// do something with i and x
+//	}
+package pvector
+
+import (
+	"iter"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+const chunkSize = 32
+
+// node is either a leaf, holding elements, or an internal node with two
+// non-nil children. Nodes are never modified after construction.
+type node[E any] struct {
+	left, right *node[E]
+	elems       []E
+
+	size   int
+	height int
+}
+
+func newLeaf[E any](elems []E) *node[E] {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	return &node[E]{elems: elems, size: len(elems)}
+}
+
+func newNode[E any](left, right *node[E]) *node[E] {
+	return &node[E]{
+		left:   left,
+		right:  right,
+		size:   left.size + right.size,
+		height: 1 + max(left.height, right.height),
+	}
+}
+
+func height[E any](n *node[E]) int {
+	if n == nil {
+		return -1
+	}
+
+	return n.height
+}
+
+func size[E any](n *node[E]) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// balance returns a node with children left and right, rotating if their
+// heights differ by two.
+func balance[E any](left, right *node[E]) *node[E] {
+	switch hl, hr := left.height, right.height; {
+	case hl > hr+1:
+		if height(left.left) >= height(left.right) {
+			return newNode(left.left, newNode(left.right, right))
+		}
+
+		return newNode(newNode(left.left, left.right.left), newNode(left.right.right, right))
+	case hr > hl+1:
+		if height(right.right) >= height(right.left) {
+			return newNode(newNode(left, right.left), right.right)
+		}
+
+		return newNode(newNode(left, right.left.left), newNode(right.left.right, right.right))
+	default:
+		return newNode(left, right)
+	}
+}
+
+// join concatenates two trees.
+// The complexity is O(|h(left) - h(right)|).
+func join[E any](left, right *node[E]) *node[E] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.height > right.height+1:
+		return balance(left.left, join(left.right, right))
+	case right.height > left.height+1:
+		return balance(join(left, right.left), right.right)
+	default:
+		return newNode(left, right)
+	}
+}
+
+// split splits tree n into the first i elements and the rest.
+func split[E any](n *node[E], i int) (*node[E], *node[E]) {
+	switch {
+	case n == nil:
+		return nil, nil
+	case i <= 0:
+		return nil, n
+	case i >= n.size:
+		return n, nil
+	case n.left == nil:
+		return newLeaf(n.elems[:i:i]), newLeaf(n.elems[i:])
+	case i < n.left.size:
+		l, r := split(n.left, i)
+		return l, join(r, n.right)
+	default:
+		l, r := split(n.right, i-n.left.size)
+		return join(n.left, l), r
+	}
+}
+
+func (n *node[E]) at(i int) E {
+	for n.left != nil {
+		if i < n.left.size {
+			n = n.left
+		} else {
+			i -= n.left.size
+			n = n.right
+		}
+	}
+
+	return n.elems[i]
+}
+
+func (n *node[E]) set(i int, v E) *node[E] {
+	if n.left == nil {
+		elems := append([]E(nil), n.elems...)
+		elems[i] = v
+		return newLeaf(elems)
+	}
+
+	if i < n.left.size {
+		return &node[E]{left: n.left.set(i, v), right: n.right, size: n.size, height: n.height}
+	}
+
+	return &node[E]{left: n.left, right: n.right.set(i-n.left.size, v), size: n.size, height: n.height}
+}
+
+func (n *node[E]) all(yield func(E) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	if n.left == nil {
+		for _, v := range n.elems {
+			if !yield(v) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return n.left.all(yield) && n.right.all(yield)
+}
+
+// Vector is a persistent vector.
+// The zero value for Vector is an empty vector ready to use.
+type Vector[E any] struct {
+	root *node[E]
+	tail []E
+}
+
+// New returns a vector holding a copy of vs.
+func New[E any](vs ...E) Vector[E] {
+	return Vector[E]{}.Append(vs...)
+}
+
+// Len returns the number of elements of vector v.
+// The complexity is O(1).
+func (v Vector[E]) Len() int { return size(v.root) + len(v.tail) }
+
+func (v Vector[E]) checkIndex(i int) {
+	if i < 0 || i >= v.Len() {
+		panic("pvector: index out of range")
+	}
+}
+
+// At returns the element at index i.
+func (v Vector[E]) At(i int) E {
+	v.checkIndex(i)
+
+	if n := size(v.root); i >= n {
+		return v.tail[i-n]
+	}
+
+	return v.root.at(i)
+}
+
+// Set returns a vector with the element at index i replaced by x.
+func (v Vector[E]) Set(i int, x E) Vector[E] {
+	v.checkIndex(i)
+
+	if n := size(v.root); i >= n {
+		tail := append([]E(nil), v.tail...)
+		tail[i-n] = x
+		return Vector[E]{root: v.root, tail: tail}
+	}
+
+	return Vector[E]{root: v.root.set(i, x), tail: v.tail}
+}
+
+// Append returns a vector with the values xs appended.
+func (v Vector[E]) Append(xs ...E) Vector[E] {
+	for len(xs) > 0 {
+		n := min(chunkSize-len(v.tail), len(xs))
+
+		tail := make([]E, len(v.tail)+n, chunkSize)
+		copy(tail, v.tail)
+		copy(tail[len(v.tail):], xs[:n])
+		xs = xs[n:]
+
+		if len(tail) == chunkSize {
+			v = Vector[E]{root: join(v.root, newLeaf(tail))}
+		} else {
+			v = Vector[E]{root: v.root, tail: tail}
+		}
+	}
+
+	return v
+}
+
+// flush returns the tree of v with the tail included.
+func (v Vector[E]) flush() *node[E] {
+	return join(v.root, newLeaf(v.tail[:len(v.tail):len(v.tail)]))
+}
+
+// Concat returns the concatenation of vectors v and w.
+// The complexity is O(log n).
+func (v Vector[E]) Concat(w Vector[E]) Vector[E] {
+	if v.Len() == 0 {
+		return w
+	}
+
+	return Vector[E]{root: join(v.flush(), w.root), tail: w.tail}
+}
+
+// Slice returns the vector of elements v[i:j].
+// The complexity is O(log n).
+func (v Vector[E]) Slice(i, j int) Vector[E] {
+	if i < 0 || j < i || j > v.Len() {
+		panic("pvector: slice bounds out of range")
+	}
+
+	n := size(v.root)
+	if i >= n {
+		return Vector[E]{tail: v.tail[i-n : j-n : j-n]}
+	}
+
+	root := v.flush()
+	root, _ = split(root, j)
+	_, root = split(root, i)
+	return Vector[E]{root: root}
+}
+
+// All returns an iterator over index-value pairs of vector v in order.
+func (v Vector[E]) All() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		i := 0
+		if !v.root.all(func(x E) bool {
+			ok := yield(i, x)
+			i++
+			return ok
+		}) {
+			return
+		}
+
+		for _, x := range v.tail {
+			if !yield(i, x) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Values returns an iterator over the elements of vector v in order.
+func (v Vector[E]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		if v.root.all(yield) {
+			for _, x := range v.tail {
+				if !yield(x) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AppendTo appends the elements of vector v to dst in order and returns the extended slice.
+func (v Vector[E]) AppendTo(dst []E) []E {
+	v.root.all(func(x E) bool {
+		dst = append(dst, x)
+		return true
+	})
+
+	return append(dst, v.tail...)
+}
+
+// Clone returns v. Vectors are persistent, so v is already independent of
+// later changes; Clone exists for uniformity with the other containers.
+func (v Vector[E]) Clone() Vector[E] { return v }
+
+// CloneFunc returns a vector of the elements of v copied by f, which
+// allows a deep clone.
+func (v Vector[E]) CloneFunc(f func(E) E) Vector[E] {
+	s := make([]E, 0, v.Len())
+	for x := range v.Values() {
+		s = append(s, f(x))
+	}
+
+	return New(s...)
+}
+
+func (n *node[E]) bytes() int64 {
+	if n == nil {
+		return 0
+	}
+
+	return memsize.Of[node[E]]() + memsize.Slice[E](cap(n.elems)) + n.left.bytes() + n.right.bytes()
+}
+
+// MemStats returns statistics about the memory used by vector v,
+// including nodes it shares with other versions.
+// The complexity is O(n/32).
+func (v Vector[E]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len:   v.Len(),
+		Cap:   v.Len(),
+		Bytes: memsize.Of[Vector[E]]() + v.root.bytes() + memsize.Slice[E](cap(v.tail)),
+	}
+}
@@ -0,0 +1,169 @@
+package pvector
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Iterable[int] = Vector[int]{}
+	_ container.Sizer         = Vector[int]{}
+)
+
+func (n *node[E]) verify(t *testing.T) {
+	t.Helper()
+
+	if n == nil {
+		return
+	}
+
+	if n.left == nil {
+		if n.right != nil || len(n.elems) == 0 || n.size != len(n.elems) || n.height != 0 {
+			t.Fatalf("malformed leaf %+v", n)
+		}
+
+		return
+	}
+
+	n.left.verify(t)
+	n.right.verify(t)
+
+	if d := n.left.height - n.right.height; d < -1 || d > 1 {
+		t.Fatalf("unbalanced node: heights %d, %d", n.left.height, n.right.height)
+	}
+
+	if n.size != n.left.size+n.right.size {
+		t.Fatalf("node size = %d, want %d", n.size, n.left.size+n.right.size)
+	}
+}
+
+func checkVector(t *testing.T, v Vector[int], want []int) {
+	t.Helper()
+
+	v.root.verify(t)
+
+	if n := v.Len(); len(want) != n {
+		t.Fatalf("v.Len() = %d, want %d", n, len(want))
+	}
+
+	for i, x := range want {
+		if y := v.At(i); x != y {
+			t.Fatalf("v.At(%d) = %d, want %d", i, y, x)
+		}
+	}
+
+	if got := v.AppendTo(nil); !slices.Equal(want, got) {
+		t.Fatalf("v.AppendTo(nil) = %v, want %v", got, want)
+	}
+
+	if got := slices.Collect(v.Values()); !slices.Equal(want, got) {
+		t.Fatalf("v.Values() = %v, want %v", got, want)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	t.Parallel()
+
+	var v Vector[int]
+	var want []int
+	checkVector(t, v, nil)
+
+	for i := 0; i < 1000; i++ {
+		v = v.Append(i)
+		want = append(want, i)
+	}
+	checkVector(t, v, want)
+
+	for i, x := range v.All() {
+		if i != x {
+			t.Fatalf("v.All() yielded (%d, %d)", i, x)
+		}
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	t.Parallel()
+
+	v1 := New(0, 1, 2)
+	v2 := v1.Append(3)
+	v3 := v2.Set(0, 10)
+	v4 := v2.Set(3, 30)
+
+	checkVector(t, v1, []int{0, 1, 2})
+	checkVector(t, v2, []int{0, 1, 2, 3})
+	checkVector(t, v3, []int{10, 1, 2, 3})
+	checkVector(t, v4, []int{0, 1, 2, 30})
+
+	// Appending to the same version twice must not clobber.
+	a := v1.Append(100)
+	b := v1.Append(200)
+	checkVector(t, a, []int{0, 1, 2, 100})
+	checkVector(t, b, []int{0, 1, 2, 200})
+}
+
+func TestSliceConcat(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	var want []int
+	for i := 0; i < 500; i++ {
+		want = append(want, i)
+	}
+	v := New(want...)
+
+	for k := 0; k < 200; k++ {
+		switch r.Intn(3) {
+		case 0:
+			i := r.Intn(len(want) + 1)
+			j := i + r.Intn(len(want)-i+1)
+			v = v.Slice(i, j)
+			want = want[i:j]
+		case 1:
+			w := v.Slice(0, v.Len()/2)
+			v = v.Concat(w)
+			want = append(want[:len(want):len(want)], want[:len(want)/2]...)
+		case 2:
+			x := r.Int()
+			v = v.Append(x)
+			want = append(want[:len(want):len(want)], x)
+		}
+		checkVector(t, v, want)
+
+		if len(want) == 0 {
+			want = []int{1, 2, 3}
+			v = New(want...)
+		}
+	}
+}
+
+func BenchmarkAppend(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var v Vector[int]
+		for j := 0; j < 1000; j++ {
+			v = v.Append(j)
+		}
+	}
+}
+
+func TestCloneFunc(t *testing.T) {
+	t.Parallel()
+
+	var want []int
+	v := New[int]()
+	for i := range 100 {
+		v = v.Append(i)
+		want = append(want, i*2)
+	}
+
+	checkVector(t, v.CloneFunc(func(x int) int { return x * 2 }), want)
+
+	c := v.Clone()
+	c = c.Set(0, -1)
+	if v.At(0) != 0 {
+		t.Errorf("changing the clone changed the vector")
+	}
+}
@@ -0,0 +1,320 @@
+// Package quotientfilter implements a quotient filter, an approximate
+// membership structure.
+//
+// A quotient filter (Bender et al., "Don't Thrash: How to Cache Your Hash
+// on Flash") stores a p-bit fingerprint of each key in a hash table of 2^q
+// slots: the high q bits, the quotient, select the canonical slot and the
+// low r = p-q bits, the remainder, are stored in it, with three metadata
+// bits per slot recording how runs of colliding remainders were shifted.
+// Runs are stored contiguously, so lookups touch a few adjacent slots,
+// and the full fingerprints can be recovered, which makes the filter
+// resizable and mergeable. Unlike a Bloom filter it supports Delete.
+//
+// Test may report false positives, with probability about 2^-r for a
+// filter at moderate load, but never false negatives.
+//
+// Keys are fingerprinted with a fixed hash function, so filters built in
+// different processes with the same fingerprint size can be merged.
+package quotientfilter
+
+import (
+	"hash/fnv"
+	"slices"
+
+	"github.com/weiwenchen2022/container/cmpx"
+)
+
+const (
+	occupied     = 1 << iota // the slot is the canonical slot of some fingerprint
+	continuation             // the slot continues a run begun in an earlier slot
+	shifted                  // the remainder is not in its canonical slot
+
+	metaBits = 3
+	metaMask = 1<<metaBits - 1
+)
+
+// maxLoad is the load factor above which Add grows the filter.
+const maxLoad = 0.9
+
+// Filter is a quotient filter.
+// To create a filter use quotientfilter.New.
+type Filter struct {
+	q, r  uint
+	slots []uint64 // remainder<<metaBits | metadata
+	n     int
+}
+
+// New returns an empty filter with 2^q slots and r-bit remainders.
+// The fingerprint size q+r is fixed for the life of the filter and must be
+// at most 64; Grow moves one bit from the remainder to the quotient.
+func New(q, r uint) *Filter {
+	if q == 0 || r == 0 || q+r > 64 || r > 64-metaBits {
+		panic("quotientfilter: invalid quotient or remainder size")
+	}
+
+	return &Filter{q: q, r: r, slots: make([]uint64, 1<<q)}
+}
+
+// Len returns the number of fingerprints stored in filter f.
+func (f *Filter) Len() int { return f.n }
+
+// Cap returns the number of slots of filter f.
+func (f *Filter) Cap() int { return len(f.slots) }
+
+// FingerprintBits returns the fingerprint size q+r of filter f.
+func (f *Filter) FingerprintBits() uint { return f.q + f.r }
+
+func (f *Filter) fingerprint(p []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(p)
+
+	fp := h.Sum64()
+	if bits := f.q + f.r; bits < 64 {
+		fp &= 1<<bits - 1
+	}
+
+	return fp
+}
+
+func (f *Filter) split(fp uint64) (quotient, remainder uint64) {
+	return fp >> f.r, fp & (1<<f.r - 1)
+}
+
+func (f *Filter) next(i uint64) uint64 { return (i + 1) & uint64(len(f.slots)-1) }
+func (f *Filter) prev(i uint64) uint64 { return (i - 1) & uint64(len(f.slots)-1) }
+
+func (f *Filter) is(i uint64, bit uint64) bool { return f.slots[i]&bit != 0 }
+func (f *Filter) empty(i uint64) bool          { return f.slots[i]&metaMask == 0 }
+func (f *Filter) remainder(i uint64) uint64    { return f.slots[i] >> metaBits }
+
+// runStart returns the slot holding the run of occupied quotient fq.
+func (f *Filter) runStart(fq uint64) uint64 {
+	// walk back to the start of the cluster
+	b := fq
+	for f.is(b, shifted) {
+		b = f.prev(b)
+	}
+
+	// walk forward, skipping one run per occupied quotient before fq
+	s := b
+	for b != fq {
+		for s = f.next(s); f.is(s, continuation); s = f.next(s) {
+		}
+
+		for b = f.next(b); !f.is(b, occupied); b = f.next(b) {
+		}
+	}
+
+	return s
+}
+
+// Test reports whether key p may be in filter f.
+// A false result means p is definitely not in f.
+func (f *Filter) Test(p []byte) bool {
+	return f.testFingerprint(f.fingerprint(p))
+}
+
+func (f *Filter) testFingerprint(fp uint64) bool {
+	fq, fr := f.split(fp)
+	if !f.is(fq, occupied) {
+		return false
+	}
+
+	s := f.runStart(fq)
+	for {
+		switch r := f.remainder(s); {
+		case r == fr:
+			return true
+		case r > fr:
+			return false // runs are sorted
+		}
+
+		if s = f.next(s); !f.is(s, continuation) {
+			return false
+		}
+	}
+}
+
+type entry struct {
+	quotient, remainder uint64
+}
+
+// cluster decodes the maximal sequence of non-empty slots around slot fq,
+// returning its first slot and its entries in storage order.
+func (f *Filter) cluster(fq uint64) (uint64, []entry) {
+	b := fq
+	for !f.empty(b) && f.is(b, shifted) {
+		b = f.prev(b)
+	}
+
+	var es []entry
+	q := b
+	for s := b; !f.empty(s); s = f.next(s) {
+		if s != b && !f.is(s, continuation) {
+			for q = f.next(q); !f.is(q, occupied); q = f.next(q) {
+			}
+		}
+
+		es = append(es, entry{q, f.remainder(s)})
+	}
+
+	return b, es
+}
+
+// layout clears the n slots from b and writes entries es from b.
+func (f *Filter) layout(b uint64, n int, es []entry) {
+	for i, s := 0, b; i < n; i, s = i+1, f.next(s) {
+		f.slots[s] = 0
+	}
+
+	m := uint64(len(f.slots))
+	dist := func(i uint64) uint64 { return (i - b) & (m - 1) }
+
+	pos := uint64(0) // distance from b of the next free slot
+	for i, e := range es {
+		newRun := i == 0 || e.quotient != es[i-1].quotient
+		if newRun {
+			pos = max(pos, dist(e.quotient))
+			f.slots[e.quotient] |= occupied
+		}
+
+		s := (b + pos) & (m - 1)
+		f.slots[s] |= e.remainder << metaBits
+		if !newRun {
+			f.slots[s] |= continuation
+		}
+		if s != e.quotient {
+			f.slots[s] |= shifted
+		}
+
+		pos++
+	}
+}
+
+// Add adds key p to filter f, growing f if its load is too high.
+// Keys may be added more than once; each addition must be matched by a
+// Delete to remove the key.
+func (f *Filter) Add(p []byte) {
+	f.addFingerprint(f.fingerprint(p))
+}
+
+func (f *Filter) addFingerprint(fp uint64) {
+	if float64(f.n+1) > maxLoad*float64(len(f.slots)) {
+		if f.r > 1 {
+			f.Grow()
+		} else if f.n+1 == len(f.slots) {
+			// at least one slot is always kept empty
+			panic("quotientfilter: filter full")
+		}
+	}
+
+	fq, fr := f.split(fp)
+	b, es := f.cluster(fq)
+
+	m := uint64(len(f.slots))
+	key := func(e entry) uint64 { return (e.quotient - b) & (m - 1) }
+	i, _ := slices.BinarySearchFunc(es, entry{fq, fr}, cmpx.Chain(
+		cmpx.CompareBy(key),
+		cmpx.CompareBy(func(e entry) uint64 { return e.remainder }),
+	))
+
+	es = slices.Insert(es, i, entry{fq, fr})
+	f.layout(b, len(es), es)
+	f.n++
+}
+
+// Delete removes one occurrence of key p from filter f and reports whether
+// a matching fingerprint was found. Deleting a key that was never added
+// may remove another key with the same fingerprint.
+func (f *Filter) Delete(p []byte) bool {
+	return f.deleteFingerprint(f.fingerprint(p))
+}
+
+func (f *Filter) deleteFingerprint(fp uint64) bool {
+	fq, fr := f.split(fp)
+	if !f.is(fq, occupied) {
+		return false
+	}
+
+	b, es := f.cluster(fq)
+	i := slices.Index(es, entry{fq, fr})
+	if i < 0 {
+		return false
+	}
+
+	n := len(es)
+	es = slices.Delete(es, i, i+1)
+	f.layout(b, n, es)
+	f.n--
+	return true
+}
+
+// fingerprints calls yield with each stored fingerprint.
+func (f *Filter) fingerprints(yield func(uint64)) {
+	// start at an empty slot so that every cluster is decoded whole
+	start := uint64(0)
+	for !f.empty(start) {
+		start = f.next(start)
+	}
+
+	seen := 0
+	for s := start; seen < len(f.slots); {
+		if f.empty(s) {
+			s = f.next(s)
+			seen++
+			continue
+		}
+
+		_, es := f.cluster(s)
+		for _, e := range es {
+			yield(e.quotient<<f.r | e.remainder)
+		}
+
+		for range es {
+			s = f.next(s)
+		}
+		seen += len(es)
+	}
+}
+
+// Grow doubles the number of slots of filter f, moving one bit of each
+// fingerprint from the remainder to the quotient. Since the load halves as
+// the remainders lose a bit, the false positive rate is unchanged.
+// It panics if the remainder is a single bit.
+func (f *Filter) Grow() {
+	if f.r <= 1 {
+		panic("quotientfilter: remainder too small to grow")
+	}
+
+	var fps []uint64
+	f.fingerprints(func(fp uint64) { fps = append(fps, fp) })
+
+	*f = Filter{q: f.q + 1, r: f.r - 1, slots: make([]uint64, 2*len(f.slots))}
+	slices.Sort(fps)
+	for _, fp := range fps {
+		f.addFingerprint(fp)
+	}
+}
+
+// Merge adds all fingerprints stored in filter other to filter f.
+// It panics if the filters have different fingerprint sizes.
+func (f *Filter) Merge(other *Filter) {
+	if f.q+f.r != other.q+other.r {
+		panic("quotientfilter: merging filters with different fingerprint sizes")
+	}
+
+	other.fingerprints(f.addFingerprint)
+}
+
+// Clear removes all keys from filter f.
+func (f *Filter) Clear() {
+	clear(f.slots)
+	f.n = 0
+}
+
+// Clone returns a copy of filter f.
+func (f *Filter) Clone() *Filter {
+	c := *f
+	c.slots = slices.Clone(f.slots)
+	return &c
+}
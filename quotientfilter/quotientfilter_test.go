@@ -0,0 +1,157 @@
+package quotientfilter
+
+import (
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var _ container.Container = (*Filter)(nil)
+
+func key(i int) []byte { return []byte(strconv.Itoa(i)) }
+
+func (f *Filter) all() []uint64 {
+	var fps []uint64
+	f.fingerprints(func(fp uint64) { fps = append(fps, fp) })
+	slices.Sort(fps)
+	return fps
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	// small quotient and remainder to force collisions, long runs and wraparound
+	f := New(4, 4)
+	var want []uint64
+	for i := 0; i < 5000; i++ {
+		fp := uint64(r.Intn(1 << 8))
+		if r.Intn(3) > 0 && f.n+2 < len(f.slots) {
+			f.addFingerprint(fp)
+			want = append(want, fp)
+		} else if j := slices.Index(want, fp); j >= 0 {
+			if !f.deleteFingerprint(fp) {
+				t.Fatalf("step %d: deleteFingerprint(%d) = false", i, fp)
+			}
+			want = slices.Delete(want, j, j+1)
+		} else if f.deleteFingerprint(fp) {
+			t.Fatalf("step %d: deleteFingerprint(%d) of absent fingerprint = true", i, fp)
+		}
+
+		got := f.all()
+		slices.Sort(want)
+		if !slices.Equal(want, got) {
+			t.Fatalf("step %d: fingerprints = %v, want %v", i, got, want)
+		}
+
+		for _, fp := range want {
+			if !f.testFingerprint(fp) {
+				t.Fatalf("step %d: testFingerprint(%d) = false", i, fp)
+			}
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	const n = 10000
+
+	f := New(8, 20)
+	for i := 0; i < n; i++ {
+		f.Add(key(i))
+	}
+
+	if got := f.Len(); got != n {
+		t.Errorf("f.Len() = %d, want %d", got, n)
+	}
+	if c := f.Cap(); float64(n) > maxLoad*float64(c) {
+		t.Errorf("f.Cap() = %d too small for %d keys", c, n)
+	}
+	if b := f.FingerprintBits(); b != 28 {
+		t.Errorf("f.FingerprintBits() = %d, want 28", b)
+	}
+
+	for i := 0; i < n; i++ {
+		if !f.Test(key(i)) {
+			t.Fatalf("f.Test(%d) = false after Add", i)
+		}
+	}
+
+	fp := 0
+	for i := n; i < 2*n; i++ {
+		if f.Test(key(i)) {
+			fp++
+		}
+	}
+	if fp > n/100 {
+		t.Errorf("%d false positives in %d queries", fp, n)
+	}
+
+	for i := 0; i < n; i += 2 {
+		if !f.Delete(key(i)) {
+			t.Fatalf("f.Delete(%d) = false", i)
+		}
+	}
+	for i := 1; i < n; i += 2 {
+		if !f.Test(key(i)) {
+			t.Fatalf("f.Test(%d) = false after deleting others", i)
+		}
+	}
+	if got := f.Len(); got != n/2 {
+		t.Errorf("f.Len() = %d, want %d", got, n/2)
+	}
+
+	f.Clear()
+	if f.Test(key(1)) || f.Len() != 0 {
+		t.Errorf("filter not empty after Clear")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	a, b := New(6, 10), New(8, 8)
+	for i := 0; i < 100; i++ {
+		a.Add(key(i))
+		b.Add(key(i + 100))
+	}
+
+	a.Merge(b)
+	for i := 0; i < 200; i++ {
+		if !a.Test(key(i)) {
+			t.Fatalf("a.Test(%d) = false after Merge", i)
+		}
+	}
+	if n := a.Len(); n != 200 {
+		t.Errorf("a.Len() = %d, want 200", n)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	f := New(8, 16)
+	for i := 0; i < 100; i++ {
+		f.Add(key(i))
+	}
+
+	c := f.Clone()
+	for i := 0; i < 100; i++ {
+		c.Delete(key(i))
+	}
+	c.Add(key(1000))
+
+	if f.Len() != 100 || c.Len() != 1 {
+		t.Errorf("f.Len(), c.Len() = %d, %d, want 100, 1", f.Len(), c.Len())
+	}
+	for i := 0; i < 100; i++ {
+		if !f.Test(key(i)) {
+			t.Fatalf("f.Test(key(%d)) = false after deleting from the clone", i)
+		}
+	}
+}
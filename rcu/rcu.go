@@ -0,0 +1,168 @@
+// Package rcu implements ordered maps and sets for read-mostly concurrent
+// use, in the style of read-copy-update.
+//
+// Readers never lock: each read loads the current version of the map
+// through an atomic pointer, and Pin returns a version that stays
+// unchanged however long it is used, for consistent iteration. Writers are
+// serialized by a mutex and publish a new version after each write. Since
+// versions are wbtree snapshots, a write copies only the O(log n) nodes on
+// its path and shares the rest; nodes retired by a write are reclaimed by
+// the garbage collector once no pinned version refers to them.
+package rcu
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+
+	"github.com/weiwenchen2022/container/wbtree"
+)
+
+// View is a read-only version of a map.
+type View[K, V any] struct {
+	m *wbtree.Map[K, V]
+}
+
+// Len returns the number of entries of view v.
+func (v View[K, V]) Len() int { return v.m.Len() }
+
+// Get returns the value stored for key k.
+// The ok result reports whether the key was present.
+func (v View[K, V]) Get(k K) (V, bool) { return v.m.Get(k) }
+
+// Contains reports whether view v has an entry for key k.
+func (v View[K, V]) Contains(k K) bool { return v.m.Contains(k) }
+
+// At returns the entry with the i'th smallest key, counting from zero.
+func (v View[K, V]) At(i int) (K, V) { return v.m.At(i) }
+
+// Rank returns the number of keys in view v less than k.
+func (v View[K, V]) Rank(k K) int { return v.m.Rank(k) }
+
+// All returns an iterator over the entries of view v in ascending key order.
+func (v View[K, V]) All() iter.Seq2[K, V] { return v.m.All() }
+
+// Keys returns an iterator over the keys of view v in ascending order.
+func (v View[K, V]) Keys() iter.Seq[K] { return v.m.Keys() }
+
+// Ascend returns an iterator over the entries of view v with keys in
+// [lo, hi), in ascending order.
+func (v View[K, V]) Ascend(lo, hi K) iter.Seq2[K, V] { return v.m.Ascend(lo, hi) }
+
+// Map is an ordered map safe for concurrent use, whose reads never block.
+// To create a map use rcu.New.
+type Map[K, V any] struct {
+	mu sync.Mutex        // serializes writers
+	w  *wbtree.Map[K, V] // the writers' version, never seen by readers
+
+	cur atomic.Pointer[wbtree.Map[K, V]] // the published version
+}
+
+// New returns an empty map ordered according to the less function.
+func New[K, V any](less func(a, b K) bool) *Map[K, V] {
+	m := &Map[K, V]{w: wbtree.New[K, V](less)}
+	m.publish()
+	return m
+}
+
+// publish makes the writers' version visible to readers. m.mu must be
+// held, or m not yet shared.
+func (m *Map[K, V]) publish() {
+	// The snapshot is never written again: later writes to m.w copy the
+	// nodes they touch.
+	m.cur.Store(m.w.Snapshot())
+}
+
+// Pin returns the current version of map m. Later writes to m are not
+// visible through it.
+func (m *Map[K, V]) Pin() View[K, V] { return View[K, V]{m.cur.Load()} }
+
+// Len returns the number of entries of map m.
+func (m *Map[K, V]) Len() int { return m.cur.Load().Len() }
+
+// Get returns the value stored for key k.
+// The ok result reports whether the key was present.
+func (m *Map[K, V]) Get(k K) (V, bool) { return m.cur.Load().Get(k) }
+
+// Contains reports whether map m has an entry for key k.
+func (m *Map[K, V]) Contains(k K) bool { return m.cur.Load().Contains(k) }
+
+// All returns an iterator over the entries of map m in ascending key
+// order, as of the call to All. Iterating does not block writers.
+func (m *Map[K, V]) All() iter.Seq2[K, V] { return m.Pin().All() }
+
+// Set sets the value for key k to v.
+func (m *Map[K, V]) Set(k K, v V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.w.Set(k, v)
+	m.publish()
+}
+
+// Delete removes the entry for key k and returns its value.
+// The ok result reports whether the key was present.
+func (m *Map[K, V]) Delete(k K) (v V, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, ok = m.w.Delete(k)
+	if ok {
+		m.publish()
+	}
+
+	return v, ok
+}
+
+// Clear removes all entries from map m.
+func (m *Map[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.w.Clear()
+	m.publish()
+}
+
+// Update calls f with the writers' version of map m and publishes the
+// result as one version, so readers see all of the writes of f or none.
+// f must not retain the map or use it after returning.
+func (m *Map[K, V]) Update(f func(w *wbtree.Map[K, V])) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f(m.w)
+	m.publish()
+}
+
+// Set is an ordered set safe for concurrent use, whose reads never block.
+// To create a set use rcu.NewSet.
+type Set[K any] struct {
+	m *Map[K, struct{}]
+}
+
+// NewSet returns an empty set ordered according to the less function.
+func NewSet[K any](less func(a, b K) bool) *Set[K] {
+	return &Set[K]{New[K, struct{}](less)}
+}
+
+// Len returns the number of members of set s.
+func (s *Set[K]) Len() int { return s.m.Len() }
+
+// Contains reports whether k is a member of set s.
+func (s *Set[K]) Contains(k K) bool { return s.m.Contains(k) }
+
+// Add adds k to set s.
+func (s *Set[K]) Add(k K) { s.m.Set(k, struct{}{}) }
+
+// Remove removes k from set s and reports whether it was a member.
+func (s *Set[K]) Remove(k K) bool {
+	_, ok := s.m.Delete(k)
+	return ok
+}
+
+// Clear removes all members from set s.
+func (s *Set[K]) Clear() { s.m.Clear() }
+
+// Values returns an iterator over the members of set s in ascending
+// order, as of the call to Values. Iterating does not block writers.
+func (s *Set[K]) Values() iter.Seq[K] { return s.m.Pin().Keys() }
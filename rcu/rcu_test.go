@@ -0,0 +1,113 @@
+package rcu
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/wbtree"
+)
+
+var (
+	_ container.Container     = (*Map[int, int])(nil)
+	_ container.Container     = (*Set[int])(nil)
+	_ container.Iterable[int] = (*Set[int])(nil)
+)
+
+func less(a, b int) bool { return a < b }
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	m := New[int, int](less)
+	for i := range 10 {
+		m.Set(i, i)
+	}
+
+	v := m.Pin()
+	m.Delete(0)
+	m.Set(1, 100)
+	m.Update(func(w *wbtree.Map[int, int]) {
+		w.Set(20, 20)
+		w.Delete(9)
+	})
+
+	if got := slices.Collect(v.Keys()); !slices.Equal(got, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("pinned keys = %v", got)
+	}
+	if x, _ := v.Get(1); x != 1 {
+		t.Errorf("pinned v.Get(1) = %d, want 1", x)
+	}
+	if x, _ := m.Get(1); x != 100 || m.Contains(0) || m.Len() != 9 {
+		t.Errorf("writes not visible in m")
+	}
+
+	m.Clear()
+	if m.Len() != 0 || v.Len() != 10 {
+		t.Errorf("m.Len(), v.Len() after Clear = %d, %d, want 0, 10", m.Len(), v.Len())
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	t.Parallel()
+
+	// the writer keeps keys 0..n-1 summing to zero in every version
+	const n = 100
+	m := New[int, int](less)
+	for i := range n {
+		m.Set(i, 0)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				sum, count := 0, 0
+				for _, v := range m.All() {
+					sum += v
+					count++
+				}
+				if sum != 0 || count != n {
+					t.Errorf("inconsistent version: sum %d, count %d", sum, count)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := range 2000 {
+		m.Update(func(w *wbtree.Map[int, int]) {
+			a, _ := w.Get(i % n)
+			b, _ := w.Get((i + 1) % n)
+			w.Set(i%n, a+i)
+			w.Set((i+1)%n, b-i)
+		})
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	s := NewSet(less)
+	s.Add(3)
+	s.Add(1)
+	s.Add(3)
+	if !s.Remove(1) || s.Remove(1) || !s.Contains(3) || s.Len() != 1 {
+		t.Errorf("set operations wrong")
+	}
+	if got := slices.Collect(s.Values()); !slices.Equal(got, []int{3}) {
+		t.Errorf("s.Values() = %v, want [3]", got)
+	}
+}
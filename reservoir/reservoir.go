@@ -0,0 +1,238 @@
+// Package reservoir implements reservoir sampling: maintaining a random
+// sample of fixed size over a stream of unknown length.
+//
+// A Reservoir keeps a uniform sample, in which every item seen so far is
+// included with equal probability. A Weighted reservoir includes items with
+// probability proportional to their weights.
+//
+// Reservoirs are not safe for concurrent use by multiple goroutines.
+package reservoir
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/weiwenchen2022/container/heap"
+)
+
+type options struct {
+	rand *rand.Rand
+}
+
+type option func(*options)
+
+// WithRand sets the source of randomness used by the reservoir.
+// The default is the top-level functions of math/rand/v2.
+func WithRand(r *rand.Rand) option {
+	return func(o *options) {
+		o.rand = r
+	}
+}
+
+func (o *options) float64() float64 {
+	if o.rand == nil {
+		return rand.Float64()
+	}
+
+	return o.rand.Float64()
+}
+
+func (o *options) uint64N(n uint64) uint64 {
+	if o.rand == nil {
+		return rand.Uint64N(n)
+	}
+
+	return o.rand.Uint64N(n)
+}
+
+// Reservoir maintains a uniform random sample of at most k items.
+// To create a reservoir use reservoir.New.
+type Reservoir[E any] struct {
+	options
+
+	k       int
+	count   uint64
+	samples []E
+}
+
+// New returns an empty reservoir holding a sample of at most k items.
+// It panics if k is not positive.
+func New[E any](k int, opts ...option) *Reservoir[E] {
+	if k <= 0 {
+		panic("reservoir: non-positive sample size")
+	}
+
+	r := &Reservoir[E]{k: k, samples: make([]E, 0, k)}
+
+	for _, opt := range opts {
+		opt(&r.options)
+	}
+
+	return r
+}
+
+// Add offers item x to reservoir r.
+// After n calls to Add, each of the n items is in the sample with
+// probability min(1, k/n).
+// The complexity is O(1).
+func (r *Reservoir[E]) Add(x E) {
+	r.count++
+
+	if len(r.samples) < r.k {
+		r.samples = append(r.samples, x)
+		return
+	}
+
+	if j := r.uint64N(r.count); j < uint64(r.k) {
+		r.samples[j] = x
+	}
+}
+
+// Len returns the number of items in the sample.
+func (r *Reservoir[E]) Len() int { return len(r.samples) }
+
+// Count returns the number of items offered to reservoir r.
+func (r *Reservoir[E]) Count() uint64 { return r.count }
+
+// Samples returns a copy of the current sample, in no particular order.
+func (r *Reservoir[E]) Samples() []E { return slices.Clone(r.samples) }
+
+// Reset removes all items from reservoir r.
+func (r *Reservoir[E]) Reset() {
+	clear(r.samples) // avoid memory leak
+	r.samples = r.samples[:0]
+	r.count = 0
+}
+
+// Clone returns a copy of reservoir r sharing its source of randomness.
+// The samples are copied by assignment, so this is a shallow clone.
+func (r *Reservoir[E]) Clone() *Reservoir[E] {
+	c := *r
+	c.samples = slices.Clone(r.samples)
+	return &c
+}
+
+// CloneFunc is like Clone but copies each sample with f, which allows a
+// deep clone.
+func (r *Reservoir[E]) CloneFunc(f func(E) E) *Reservoir[E] {
+	c := *r
+	c.samples = make([]E, len(r.samples), cap(r.samples))
+	for i, x := range r.samples {
+		c.samples[i] = f(x)
+	}
+
+	return &c
+}
+
+type keyed[E any] struct {
+	key float64
+	x   E
+}
+
+// Weighted maintains a weighted random sample of at most k items, using
+// the A-Res algorithm of Efraimidis and Spirakis.
+// To create a reservoir use reservoir.NewWeighted.
+type Weighted[E any] struct {
+	options
+
+	k       int
+	count   uint64
+	samples *heap.Heap[keyed[E]] // min-heap of keys
+}
+
+// NewWeighted returns an empty weighted reservoir holding a sample of at most k items.
+// It panics if k is not positive.
+func NewWeighted[E any](k int, opts ...option) *Weighted[E] {
+	if k <= 0 {
+		panic("reservoir: non-positive sample size")
+	}
+
+	r := &Weighted[E]{k: k}
+	r.samples = heap.New(r.less, heap.WithInitialCap[keyed[E]](k))
+
+	for _, opt := range opts {
+		opt(&r.options)
+	}
+
+	return r
+}
+
+// Add offers item x with weight w to reservoir r.
+// Items with non-positive weight are never sampled.
+// The complexity is O(log k).
+func (r *Weighted[E]) Add(x E, w float64) {
+	r.count++
+
+	if !(w > 0) {
+		return
+	}
+
+	// key = u^(1/w), compared in log space for precision
+	key := math.Log(r.float64()) / w
+
+	if r.samples.Len() < r.k {
+		r.samples.Push(keyed[E]{key, x})
+		return
+	}
+
+	if key > r.samples.Peek().key {
+		r.samples.Pop()
+		r.samples.Push(keyed[E]{key, x})
+	}
+}
+
+func (*Weighted[E]) less(a, b keyed[E]) bool { return a.key < b.key }
+
+// Len returns the number of items in the sample.
+func (r *Weighted[E]) Len() int { return r.samples.Len() }
+
+// Count returns the number of items offered to reservoir r.
+func (r *Weighted[E]) Count() uint64 { return r.count }
+
+// Samples returns a copy of the current sample, in increasing order of key.
+// The complexity is O(k log k).
+func (r *Weighted[E]) Samples() []E {
+	ks := make([]keyed[E], 0, r.k)
+	for r.samples.Len() > 0 {
+		ks = append(ks, r.samples.Pop())
+	}
+
+	// a sorted slice satisfies the heap invariants
+	r.samples = heap.New(r.less, heap.WithData(ks))
+
+	xs := make([]E, len(ks))
+	for i, kx := range ks {
+		xs[i] = kx.x
+	}
+
+	return xs
+}
+
+// Reset removes all items from reservoir r.
+func (r *Weighted[E]) Reset() {
+	for r.samples.Len() > 0 {
+		r.samples.Pop()
+	}
+	r.count = 0
+}
+
+// Clone returns a copy of reservoir r sharing its source of randomness.
+// The samples are copied by assignment, so this is a shallow clone.
+func (r *Weighted[E]) Clone() *Weighted[E] {
+	c := *r
+	c.samples = r.samples.Clone()
+	return &c
+}
+
+// CloneFunc is like Clone but copies each sample with f, which allows a
+// deep clone.
+func (r *Weighted[E]) CloneFunc(f func(E) E) *Weighted[E] {
+	c := *r
+	c.samples = r.samples.CloneFunc(func(k keyed[E]) keyed[E] {
+		k.x = f(k.x)
+		return k
+	})
+
+	return &c
+}
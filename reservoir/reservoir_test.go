@@ -0,0 +1,124 @@
+package reservoir
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestReservoir(t *testing.T) {
+	t.Parallel()
+
+	const (
+		n      = 10
+		k      = 3
+		trials = 30000
+	)
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	counts := make([]int, n)
+	r := New[int](k, WithRand(rng))
+	for trial := 0; trial < trials; trial++ {
+		r.Reset()
+		for i := 0; i < n; i++ {
+			r.Add(i)
+		}
+
+		if r.Len() != k || r.Count() != n {
+			t.Fatalf("r.Len(), r.Count() = %d, %d, want %d, %d", r.Len(), r.Count(), k, n)
+		}
+
+		for _, x := range r.Samples() {
+			counts[x]++
+		}
+	}
+
+	// each item is sampled with probability k/n
+	want := float64(trials) * k / n
+	for i, c := range counts {
+		if math.Abs(float64(c)-want)/want > 0.05 {
+			t.Errorf("item %d sampled %d times, want ~%v", i, c, want)
+		}
+	}
+}
+
+func TestShortStream(t *testing.T) {
+	t.Parallel()
+
+	r := New[string](5)
+	r.Add("a")
+	r.Add("b")
+	if s := r.Samples(); len(s) != 2 {
+		t.Errorf("r.Samples() = %v, want both items", s)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	t.Parallel()
+
+	const trials = 20000
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	weights := []float64{1, 2, 7, 0}
+	counts := make([]int, len(weights))
+	r := NewWeighted[int](1, WithRand(rng))
+	for trial := 0; trial < trials; trial++ {
+		r.Reset()
+		for i, w := range weights {
+			r.Add(i, w)
+		}
+
+		for _, x := range r.Samples() {
+			counts[x]++
+		}
+	}
+
+	if counts[3] != 0 {
+		t.Errorf("zero-weight item sampled %d times", counts[3])
+	}
+	for i, w := range weights[:3] {
+		want := trials * w / 10
+		if math.Abs(float64(counts[i])-want)/want > 0.1 {
+			t.Errorf("item %d sampled %d times, want ~%v", i, counts[i], want)
+		}
+	}
+
+	// Samples leaves the reservoir intact
+	r.Reset()
+	for i := 0; i < 10; i++ {
+		r.Add(i, 1)
+	}
+	r2 := NewWeighted[int](4)
+	for i := 0; i < 10; i++ {
+		r2.Add(i, 1)
+	}
+	if a, b := len(r2.Samples()), len(r2.Samples()); a != 4 || b != 4 || r2.Len() != 4 {
+		t.Errorf("r2.Samples() lengths = %d, %d, r2.Len() = %d, want 4", a, b, r2.Len())
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	r := New[[]int](2)
+	r.Add([]int{1})
+	r.Add([]int{2})
+
+	c := r.CloneFunc(slices.Clone)
+	c.Samples()[0][0] = 9
+	c.Add([]int{3})
+	if r.Count() != 2 || r.Samples()[0][0] == 9 {
+		t.Errorf("changing the clone changed the reservoir")
+	}
+
+	w := NewWeighted[int](2)
+	w.Add(1, 1)
+	w.Add(2, 1)
+	wc := w.CloneFunc(func(x int) int { return -x })
+	got := wc.Samples()
+	slices.Sort(got)
+	if !slices.Equal(got, []int{-2, -1}) || w.Clone().Len() != 2 {
+		t.Errorf("wc.Samples() = %v, want [-2 -1]", got)
+	}
+}
@@ -0,0 +1,230 @@
+// Package rle implements a run-length encoded sequence.
+//
+// A Seq stores each maximal run of equal values once, with its extent, so
+// its memory is proportional to the number of runs rather than to its
+// length. Positional access takes O(log r) time for r runs; insertions and
+// deletions take O(r) to shift the runs after them. This suits long
+// sequences with long constant stretches, such as timelines and
+// visibility bitmaps.
+//
+// To iterate over the runs of a sequence (where s is a *Seq):
+//
+//	for v, n := range s.Runs() {
+//		// v is repeated n times
+//	}
+package rle
+
+import (
+	"iter"
+	"slices"
+	"sort"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+type run[E comparable] struct {
+	v   E
+	end int // index after the last value of the run
+}
+
+// Seq is a run-length encoded sequence of values.
+// The zero value for Seq is an empty sequence ready to use.
+type Seq[E comparable] struct {
+	runs []run[E]
+}
+
+// New returns an empty sequence.
+func New[E comparable]() *Seq[E] { return new(Seq[E]) }
+
+// Len returns the number of values of sequence s.
+func (s *Seq[E]) Len() int {
+	if len(s.runs) == 0 {
+		return 0
+	}
+
+	return s.runs[len(s.runs)-1].end
+}
+
+// NumRuns returns the number of runs of sequence s.
+func (s *Seq[E]) NumRuns() int { return len(s.runs) }
+
+// Clear removes all values from sequence s.
+func (s *Seq[E]) Clear() {
+	clear(s.runs)
+	s.runs = s.runs[:0]
+}
+
+// find returns the index of the run containing index i < s.Len().
+func (s *Seq[E]) find(i int) int {
+	return sort.Search(len(s.runs), func(r int) bool { return s.runs[r].end > i })
+}
+
+func (s *Seq[E]) start(r int) int {
+	if r == 0 {
+		return 0
+	}
+
+	return s.runs[r-1].end
+}
+
+// split makes a run start at index i and returns the index of that run,
+// or the number of runs if i is the length of s.
+func (s *Seq[E]) split(i int) int {
+	if i == s.Len() {
+		return len(s.runs)
+	}
+
+	r := s.find(i)
+	if s.start(r) == i {
+		return r
+	}
+
+	s.runs = slices.Insert(s.runs, r, run[E]{s.runs[r].v, i})
+	return r + 1
+}
+
+// coalesce merges the runs around index r that have equal values.
+func (s *Seq[E]) coalesce(r int) {
+	for k := min(r, len(s.runs)-2); k >= max(r-1, 0); k-- {
+		if s.runs[k].v == s.runs[k+1].v {
+			s.runs[k].end = s.runs[k+1].end
+			s.runs = slices.Delete(s.runs, k+1, k+2)
+		}
+	}
+}
+
+// Get returns the value at index i.
+// It panics if i is out of range.
+func (s *Seq[E]) Get(i int) E {
+	if i < 0 || i >= s.Len() {
+		panic("rle: index out of range")
+	}
+
+	return s.runs[s.find(i)].v
+}
+
+// Insert inserts n copies of v at index i, shifting the values from i on.
+// It panics if i is out of range or n is negative.
+func (s *Seq[E]) Insert(i int, v E, n int) {
+	if i < 0 || i > s.Len() {
+		panic("rle: index out of range")
+	}
+	if n < 0 {
+		panic("rle: negative count")
+	}
+	if n == 0 {
+		return
+	}
+
+	r := s.split(i)
+	s.runs = slices.Insert(s.runs, r, run[E]{v, i})
+	for k := r; k < len(s.runs); k++ {
+		s.runs[k].end += n
+	}
+	s.coalesce(r)
+}
+
+// Append adds n copies of v at the end of sequence s.
+// It panics if n is negative.
+func (s *Seq[E]) Append(v E, n int) { s.Insert(s.Len(), v, n) }
+
+// Delete removes the values s[i:j], shifting the values after them.
+// It panics if s[i:j] is not a valid slice of s.
+func (s *Seq[E]) Delete(i, j int) {
+	if i < 0 || j > s.Len() || i > j {
+		panic("rle: index out of range")
+	}
+	if i == j {
+		return
+	}
+
+	a := s.split(i)
+	b := s.split(j)
+	s.runs = slices.Delete(s.runs, a, b)
+	for k := a; k < len(s.runs); k++ {
+		s.runs[k].end -= j - i
+	}
+	s.coalesce(a)
+}
+
+// Fill sets the values s[i:j] to v.
+// It panics if s[i:j] is not a valid slice of s.
+func (s *Seq[E]) Fill(i, j int, v E) {
+	if i < 0 || j > s.Len() || i > j {
+		panic("rle: index out of range")
+	}
+	if i == j {
+		return
+	}
+
+	a := s.split(i)
+	b := s.split(j)
+	s.runs = slices.Replace(s.runs, a, b, run[E]{v, j})
+	s.coalesce(a)
+}
+
+// Set sets the value at index i to v.
+// It panics if i is out of range.
+func (s *Seq[E]) Set(i int, v E) {
+	if i < 0 || i >= s.Len() {
+		panic("rle: index out of range")
+	}
+
+	s.Fill(i, i+1, v)
+}
+
+// Runs returns an iterator over the runs of sequence s, yielding the value
+// and length of each.
+func (s *Seq[E]) Runs() iter.Seq2[E, int] {
+	return func(yield func(E, int) bool) {
+		start := 0
+		for _, r := range s.runs {
+			if !yield(r.v, r.end-start) {
+				return
+			}
+			start = r.end
+		}
+	}
+}
+
+// All returns an iterator over the indexes and values of sequence s.
+func (s *Seq[E]) All() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		i := 0
+		for _, r := range s.runs {
+			for ; i < r.end; i++ {
+				if !yield(i, r.v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values of sequence s.
+func (s *Seq[E]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, v := range s.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a copy of sequence s. The values are copied by assignment,
+// so this is a shallow clone.
+func (s *Seq[E]) Clone() *Seq[E] {
+	return &Seq[E]{runs: slices.Clone(s.runs)}
+}
+
+// MemStats returns statistics about the memory used by sequence s.
+// Cap is the number of runs s can hold without allocating.
+func (s *Seq[E]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len:   s.Len(),
+		Cap:   cap(s.runs),
+		Bytes: memsize.Of[Seq[E]]() + memsize.Slice[run[E]](cap(s.runs)),
+	}
+}
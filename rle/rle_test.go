@@ -0,0 +1,107 @@
+package rle
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container         = (*Seq[int])(nil)
+	_ container.Iterable[int]     = (*Seq[int])(nil)
+	_ container.Cloner[*Seq[int]] = (*Seq[int])(nil)
+	_ container.Sizer             = (*Seq[int])(nil)
+)
+
+func (s *Seq[E]) verify(t *testing.T, want []E) {
+	t.Helper()
+
+	if got := slices.Collect(s.Values()); !slices.Equal(got, want) {
+		t.Fatalf("s = %v, want %v", got, want)
+	}
+	for k := range s.runs {
+		if s.runs[k].end <= s.start(k) {
+			t.Fatalf("run %d is empty", k)
+		}
+		if k > 0 && s.runs[k].v == s.runs[k-1].v {
+			t.Fatalf("runs %d and %d have equal values", k-1, k)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	var s Seq[int]
+	var want []int
+	for step := range 5000 {
+		n := len(want)
+		switch i := r.Intn(n + 1); r.Intn(4) {
+		case 0:
+			v, k := r.Intn(3), r.Intn(5)
+			s.Insert(i, v, k)
+			want = slices.Insert(want, i, slices.Repeat([]int{v}, k)...)
+		case 1:
+			j := i + r.Intn(n-i+1)
+			s.Delete(i, j)
+			want = slices.Delete(want, i, j)
+		case 2:
+			j, v := i+r.Intn(n-i+1), r.Intn(3)
+			s.Fill(i, j, v)
+			for k := i; k < j; k++ {
+				want[k] = v
+			}
+		default:
+			if i < n {
+				v := r.Intn(3)
+				s.Set(i, v)
+				want[i] = v
+			}
+		}
+
+		s.verify(t, want)
+		if i := r.Intn(len(want) + 1); i < len(want) && s.Get(i) != want[i] {
+			t.Fatalf("step %d: s.Get(%d) = %d, want %d", step, i, s.Get(i), want[i])
+		}
+	}
+}
+
+func TestRuns(t *testing.T) {
+	t.Parallel()
+
+	s := New[string]()
+	s.Append("a", 3)
+	s.Append("a", 2)
+	s.Append("b", 1000)
+	s.Insert(5, "c", 1)
+
+	type pair struct {
+		v string
+		n int
+	}
+	var got []pair
+	for v, n := range s.Runs() {
+		got = append(got, pair{v, n})
+	}
+	if want := []pair{{"a", 5}, {"c", 1}, {"b", 1000}}; !slices.Equal(got, want) {
+		t.Errorf("s.Runs() = %v, want %v", got, want)
+	}
+	if s.Len() != 1006 || s.NumRuns() != 3 {
+		t.Errorf("s.Len(), s.NumRuns() = %d, %d, want 1006, 3", s.Len(), s.NumRuns())
+	}
+
+	c := s.Clone()
+	s.Delete(5, 6)
+	if s.NumRuns() != 2 || c.NumRuns() != 3 {
+		t.Errorf("s.NumRuns(), c.NumRuns() = %d, %d, want 2, 3", s.NumRuns(), c.NumRuns())
+	}
+
+	s.Clear()
+	if s.Len() != 0 || s.NumRuns() != 0 {
+		t.Errorf("sequence not empty after Clear")
+	}
+}
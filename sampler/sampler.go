@@ -0,0 +1,215 @@
+// Package sampler implements weighted random selection of indexes.
+//
+// An Alias picks from fixed weights in O(1) time using Vose's alias
+// method, after O(n) preprocessing. A Dynamic keeps its weights in a
+// Fenwick tree, so weights can be changed and added in O(log n) time, and
+// picks in O(log n) time.
+//
+// To spread requests over backends by capacity (where r is a *rand.Rand):
+//
+//	s := sampler.NewAlias(capacities)
+//	b := backends[s.Pick(r)]
+//
+// Samplers are not safe for concurrent use by multiple goroutines.
+package sampler
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+)
+
+func checkWeight(w float64) {
+	if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+		panic("sampler: invalid weight")
+	}
+}
+
+func intN(r *rand.Rand, n int) int {
+	if r == nil {
+		return rand.IntN(n)
+	}
+
+	return r.IntN(n)
+}
+
+func float64n(r *rand.Rand) float64 {
+	if r == nil {
+		return rand.Float64()
+	}
+
+	return r.Float64()
+}
+
+// Alias picks indexes with probability proportional to fixed weights.
+// To create an alias sampler use sampler.NewAlias.
+type Alias struct {
+	prob  []float64 // probability of keeping index i rather than its alias
+	alias []int
+}
+
+// NewAlias returns a sampler picking index i with probability proportional
+// to weights[i]. It panics if a weight is negative, NaN or infinite, or if
+// the weights sum to zero.
+func NewAlias(weights []float64) *Alias {
+	total := 0.0
+	for _, w := range weights {
+		checkWeight(w)
+		total += w
+	}
+	if !(total > 0) || math.IsInf(total, 0) {
+		panic("sampler: total weight not positive and finite")
+	}
+
+	n := len(weights)
+	a := &Alias{prob: make([]float64, n), alias: make([]int, n)}
+
+	// Scale the weights to average 1, then pair each index below 1 with
+	// one above 1 that fills the rest of its column.
+	var small, large []int
+	for i, w := range weights {
+		a.prob[i] = w * float64(n) / total
+		if a.prob[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+
+		a.alias[s] = l
+		a.prob[l] -= 1 - a.prob[s]
+		if a.prob[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+
+	// What is left is 1 up to rounding.
+	for _, i := range large {
+		a.prob[i] = 1
+	}
+	for _, i := range small {
+		a.prob[i] = 1
+	}
+
+	return a
+}
+
+// Len returns the number of weights of sampler a.
+func (a *Alias) Len() int { return len(a.prob) }
+
+// Pick returns a random index chosen with probability proportional to its
+// weight, using r as the source of randomness. If r is nil, Pick uses the
+// top-level functions of math/rand/v2.
+func (a *Alias) Pick(r *rand.Rand) int {
+	i := intN(r, len(a.prob))
+	if float64n(r) < a.prob[i] {
+		return i
+	}
+
+	return a.alias[i]
+}
+
+// Dynamic picks indexes with probability proportional to weights that can
+// change. The zero value is an empty sampler ready to use.
+type Dynamic struct {
+	weights []float64
+	tree    []float64 // Fenwick tree of the weights, 1-based
+}
+
+// NewDynamic returns a sampler with the given initial weights.
+// It panics if a weight is negative, NaN or infinite.
+func NewDynamic(weights ...float64) *Dynamic {
+	d := &Dynamic{}
+	for _, w := range weights {
+		d.Append(w)
+	}
+
+	return d
+}
+
+// Len returns the number of weights of sampler d.
+func (d *Dynamic) Len() int { return len(d.weights) }
+
+// Clear removes all weights from sampler d.
+func (d *Dynamic) Clear() {
+	d.weights = d.weights[:0]
+	d.tree = d.tree[:0]
+}
+
+// Weight returns the weight of index i.
+// It panics if i is out of range.
+func (d *Dynamic) Weight(i int) float64 { return d.weights[i] }
+
+// Total returns the sum of the weights of sampler d.
+func (d *Dynamic) Total() float64 {
+	total := 0.0
+	for i := len(d.tree); i > 0; i &= i - 1 {
+		total += d.tree[i-1]
+	}
+
+	return total
+}
+
+// Append adds index d.Len() with weight w and returns it.
+// It panics if w is negative, NaN or infinite.
+func (d *Dynamic) Append(w float64) int {
+	checkWeight(w)
+
+	// The new node sums the weights of indexes (i&(i-1), i], 1-based,
+	// which are w and those summed by the nodes below it.
+	i := len(d.tree) + 1
+	sum := w
+	for j, lo := i-1, i&(i-1); j > lo; j &= j - 1 {
+		sum += d.tree[j-1]
+	}
+
+	d.weights = append(d.weights, w)
+	d.tree = append(d.tree, sum)
+	return i - 1
+}
+
+// Set sets the weight of index i to w.
+// It panics if i is out of range or w is negative, NaN or infinite.
+func (d *Dynamic) Set(i int, w float64) {
+	checkWeight(w)
+
+	delta := w - d.weights[i]
+	d.weights[i] = w
+	for j := i + 1; j <= len(d.tree); j += j & -j {
+		d.tree[j-1] += delta
+	}
+}
+
+// Pick returns a random index chosen with probability proportional to its
+// weight, using r as the source of randomness. If r is nil, Pick uses the
+// top-level functions of math/rand/v2.
+// It panics if the weights sum to zero.
+func (d *Dynamic) Pick(r *rand.Rand) int {
+	total := d.Total()
+	if !(total > 0) {
+		panic("sampler: total weight not positive")
+	}
+
+	for {
+		u := float64n(r) * total
+
+		// Descend the tree for the first index whose prefix sum exceeds u.
+		i := 0
+		for step := 1 << (bits.Len(uint(len(d.tree))) - 1); step > 0; step >>= 1 {
+			if j := i + step; j <= len(d.tree) && d.tree[j-1] <= u {
+				i = j
+				u -= d.tree[j-1]
+			}
+		}
+
+		// Rounding in the tree can land past the end or on a zero weight.
+		if i < len(d.weights) && d.weights[i] > 0 {
+			return i
+		}
+	}
+}
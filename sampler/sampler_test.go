@@ -0,0 +1,80 @@
+package sampler
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func checkDistribution(t *testing.T, pick func() int, weights []float64) {
+	t.Helper()
+
+	const n = 100000
+
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+
+	counts := make([]int, len(weights))
+	for range n {
+		counts[pick()]++
+	}
+
+	for i, w := range weights {
+		want := w / total
+		got := float64(counts[i]) / n
+		if w == 0 && counts[i] != 0 || math.Abs(got-want) > 0.01 {
+			t.Errorf("index %d picked with frequency %.3f, want %.3f", i, got, want)
+		}
+	}
+}
+
+func TestAlias(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewPCG(1, 2))
+	weights := []float64{1, 0, 3, 0.5, 5.5}
+	a := NewAlias(weights)
+	if a.Len() != len(weights) {
+		t.Errorf("a.Len() = %d, want %d", a.Len(), len(weights))
+	}
+	checkDistribution(t, func() int { return a.Pick(r) }, weights)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewAlias of zero weights did not panic")
+		}
+	}()
+	NewAlias([]float64{0, 0})
+}
+
+func TestDynamic(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewPCG(1, 2))
+	weights := []float64{2, 1, 0, 4, 1, 3, 2}
+	d := NewDynamic(weights...)
+	if d.Total() != 13 {
+		t.Errorf("d.Total() = %v, want 13", d.Total())
+	}
+	checkDistribution(t, func() int { return d.Pick(r) }, weights)
+
+	d.Set(0, 0)
+	d.Set(2, 5)
+	weights[0], weights[2] = 0, 5
+	weights = append(weights, 1)
+	if i := d.Append(1); i != 7 {
+		t.Errorf("d.Append(1) = %d, want 7", i)
+	}
+	if d.Total() != 17 || d.Weight(2) != 5 {
+		t.Errorf("d.Total() = %v, d.Weight(2) = %v, want 17, 5", d.Total(), d.Weight(2))
+	}
+	checkDistribution(t, func() int { return d.Pick(r) }, weights)
+
+	d.Clear()
+	d.Append(1)
+	if i := d.Pick(nil); i != 0 {
+		t.Errorf("d.Pick(nil) = %d, want 0", i)
+	}
+}
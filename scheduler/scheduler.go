@@ -0,0 +1,290 @@
+// Package scheduler dispatches prioritized jobs to a fixed number of
+// workers.
+//
+// A job is submitted with a priority and a time before which it must not
+// start. Among the jobs that may start, the workers run those of higher
+// priority first, and jobs of equal priority in the order they were
+// submitted. Each job runs with its own context, derived from the one it
+// was submitted with: canceling it removes a waiting job from the queue
+// and is visible to a running one.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/weiwenchen2022/container/heap"
+)
+
+// ErrClosed is returned by Submit after Drain or Close, and reported by
+// the jobs that Close discarded.
+var ErrClosed = errors.New("scheduler: closed")
+
+// A Job is a unit of work. It should return promptly once ctx is done.
+type Job func(ctx context.Context) error
+
+// Task is a submitted job.
+type Task struct {
+	job       Job
+	ctx       context.Context
+	cancel    context.CancelFunc
+	priority  int
+	notBefore time.Time
+	seq       uint64
+
+	index   int // in the ready or delayed heap, or -1
+	delayed bool
+
+	done chan struct{}
+	err  error
+}
+
+// Cancel cancels the context of task t. A task that has not started is
+// removed from the queue and reports the cancellation as its error.
+func (t *Task) Cancel() { t.cancel() }
+
+// Done returns a channel that is closed when task t has finished or was
+// removed from the queue.
+func (t *Task) Done() <-chan struct{} { return t.done }
+
+// Err returns the error of the job of task t, or the reason it did not
+// run. It must only be called after Done is closed.
+func (t *Task) Err() error { return t.err }
+
+// Scheduler is a queue of jobs run by a fixed number of workers.
+// To create a scheduler use scheduler.New.
+type Scheduler struct {
+	mu      sync.Mutex
+	changed chan struct{} // closed and replaced when the queue changes
+
+	ready   *heap.Heap[*Task] // by priority, then submission order
+	delayed *heap.Heap[*Task] // by start time
+	seq     uint64
+	running int
+
+	draining, closed bool
+
+	wg sync.WaitGroup
+}
+
+// New returns a scheduler running at most workers jobs at a time.
+// It panics if workers is not positive.
+func New(workers int) *Scheduler {
+	if workers <= 0 {
+		panic("scheduler: non-positive workers")
+	}
+
+	setIndex := func(t *Task, i int) { t.index = i }
+	s := &Scheduler{
+		changed: make(chan struct{}),
+		ready: heap.New(func(a, b *Task) bool {
+			if a.priority != b.priority {
+				return a.priority > b.priority
+			}
+			return a.seq < b.seq
+		}, heap.WithSetIndex(setIndex)),
+		delayed: heap.New(func(a, b *Task) bool {
+			if !a.notBefore.Equal(b.notBefore) {
+				return a.notBefore.Before(b.notBefore)
+			}
+			return a.seq < b.seq
+		}, heap.WithSetIndex(setIndex)),
+	}
+
+	s.wg.Add(workers)
+	for range workers {
+		go s.worker()
+	}
+
+	return s
+}
+
+// notify wakes the workers. s.mu must be held.
+func (s *Scheduler) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Submit queues job to run with the given priority, not before notBefore;
+// a zero notBefore lets it start at once. The job runs with a context
+// derived from ctx, and is removed from the queue if ctx is done before it
+// starts.
+func (s *Scheduler) Submit(ctx context.Context, job Job, priority int, notBefore time.Time) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.draining || s.closed {
+		return nil, ErrClosed
+	}
+
+	t := &Task{job: job, priority: priority, notBefore: notBefore, seq: s.seq, index: -1, done: make(chan struct{})}
+	s.seq++
+	t.ctx, t.cancel = context.WithCancel(ctx)
+
+	if !notBefore.IsZero() && time.Now().Before(notBefore) {
+		t.delayed = true
+		s.delayed.Push(t)
+	} else {
+		s.ready.Push(t)
+	}
+	s.notify()
+
+	context.AfterFunc(t.ctx, func() { s.discard(t, context.Cause(t.ctx)) })
+	return t, nil
+}
+
+// discard removes task t from the queue, if it is still waiting, and
+// finishes it with err.
+func (s *Scheduler) discard(t *Task, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.index < 0 {
+		return
+	}
+
+	if t.delayed {
+		s.delayed.Remove(t.index)
+	} else {
+		s.ready.Remove(t.index)
+	}
+	t.err = err
+	close(t.done)
+	s.notify()
+}
+
+// Len returns the number of jobs waiting to run.
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ready.Len() + s.delayed.Len()
+}
+
+// Running returns the number of jobs running.
+func (s *Scheduler) Running() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.running
+}
+
+// promote moves the delayed tasks that may start to the ready queue and
+// returns the time the next delayed task may start. s.mu must be held.
+func (s *Scheduler) promote() (next time.Time) {
+	now := time.Now()
+	for s.delayed.Len() > 0 {
+		t := s.delayed.Peek()
+		if now.Before(t.notBefore) {
+			return t.notBefore
+		}
+
+		s.delayed.Pop()
+		t.delayed = false
+		s.ready.Push(t)
+	}
+
+	return next
+}
+
+func (s *Scheduler) worker() {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	s.mu.Lock()
+	for {
+		next := s.promote()
+		if s.ready.Len() > 0 {
+			t := s.ready.Pop()
+			s.running++
+			s.mu.Unlock()
+
+			t.err = t.job(t.ctx)
+			t.cancel()
+			close(t.done)
+
+			s.mu.Lock()
+			s.running--
+			s.notify()
+			continue
+		}
+
+		if s.closed || s.draining && s.delayed.Len() == 0 {
+			break
+		}
+
+		changed := s.changed
+		s.mu.Unlock()
+
+		var wake <-chan time.Time
+		if !next.IsZero() {
+			timer.Reset(time.Until(next))
+			wake = timer.C
+		}
+		select {
+		case <-changed:
+		case <-wake:
+		}
+		timer.Stop()
+
+		s.mu.Lock()
+	}
+	s.mu.Unlock()
+}
+
+// Drain stops the scheduler from accepting jobs and waits until all
+// submitted jobs, including delayed ones, have finished or ctx is done.
+// It returns ctx.Err() if ctx is done first; the workers keep draining
+// the queue in the background.
+func (s *Scheduler) Drain(ctx context.Context) error {
+	s.mu.Lock()
+	s.draining = true
+	s.notify()
+	s.mu.Unlock()
+
+	return s.wait(ctx)
+}
+
+// Close stops the scheduler from accepting jobs, discards the waiting
+// jobs, which report ErrClosed, and waits for the running jobs to finish.
+// Their contexts are not canceled.
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	s.closed = true
+	var discarded []*Task
+	for _, h := range []*heap.Heap[*Task]{s.ready, s.delayed} {
+		for h.Len() > 0 {
+			discarded = append(discarded, h.Pop())
+		}
+	}
+	for _, t := range discarded {
+		t.err = ErrClosed
+		close(t.done)
+	}
+	s.notify()
+	s.mu.Unlock()
+
+	for _, t := range discarded {
+		t.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
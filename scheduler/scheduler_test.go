@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPriority(t *testing.T) {
+	t.Parallel()
+
+	s := New(1)
+
+	// block the only worker until all jobs are queued
+	release := make(chan struct{})
+	s.Submit(context.Background(), func(context.Context) error { <-release; return nil }, 0, time.Time{})
+	for s.Running() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	var mu sync.Mutex
+	var order []int
+	for i, p := range []int{1, 3, 2, 3, 1} {
+		s.Submit(context.Background(), func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, i)
+			return nil
+		}, p, time.Time{})
+	}
+	if n := s.Len(); n != 5 {
+		t.Errorf("s.Len() = %d, want 5", n)
+	}
+	close(release)
+
+	if err := s.Drain(context.Background()); err != nil {
+		t.Fatalf("s.Drain() = %v", err)
+	}
+	if want := []int{1, 3, 2, 0, 4}; !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if _, err := s.Submit(context.Background(), nil, 0, time.Time{}); err != ErrClosed {
+		t.Errorf("Submit after Drain = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	t.Parallel()
+
+	s := New(2)
+	start := time.Now()
+	var ran time.Time
+	task, _ := s.Submit(context.Background(), func(context.Context) error {
+		ran = time.Now()
+		return errors.New("failed")
+	}, 0, start.Add(20*time.Millisecond))
+
+	if err := s.Drain(context.Background()); err != nil {
+		t.Fatalf("s.Drain() = %v", err)
+	}
+	<-task.Done()
+	if d := ran.Sub(start); d < 20*time.Millisecond {
+		t.Errorf("delayed job ran after %v, want >= 20ms", d)
+	}
+	if err := task.Err(); err == nil || err.Error() != "failed" {
+		t.Errorf("task.Err() = %v, want failed", err)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	t.Parallel()
+
+	s := New(1)
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	waiting, _ := s.Submit(ctx, func(context.Context) error { return nil }, 0, time.Now().Add(time.Hour))
+	cancel()
+	<-waiting.Done()
+	if err := waiting.Err(); err != context.Canceled {
+		t.Errorf("waiting.Err() = %v, want %v", err, context.Canceled)
+	}
+	if n := s.Len(); n != 0 {
+		t.Errorf("s.Len() after cancel = %d, want 0", n)
+	}
+
+	started := make(chan struct{})
+	running, _ := s.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, 0, time.Time{})
+	<-started
+	running.Cancel()
+	<-running.Done()
+	if err := running.Err(); err != context.Canceled {
+		t.Errorf("running.Err() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	s := New(1)
+	task, _ := s.Submit(context.Background(), func(context.Context) error { return nil }, 0, time.Now().Add(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Drain(ctx); err != context.DeadlineExceeded {
+		t.Errorf("s.Drain() = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	s.Close()
+	<-task.Done()
+	if err := task.Err(); err != ErrClosed {
+		t.Errorf("task.Err() = %v, want %v", err, ErrClosed)
+	}
+}
@@ -0,0 +1,206 @@
+// Package smallvec implements a vector that stores a few elements inline.
+//
+// A Vec keeps up to len(A) elements in an array of type A inside the Vec
+// itself and only allocates when it grows beyond that, when all elements
+// move to a slice on the heap. Embedding a Vec in a struct therefore costs
+// no allocation for the common case of fields that usually hold zero to a
+// handful of elements:
+//
+//	type node struct {
+//		children smallvec.Vec[*node, [4]*node]
+//	}
+package smallvec
+
+import (
+	"iter"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+	"github.com/weiwenchen2022/container/sortx"
+)
+
+// Array is the set of array types a Vec can store elements inline in.
+type Array[E any] interface {
+	~[1]E | ~[2]E | ~[3]E | ~[4]E | ~[5]E | ~[6]E | ~[7]E | ~[8]E |
+		~[12]E | ~[16]E | ~[24]E | ~[32]E | ~[64]E
+}
+
+// Vec is a vector of elements of type E storing up to len(A) of them
+// inline. The zero value for Vec is an empty vector ready to use.
+// A Vec must not be copied after it has spilled to the heap, since the
+// copies would share elements; use Clone instead.
+type Vec[E any, A Array[E]] struct {
+	inline A
+	n      int // number of inline elements
+	heap   []E // all elements, once spilled
+}
+
+// Len returns the number of elements of vector v.
+func (v *Vec[E, A]) Len() int {
+	if v.heap != nil {
+		return len(v.heap)
+	}
+
+	return v.n
+}
+
+// Spilled reports whether the elements of vector v are stored on the heap.
+func (v *Vec[E, A]) Spilled() bool { return v.heap != nil }
+
+// Clear removes all elements from vector v, returning it to inline storage.
+func (v *Vec[E, A]) Clear() {
+	var zero A
+	v.inline, v.n, v.heap = zero, 0, nil
+}
+
+// At returns the element at index i.
+// It panics if i is out of range.
+func (v *Vec[E, A]) At(i int) E {
+	if v.heap != nil {
+		return v.heap[i]
+	}
+
+	v.checkIndex(i)
+	return v.inline[i]
+}
+
+// Set sets the element at index i to x.
+// It panics if i is out of range.
+func (v *Vec[E, A]) Set(i int, x E) {
+	if v.heap != nil {
+		v.heap[i] = x
+		return
+	}
+
+	v.checkIndex(i)
+	v.inline[i] = x
+}
+
+func (v *Vec[E, A]) checkIndex(i int) {
+	if i < 0 || i >= v.n {
+		panic("smallvec: index out of range")
+	}
+}
+
+// Append appends xs to vector v, spilling to the heap if they do not fit
+// inline.
+func (v *Vec[E, A]) Append(xs ...E) {
+	if v.heap == nil && v.n+len(xs) <= len(v.inline) {
+		for _, x := range xs {
+			v.inline[v.n] = x
+			v.n++
+		}
+
+		return
+	}
+
+	if v.heap == nil {
+		v.heap = make([]E, v.n, max(2*len(v.inline), v.n+len(xs)))
+		for i := range v.n {
+			v.heap[i] = v.inline[i]
+		}
+
+		var zero A
+		v.inline, v.n = zero, 0
+	}
+
+	v.heap = append(v.heap, xs...)
+}
+
+// Pop removes and returns the last element of vector v.
+// The ok result is false if v is empty.
+func (v *Vec[E, A]) Pop() (x E, ok bool) {
+	var zero E
+	if v.heap != nil {
+		n := len(v.heap)
+		if n == 0 {
+			return zero, false
+		}
+
+		x = v.heap[n-1]
+		v.heap[n-1] = zero
+		v.heap = v.heap[:n-1]
+		return x, true
+	}
+
+	if v.n == 0 {
+		return zero, false
+	}
+
+	v.n--
+	x, v.inline[v.n] = v.inline[v.n], zero
+	return x, true
+}
+
+// All returns an iterator over the indexes and elements of vector v.
+// v must not be modified during iteration.
+func (v *Vec[E, A]) All() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		for i := range v.Len() {
+			if !yield(i, v.At(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the elements of vector v.
+// v must not be modified during iteration.
+func (v *Vec[E, A]) Values() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := range v.Len() {
+			if !yield(v.At(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Sort sorts the elements of vector v in ascending order as determined by
+// less. The sort is not guaranteed to be stable.
+func (v *Vec[E, A]) Sort(less func(a, b E) bool) {
+	if v.heap != nil {
+		sortx.Sort(v.heap, less)
+		return
+	}
+
+	sortx.SortIndexed(v, less)
+}
+
+// AppendTo appends the elements of vector v to dst and returns the
+// extended slice.
+func (v *Vec[E, A]) AppendTo(dst []E) []E {
+	if v.heap != nil {
+		return append(dst, v.heap...)
+	}
+
+	for i := range v.n {
+		dst = append(dst, v.inline[i])
+	}
+
+	return dst
+}
+
+// Clone returns a copy of vector v. The elements are copied by assignment,
+// so this is a shallow clone.
+func (v *Vec[E, A]) Clone() Vec[E, A] {
+	c := *v
+	if v.heap != nil {
+		c.heap = append(make([]E, 0, cap(v.heap)), v.heap...)
+	}
+
+	return c
+}
+
+// MemStats returns statistics about the memory used by vector v.
+func (v *Vec[E, A]) MemStats() container.MemStats {
+	if v.heap != nil {
+		return container.MemStats{
+			Len:   len(v.heap),
+			Cap:   cap(v.heap),
+			Bytes: memsize.Of[Vec[E, A]]() + memsize.Slice[E](cap(v.heap)),
+		}
+	}
+
+	return container.MemStats{Len: v.n, Cap: len(v.inline), Bytes: memsize.Of[Vec[E, A]]()}
+}
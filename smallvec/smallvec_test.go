@@ -0,0 +1,128 @@
+package smallvec
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container     = (*Vec[int, [4]int])(nil)
+	_ container.Iterable[int] = (*Vec[int, [4]int])(nil)
+	_ container.Sizer         = (*Vec[int, [4]int])(nil)
+)
+
+func checkVec(t *testing.T, v *Vec[int, [4]int], want []int) {
+	t.Helper()
+
+	if n := v.Len(); n != len(want) {
+		t.Fatalf("v.Len() = %d, want %d", n, len(want))
+	}
+	for i, x := range want {
+		if y := v.At(i); x != y {
+			t.Fatalf("v.At(%d) = %d, want %d", i, y, x)
+		}
+	}
+	if got := v.AppendTo(nil); !slices.Equal(got, want) {
+		t.Fatalf("v.AppendTo(nil) = %v, want %v", got, want)
+	}
+	if got := slices.Collect(v.Values()); !slices.Equal(got, want) {
+		t.Fatalf("v.Values() = %v, want %v", got, want)
+	}
+}
+
+func TestVec(t *testing.T) {
+	t.Parallel()
+
+	var v Vec[int, [4]int]
+	checkVec(t, &v, nil)
+	if _, ok := v.Pop(); ok {
+		t.Errorf("v.Pop() of empty vector ok")
+	}
+
+	v.Append(1, 2)
+	v.Append(3)
+	v.Set(0, 0)
+	checkVec(t, &v, []int{0, 2, 3})
+	if v.Spilled() {
+		t.Errorf("vector of 3 spilled")
+	}
+
+	if x, ok := v.Pop(); !ok || x != 3 {
+		t.Errorf("v.Pop() = %d, %t, want 3, true", x, ok)
+	}
+
+	v.Append(4, 5, 6)
+	if !v.Spilled() {
+		t.Errorf("vector of 5 not spilled")
+	}
+	v.Set(4, 7)
+	checkVec(t, &v, []int{0, 2, 4, 5, 7})
+
+	c := v.Clone()
+	c.Set(0, 9)
+	c.Pop()
+	checkVec(t, &v, []int{0, 2, 4, 5, 7})
+	checkVec(t, &c, []int{9, 2, 4, 5})
+
+	v.Clear()
+	checkVec(t, &v, nil)
+	if v.Spilled() {
+		t.Errorf("vector spilled after Clear")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("v.At(0) of empty vector did not panic")
+		}
+	}()
+	v.At(0)
+}
+
+func TestAllocs(t *testing.T) {
+	var v Vec[int, [4]int]
+	n := testing.AllocsPerRun(100, func() {
+		v.Clear()
+		v.Append(1, 2, 3, 4)
+		v.Pop()
+	})
+	if n != 0 {
+		t.Errorf("inline use allocates %v times", n)
+	}
+}
+
+func TestMemStats(t *testing.T) {
+	t.Parallel()
+
+	var v Vec[int, [4]int]
+	v.Append(1)
+	inline := v.MemStats()
+	if inline.Len != 1 || inline.Cap != 4 {
+		t.Errorf("inline v.MemStats() = %+v", inline)
+	}
+
+	v.Append(2, 3, 4, 5)
+	if s := v.MemStats(); s.Len != 5 || s.Cap < 5 || s.Bytes <= inline.Bytes {
+		t.Errorf("spilled v.MemStats() = %+v", s)
+	}
+}
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+
+	var v Vec[int, [4]int]
+	v.Append(3, 1, 2)
+	v.Sort(less)
+	if got := v.AppendTo(nil); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("inline v = %v, want [1 2 3]", got)
+	}
+
+	v.Append(9, 0, 5)
+	v.Sort(less)
+	if got := v.AppendTo(nil); !v.Spilled() || !slices.Equal(got, []int{0, 1, 2, 3, 5, 9}) {
+		t.Errorf("spilled v = %v, want [0 1 2 3 5 9]", got)
+	}
+}
@@ -0,0 +1,175 @@
+// Package sortedslice implements a slice kept in sorted order.
+//
+// Insert and Delete find their position by binary search and shift the
+// elements after it, so they cost O(log n) comparisons and O(n) copying.
+// For small to medium sizes the copying is a fast memmove, and the
+// contiguous layout makes searching and iteration faster than in a tree.
+//
+// To iterate over a sorted slice (where s is a *Slice):
+//
+//	for x := range s.Values() {
+//		// do something with x
+//	}
+package sortedslice
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+// Slice is a sorted slice of elements of type E.
+// Equal elements are kept in insertion order.
+// To create a sorted slice use sortedslice.New or sortedslice.NewFunc.
+type Slice[E any] struct {
+	cmp func(a, b E) int
+	s   []E
+}
+
+// New returns a sorted slice of xs in the natural order of E.
+func New[E cmp.Ordered](xs ...E) *Slice[E] {
+	return NewFunc(cmp.Compare[E], xs...)
+}
+
+// NewFunc returns a sorted slice of xs ordered by cmp, which returns a
+// negative number when a < b, a positive number when a > b and zero when
+// a and b are equal.
+func NewFunc[E any](cmp func(a, b E) int, xs ...E) *Slice[E] {
+	s := slices.Clone(xs)
+	slices.SortStableFunc(s, cmp)
+	return &Slice[E]{cmp: cmp, s: s}
+}
+
+// Wrap returns a sorted slice using xs as its storage, without copying it.
+// It sorts xs in place, in the natural order of E, unless xs is already
+// sorted. The sorted slice takes ownership of xs, and the caller should
+// not use xs after this call.
+func Wrap[E cmp.Ordered](xs []E) *Slice[E] {
+	return WrapFunc(xs, cmp.Compare[E])
+}
+
+// WrapFunc is like Wrap but orders the elements by cmp, like NewFunc.
+func WrapFunc[E any](xs []E, cmp func(a, b E) int) *Slice[E] {
+	if !slices.IsSortedFunc(xs, cmp) {
+		slices.SortStableFunc(xs, cmp)
+	}
+
+	return &Slice[E]{cmp: cmp, s: xs}
+}
+
+// Len returns the number of elements of sorted slice s.
+func (s *Slice[E]) Len() int { return len(s.s) }
+
+// Clear removes all elements from sorted slice s.
+func (s *Slice[E]) Clear() {
+	clear(s.s)
+	s.s = s.s[:0]
+}
+
+// At returns the i'th smallest element of sorted slice s.
+// It panics if i is out of range.
+func (s *Slice[E]) At(i int) E { return s.s[i] }
+
+// search returns the index of the first element not less than x, if after
+// is false, or greater than x, if after is true.
+func (s *Slice[E]) search(x E, after bool) int {
+	lo, hi := 0, len(s.s)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		if c := s.cmp(s.s[m], x); c < 0 || after && c == 0 {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+
+	return lo
+}
+
+// Insert adds x to sorted slice s after any equal elements and returns its
+// index.
+func (s *Slice[E]) Insert(x E) int {
+	i := s.search(x, true)
+	s.s = slices.Insert(s.s, i, x)
+	return i
+}
+
+// Index returns the index of the first element equal to x, or the index x
+// would be inserted at and false if there is none.
+func (s *Slice[E]) Index(x E) (int, bool) {
+	i := s.search(x, false)
+	return i, i < len(s.s) && s.cmp(s.s[i], x) == 0
+}
+
+// Contains reports whether an element equal to x is in sorted slice s.
+func (s *Slice[E]) Contains(x E) bool {
+	_, ok := s.Index(x)
+	return ok
+}
+
+// Count returns the number of elements equal to x.
+func (s *Slice[E]) Count(x E) int {
+	return s.search(x, true) - s.search(x, false)
+}
+
+// Delete removes the first element equal to x and reports whether there
+// was one.
+func (s *Slice[E]) Delete(x E) bool {
+	i, ok := s.Index(x)
+	if ok {
+		s.DeleteAt(i)
+	}
+
+	return ok
+}
+
+// DeleteAt removes and returns the element at index i.
+// It panics if i is out of range.
+func (s *Slice[E]) DeleteAt(i int) E {
+	x := s.s[i]
+	s.s = slices.Delete(s.s, i, i+1)
+	return x
+}
+
+// All returns an iterator over the indexes and elements of sorted slice s
+// in ascending order. s must not be modified during iteration.
+func (s *Slice[E]) All() iter.Seq2[int, E] { return slices.All(s.s) }
+
+// Values returns an iterator over the elements of sorted slice s in
+// ascending order. s must not be modified during iteration.
+func (s *Slice[E]) Values() iter.Seq[E] { return slices.Values(s.s) }
+
+// Backward returns an iterator over the indexes and elements of sorted
+// slice s in descending order. s must not be modified during iteration.
+func (s *Slice[E]) Backward() iter.Seq2[int, E] { return slices.Backward(s.s) }
+
+// Range returns an iterator over the elements x of sorted slice s with
+// lo <= x < hi, in ascending order. s must not be modified during
+// iteration.
+func (s *Slice[E]) Range(lo, hi E) iter.Seq[E] {
+	i := s.search(lo, false)
+	j := max(i, s.search(hi, false))
+	return slices.Values(s.s[i:j])
+}
+
+// AppendTo appends the elements of sorted slice s to dst in ascending
+// order and returns the extended slice.
+func (s *Slice[E]) AppendTo(dst []E) []E { return append(dst, s.s...) }
+
+// Clone returns a copy of sorted slice s. The elements are copied by
+// assignment, so this is a shallow clone.
+func (s *Slice[E]) Clone() *Slice[E] {
+	return &Slice[E]{cmp: s.cmp, s: slices.Clone(s.s)}
+}
+
+// MemStats returns statistics about the memory used by sorted slice s.
+func (s *Slice[E]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len:   len(s.s),
+		Cap:   cap(s.s),
+		Bytes: memsize.Of[Slice[E]]() + memsize.Slice[E](cap(s.s)),
+	}
+}
@@ -0,0 +1,113 @@
+package sortedslice
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container     = (*Slice[int])(nil)
+	_ container.Iterable[int] = (*Slice[int])(nil)
+	_ container.Sizer         = (*Slice[int])(nil)
+)
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	s := New[int]()
+	var want []int
+	for i := 0; i < 5000; i++ {
+		x := r.Intn(100)
+		if r.Intn(3) == 0 {
+			j, ok := slices.BinarySearch(want, x)
+			if got := s.Delete(x); got != ok {
+				t.Fatalf("s.Delete(%d) = %t, want %t", x, got, ok)
+			}
+			if ok {
+				want = slices.Delete(want, j, j+1)
+			}
+		} else {
+			s.Insert(x)
+			want = append(want, x)
+			slices.Sort(want)
+		}
+
+		if got := s.AppendTo(nil); !slices.Equal(got, want) {
+			t.Fatalf("step %d: s = %v, want %v", i, got, want)
+		}
+	}
+
+	for x := range 100 {
+		wi, wok := slices.BinarySearch(want, x)
+		if i, ok := s.Index(x); i != wi || ok != wok {
+			t.Errorf("s.Index(%d) = %d, %t, want %d, %t", x, i, ok, wi, wok)
+		}
+		if s.Contains(x) != wok {
+			t.Errorf("s.Contains(%d) = %t, want %t", x, !wok, wok)
+		}
+	}
+}
+
+func TestFunc(t *testing.T) {
+	t.Parallel()
+
+	byLen := func(a, b string) int { return len(a) - len(b) }
+	s := NewFunc(byLen, "ccc", "a", "bb")
+	if i := s.Insert("xx"); i != 2 {
+		t.Errorf(`s.Insert("xx") = %d, want 2`, i)
+	}
+	if n := s.Count("yy"); n != 2 {
+		t.Errorf(`s.Count("yy") = %d, want 2`, n)
+	}
+
+	want := []string{"a", "bb", "xx", "ccc"}
+	if got := slices.Collect(s.Values()); !slices.Equal(got, want) {
+		t.Errorf("s.Values() = %v, want %v", got, want)
+	}
+	for i, x := range s.Backward() {
+		if x != want[i] || s.At(i) != x {
+			t.Errorf("s.Backward() yielded %d, %q", i, x)
+		}
+	}
+
+	if got := slices.Collect(s.Range("zz", "zzzzz")); !slices.Equal(got, want[1:]) {
+		t.Errorf("s.Range(2, 5) = %v, want %v", got, want[1:])
+	}
+	if got := slices.Collect(s.Range("zzz", "z")); len(got) != 0 {
+		t.Errorf("empty s.Range = %v", got)
+	}
+
+	c := s.Clone()
+	if x := c.DeleteAt(0); x != "a" {
+		t.Errorf("c.DeleteAt(0) = %q, want %q", x, "a")
+	}
+	if s.Len() != 4 || c.Len() != 3 {
+		t.Errorf("changing the clone changed the slice")
+	}
+
+	s.Clear()
+	if s.Len() != 0 || strings.Join(slices.Collect(s.Values()), "") != "" {
+		t.Errorf("slice not empty after Clear")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	xs := []int{3, 1, 2}
+	s := Wrap(xs)
+	if !slices.Equal(xs, []int{1, 2, 3}) || s.Len() != 3 {
+		t.Errorf("Wrap did not sort in place: %v", xs)
+	}
+
+	s.Insert(0)
+	if got := slices.Collect(s.Values()); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("s = %v, want [0 1 2 3]", got)
+	}
+}
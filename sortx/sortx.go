@@ -0,0 +1,91 @@
+// Package sortx sorts slices and indexed containers by less functions,
+// the form of ordering taken by heap.New and the other ordered containers
+// of this module, so one function can order both.
+//
+// Sort uses pattern-defeating quicksort, which is O(n log n) in the worst
+// case and linear on many common patterns such as sorted and reversed
+// input; Stable keeps equal elements in their original order.
+package sortx
+
+import (
+	"slices"
+
+	"github.com/weiwenchen2022/container/cmpx"
+)
+
+// Sort sorts s in ascending order as determined by less.
+// The sort is not guaranteed to be stable.
+func Sort[E any](s []E, less func(a, b E) bool) {
+	slices.SortFunc(s, cmpx.Compare(less))
+}
+
+// Stable sorts s in ascending order as determined by less, keeping equal
+// elements in their original order.
+func Stable[E any](s []E, less func(a, b E) bool) {
+	slices.SortStableFunc(s, cmpx.Compare(less))
+}
+
+// IsSorted reports whether s is sorted in ascending order as determined
+// by less.
+func IsSorted[E any](s []E, less func(a, b E) bool) bool {
+	for i := len(s) - 1; i > 0; i-- {
+		if less(s[i], s[i-1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Indexed is implemented by containers whose elements can be read and
+// written by position, such as *gapbuffer.Buffer and *smallvec.Vec.
+type Indexed[E any] interface {
+	Len() int
+	At(i int) E
+	Set(i int, v E)
+}
+
+// collect returns the elements of x as a slice.
+func collect[E any](x Indexed[E]) []E {
+	s := make([]E, x.Len())
+	for i := range s {
+		s[i] = x.At(i)
+	}
+
+	return s
+}
+
+func store[E any](x Indexed[E], s []E) {
+	for i, v := range s {
+		x.Set(i, v)
+	}
+}
+
+// SortIndexed sorts the elements of x in ascending order as determined by
+// less. It copies the elements into a temporary slice, sorts it and writes
+// them back, so it uses O(n) extra space and n calls each of At and Set.
+func SortIndexed[E any](x Indexed[E], less func(a, b E) bool) {
+	s := collect(x)
+	Sort(s, less)
+	store(x, s)
+}
+
+// StableIndexed is like SortIndexed but keeps equal elements in their
+// original order.
+func StableIndexed[E any](x Indexed[E], less func(a, b E) bool) {
+	s := collect(x)
+	Stable(s, less)
+	store(x, s)
+}
+
+// IsSortedIndexed reports whether the elements of x are sorted in
+// ascending order as determined by less.
+func IsSortedIndexed[E any](x Indexed[E], less func(a, b E) bool) bool {
+	for i := x.Len() - 1; i > 0; i-- {
+		if less(x.At(i), x.At(i-1)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,65 @@
+package sortx
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+// indexed is a slice implementing Indexed.
+type indexed []int
+
+func (s indexed) Len() int         { return len(s) }
+func (s indexed) At(i int) int     { return s[i] }
+func (s indexed) Set(i int, v int) { s[i] = v }
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = r.Intn(100)
+	}
+	want := slices.Sorted(slices.Values(s))
+
+	a := slices.Clone(s)
+	Sort(a, less)
+	if !slices.Equal(a, want) || !IsSorted(a, less) {
+		t.Errorf("Sort did not sort")
+	}
+
+	x := indexed(slices.Clone(s))
+	if IsSortedIndexed(x, less) {
+		t.Errorf("IsSortedIndexed of random input = true")
+	}
+	SortIndexed(x, less)
+	if !slices.Equal(x, want) || !IsSortedIndexed(x, less) {
+		t.Errorf("SortIndexed did not sort")
+	}
+}
+
+func TestStable(t *testing.T) {
+	t.Parallel()
+
+	type pair struct{ key, seq int }
+	byKey := func(a, b pair) bool { return a.key < b.key }
+
+	r := rand.New(rand.NewSource(1))
+	s := make([]pair, 1000)
+	for i := range s {
+		s[i] = pair{r.Intn(10), i}
+	}
+
+	Stable(s, byKey)
+	for i := 1; i < len(s); i++ {
+		if s[i].key == s[i-1].key && s[i].seq < s[i-1].seq {
+			t.Fatalf("Stable reordered equal elements at %d", i)
+		}
+	}
+	if !IsSorted(s, byKey) {
+		t.Errorf("Stable did not sort")
+	}
+}
@@ -0,0 +1,151 @@
+// Package stats computes order statistics of the values of an iter.Seq,
+// such as the Values iterator of any container in this module.
+//
+// Median and Percentile collect the values and find the one of the
+// requested rank by selection, which takes expected linear time instead of
+// the O(n log n) of sorting. To compute several percentiles of the same
+// values, collect them once and use Select.
+package stats
+
+import (
+	"cmp"
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// MinMax returns the smallest and largest values of seq.
+// The ok result reports whether seq has any values.
+// For floating-point values, NaNs are smaller than any other value, as
+// with cmp.Compare.
+func MinMax[E cmp.Ordered](seq iter.Seq[E]) (min, max E, ok bool) {
+	return MinMaxFunc(seq, cmp.Compare[E])
+}
+
+// MinMaxFunc is like MinMax but uses cmp to compare values. If several
+// values are minimal or maximal, the first one is returned.
+func MinMaxFunc[E any](seq iter.Seq[E], cmp func(a, b E) int) (min, max E, ok bool) {
+	for v := range seq {
+		switch {
+		case !ok:
+			min, max, ok = v, v, true
+		case cmp(v, min) < 0:
+			min = v
+		case cmp(v, max) > 0:
+			max = v
+		}
+	}
+
+	return min, max, ok
+}
+
+// Median returns the lower median of the values of seq: the value of rank
+// (n-1)/2 among n values in ascending order.
+// The ok result reports whether seq has any values.
+func Median[E cmp.Ordered](seq iter.Seq[E]) (E, bool) {
+	return MedianFunc(seq, cmp.Compare[E])
+}
+
+// MedianFunc is like Median but uses cmp to compare values.
+func MedianFunc[E any](seq iter.Seq[E], cmp func(a, b E) int) (E, bool) {
+	return PercentileFunc(seq, 0.5, cmp)
+}
+
+// Percentile returns the value of seq at quantile q by the nearest-rank
+// method: the value of rank ⌈q·n⌉-1 among n values in ascending order, or
+// the smallest value if q is 0.
+// The ok result reports whether seq has any values.
+// It panics if q is not in [0, 1].
+func Percentile[E cmp.Ordered](seq iter.Seq[E], q float64) (E, bool) {
+	return PercentileFunc(seq, q, cmp.Compare[E])
+}
+
+// PercentileFunc is like Percentile but uses cmp to compare values.
+func PercentileFunc[E any](seq iter.Seq[E], q float64, cmp func(a, b E) int) (E, bool) {
+	if !(q >= 0 && q <= 1) {
+		panic("stats: quantile out of range [0, 1]")
+	}
+
+	s := slices.Collect(seq)
+	if len(s) == 0 {
+		var zero E
+		return zero, false
+	}
+
+	k := max(int(math.Ceil(q*float64(len(s))))-1, 0)
+	SelectFunc(s, k, cmp)
+	return s[k], true
+}
+
+// Select reorders s so that s[k] is the value that would be there if s
+// were sorted, no value of s[:k] is greater than it and no value of
+// s[k+1:] is smaller.
+// The expected complexity is O(n), and O(n log n) in the worst case.
+// It panics if k is out of range.
+func Select[E cmp.Ordered](s []E, k int) {
+	SelectFunc(s, k, cmp.Compare[E])
+}
+
+// SelectFunc is like Select but uses cmp to compare values.
+func SelectFunc[E any](s []E, k int, cmp func(a, b E) int) {
+	if k < 0 || k >= len(s) {
+		panic("stats: index out of range")
+	}
+
+	lo, hi := 0, len(s)
+	// Fall back to sorting after too many bad partitions.
+	for budget := 2 * bits.Len(uint(len(s))); hi-lo > 16; budget-- {
+		if budget == 0 {
+			slices.SortFunc(s[lo:hi], cmp)
+			return
+		}
+
+		lt, gt := partition(s[lo:hi], cmp)
+		switch {
+		case k < lo+lt:
+			hi = lo + lt
+		case k >= lo+gt:
+			lo += gt
+		default:
+			return
+		}
+	}
+
+	slices.SortFunc(s[lo:hi], cmp)
+}
+
+// partition reorders s around the median of its first, middle and last
+// values into values smaller than, equal to and greater than it, and
+// returns the bounds of the equal values.
+func partition[E any](s []E, cmp func(a, b E) int) (lt, gt int) {
+	a, b, c := 0, len(s)/2, len(s)-1
+	if cmp(s[b], s[a]) < 0 {
+		a, b = b, a
+	}
+	if cmp(s[c], s[b]) < 0 {
+		b = c
+		if cmp(s[b], s[a]) < 0 {
+			b = a
+		}
+	}
+	pivot := s[b]
+
+	// Dutch national flag partition.
+	lt, i, gt := 0, 0, len(s)
+	for i < gt {
+		switch c := cmp(s[i], pivot); {
+		case c < 0:
+			s[lt], s[i] = s[i], s[lt]
+			lt++
+			i++
+		case c > 0:
+			gt--
+			s[i], s[gt] = s[gt], s[i]
+		default:
+			i++
+		}
+	}
+
+	return lt, gt
+}
@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 17, 100, 1000} {
+		for _, dups := range []int{3, 1 << 30} {
+			s := make([]int, n)
+			for i := range s {
+				s[i] = r.Intn(dups)
+			}
+			sorted := slices.Sorted(slices.Values(s))
+
+			for range 10 {
+				k := r.Intn(n)
+				c := slices.Clone(s)
+				Select(c, k)
+				if c[k] != sorted[k] {
+					t.Fatalf("n = %d: Select(k = %d) = %d, want %d", n, k, c[k], sorted[k])
+				}
+				for i, v := range c {
+					if i < k && v > c[k] || i > k && v < c[k] {
+						t.Fatalf("n = %d: s[%d] = %d on the wrong side of s[%d] = %d", n, i, v, k, c[k])
+					}
+				}
+			}
+		}
+	}
+
+	// sorted input must not degrade
+	s := make([]int, 100000)
+	for i := range s {
+		s[i] = i
+	}
+	Select(s, 70000)
+	if s[70000] != 70000 {
+		t.Errorf("Select of sorted input = %d, want 70000", s[70000])
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	vs := []int{15, 20, 35, 40, 50}
+	tests := []struct {
+		q    float64
+		want int
+	}{
+		{0, 15},
+		{0.05, 15},
+		{0.3, 20},
+		{0.4, 20},
+		{0.5, 35},
+		{1, 50},
+	}
+	for _, tt := range tests {
+		if got, ok := Percentile(slices.Values(vs), tt.q); !ok || got != tt.want {
+			t.Errorf("Percentile(%v) = %d, %t, want %d, true", tt.q, got, ok, tt.want)
+		}
+	}
+
+	if got, _ := Median(slices.Values([]int{4, 1, 3, 2})); got != 2 {
+		t.Errorf("Median = %d, want 2", got)
+	}
+	if _, ok := Median(slices.Values([]int(nil))); ok {
+		t.Errorf("Median of no values reports ok")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Percentile(1.5) did not panic")
+		}
+	}()
+	Percentile(slices.Values(vs), 1.5)
+}
+
+func TestMinMax(t *testing.T) {
+	t.Parallel()
+
+	if min, max, ok := MinMax(slices.Values([]int{3, 1, 4, 1, 5, 9, 2, 6})); !ok || min != 1 || max != 9 {
+		t.Errorf("MinMax = %d, %d, %t, want 1, 9, true", min, max, ok)
+	}
+	if _, _, ok := MinMax(slices.Values([]string(nil))); ok {
+		t.Errorf("MinMax of no values reports ok")
+	}
+}
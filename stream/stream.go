@@ -0,0 +1,131 @@
+// Package stream implements lazily evaluated streams whose terminal
+// operations collect directly into the containers of this module.
+//
+// A Stream is an iter.Seq with methods. Intermediate operations such as
+// Filter and Map only wrap the stream; nothing runs until a terminal
+// operation such as ToList or ToHeap ranges over it, so pipelines allocate
+// no intermediate slices:
+//
+//	byHost := make(map[string]*list.List[Request])
+//	stream.GroupByTo(stream.Of(requests).Filter(failed), Request.Host, byHost)
+package stream
+
+import (
+	"iter"
+
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/list"
+	"github.com/weiwenchen2022/container/xiter"
+)
+
+// A Stream is a lazily evaluated sequence of values.
+// A Stream can be ranged over like the iter.Seq it wraps; whether it can
+// be consumed more than once depends on that sequence.
+type Stream[E any] iter.Seq[E]
+
+// Of returns a stream over the values of seq.
+func Of[E any](seq iter.Seq[E]) Stream[E] { return Stream[E](seq) }
+
+// Seq returns the values of stream s as an iter.Seq.
+func (s Stream[E]) Seq() iter.Seq[E] { return iter.Seq[E](s) }
+
+// Filter returns a stream over the values of s for which keep returns true.
+func (s Stream[E]) Filter(keep func(E) bool) Stream[E] {
+	return Stream[E](xiter.Filter(s.Seq(), keep))
+}
+
+// Take returns a stream over the first n values of s.
+func (s Stream[E]) Take(n int) Stream[E] { return Stream[E](xiter.Take(s.Seq(), n)) }
+
+// Drop returns a stream over the values of s after the first n.
+func (s Stream[E]) Drop(n int) Stream[E] { return Stream[E](xiter.Drop(s.Seq(), n)) }
+
+// Map returns a stream over f applied to the values of s.
+func Map[In, Out any](s Stream[In], f func(In) Out) Stream[Out] {
+	return Stream[Out](xiter.Map(s.Seq(), f))
+}
+
+// ForEach calls f for each value of stream s.
+func (s Stream[E]) ForEach(f func(E)) {
+	for x := range s {
+		f(x)
+	}
+}
+
+// Count returns the number of values of stream s.
+func (s Stream[E]) Count() int {
+	n := 0
+	for range s {
+		n++
+	}
+
+	return n
+}
+
+// Reduce returns the result of combining the values of stream s in order
+// with f, starting from init.
+func Reduce[E, R any](s Stream[E], init R, f func(R, E) R) R {
+	acc := init
+	for x := range s {
+		acc = f(acc, x)
+	}
+
+	return acc
+}
+
+// ToSlice returns the values of stream s appended to dst.
+func (s Stream[E]) ToSlice(dst []E) []E {
+	for x := range s {
+		dst = append(dst, x)
+	}
+
+	return dst
+}
+
+// ToList returns a list of the values of stream s in order.
+func (s Stream[E]) ToList() *list.List[E] {
+	l := list.New[E]()
+	for x := range s {
+		l.PushBack(x)
+	}
+
+	return l
+}
+
+// ToHeap returns a heap of the values of stream s ordered by less.
+func (s Stream[E]) ToHeap(less func(a, b E) bool) *heap.Heap[E] {
+	h := heap.New(less, heap.WithData(s.ToSlice(nil)))
+	h.Init()
+	return h
+}
+
+// ToSet returns the set of distinct values of stream s.
+func ToSet[E comparable](s Stream[E]) map[E]struct{} {
+	set := make(map[E]struct{})
+	for x := range s {
+		set[x] = struct{}{}
+	}
+
+	return set
+}
+
+// GroupByTo appends each value x of stream s to the list m[key(x)],
+// creating lists as needed, and returns m. If m is nil a new map is
+// allocated. Values keep their stream order within each group.
+func GroupByTo[E any, K comparable](s Stream[E], key func(E) K, m map[K]*list.List[E]) map[K]*list.List[E] {
+	if m == nil {
+		m = make(map[K]*list.List[E])
+	}
+
+	for x := range s {
+		k := key(x)
+		l := m[k]
+		if l == nil {
+			l = list.New[E]()
+			m[k] = l
+		}
+		l.PushBack(x)
+	}
+
+	return m
+}
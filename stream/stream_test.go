@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/weiwenchen2022/container/list"
+)
+
+func values[E any](l *list.List[E]) []E {
+	var s []E
+	for e := l.Front(); e != nil; e = e.Next() {
+		s = append(s, e.Value)
+	}
+
+	return s
+}
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	s := Map(Of(slices.Values([]int{1, 2, 3, 4, 5, 6, 7, 8})).Filter(func(x int) bool {
+		calls++
+		return x%2 == 0
+	}), func(x int) string { return strconv.Itoa(x * x) })
+	if calls != 0 {
+		t.Errorf("stream evaluated before a terminal operation")
+	}
+
+	if got, want := values(s.Take(3).ToList()), []string{"4", "16", "36"}; !slices.Equal(got, want) {
+		t.Errorf("ToList() = %v, want %v", got, want)
+	}
+	if calls != 6 {
+		t.Errorf("Take(3) consumed %d source values, want 6", calls)
+	}
+
+	if n := s.Drop(1).Count(); n != 3 {
+		t.Errorf("Drop(1).Count() = %d, want 3", n)
+	}
+
+	sum := Reduce(Of(slices.Values([]int{1, 2, 3})), 0, func(acc, x int) int { return acc + x })
+	if sum != 6 {
+		t.Errorf("Reduce(sum) = %d, want 6", sum)
+	}
+
+	var got []int
+	Of(slices.Values([]int{3, 1})).ForEach(func(x int) { got = append(got, x) })
+	if !slices.Equal(got, []int{3, 1}) {
+		t.Errorf("ForEach visited %v", got)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	t.Parallel()
+
+	words := Of(slices.Values([]string{"pear", "apple", "plum", "avocado", "pear"}))
+
+	h := words.ToHeap(func(a, b string) bool { return a < b })
+	var sorted []string
+	for h.Len() > 0 {
+		sorted = append(sorted, h.Pop())
+	}
+	if want := []string{"apple", "avocado", "pear", "pear", "plum"}; !slices.Equal(sorted, want) {
+		t.Errorf("ToHeap popped %v, want %v", sorted, want)
+	}
+
+	if set := ToSet(words); len(set) != 4 {
+		t.Errorf("ToSet() has %d values, want 4", len(set))
+	}
+
+	groups := GroupByTo(words, func(w string) byte { return w[0] }, nil)
+	if got := values(groups['p']); !slices.Equal(got, []string{"pear", "plum", "pear"}) {
+		t.Errorf("group p = %v", got)
+	}
+	GroupByTo(Of(slices.Values([]string{"apricot"})), func(w string) byte { return w[0] }, groups)
+	if got := values(groups['a']); !slices.Equal(got, []string{"apple", "avocado", "apricot"}) {
+		t.Errorf("group a = %v", got)
+	}
+
+	if got := words.Take(2).ToSlice([]string{"x"}); !slices.Equal(got, []string{"x", "pear", "apple"}) {
+		t.Errorf("ToSlice = %v", got)
+	}
+}
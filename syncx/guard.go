@@ -0,0 +1,49 @@
+// Package syncx provides helpers for sharing the containers of this module
+// between goroutines.
+//
+// The containers themselves are not safe for concurrent use. A Guard wraps
+// one with a sync.RWMutex and only gives access to it inside a function
+// holding the lock, so a sequence of operations runs as a unit:
+//
+//	g := syncx.New(lru.New[string, int](100))
+//	g.Do(func(c **lru.Cache[string, int]) {
+//		if _, ok := (*c).Get(k); !ok {
+//			(*c).Put(k, v)
+//		}
+//	})
+package syncx
+
+import "sync"
+
+// A Guard protects a value of type T with a read-write mutex.
+// The zero value guards the zero value of T.
+// A Guard must not be copied after first use.
+type Guard[T any] struct {
+	mu sync.RWMutex
+	v  T
+}
+
+// New returns a Guard protecting v.
+func New[T any](v T) *Guard[T] {
+	return &Guard[T]{v: v}
+}
+
+// Do calls f with a pointer to the guarded value while holding the write
+// lock. The pointer must not be retained after f returns.
+func (g *Guard[T]) Do(f func(v *T)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	f(&g.v)
+}
+
+// RDo calls f with a pointer to the guarded value while holding the read
+// lock, so calls may run concurrently with each other. f must not modify
+// the value, including through methods that update internal state, such as
+// the Get method of a cache that records recency.
+func (g *Guard[T]) RDo(f func(v *T)) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	f(&g.v)
+}
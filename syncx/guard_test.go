@@ -0,0 +1,62 @@
+package syncx
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/weiwenchen2022/container/list"
+)
+
+func TestGuard(t *testing.T) {
+	t.Parallel()
+
+	g := New(list.New[int]())
+
+	const n = 100
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			g.Do(func(l **list.List[int]) { (*l).PushBack(i) })
+		}()
+		go func() {
+			defer wg.Done()
+			g.RDo(func(l **list.List[int]) {
+				sum := 0
+				for e := (*l).Front(); e != nil; e = e.Next() {
+					sum += e.Value
+				}
+				_ = sum
+			})
+		}()
+	}
+	wg.Wait()
+
+	g.RDo(func(l **list.List[int]) {
+		if got := (*l).Len(); got != n {
+			t.Errorf("l.Len() = %d, want %d", got, n)
+		}
+	})
+
+	var z Guard[int]
+	z.Do(func(v *int) { *v++ })
+	z.RDo(func(v *int) {
+		if *v != 1 {
+			t.Errorf("zero Guard value = %d, want 1", *v)
+		}
+	})
+}
+
+func TestGuardPanic(t *testing.T) {
+	t.Parallel()
+
+	g := New(0)
+	func() {
+		defer func() { recover() }()
+		g.Do(func(v *int) { panic("boom") })
+	}()
+
+	// the lock must have been released
+	g.Do(func(v *int) { *v = 1 })
+}
@@ -0,0 +1,170 @@
+// Package taskgraph runs a directed acyclic graph of tasks with bounded
+// parallelism, starting each task once all of its dependencies have
+// completed successfully.
+//
+// A task that fails prevents its dependents from running; tasks that do
+// not depend on it keep running. Canceling the context passed to Run stops
+// new tasks from starting and is visible to the running ones.
+package taskgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/weiwenchen2022/container/graph"
+	"github.com/weiwenchen2022/container/list"
+)
+
+// ErrSkipped is reported for tasks that did not run because a task they
+// depend on, directly or indirectly, failed.
+var ErrSkipped = errors.New("taskgraph: dependency failed")
+
+// ErrCycle is returned by Run if the dependencies form a cycle.
+var ErrCycle = errors.New("taskgraph: dependency cycle")
+
+// A Task is a unit of work. It should return promptly once ctx is done.
+type Task func(ctx context.Context) error
+
+// Executor is a graph of tasks identified by keys of type K.
+// To create an executor use taskgraph.New.
+//
+// An Executor must not be modified while Run is in progress.
+type Executor[K comparable] struct {
+	parallelism int
+
+	// an edge u -> v means v depends on u
+	g *graph.Graph[K, Task, struct{}]
+}
+
+// New returns an empty executor running at most parallelism tasks at a time.
+// It panics if parallelism is not positive.
+func New[K comparable](parallelism int) *Executor[K] {
+	if parallelism <= 0 {
+		panic("taskgraph: non-positive parallelism")
+	}
+
+	return &Executor[K]{
+		parallelism: parallelism,
+		g:           graph.NewDirected[K, Task, struct{}](),
+	}
+}
+
+// Len returns the number of tasks in executor e.
+func (e *Executor[K]) Len() int { return e.g.Order() }
+
+// Clone returns a copy of executor e with the same tasks and dependencies.
+func (e *Executor[K]) Clone() *Executor[K] {
+	return &Executor[K]{parallelism: e.parallelism, g: e.g.Clone()}
+}
+
+// Add adds task t with key id, which runs after the tasks with keys deps.
+// The dependencies may be added before or after t. Adding a task with an
+// existing key replaces it and adds to its dependencies.
+func (e *Executor[K]) Add(id K, t Task, deps ...K) {
+	e.g.SetNode(id, t)
+	for _, d := range deps {
+		e.g.AddEdge(d, id, struct{}{})
+	}
+}
+
+// validate checks that every dependency names a task and that there are
+// no cycles.
+func (e *Executor[K]) validate() error {
+	for id := range e.g.Nodes() {
+		if t, _ := e.g.Node(id); t == nil {
+			return fmt.Errorf("taskgraph: unknown task %v", id)
+		}
+
+		if e.g.HasEdge(id, id) {
+			return ErrCycle
+		}
+	}
+
+	for _, comp := range graph.StronglyConnectedComponents(e.g) {
+		if len(comp) > 1 {
+			return ErrCycle
+		}
+	}
+
+	return nil
+}
+
+// Run runs the tasks of executor e, each at most once, and waits for the
+// started ones to return.
+// It returns the errors of the tasks that did not complete successfully:
+// the error a task returned, ErrSkipped if a dependency failed, or the
+// context's error for tasks not started because ctx was done.
+// If a dependency names no task or the dependencies form a cycle, Run
+// returns an error without running any task.
+func (e *Executor[K]) Run(ctx context.Context) (errs map[K]error, err error) {
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		id  K
+		err error
+	}
+
+	pending := make(map[K]int, e.g.Order()) // unfinished dependencies
+	ready := list.New[K]()
+	for id := range e.g.Nodes() {
+		if pending[id] = e.g.InDegree(id); pending[id] == 0 {
+			ready.PushBack(id)
+		}
+	}
+
+	errs = make(map[K]error)
+	finished := make(map[K]bool, e.g.Order())
+	done := make(chan result)
+	running := 0
+	for {
+		for running < e.parallelism && ready.Len() > 0 && ctx.Err() == nil {
+			id := ready.Remove(ready.Front())
+			t, _ := e.g.Node(id)
+			running++
+			go func() { done <- result{id, t(ctx)} }()
+		}
+
+		if running == 0 {
+			break
+		}
+
+		r := <-done
+		running--
+		finished[r.id] = true
+		if r.err != nil {
+			errs[r.id] = r.err
+			continue
+		}
+
+		for v := range e.g.Neighbors(r.id) {
+			if pending[v]--; pending[v] == 0 {
+				ready.PushBack(v)
+			}
+		}
+	}
+
+	for id, err := range errs {
+		if err == ErrSkipped {
+			continue
+		}
+
+		for v := range e.g.BFS(id) {
+			if !finished[v] {
+				errs[v] = ErrSkipped
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		for id := range e.g.Nodes() {
+			if !finished[id] && errs[id] == nil {
+				errs[id] = err
+			}
+		}
+	}
+
+	return errs, nil
+}
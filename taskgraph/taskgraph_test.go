@@ -0,0 +1,178 @@
+package taskgraph
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var order []string
+	task := func(id string) Task {
+		return func(context.Context) error {
+			mu.Lock()
+			order = append(order, id)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	e := New[string](2)
+	e.Add("link", task("link"), "compile-a", "compile-b")
+	e.Add("compile-a", task("compile-a"), "generate")
+	e.Add("compile-b", task("compile-b"))
+	e.Add("generate", task("generate"))
+	e.Add("package", task("package"), "link")
+
+	errs, err := e.Run(context.Background())
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("e.Run() = %v, %v", errs, err)
+	}
+
+	if len(order) != e.Len() {
+		t.Fatalf("ran %v, want %d tasks", order, e.Len())
+	}
+
+	pos := func(id string) int { return slices.Index(order, id) }
+	for _, dep := range [][2]string{{"generate", "compile-a"}, {"compile-a", "link"}, {"compile-b", "link"}, {"link", "package"}} {
+		if pos(dep[0]) > pos(dep[1]) {
+			t.Errorf("%s ran before %s: %v", dep[1], dep[0], order)
+		}
+	}
+}
+
+func TestParallelism(t *testing.T) {
+	t.Parallel()
+
+	const n, limit = 20, 3
+	var running, peak atomic.Int32
+	e := New[int](limit)
+	for i := range n {
+		e.Add(i, func(context.Context) error {
+			r := running.Add(1)
+			for {
+				p := peak.Load()
+				if r <= p || peak.CompareAndSwap(p, r) {
+					break
+				}
+			}
+			running.Add(-1)
+			return nil
+		})
+	}
+
+	if errs, err := e.Run(context.Background()); err != nil || len(errs) != 0 {
+		t.Fatalf("e.Run() = %v, %v", errs, err)
+	}
+	if peak.Load() > limit {
+		t.Errorf("peak parallelism = %d, want at most %d", peak.Load(), limit)
+	}
+}
+
+func TestFailure(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	ok := func(context.Context) error { return nil }
+	e := New[string](4)
+	e.Add("a", func(context.Context) error { return errBoom })
+	e.Add("b", ok, "a")
+	e.Add("c", ok, "b")
+	e.Add("d", ok)
+
+	errs, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]error{"a": errBoom, "b": ErrSkipped, "c": ErrSkipped}
+	if len(errs) != len(want) {
+		t.Errorf("errs = %v, want %v", errs, want)
+	}
+	for id, w := range want {
+		if !errors.Is(errs[id], w) {
+			t.Errorf("errs[%s] = %v, want %v", id, errs[id], w)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := New[int](1)
+	e.Add(0, func(ctx context.Context) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	e.Add(1, func(context.Context) error { return nil })
+	e.Add(2, func(context.Context) error { return nil }, 1)
+
+	errs, err := e.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id := range 3 {
+		if !errors.Is(errs[id], context.Canceled) {
+			t.Errorf("errs[%d] = %v, want %v", id, errs[id], context.Canceled)
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	t.Parallel()
+
+	ok := func(context.Context) error { return nil }
+
+	e := New[int](1)
+	e.Add(0, ok, 2)
+	e.Add(1, ok, 0)
+	e.Add(2, ok, 1)
+	if _, err := e.Run(context.Background()); err != ErrCycle {
+		t.Errorf("e.Run() with a cycle = %v, want %v", err, ErrCycle)
+	}
+
+	e = New[int](1)
+	e.Add(0, ok, 0)
+	if _, err := e.Run(context.Background()); err != ErrCycle {
+		t.Errorf("e.Run() with a self-dependency = %v, want %v", err, ErrCycle)
+	}
+
+	e = New[int](1)
+	e.Add(0, ok, 7)
+	if _, err := e.Run(context.Background()); err == nil {
+		t.Errorf("e.Run() with an unknown dependency succeeded")
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	var runs atomic.Int32
+	task := func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}
+
+	e := New[int](2)
+	e.Add(1, task)
+	e.Add(2, task, 1)
+
+	c := e.Clone()
+	c.Add(3, task, 2)
+	if e.Len() != 2 || c.Len() != 3 {
+		t.Errorf("e.Len(), c.Len() = %d, %d, want 2, 3", e.Len(), c.Len())
+	}
+
+	if errs, err := c.Run(context.Background()); err != nil || len(errs) != 0 || runs.Load() != 3 {
+		t.Errorf("c.Run() = %v, %v after %d runs", errs, err, runs.Load())
+	}
+}
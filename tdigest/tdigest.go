@@ -0,0 +1,240 @@
+// Package tdigest implements the t-digest, a sketch for estimating
+// quantiles of a stream of values.
+//
+// A t-digest (Dunning and Ertl, "Computing Extremely Accurate Quantiles
+// Using t-Digests") summarizes a distribution as a sorted list of weighted
+// centroids. Centroids near the tails are kept small, so estimates of
+// extreme quantiles such as the 99.9th percentile are much more accurate
+// than those of the median, which is what latency tracking needs. The size
+// of a digest is bounded by its compression parameter regardless of the
+// number of values added, and digests can be merged.
+package tdigest
+
+import (
+	"math"
+	"slices"
+)
+
+type centroid struct {
+	mean, weight float64
+}
+
+// TDigest is a t-digest.
+// To create a digest use tdigest.New.
+type TDigest struct {
+	compression float64
+
+	centroids []centroid // merged, sorted by mean
+	buffer    []centroid // unmerged
+	total     float64    // weight of centroids and buffer
+
+	min, max float64
+}
+
+type option func(*TDigest)
+
+// WithCompression sets the compression parameter, which bounds the number
+// of centroids to about 2×compression. Larger values give more accurate
+// estimates and use more memory. The default is 100.
+func WithCompression(compression float64) option {
+	return func(t *TDigest) {
+		t.compression = compression
+	}
+}
+
+// New returns an empty digest.
+func New(opts ...option) *TDigest {
+	t := &TDigest{compression: 100, min: math.Inf(1), max: math.Inf(-1)}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Count returns the total weight of the values added to digest t.
+func (t *TDigest) Count() float64 { return t.total }
+
+// Min returns the smallest value added to digest t, or +Inf if it is empty.
+func (t *TDigest) Min() float64 { return t.min }
+
+// Max returns the largest value added to digest t, or -Inf if it is empty.
+func (t *TDigest) Max() float64 { return t.max }
+
+// Reset removes all values from digest t.
+func (t *TDigest) Reset() {
+	t.centroids, t.buffer = t.centroids[:0], t.buffer[:0]
+	t.total = 0
+	t.min, t.max = math.Inf(1), math.Inf(-1)
+}
+
+// Clone returns a copy of digest t.
+func (t *TDigest) Clone() *TDigest {
+	c := *t
+	c.centroids = slices.Clone(t.centroids)
+	c.buffer = slices.Clone(t.buffer)
+	return &c
+}
+
+// Add adds value x to digest t.
+func (t *TDigest) Add(x float64) { t.AddWeighted(x, 1) }
+
+// AddWeighted adds value x with weight w to digest t.
+// NaN values and non-positive weights are ignored.
+func (t *TDigest) AddWeighted(x, w float64) {
+	if math.IsNaN(x) || !(w > 0) {
+		return
+	}
+
+	t.buffer = append(t.buffer, centroid{x, w})
+	t.total += w
+	t.min, t.max = min(t.min, x), max(t.max, x)
+
+	if len(t.buffer) >= int(8*t.compression) {
+		t.process()
+	}
+}
+
+// Merge adds all values summarized by digest other to digest t.
+func (t *TDigest) Merge(other *TDigest) {
+	if other.total == 0 {
+		return
+	}
+
+	t.buffer = append(append(t.buffer, other.centroids...), other.buffer...)
+	t.total += other.total
+	t.min, t.max = min(t.min, other.min), max(t.max, other.max)
+	t.process()
+}
+
+// k is the scale function k1, mapping quantiles to centroid indexes.
+func (t *TDigest) k(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// kInv is the inverse of k.
+func (t *TDigest) kInv(k float64) float64 {
+	return (math.Sin(k*2*math.Pi/t.compression) + 1) / 2
+}
+
+// process merges the buffered values into the centroids.
+func (t *TDigest) process() {
+	if len(t.buffer) == 0 {
+		return
+	}
+
+	all := append(t.buffer, t.centroids...)
+	slices.SortFunc(all, func(a, b centroid) int {
+		switch {
+		case a.mean < b.mean:
+			return -1
+		case a.mean > b.mean:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	merged := t.centroids[:0]
+	if cap(merged) < len(all) {
+		merged = make([]centroid, 0, len(all))
+	}
+
+	cur := all[0]
+	soFar := 0.0
+	limit := t.total * t.kInv(t.k(0)+1)
+	for _, c := range all[1:] {
+		if soFar+cur.weight+c.weight <= limit {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+
+		soFar += cur.weight
+		merged = append(merged, cur)
+		limit = t.total * t.kInv(t.k(soFar/t.total)+1)
+		cur = c
+	}
+
+	t.centroids = append(merged, cur)
+	t.buffer = all[:0]
+}
+
+// Quantile returns an estimate of the value at quantile q, in [0, 1].
+// It returns NaN if the digest is empty.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.process()
+
+	cs := t.centroids
+	switch {
+	case len(cs) == 0 || q < 0 || q > 1:
+		return math.NaN()
+	case len(cs) == 1:
+		return cs[0].mean
+	}
+
+	index := q * t.total
+
+	// between the minimum and the first centroid
+	if first := cs[0]; index < first.weight/2 {
+		return t.min + index/(first.weight/2)*(first.mean-t.min)
+	}
+
+	soFar := cs[0].weight / 2
+	for i := 0; i < len(cs)-1; i++ {
+		dw := (cs[i].weight + cs[i+1].weight) / 2
+		if soFar+dw > index {
+			frac := (index - soFar) / dw
+			return cs[i].mean + frac*(cs[i+1].mean-cs[i].mean)
+		}
+
+		soFar += dw
+	}
+
+	// between the last centroid and the maximum
+	last := cs[len(cs)-1]
+	frac := min(1, (index-soFar)/(last.weight/2))
+	return last.mean + frac*(t.max-last.mean)
+}
+
+// CDF returns an estimate of the fraction of values less than or equal to x.
+// It returns NaN if the digest is empty.
+func (t *TDigest) CDF(x float64) float64 {
+	t.process()
+
+	cs := t.centroids
+	switch {
+	case len(cs) == 0:
+		return math.NaN()
+	case x < t.min:
+		return 0
+	case x >= t.max:
+		return 1
+	}
+
+	if first := cs[0]; x < first.mean {
+		return (x - t.min) / (first.mean - t.min) * first.weight / 2 / t.total
+	}
+
+	soFar := cs[0].weight / 2
+	for i := 0; i < len(cs)-1; i++ {
+		dw := (cs[i].weight + cs[i+1].weight) / 2
+		if x < cs[i+1].mean {
+			frac := (x - cs[i].mean) / (cs[i+1].mean - cs[i].mean)
+			return (soFar + frac*dw) / t.total
+		}
+
+		soFar += dw
+	}
+
+	last := cs[len(cs)-1]
+	frac := (x - last.mean) / (t.max - last.mean)
+	return (soFar + frac*last.weight/2) / t.total
+}
+
+// Centroids returns the number of centroids summarizing digest t.
+func (t *TDigest) Centroids() int {
+	t.process()
+	return len(t.centroids)
+}
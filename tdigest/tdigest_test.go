@@ -0,0 +1,126 @@
+package tdigest
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func exact(sorted []float64, q float64) float64 {
+	return sorted[int(q*float64(len(sorted)-1))]
+}
+
+func TestQuantile(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	td := New()
+	var xs []float64
+	for i := 0; i < 100000; i++ {
+		x := r.ExpFloat64()
+		td.Add(x)
+		xs = append(xs, x)
+	}
+	slices.Sort(xs)
+
+	if n := td.Count(); n != 100000 {
+		t.Errorf("td.Count() = %v, want 100000", n)
+	}
+	if td.Min() != xs[0] || td.Max() != xs[len(xs)-1] {
+		t.Errorf("td.Min(), td.Max() = %v, %v, want %v, %v", td.Min(), td.Max(), xs[0], xs[len(xs)-1])
+	}
+	if n := td.Centroids(); n > 300 {
+		t.Errorf("td.Centroids() = %d, want <= 300", n)
+	}
+
+	for _, q := range []float64{0.001, 0.01, 0.1, 0.5, 0.9, 0.99, 0.999} {
+		// accuracy is relative to the distance from the nearer tail
+		tol := 0.001 + 0.01*min(q, 1-q)
+
+		got, want := td.Quantile(q), exact(xs, q)
+		if rank := float64(sort.SearchFloat64s(xs, got)) / float64(len(xs)); math.Abs(rank-q) > tol {
+			t.Errorf("td.Quantile(%v) = %v at rank %v, want %v", q, got, rank, want)
+		}
+
+		if c := td.CDF(want); math.Abs(c-q) > tol {
+			t.Errorf("td.CDF(%v) = %v, want %v", want, c, q)
+		}
+	}
+
+	if q := td.Quantile(0); q != xs[0] {
+		t.Errorf("td.Quantile(0) = %v, want %v", q, xs[0])
+	}
+	if q := td.Quantile(1); q != xs[len(xs)-1] {
+		t.Errorf("td.Quantile(1) = %v, want %v", q, xs[len(xs)-1])
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	a, b := New(), New()
+	for i := 0; i < 5000; i++ {
+		a.Add(float64(i))
+		b.Add(float64(i + 5000))
+	}
+
+	a.Merge(b)
+	if n := a.Count(); n != 10000 {
+		t.Errorf("a.Count() = %v, want 10000", n)
+	}
+	if m := a.Quantile(0.5); math.Abs(m-5000) > 50 {
+		t.Errorf("a.Quantile(0.5) = %v, want ~5000", m)
+	}
+	if c := a.CDF(2500); math.Abs(c-0.25) > 0.01 {
+		t.Errorf("a.CDF(2500) = %v, want ~0.25", c)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	t.Parallel()
+
+	td := New(WithCompression(50))
+	if q := td.Quantile(0.5); !math.IsNaN(q) {
+		t.Errorf("empty td.Quantile(0.5) = %v, want NaN", q)
+	}
+	if c := td.CDF(0); !math.IsNaN(c) {
+		t.Errorf("empty td.CDF(0) = %v, want NaN", c)
+	}
+
+	td.Add(3)
+	if q := td.Quantile(0.9); q != 3 {
+		t.Errorf("td.Quantile(0.9) = %v, want 3", q)
+	}
+
+	td.Reset()
+	if n := td.Count(); n != 0 {
+		t.Errorf("td.Count() after Reset = %v", n)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	a := New()
+	for i := 0; i < 1000; i++ {
+		a.Add(float64(i))
+	}
+
+	b := a.Clone()
+	for i := 0; i < 1000; i++ {
+		b.Add(1e6)
+	}
+
+	if n := a.Count(); n != 1000 {
+		t.Errorf("a.Count() = %v, want 1000", n)
+	}
+	if m := a.Max(); m != 999 {
+		t.Errorf("a.Max() = %v, want 999", m)
+	}
+	if m := b.Quantile(0.9); m != 1e6 {
+		t.Errorf("b.Quantile(0.9) = %v, want 1e6", m)
+	}
+}
@@ -0,0 +1,100 @@
+package tinylfu
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	sketchDepth = 4
+	maxCount    = 15
+)
+
+// sketch is a count-min sketch of small saturating counters that
+// periodically halves all counters, so that frequencies reflect recent
+// history rather than all time.
+type sketch[K comparable] struct {
+	seed maphash.Seed
+
+	rows [sketchDepth][]uint8
+	mask uint64
+
+	samples, resetAt int
+}
+
+func newSketch[K comparable](capacity int) *sketch[K] {
+	width := 1 << bits.Len(uint(max(capacity, 16)-1))
+
+	s := &sketch[K]{
+		seed:    maphash.MakeSeed(),
+		mask:    uint64(width - 1),
+		resetAt: 10 * max(capacity, 16),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+
+	return s
+}
+
+// indexes returns the counter index in each row for key k.
+func (s *sketch[K]) indexes(k K) (idx [sketchDepth]uint64) {
+	h := maphash.Comparable(s.seed, k)
+	h1, h2 := h, h>>32|1
+	for i := range idx {
+		idx[i] = (h1 + uint64(i)*h2) & s.mask
+	}
+
+	return idx
+}
+
+// increment records an occurrence of key k.
+func (s *sketch[K]) increment(k K) {
+	for i, j := range s.indexes(k) {
+		if s.rows[i][j] < maxCount {
+			s.rows[i][j]++
+		}
+	}
+
+	if s.samples++; s.samples >= s.resetAt {
+		s.reset()
+	}
+}
+
+// estimate returns the estimated frequency of key k.
+func (s *sketch[K]) estimate(k K) uint8 {
+	n := uint8(maxCount)
+	for i, j := range s.indexes(k) {
+		n = min(n, s.rows[i][j])
+	}
+
+	return n
+}
+
+// reset halves all counters.
+func (s *sketch[K]) reset() {
+	for _, row := range s.rows {
+		for j := range row {
+			row[j] >>= 1
+		}
+	}
+
+	s.samples /= 2
+}
+
+func (s *sketch[K]) clear() {
+	for _, row := range s.rows {
+		clear(row)
+	}
+
+	s.samples = 0
+}
+
+func (s *sketch[K]) clone() *sketch[K] {
+	c := *s
+	for i, row := range s.rows {
+		c.rows[i] = append([]uint8(nil), row...)
+	}
+
+	return &c
+}
@@ -0,0 +1,300 @@
+// Package tinylfu implements a W-TinyLFU cache.
+//
+// W-TinyLFU (Einziger, Friedman and Manes, "TinyLFU: A Highly Efficient
+// Cache Admission Policy") places new entries in a small LRU admission
+// window. Entries leaving the window compete for a place in the main
+// cache, a segmented LRU, against the main cache's eviction victim; a
+// count-min sketch of recent access frequencies decides which of the two
+// is kept. The window absorbs bursts of new keys while the frequency filter
+// protects popular entries, giving high hit ratios on skewed workloads.
+//
+// A Cache is not safe for concurrent use by multiple goroutines.
+package tinylfu
+
+import (
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/cachestats"
+	"github.com/weiwenchen2022/container/internal/memsize"
+	"github.com/weiwenchen2022/container/list"
+)
+
+const (
+	window = iota
+	probation
+	protected
+)
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	where int // which segment holds the entry
+}
+
+// Cache is a W-TinyLFU cache holding at most a fixed number of entries.
+// To create a cache use tinylfu.New.
+type Cache[K comparable, V any] struct {
+	capacity int
+
+	windowFraction float64
+	caps           [3]int // maximum length of each segment
+
+	segments [3]*list.List[entry[K, V]] // front is most recently used
+	items    map[K]*list.Element[entry[K, V]]
+
+	sketch *sketch[K]
+
+	onEvict func(K, V)
+	onHit   func(K, V)
+
+	stats cachestats.Recorder
+}
+
+type option[K comparable, V any] func(*Cache[K, V])
+
+// WithOnEvict sets a function called with the key and value of each entry
+// evicted to make room for another.
+// It is not called for entries removed with Remove or Clear.
+func WithOnEvict[K comparable, V any](f func(K, V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onEvict = f
+	}
+}
+
+// WithWindowFraction sets the fraction of the capacity used by the
+// admission window. The default is 0.01; larger windows favor
+// recency-biased workloads.
+// It panics if f is not in the range (0, 1).
+func WithWindowFraction[K comparable, V any](f float64) option[K, V] {
+	if f <= 0 || f >= 1 {
+		panic("tinylfu: window fraction out of range")
+	}
+
+	return func(c *Cache[K, V]) {
+		c.windowFraction = f
+	}
+}
+
+// WithOnHit sets a function called with the key and value of each entry
+// found by Get.
+func WithOnHit[K comparable, V any](f func(K, V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onHit = f
+	}
+}
+
+// New returns an empty cache holding at most capacity entries.
+// It panics if capacity is less than 2.
+func New[K comparable, V any](capacity int, opts ...option[K, V]) *Cache[K, V] {
+	if capacity < 2 {
+		panic("tinylfu: capacity less than 2")
+	}
+
+	c := &Cache[K, V]{
+		capacity:       capacity,
+		windowFraction: 0.01,
+		items:          make(map[K]*list.Element[entry[K, V]]),
+		sketch:         newSketch[K](capacity),
+	}
+	for i := range c.segments {
+		c.segments[i] = list.New[entry[K, V]]()
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.caps[window] = min(max(1, int(float64(capacity)*c.windowFraction)), capacity-1)
+	main := capacity - c.caps[window]
+	c.caps[protected] = main * 4 / 5
+	c.caps[probation] = main - c.caps[protected]
+
+	return c
+}
+
+// Len returns the number of entries in cache c.
+func (c *Cache[K, V]) Len() int { return len(c.items) }
+
+// Cap returns the maximum number of entries cache c holds.
+func (c *Cache[K, V]) Cap() int { return c.capacity }
+
+// Hits returns the number of calls to Get that found their key.
+func (c *Cache[K, V]) Hits() uint64 { return c.stats.Hits() }
+
+// Misses returns the number of calls to Get that did not find their key.
+func (c *Cache[K, V]) Misses() uint64 { return c.stats.Misses() }
+
+// Stats returns a snapshot of the statistics of cache c.
+func (c *Cache[K, V]) Stats() cachestats.Stats { return c.stats.Stats() }
+
+// move moves e to the front of segment where, returning the new element.
+func (c *Cache[K, V]) move(e *list.Element[entry[K, V]], where int) *list.Element[entry[K, V]] {
+	if e.Value.where == where {
+		c.segments[where].MoveToFront(e)
+		return e
+	}
+
+	x := c.segments[e.Value.where].Remove(e)
+	x.where = where
+	e = c.segments[where].PushFront(x)
+	c.items[x.key] = e
+	return e
+}
+
+// touch records an access to the resident entry e.
+func (c *Cache[K, V]) touch(e *list.Element[entry[K, V]]) *list.Element[entry[K, V]] {
+	c.sketch.increment(e.Value.key)
+
+	switch e.Value.where {
+	case window, protected:
+		return c.move(e, e.Value.where)
+	}
+
+	// promote from probation, demoting the protected LRU if needed
+	e = c.move(e, protected)
+	if c.segments[protected].Len() > c.caps[protected] {
+		c.move(c.segments[protected].Back(), probation)
+	}
+
+	return e
+}
+
+// Get returns the value stored for key k and records the access.
+// The ok result reports whether the key was present.
+func (c *Cache[K, V]) Get(k K) (v V, ok bool) {
+	e, ok := c.items[k]
+	if !ok {
+		c.sketch.increment(k)
+		c.stats.Miss()
+		return v, false
+	}
+
+	c.stats.Hit()
+	v = c.touch(e).Value.value
+	if c.onHit != nil {
+		c.onHit(k, v)
+	}
+
+	return v, true
+}
+
+// Peek returns the value stored for key k without recording an access.
+// The ok result reports whether the key was present.
+func (c *Cache[K, V]) Peek(k K) (v V, ok bool) {
+	if e, ok := c.items[k]; ok {
+		return e.Value.value, true
+	}
+
+	return v, false
+}
+
+// Contains reports whether key k is in cache c, without recording an access.
+func (c *Cache[K, V]) Contains(k K) bool {
+	_, ok := c.items[k]
+	return ok
+}
+
+// Put stores value v for key k and records the access. A new entry enters
+// the admission window, possibly causing the eviction of another entry.
+func (c *Cache[K, V]) Put(k K, v V) {
+	if e, ok := c.items[k]; ok {
+		c.touch(e).Value.value = v
+		return
+	}
+
+	c.sketch.increment(k)
+	c.items[k] = c.segments[window].PushFront(entry[K, V]{key: k, value: v, where: window})
+
+	if c.segments[window].Len() <= c.caps[window] {
+		return
+	}
+
+	// The window overflowed: its LRU entry is a candidate for the main cache.
+	candidate := c.move(c.segments[window].Back(), probation)
+	if c.segments[probation].Len()+c.segments[protected].Len() <= c.caps[probation]+c.caps[protected] {
+		return
+	}
+
+	victim := c.segments[probation].Back()
+	if victim == candidate {
+		victim = c.segments[protected].Back()
+	}
+
+	if c.sketch.estimate(candidate.Value.key) > c.sketch.estimate(victim.Value.key) {
+		c.evict(victim)
+	} else {
+		c.evict(candidate)
+	}
+}
+
+func (c *Cache[K, V]) evict(e *list.Element[entry[K, V]]) {
+	x := c.segments[e.Value.where].Remove(e)
+	delete(c.items, x.key)
+	c.stats.Evict()
+
+	if c.onEvict != nil {
+		c.onEvict(x.key, x.value)
+	}
+}
+
+// Remove removes the entry for key k and reports whether it was present.
+func (c *Cache[K, V]) Remove(k K) bool {
+	e, ok := c.items[k]
+	if ok {
+		delete(c.items, k)
+		c.segments[e.Value.where].Remove(e)
+	}
+
+	return ok
+}
+
+// Clear removes all entries from cache c and forgets recorded frequencies.
+// The statistics are not reset.
+func (c *Cache[K, V]) Clear() {
+	for _, l := range c.segments {
+		l.Init()
+	}
+	clear(c.items)
+	c.sketch.clear()
+}
+
+// Clone returns a copy of cache c with the same entries, segments,
+// frequency estimates, options and statistics. The values are copied by
+// assignment, so this is a shallow clone.
+func (c *Cache[K, V]) Clone() *Cache[K, V] {
+	return c.CloneFunc(func(v V) V { return v })
+}
+
+// CloneFunc is like Clone but copies each value with f, which allows a
+// deep clone.
+func (c *Cache[K, V]) CloneFunc(f func(V) V) *Cache[K, V] {
+	d := *c
+	d.items = make(map[K]*list.Element[entry[K, V]], len(c.items))
+	d.sketch = c.sketch.clone()
+	d.stats = c.stats.Clone()
+	for i, l := range c.segments {
+		d.segments[i] = list.New[entry[K, V]]()
+		for e := l.Front(); e != nil; e = e.Next() {
+			x := e.Value
+			x.value = f(x.value)
+			d.items[x.key] = d.segments[i].PushBack(x)
+		}
+	}
+
+	return &d
+}
+
+// MemStats returns statistics about the memory used by cache c, including
+// its frequency sketch.
+// Cap is the capacity of the cache.
+func (c *Cache[K, V]) MemStats() container.MemStats {
+	bytes := memsize.Of[Cache[K, V]]() + memsize.Map[K, *list.Element[entry[K, V]]](len(c.items)) +
+		memsize.Of[sketch[K]]()
+	for _, l := range c.segments {
+		bytes += l.MemStats().Bytes
+	}
+	for _, row := range c.sketch.rows {
+		bytes += memsize.Slice[uint8](cap(row))
+	}
+
+	return container.MemStats{Len: c.Len(), Cap: c.capacity, Bytes: bytes}
+}
@@ -0,0 +1,190 @@
+package tinylfu
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/lru"
+)
+
+var (
+	_ container.Container = (*Cache[int, int])(nil)
+	_ container.Sizer     = (*Cache[int, int])(nil)
+)
+
+func (c *Cache[K, V]) verify(t *testing.T) {
+	t.Helper()
+
+	if n := c.Len(); n > c.capacity {
+		t.Fatalf("c.Len() = %d > %d", n, c.capacity)
+	}
+
+	if n := c.segments[window].Len(); n > c.caps[window] {
+		t.Fatalf("window length %d > %d", n, c.caps[window])
+	}
+
+	if n := c.segments[protected].Len(); n > c.caps[protected] {
+		t.Fatalf("protected length %d > %d", n, c.caps[protected])
+	}
+
+	n := 0
+	for where, l := range c.segments {
+		for e := l.Front(); e != nil; e = e.Next() {
+			if e.Value.where != where || c.items[e.Value.key] != e {
+				t.Fatalf("entry %v misplaced", e.Value.key)
+			}
+			n++
+		}
+	}
+
+	if n != len(c.items) {
+		t.Fatalf("segments hold %d entries, items %d", n, len(c.items))
+	}
+}
+
+func TestSketch(t *testing.T) {
+	t.Parallel()
+
+	s := newSketch[int](100)
+	for i := 0; i < 10; i++ {
+		s.increment(1)
+	}
+	s.increment(2)
+
+	if n := s.estimate(1); n < 10 {
+		t.Errorf("s.estimate(1) = %d, want >= 10", n)
+	}
+	if n := s.estimate(2); n < 1 || n >= 10 {
+		t.Errorf("s.estimate(2) = %d, want in [1, 10)", n)
+	}
+
+	s.reset()
+	if n := s.estimate(1); n < 5 || n > 7 {
+		t.Errorf("s.estimate(1) after reset = %d, want ~5", n)
+	}
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	evictions := 0
+	c := New(100, WithWindowFraction[int, int](0.1), WithOnEvict(func(k, v int) {
+		if k != v {
+			t.Fatalf("evicted %d with value %d", k, v)
+		}
+		evictions++
+	}))
+
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(400)
+		if v, ok := c.Get(k); ok {
+			if v != k {
+				t.Fatalf("c.Get(%d) = %d", k, v)
+			}
+		} else {
+			c.Put(k, k)
+		}
+
+		if r.Intn(100) == 0 {
+			c.Remove(r.Intn(400))
+		}
+
+		c.verify(t)
+	}
+
+	if evictions == 0 {
+		t.Errorf("no evictions")
+	}
+
+	c.Clear()
+	if n := c.Len(); n != 0 {
+		t.Errorf("c.Len() after Clear = %d", n)
+	}
+	c.verify(t)
+}
+
+func TestHitRatio(t *testing.T) {
+	t.Parallel()
+
+	const (
+		capacity = 500
+		n        = 200000
+	)
+
+	r := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(r, 1.1, 1, 100000)
+
+	c := New[uint64, bool](capacity)
+	l := lru.New[uint64, bool](capacity)
+	for i := 0; i < n; i++ {
+		k := zipf.Uint64()
+		if _, ok := c.Get(k); !ok {
+			c.Put(k, true)
+		}
+		if _, ok := l.Get(k); !ok {
+			l.Put(k, true)
+		}
+	}
+
+	ratio := func(hits, misses uint64) float64 { return float64(hits) / float64(hits+misses) }
+	got, base := ratio(c.Hits(), c.Misses()), ratio(l.Hits(), l.Misses())
+	if got <= base {
+		t.Errorf("tinylfu hit ratio %.3f not better than lru %.3f", got, base)
+	}
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	hits := 0
+	c := New(2, WithOnHit(func(k, v int) { hits++ }))
+	c.Put(1, 1)
+	c.Get(1)
+	c.Get(2)
+	c.Put(2, 2)
+	c.Put(3, 3)
+
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.Evictions != 1 || hits != 1 {
+		t.Errorf("c.Stats() = %+v, hits = %d", s, hits)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	c := New[int, int](50)
+	for i := 0; i < 2000; i++ {
+		k := r.Intn(200)
+		if _, ok := c.Get(k); !ok {
+			c.Put(k, k)
+		}
+	}
+
+	d := c.CloneFunc(func(v int) int { return v + 1 })
+	d.verify(t)
+	if d.Len() != c.Len() || d.Stats() != c.Stats() {
+		t.Errorf("clone differs from the cache")
+	}
+	for k := range 200 {
+		if v, ok := c.Peek(k); ok {
+			if w, ok := d.Peek(k); !ok || w != v+1 {
+				t.Errorf("d.Peek(%d) = %d, %t, want %d, true", k, w, ok, v+1)
+			}
+		}
+		if c.sketch.estimate(k) != d.sketch.estimate(k) {
+			t.Errorf("frequency of %d differs in the clone", k)
+		}
+	}
+
+	n := c.Len()
+	d.Clear()
+	if c.Len() != n {
+		t.Errorf("clearing the clone changed the cache")
+	}
+	c.verify(t)
+}
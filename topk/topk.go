@@ -0,0 +1,166 @@
+// Package topk implements the space-saving algorithm for finding the most
+// frequent items of a stream.
+//
+// A Summary (Metwally, Agrawal and El Abbadi, "Efficient Computation of
+// Frequent and Top-k Elements in Data Streams") monitors a fixed number of
+// items with a counter each. When an unmonitored item arrives and all
+// counters are in use, it replaces the item with the smallest count and
+// inherits that count as its error. Every item whose true frequency exceeds
+// the total stream weight divided by the capacity is guaranteed to be
+// monitored, and each reported count overestimates the true count by at
+// most its reported error.
+package topk
+
+import (
+	"slices"
+
+	"github.com/weiwenchen2022/container/cmpx"
+	"github.com/weiwenchen2022/container/heap"
+)
+
+// Item is a monitored item with its estimated count.
+// The true count of Key is in the range [Count-Error, Count].
+type Item[K comparable] struct {
+	Key   K
+	Count uint64
+	Error uint64
+}
+
+type counter[K comparable] struct {
+	Item[K]
+	index int
+}
+
+// Summary tracks the approximate most frequent items of a stream.
+// To create a summary use topk.New.
+type Summary[K comparable] struct {
+	capacity int
+	total    uint64
+
+	counters map[K]*counter[K]
+	minHeap  *heap.Heap[*counter[K]] // by count
+}
+
+// New returns an empty summary monitoring at most capacity items.
+// It panics if capacity is not positive.
+func New[K comparable](capacity int) *Summary[K] {
+	if capacity <= 0 {
+		panic("topk: non-positive capacity")
+	}
+
+	return &Summary[K]{
+		capacity: capacity,
+		counters: make(map[K]*counter[K], capacity),
+		minHeap: heap.New(func(a, b *counter[K]) bool { return a.Count < b.Count },
+			heap.WithInitialCap[*counter[K]](capacity),
+			heap.WithSetIndex(func(c *counter[K], i int) { c.index = i })),
+	}
+}
+
+// Len returns the number of items monitored by summary s.
+func (s *Summary[K]) Len() int { return len(s.counters) }
+
+// Total returns the total weight of the items added to summary s.
+func (s *Summary[K]) Total() uint64 { return s.total }
+
+// Add records an occurrence of item x.
+// The complexity is O(log k) where k is the capacity.
+func (s *Summary[K]) Add(x K) { s.AddN(x, 1) }
+
+// AddN records n occurrences of item x.
+// The complexity is O(log k) where k is the capacity.
+func (s *Summary[K]) AddN(x K, n uint64) {
+	s.total += n
+
+	if c, ok := s.counters[x]; ok {
+		c.Count += n
+		s.minHeap.Fix(c.index)
+		return
+	}
+
+	if len(s.counters) < s.capacity {
+		c := &counter[K]{Item: Item[K]{Key: x, Count: n}}
+		s.counters[x] = c
+		s.minHeap.Push(c)
+		return
+	}
+
+	// replace the item with the smallest count
+	c := s.minHeap.Peek()
+	delete(s.counters, c.Key)
+	c.Key, c.Error = x, c.Count
+	c.Count += n
+	s.counters[x] = c
+	s.minHeap.Fix(c.index)
+}
+
+// Count returns the estimated count of item x.
+// The ok result reports whether x is monitored; if it is not, its true
+// count is at most the smallest monitored count.
+func (s *Summary[K]) Count(x K) (item Item[K], ok bool) {
+	if c, ok := s.counters[x]; ok {
+		return c.Item, true
+	}
+
+	return Item[K]{Key: x}, false
+}
+
+// Top returns up to n monitored items with the largest counts, in
+// descending order of count.
+func (s *Summary[K]) Top(n int) []Item[K] {
+	items := make([]Item[K], 0, len(s.counters))
+	for _, c := range s.counters {
+		items = append(items, c.Item)
+	}
+
+	slices.SortFunc(items, cmpx.Chain(
+		cmpx.ReverseCompare(cmpx.CompareBy(func(it Item[K]) uint64 { return it.Count })),
+		cmpx.CompareBy(func(it Item[K]) uint64 { return it.Error }),
+	))
+
+	return items[:min(n, len(items))]
+}
+
+// Guaranteed reports whether the items returned by Top(n) are certainly
+// the n most frequent items: the lower bound of each item's count is at
+// least the count of the first item not returned.
+func (s *Summary[K]) Guaranteed(n int) bool {
+	items := s.Top(n + 1)
+	if len(items) <= n {
+		return true
+	}
+
+	next := items[n].Count
+	for _, it := range items[:n] {
+		if it.Count-it.Error < next {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Reset removes all items from summary s.
+func (s *Summary[K]) Reset() {
+	clear(s.counters)
+	for s.minHeap.Len() > 0 {
+		s.minHeap.Pop()
+	}
+	s.total = 0
+}
+
+// Clone returns a copy of summary s.
+func (s *Summary[K]) Clone() *Summary[K] {
+	c := &Summary[K]{
+		capacity: s.capacity,
+		total:    s.total,
+		counters: make(map[K]*counter[K], s.capacity),
+	}
+	c.minHeap = s.minHeap.CloneFunc(func(x *counter[K]) *counter[K] {
+		y := *x
+		c.counters[y.Key] = &y
+		return &y
+	})
+
+	return c
+}
@@ -0,0 +1,105 @@
+package topk
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestExact(t *testing.T) {
+	t.Parallel()
+
+	s := New[string](10)
+	for i, w := range []string{"a", "b", "c"} {
+		s.AddN(w, uint64(10*(i+1)))
+	}
+	s.Add("a")
+
+	top := s.Top(2)
+	if len(top) != 2 || top[0].Key != "c" || top[1].Key != "b" {
+		t.Errorf("s.Top(2) = %v", top)
+	}
+
+	if it, ok := s.Count("a"); !ok || it.Count != 11 || it.Error != 0 {
+		t.Errorf(`s.Count("a") = %v, %t`, it, ok)
+	}
+
+	if !s.Guaranteed(2) {
+		t.Errorf("s.Guaranteed(2) = false with exact counts")
+	}
+
+	if n := s.Total(); n != 61 {
+		t.Errorf("s.Total() = %d, want 61", n)
+	}
+
+	s.Reset()
+	if s.Len() != 0 || s.Total() != 0 {
+		t.Errorf("after Reset Len, Total = %d, %d", s.Len(), s.Total())
+	}
+}
+
+func TestZipf(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(r, 1.2, 1, 10000)
+
+	s := New[uint64](100)
+	counts := make(map[uint64]uint64)
+	for i := 0; i < 100000; i++ {
+		x := zipf.Uint64()
+		s.Add(x)
+		counts[x]++
+	}
+
+	if n := s.Len(); n != 100 {
+		t.Errorf("s.Len() = %d, want 100", n)
+	}
+
+	for _, it := range s.Top(100) {
+		if c := counts[it.Key]; c > it.Count || c < it.Count-it.Error {
+			t.Errorf("item %d true count %d outside [%d, %d]", it.Key, c, it.Count-it.Error, it.Count)
+		}
+	}
+
+	// the heaviest hitters of a skewed stream are found exactly
+	top := s.Top(5)
+	for i, it := range top {
+		if it.Key != uint64(i) {
+			t.Errorf("s.Top(5)[%d] = %v, want key %d", i, it, i)
+		}
+	}
+	if !s.Guaranteed(5) {
+		t.Errorf("s.Guaranteed(5) = false")
+	}
+
+	// every item more frequent than total/capacity is monitored
+	for x, c := range counts {
+		if c > s.Total()/100 {
+			if _, ok := s.Count(x); !ok {
+				t.Errorf("frequent item %d (count %d) not monitored", x, c)
+			}
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	s := New[string](2)
+	s.AddN("a", 5)
+	s.AddN("b", 3)
+
+	c := s.Clone()
+	c.AddN("c", 10)
+	c.Add("b")
+
+	if top := s.Top(2); len(top) != 2 || top[0] != (Item[string]{"a", 5, 0}) || top[1] != (Item[string]{"b", 3, 0}) {
+		t.Errorf("s.Top(2) = %v after changing the clone", top)
+	}
+	if top := c.Top(1); len(top) != 1 || top[0].Key != "c" {
+		t.Errorf("c.Top(1) = %v", top)
+	}
+	if c.Total() != 19 || s.Total() != 8 {
+		t.Errorf("totals = %d, %d, want 19, 8", c.Total(), s.Total())
+	}
+}
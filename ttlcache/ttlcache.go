@@ -0,0 +1,375 @@
+// Package ttlcache implements a cache whose entries expire after a time-to-live.
+//
+// Expired entries are removed lazily when accessed, by DeleteExpired, and,
+// if the cache was created with WithBackgroundExpiration, by a single
+// background goroutine that sleeps until the earliest expiration time
+// recorded in a timer heap.
+//
+// A Cache is safe for concurrent use by multiple goroutines.
+package ttlcache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/cachestats"
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+// NoTTL marks an entry that never expires.
+const NoTTL time.Duration = 0
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time // zero if the entry never expires
+
+	index int // index in the expiry heap, or -1
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// Cache is a cache of entries that expire.
+// To create a cache use ttlcache.New.
+type Cache[K comparable, V any] struct {
+	mu     sync.Mutex
+	items  map[K]*entry[K, V]
+	expiry *heap.Heap[*entry[K, V]] // entries with an expiration time, earliest first
+
+	defaultTTL time.Duration
+	onExpire   func(K, V)
+	onHit      func(K, V)
+	now        func() time.Time
+
+	stats cachestats.Recorder
+
+	background bool
+	wake       chan struct{}
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+type option[K comparable, V any] func(*Cache[K, V])
+
+// WithDefaultTTL sets the time-to-live of entries stored with Set.
+// The default is NoTTL.
+func WithDefaultTTL[K comparable, V any](ttl time.Duration) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.defaultTTL = ttl
+	}
+}
+
+// WithOnExpire sets a function called with the key and value of each entry
+// removed because it expired. The function is called without the cache's
+// lock held and may use the cache.
+func WithOnExpire[K comparable, V any](f func(K, V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onExpire = f
+	}
+}
+
+// WithOnHit sets a function called with the key and value of each entry
+// found by Get. The function is called without the cache's lock held.
+func WithOnHit[K comparable, V any](f func(K, V)) option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onHit = f
+	}
+}
+
+// WithBackgroundExpiration starts a goroutine that removes entries as they
+// expire. Close must be called to stop it.
+func WithBackgroundExpiration[K comparable, V any]() option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.background = true
+	}
+}
+
+// New returns an empty cache.
+func New[K comparable, V any](opts ...option[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{
+		items: make(map[K]*entry[K, V]),
+		expiry: heap.New(func(a, b *entry[K, V]) bool { return a.expires.Before(b.expires) },
+			heap.WithSetIndex(func(e *entry[K, V], i int) { e.index = i })),
+		now:  time.Now,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.background {
+		go c.run()
+	}
+
+	return c
+}
+
+// Close stops the background expiration goroutine, if any.
+// The cache remains usable, with lazy expiration only.
+func (c *Cache[K, V]) Close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// Len returns the number of entries in cache c, including expired entries
+// that have not been removed yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
+}
+
+// Get returns the value stored for key k.
+// The ok result reports whether the key was present and unexpired.
+func (c *Cache[K, V]) Get(k K) (v V, ok bool) {
+	c.mu.Lock()
+
+	e, ok := c.items[k]
+	if !ok {
+		c.stats.Miss()
+		c.mu.Unlock()
+		return v, false
+	}
+
+	if e.expired(c.now()) {
+		c.remove(e)
+		c.stats.Miss()
+		c.stats.Evict()
+		c.mu.Unlock()
+		c.expired([]*entry[K, V]{e})
+		return v, false
+	}
+
+	c.stats.Hit()
+	v = e.value
+	c.mu.Unlock()
+
+	if c.onHit != nil {
+		c.onHit(k, v)
+	}
+
+	return v, true
+}
+
+// Stats returns a snapshot of the statistics of cache c.
+// Expired entries are counted as evictions.
+func (c *Cache[K, V]) Stats() cachestats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats.Stats()
+}
+
+// TTL returns the remaining time-to-live of key k, or NoTTL if it never expires.
+// The ok result reports whether the key was present and unexpired.
+func (c *Cache[K, V]) TTL(k K) (ttl time.Duration, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[k]
+	switch now := c.now(); {
+	case !ok || e.expired(now):
+		return 0, false
+	case e.expires.IsZero():
+		return NoTTL, true
+	default:
+		return e.expires.Sub(now), true
+	}
+}
+
+// Set stores value v for key k with the default time-to-live.
+func (c *Cache[K, V]) Set(k K, v V) {
+	c.SetWithTTL(k, v, c.defaultTTL)
+}
+
+// SetWithTTL stores value v for key k, expiring after ttl.
+// A ttl of NoTTL means the entry never expires.
+func (c *Cache[K, V]) SetWithTTL(k K, v V, ttl time.Duration) {
+	var expires time.Time
+	if ttl != NoTTL {
+		expires = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[k]
+	if !ok {
+		e = &entry[K, V]{key: k, index: -1}
+		c.items[k] = e
+	}
+	e.value = v
+	e.expires = expires
+
+	switch {
+	case expires.IsZero():
+		if e.index >= 0 {
+			c.expiry.Remove(e.index)
+		}
+		return
+	case e.index >= 0:
+		c.expiry.Fix(e.index)
+	default:
+		c.expiry.Push(e)
+	}
+
+	if c.expiry.Peek() == e {
+		// the earliest expiration changed; let the background goroutine know
+		select {
+		case c.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Delete removes the entry for key k and reports whether it was present.
+// The expiration callback is not called.
+func (c *Cache[K, V]) Delete(k K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[k]
+	if ok {
+		c.remove(e)
+	}
+
+	return ok
+}
+
+// DeleteExpired removes all expired entries and returns the number removed.
+func (c *Cache[K, V]) DeleteExpired() int {
+	c.mu.Lock()
+	es := c.popExpired(c.now())
+	c.mu.Unlock()
+
+	c.expired(es)
+	return len(es)
+}
+
+// Clear removes all entries from cache c.
+// The expiration callback is not called, and the statistics are not reset.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.items)
+	for c.expiry.Len() > 0 {
+		c.expiry.Pop()
+	}
+}
+
+// Clone returns a copy of cache c with the same entries, expiration
+// times, options and statistics. The values are copied by assignment, so
+// this is a shallow clone.
+// If c runs background expiration and has not been closed, so does the
+// clone, which must be closed as well.
+func (c *Cache[K, V]) Clone() *Cache[K, V] {
+	return c.CloneFunc(func(v V) V { return v })
+}
+
+// CloneFunc is like Clone but copies each value with f, which allows a
+// deep clone. f is called with the lock of c held and must not use c.
+func (c *Cache[K, V]) CloneFunc(f func(V) V) *Cache[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := New[K, V]()
+	d.defaultTTL, d.onExpire, d.onHit, d.now = c.defaultTTL, c.onExpire, c.onHit, c.now
+	d.stats = c.stats.Clone()
+	for k, e := range c.items {
+		x := &entry[K, V]{key: k, value: f(e.value), expires: e.expires, index: -1}
+		d.items[k] = x
+		if !x.expires.IsZero() {
+			d.expiry.Push(x)
+		}
+	}
+
+	select {
+	case <-c.done:
+	default:
+		if c.background {
+			d.background = true
+			go d.run()
+		}
+	}
+
+	return d
+}
+
+// remove removes e from the cache. c.mu must be held.
+func (c *Cache[K, V]) remove(e *entry[K, V]) {
+	delete(c.items, e.key)
+	if e.index >= 0 {
+		c.expiry.Remove(e.index)
+	}
+}
+
+// popExpired removes and returns the entries expired at now. c.mu must be held.
+func (c *Cache[K, V]) popExpired(now time.Time) []*entry[K, V] {
+	var es []*entry[K, V]
+	for c.expiry.Len() > 0 && c.expiry.Peek().expired(now) {
+		e := c.expiry.Pop()
+		delete(c.items, e.key)
+		c.stats.Evict()
+		es = append(es, e)
+	}
+
+	return es
+}
+
+func (c *Cache[K, V]) expired(es []*entry[K, V]) {
+	if c.onExpire == nil {
+		return
+	}
+
+	for _, e := range es {
+		c.onExpire(e.key, e.value)
+	}
+}
+
+// run removes entries as they expire until the cache is closed.
+func (c *Cache[K, V]) run() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		c.mu.Lock()
+		es := c.popExpired(c.now())
+		next := time.Hour
+		if c.expiry.Len() > 0 {
+			next = c.expiry.Peek().expires.Sub(c.now())
+		}
+		c.mu.Unlock()
+
+		c.expired(es)
+		timer.Reset(next)
+
+		select {
+		case <-timer.C:
+		case <-c.wake:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// MemStats returns statistics about the memory used by cache c, including
+// expired entries not yet removed.
+func (c *Cache[K, V]) MemStats() container.MemStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := len(c.items)
+	return container.MemStats{
+		Len: n,
+		Cap: n,
+		Bytes: memsize.Of[Cache[K, V]]() + memsize.Map[K, *entry[K, V]](n) +
+			int64(n)*memsize.Of[entry[K, V]]() + c.expiry.MemStats().Bytes,
+	}
+}
@@ -0,0 +1,183 @@
+package ttlcache
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container = (*Cache[int, int])(nil)
+	_ container.Sizer     = (*Cache[int, int])(nil)
+)
+
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.t = c.t.Add(d)
+}
+
+func TestLazyExpiration(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(0, 0)}
+
+	var expired []string
+	c := New(WithDefaultTTL[string, int](time.Minute), WithOnExpire(func(k string, v int) {
+		expired = append(expired, k)
+	}))
+	c.now = clock.now
+
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, time.Second)
+	c.SetWithTTL("c", 3, NoTTL)
+	c.SetWithTTL("d", 4, 2*time.Minute)
+
+	if ttl, ok := c.TTL("b"); !ok || ttl != time.Second {
+		t.Errorf(`c.TTL("b") = %v, %t, want %v, true`, ttl, ok, time.Second)
+	}
+	if ttl, ok := c.TTL("c"); !ok || ttl != NoTTL {
+		t.Errorf(`c.TTL("c") = %v, %t, want %v, true`, ttl, ok, NoTTL)
+	}
+
+	clock.advance(time.Second)
+	if _, ok := c.Get("b"); ok {
+		t.Errorf(`c.Get("b") found expired entry`)
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf(`c.Get("a") = %d, %t, want 1, true`, v, ok)
+	}
+	if want := []string{"b"}; !slices.Equal(want, expired) {
+		t.Errorf("expired = %v, want %v", expired, want)
+	}
+
+	// refreshing d removes its expiration
+	c.SetWithTTL("d", 5, NoTTL)
+
+	clock.advance(time.Hour)
+	if n := c.DeleteExpired(); n != 1 {
+		t.Errorf("c.DeleteExpired() = %d, want 1", n)
+	}
+	if n := c.Len(); n != 2 {
+		t.Errorf("c.Len() = %d, want 2", n)
+	}
+	if want := []string{"b", "a"}; !slices.Equal(want, expired) {
+		t.Errorf("expired = %v, want %v", expired, want)
+	}
+
+	if !c.Delete("c") || c.Delete("c") {
+		t.Errorf(`c.Delete("c") results wrong`)
+	}
+
+	c.Clear()
+	if n := c.Len(); n != 0 {
+		t.Errorf("c.Len() after Clear = %d", n)
+	}
+}
+
+func TestBackgroundExpiration(t *testing.T) {
+	t.Parallel()
+
+	expired := make(chan int, 10)
+	c := New(WithBackgroundExpiration[int, int](), WithOnExpire(func(k, v int) {
+		expired <- k
+	}))
+	defer c.Close()
+
+	c.SetWithTTL(1, 1, time.Hour)
+	c.SetWithTTL(2, 2, 10*time.Millisecond)
+
+	select {
+	case k := <-expired:
+		if k != 2 {
+			t.Errorf("expired key %d, want 2", k)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("entry not expired in the background")
+	}
+
+	if n := c.Len(); n != 1 {
+		t.Errorf("c.Len() = %d, want 1", n)
+	}
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(0, 0)}
+
+	hits := 0
+	c := New(WithOnHit(func(k, v int) { hits++ }))
+	c.now = clock.now
+
+	c.SetWithTTL(1, 1, time.Second)
+	c.Get(1)
+	c.Get(2)
+	clock.advance(time.Second)
+	c.Get(1)
+
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 2 || s.Evictions != 1 || hits != 1 {
+		t.Errorf("c.Stats() = %+v, hits = %d", s, hits)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	c := New[string, []int]()
+	c.now = clock.now
+	c.SetWithTTL("a", []int{1}, time.Second)
+	c.SetWithTTL("b", []int{2}, NoTTL)
+
+	d := c.CloneFunc(slices.Clone)
+	if ttl, ok := d.TTL("a"); !ok || ttl != time.Second {
+		t.Errorf(`d.TTL("a") = %v, %t, want %v, true`, ttl, ok, time.Second)
+	}
+
+	v, _ := d.Get("b")
+	v[0] = 9
+	d.Delete("a")
+	if v, ok := c.Get("b"); !ok || v[0] != 2 {
+		t.Errorf("changing the clone changed the cache: %v", v)
+	}
+	if c.Len() != 2 {
+		t.Errorf("c.Len() = %d, want 2", c.Len())
+	}
+
+	clock.advance(time.Second)
+	e := c.Clone()
+	if _, ok := e.Get("a"); ok {
+		t.Errorf("expired entry found in the clone")
+	}
+
+	expired := make(chan int, 1)
+	bg := New(WithBackgroundExpiration[int, int](), WithOnExpire(func(k, v int) { expired <- k }))
+	bgc := bg.Clone()
+	bg.Close()
+	defer bgc.Close()
+	bgc.SetWithTTL(1, 1, 10*time.Millisecond)
+
+	select {
+	case <-expired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("entry not expired in the background of the clone")
+	}
+}
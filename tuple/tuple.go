@@ -0,0 +1,104 @@
+// Package tuple implements generic pairs and triples.
+//
+// Tuples are plain structs, so they can be map keys when their components
+// are comparable. When the components are ordered, ComparePair and LessPair
+// order pairs lexicographically, for use with slices.SortFunc, heap.New or
+// wbtree.New:
+//
+//	h := heap.New(tuple.LessPair[int, string]) // min-heap by priority, then name
+//	h.Push(tuple.MakePair(1, "urgent"))
+package tuple
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+)
+
+// Pair is a pair of values.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// MakePair returns the pair of a and b.
+func MakePair[A, B any](a A, b B) Pair[A, B] { return Pair[A, B]{a, b} }
+
+// Unpack returns the components of pair p.
+func (p Pair[A, B]) Unpack() (A, B) { return p.First, p.Second }
+
+// Swap returns pair p with its components exchanged.
+func (p Pair[A, B]) Swap() Pair[B, A] { return Pair[B, A]{p.Second, p.First} }
+
+// String returns pair p formatted as "(first, second)".
+func (p Pair[A, B]) String() string { return fmt.Sprintf("(%v, %v)", p.First, p.Second) }
+
+// ComparePair compares pairs x and y lexicographically.
+// The result is -1 if x < y, 0 if x == y and +1 if x > y.
+func ComparePair[A, B cmp.Ordered](x, y Pair[A, B]) int {
+	if c := cmp.Compare(x.First, y.First); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(x.Second, y.Second)
+}
+
+// LessPair reports whether pair x is lexicographically less than pair y.
+func LessPair[A, B cmp.Ordered](x, y Pair[A, B]) bool { return ComparePair(x, y) < 0 }
+
+// Triple is a triple of values.
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// MakeTriple returns the triple of a, b and c.
+func MakeTriple[A, B, C any](a A, b B, c C) Triple[A, B, C] { return Triple[A, B, C]{a, b, c} }
+
+// Unpack returns the components of triple t.
+func (t Triple[A, B, C]) Unpack() (A, B, C) { return t.First, t.Second, t.Third }
+
+// String returns triple t formatted as "(first, second, third)".
+func (t Triple[A, B, C]) String() string {
+	return fmt.Sprintf("(%v, %v, %v)", t.First, t.Second, t.Third)
+}
+
+// CompareTriple compares triples x and y lexicographically.
+// The result is -1 if x < y, 0 if x == y and +1 if x > y.
+func CompareTriple[A, B, C cmp.Ordered](x, y Triple[A, B, C]) int {
+	if c := cmp.Compare(x.First, y.First); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(x.Second, y.Second); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(x.Third, y.Third)
+}
+
+// LessTriple reports whether triple x is lexicographically less than triple y.
+func LessTriple[A, B, C cmp.Ordered](x, y Triple[A, B, C]) bool { return CompareTriple(x, y) < 0 }
+
+// Pairs returns an iterator over the pairs yielded by seq, such as the
+// All iterator of a map or the result of xiter.Zip.
+func Pairs[A, B any](seq iter.Seq2[A, B]) iter.Seq[Pair[A, B]] {
+	return func(yield func(Pair[A, B]) bool) {
+		for a, b := range seq {
+			if !yield(Pair[A, B]{a, b}) {
+				return
+			}
+		}
+	}
+}
+
+// Unpairs returns an iterator over the components of the pairs of seq.
+func Unpairs[A, B any](seq iter.Seq[Pair[A, B]]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		for p := range seq {
+			if !yield(p.First, p.Second) {
+				return
+			}
+		}
+	}
+}
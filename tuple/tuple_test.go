@@ -0,0 +1,70 @@
+package tuple
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container/heap"
+	"github.com/weiwenchen2022/container/xiter"
+)
+
+func TestPair(t *testing.T) {
+	t.Parallel()
+
+	p := MakePair(1, "one")
+	if a, b := p.Unpack(); a != 1 || b != "one" {
+		t.Errorf("p.Unpack() = %d, %q", a, b)
+	}
+	if s := p.Swap(); s.First != "one" || s.Second != 1 {
+		t.Errorf("p.Swap() = %v", s)
+	}
+	if s := p.String(); s != "(1, one)" {
+		t.Errorf("p.String() = %q", s)
+	}
+
+	ps := []Pair[int, string]{{2, "a"}, {1, "b"}, {1, "a"}, {2, "a"}}
+	slices.SortFunc(ps, ComparePair)
+	want := []Pair[int, string]{{1, "a"}, {1, "b"}, {2, "a"}, {2, "a"}}
+	if !slices.Equal(ps, want) {
+		t.Errorf("sorted = %v, want %v", ps, want)
+	}
+
+	h := heap.New(LessPair[int, string])
+	for _, p := range []Pair[int, string]{{3, "c"}, {1, "z"}, {1, "a"}} {
+		h.Push(p)
+	}
+	if p := h.Pop(); p != MakePair(1, "a") {
+		t.Errorf("h.Pop() = %v, want (1, a)", p)
+	}
+}
+
+func TestTriple(t *testing.T) {
+	t.Parallel()
+
+	x, y := MakeTriple(1, 2.5, "a"), MakeTriple(1, 2.5, "b")
+	if !LessTriple(x, y) || LessTriple(y, x) || CompareTriple(x, x) != 0 {
+		t.Errorf("triple ordering wrong")
+	}
+	if a, b, c := x.Unpack(); a != 1 || b != 2.5 || c != "a" {
+		t.Errorf("x.Unpack() = %v, %v, %v", a, b, c)
+	}
+	if s := x.String(); s != "(1, 2.5, a)" {
+		t.Errorf("x.String() = %q", s)
+	}
+}
+
+func TestSeq(t *testing.T) {
+	t.Parallel()
+
+	zipped := Pairs(xiter.Zip(slices.Values([]int{1, 2, 3}), slices.Values([]string{"a", "b"})))
+	want := []Pair[int, string]{{1, "a"}, {2, "b"}}
+	if got := slices.Collect(zipped); !slices.Equal(got, want) {
+		t.Errorf("Pairs(Zip) = %v, want %v", got, want)
+	}
+
+	m := maps.Collect(Unpairs(slices.Values(want)))
+	if len(m) != 2 || m[1] != "a" || m[2] != "b" {
+		t.Errorf("Unpairs = %v", m)
+	}
+}
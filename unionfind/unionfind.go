@@ -0,0 +1,207 @@
+// Package unionfind implements a disjoint-set forest with path compression
+// and union by rank.
+//
+// Elements are values of a comparable type and are added implicitly the
+// first time they are used, each in a set of its own. Find, Union and
+// Connected run in amortized nearly constant time.
+package unionfind
+
+import (
+	"iter"
+	"maps"
+	"slices"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+// UnionFind is a collection of disjoint sets.
+// The zero value for UnionFind is an empty collection ready to use.
+type UnionFind[K comparable] struct {
+	index  map[K]int
+	elems  []K
+	parent []int
+	rank   []uint8
+	size   []int // size of the set, valid at roots
+
+	sets int
+}
+
+// New returns an empty collection.
+func New[K comparable]() *UnionFind[K] {
+	return new(UnionFind[K])
+}
+
+// Len returns the number of elements in u.
+func (u *UnionFind[K]) Len() int { return len(u.elems) }
+
+// SetCount returns the number of disjoint sets in u.
+func (u *UnionFind[K]) SetCount() int { return u.sets }
+
+// Add adds x to u in a set of its own and reports whether it was not
+// already present.
+func (u *UnionFind[K]) Add(x K) bool {
+	if _, ok := u.index[x]; ok {
+		return false
+	}
+
+	u.id(x)
+	return true
+}
+
+// Contains reports whether x is in u.
+func (u *UnionFind[K]) Contains(x K) bool {
+	_, ok := u.index[x]
+	return ok
+}
+
+// id returns the index of x, adding it if necessary.
+func (u *UnionFind[K]) id(x K) int {
+	if i, ok := u.index[x]; ok {
+		return i
+	}
+
+	if u.index == nil {
+		u.index = make(map[K]int)
+	}
+
+	i := len(u.elems)
+	u.index[x] = i
+	u.elems = append(u.elems, x)
+	u.parent = append(u.parent, i)
+	u.rank = append(u.rank, 0)
+	u.size = append(u.size, 1)
+	u.sets++
+	return i
+}
+
+func (u *UnionFind[K]) root(i int) int {
+	r := i
+	for u.parent[r] != r {
+		r = u.parent[r]
+	}
+
+	// path compression
+	for u.parent[i] != r {
+		u.parent[i], i = r, u.parent[i]
+	}
+
+	return r
+}
+
+// Find returns the representative of the set containing x.
+// Elements of the same set have the same representative until the next Union.
+func (u *UnionFind[K]) Find(x K) K {
+	return u.elems[u.root(u.id(x))]
+}
+
+// Union merges the sets containing x and y and reports whether they were
+// distinct.
+func (u *UnionFind[K]) Union(x, y K) bool {
+	i, j := u.root(u.id(x)), u.root(u.id(y))
+	if i == j {
+		return false
+	}
+
+	// union by rank
+	if u.rank[i] < u.rank[j] {
+		i, j = j, i
+	}
+	if u.rank[i] == u.rank[j] {
+		u.rank[i]++
+	}
+
+	u.parent[j] = i
+	u.size[i] += u.size[j]
+	u.sets--
+	return true
+}
+
+// Connected reports whether x and y are in the same set.
+func (u *UnionFind[K]) Connected(x, y K) bool {
+	return u.root(u.id(x)) == u.root(u.id(y))
+}
+
+// Size returns the number of elements in the set containing x.
+func (u *UnionFind[K]) Size(x K) int {
+	return u.size[u.root(u.id(x))]
+}
+
+// Members returns an iterator over the elements of the set containing x,
+// in the order they were added.
+// The complexity of a full iteration is O(n) where n = u.Len().
+func (u *UnionFind[K]) Members(x K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		i, ok := u.index[x]
+		if !ok {
+			return
+		}
+
+		r := u.root(i)
+		for j, e := range u.elems {
+			if u.root(j) == r && !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// Sets returns an iterator over the disjoint sets of u, ordered by the
+// first-added element of each.
+// The complexity of a full iteration is O(n) where n = u.Len().
+func (u *UnionFind[K]) Sets() iter.Seq[[]K] {
+	return func(yield func([]K) bool) {
+		groups := make(map[int]int) // root to index in sets
+		var sets [][]K
+		for j, e := range u.elems {
+			r := u.root(j)
+			g, ok := groups[r]
+			if !ok {
+				g = len(sets)
+				groups[r] = g
+				sets = append(sets, make([]K, 0, u.size[r]))
+			}
+
+			sets[g] = append(sets[g], e)
+		}
+
+		for _, s := range sets {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// Clear removes all elements from u.
+func (u *UnionFind[K]) Clear() {
+	clear(u.index)
+	u.elems = u.elems[:0]
+	u.parent = u.parent[:0]
+	u.rank = u.rank[:0]
+	u.size = u.size[:0]
+	u.sets = 0
+}
+
+// Clone returns a copy of u.
+func (u *UnionFind[K]) Clone() *UnionFind[K] {
+	return &UnionFind[K]{
+		index:  maps.Clone(u.index),
+		elems:  slices.Clone(u.elems),
+		parent: slices.Clone(u.parent),
+		rank:   slices.Clone(u.rank),
+		size:   slices.Clone(u.size),
+		sets:   u.sets,
+	}
+}
+
+// MemStats returns statistics about the memory used by u.
+func (u *UnionFind[K]) MemStats() container.MemStats {
+	return container.MemStats{
+		Len: len(u.elems),
+		Cap: cap(u.elems),
+		Bytes: memsize.Of[UnionFind[K]]() + memsize.Map[K, int](len(u.index)) +
+			memsize.Slice[K](cap(u.elems)) + memsize.Slice[int](cap(u.parent)) +
+			memsize.Slice[uint8](cap(u.rank)) + memsize.Slice[int](cap(u.size)),
+	}
+}
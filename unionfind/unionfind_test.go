@@ -0,0 +1,134 @@
+package unionfind
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container = (*UnionFind[int])(nil)
+	_ container.Sizer     = (*UnionFind[int])(nil)
+)
+
+func TestUnionFind(t *testing.T) {
+	t.Parallel()
+
+	var u UnionFind[string]
+	for _, x := range []string{"a", "b", "c", "d", "e"} {
+		if !u.Add(x) {
+			t.Errorf("u.Add(%q) = false", x)
+		}
+	}
+	if u.Add("a") {
+		t.Errorf("u.Add(a) twice = true")
+	}
+
+	if u.SetCount() != 5 {
+		t.Errorf("SetCount = %d, want 5", u.SetCount())
+	}
+
+	if !u.Union("a", "c") || !u.Union("d", "e") || !u.Union("c", "e") {
+		t.Fatalf("Union of distinct sets = false")
+	}
+	if u.Union("a", "d") {
+		t.Errorf("Union of the same set = true")
+	}
+
+	if u.SetCount() != 2 || u.Size("e") != 4 || u.Size("b") != 1 {
+		t.Errorf("SetCount, Size(e), Size(b) = %d, %d, %d, want 2, 4, 1", u.SetCount(), u.Size("e"), u.Size("b"))
+	}
+
+	if !u.Connected("a", "e") || u.Connected("a", "b") {
+		t.Errorf("Connected is wrong")
+	}
+
+	if u.Find("a") != u.Find("d") {
+		t.Errorf("Find(a) = %q, Find(d) = %q, want equal", u.Find("a"), u.Find("d"))
+	}
+
+	if want, got := []string{"a", "c", "d", "e"}, slices.Collect(u.Members("d")); !slices.Equal(want, got) {
+		t.Errorf("Members(d) = %v, want %v", got, want)
+	}
+
+	want := [][]string{{"a", "c", "d", "e"}, {"b"}}
+	if got := slices.Collect(u.Sets()); !slices.EqualFunc(want, got, slices.Equal) {
+		t.Errorf("Sets = %v, want %v", got, want)
+	}
+
+	// implicit add
+	if u.Find("z") != "z" || u.Len() != 6 {
+		t.Errorf("Find did not add a new element")
+	}
+
+	u.Clear()
+	if u.Len() != 0 || u.SetCount() != 0 || u.Contains("a") {
+		t.Errorf("Clear left elements behind")
+	}
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	const n = 200
+	r := rand.New(rand.NewPCG(1, 2))
+	u := New[int]()
+
+	// naive labels for comparison
+	label := make([]int, n)
+	for i := range label {
+		label[i] = i
+		u.Add(i)
+	}
+
+	for range 150 {
+		x, y := r.IntN(n), r.IntN(n)
+		merged := label[x] != label[y]
+		if got := u.Union(x, y); got != merged {
+			t.Fatalf("Union(%d, %d) = %t, want %t", x, y, got, merged)
+		}
+
+		if merged {
+			old := label[y]
+			for i := range label {
+				if label[i] == old {
+					label[i] = label[x]
+				}
+			}
+		}
+	}
+
+	sets := make(map[int]bool)
+	for i := range n {
+		sets[label[i]] = true
+		for j := range n {
+			if got, want := u.Connected(i, j), label[i] == label[j]; got != want {
+				t.Fatalf("Connected(%d, %d) = %t, want %t", i, j, got, want)
+			}
+		}
+	}
+	if u.SetCount() != len(sets) {
+		t.Errorf("SetCount = %d, want %d", u.SetCount(), len(sets))
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	u := New[int]()
+	u.Union(1, 2)
+	u.Add(3)
+
+	c := u.Clone()
+	c.Union(2, 3)
+	c.Add(4)
+
+	if u.Connected(1, 3) || u.Len() != 3 || u.SetCount() != 2 {
+		t.Errorf("changing the clone changed the original")
+	}
+	if !c.Connected(1, 3) || c.Size(3) != 3 || c.SetCount() != 2 {
+		t.Errorf("clone: Connected(1, 3) %t, Size(3) %d, SetCount %d", c.Connected(1, 3), c.Size(3), c.SetCount())
+	}
+}
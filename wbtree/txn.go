@@ -0,0 +1,93 @@
+package wbtree
+
+import (
+	"errors"
+	"iter"
+)
+
+// ErrConflict is returned by Txn.Commit if the map was modified after the
+// transaction began.
+var ErrConflict = errors.New("wbtree: map modified during transaction")
+
+// Txn is a batch of mutations of a map that are applied together by
+// Commit or discarded by Rollback. Reads through a Txn see its own writes;
+// reads through the map do not see them until Commit.
+// To create a transaction use Map.Txn.
+type Txn[K, V any] struct {
+	m    *Map[K, V]
+	base *node[K, V] // root of m when the transaction began
+	work *Map[K, V]
+
+	events []func() // observer calls deferred until Commit
+}
+
+// Txn begins a transaction on map m.
+// The complexity is O(1); like a snapshot, the transaction copies the
+// nodes its writes touch.
+func (m *Map[K, V]) Txn() *Txn[K, V] {
+	t := &Txn[K, V]{m: m, base: m.root, work: m.Snapshot()}
+	if f := m.onInsert; f != nil {
+		t.work.onInsert = func(k K, v V) { t.events = append(t.events, func() { f(k, v) }) }
+	}
+	if f := m.onUpdate; f != nil {
+		t.work.onUpdate = func(k K, old, new V) { t.events = append(t.events, func() { f(k, old, new) }) }
+	}
+	if f := m.onRemove; f != nil {
+		t.work.onRemove = func(k K, v V) { t.events = append(t.events, func() { f(k, v) }) }
+	}
+
+	return t
+}
+
+func (t *Txn[K, V]) active() *Map[K, V] {
+	if t.work == nil {
+		panic("wbtree: transaction already finished")
+	}
+
+	return t.work
+}
+
+// Len returns the number of entries of the map as changed by t.
+func (t *Txn[K, V]) Len() int { return t.active().Len() }
+
+// Get returns the value stored for key k as changed by t.
+func (t *Txn[K, V]) Get(k K) (v V, ok bool) { return t.active().Get(k) }
+
+// Contains reports whether the map as changed by t has an entry for key k.
+func (t *Txn[K, V]) Contains(k K) bool { return t.active().Contains(k) }
+
+// Set sets the value for key k to v.
+func (t *Txn[K, V]) Set(k K, v V) { t.active().Set(k, v) }
+
+// Delete removes the entry for key k.
+func (t *Txn[K, V]) Delete(k K) (v V, ok bool) { return t.active().Delete(k) }
+
+// All returns an iterator over the entries of the map as changed by t,
+// in ascending key order.
+func (t *Txn[K, V]) All() iter.Seq2[K, V] { return t.active().All() }
+
+// Commit applies the mutations of t to the map in one step and ends the
+// transaction. If the map was modified after t began, Commit applies
+// nothing and returns ErrConflict.
+// It panics if t has already been committed or rolled back.
+func (t *Txn[K, V]) Commit() error {
+	work := t.active()
+	t.work = nil
+	if t.m.root != t.base {
+		t.events = nil
+		return ErrConflict
+	}
+
+	// The map takes over the nodes written by the transaction.
+	t.m.root, t.m.owner = work.root, work.owner
+	for _, f := range t.events {
+		f()
+	}
+	t.events = nil
+	return nil
+}
+
+// Rollback discards the mutations of t and ends the transaction.
+// Rolling back a finished transaction does nothing, so Rollback can be
+// deferred.
+func (t *Txn[K, V]) Rollback() { t.work, t.events = nil, nil }
@@ -0,0 +1,522 @@
+// Package wbtree implements an ordered map as a weight-balanced binary
+// search tree with O(1) snapshots.
+//
+// Snapshot returns an independent copy of a map in constant time by sharing
+// all nodes between the two versions. Nodes are tagged with the version
+// that owns them: a write modifies nodes it owns in place and copies the
+// path to the nodes it doesn't, so a version that is never snapshotted
+// pays no copying cost, and a long-running reader can iterate a snapshot
+// while a writer keeps mutating the original.
+//
+// To iterate over a map (where m is a *Map):
+//
+//	for k, v := range m.All() {
+//		// do something with k and v
+//	}
+package wbtree
+
+import (
+	"iter"
+
+	"github.com/weiwenchen2022/container"
+	"github.com/weiwenchen2022/container/internal/mem"
+	"github.com/weiwenchen2022/container/internal/memsize"
+)
+
+// Balance parameters from Hirai and Yamamoto, "Balancing weight-balanced trees".
+const (
+	delta = 3
+	gamma = 2
+)
+
+// owner identifies the version of a map allowed to modify a node in place.
+type owner struct{ _ byte }
+
+type node[K, V any] struct {
+	key   K
+	value V
+
+	left, right *node[K, V]
+	size        int
+
+	owner *owner
+}
+
+func size[K, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+func (n *node[K, V]) update() { n.size = 1 + size(n.left) + size(n.right) }
+
+// Map is an ordered map from keys of type K to values of type V.
+// To create a map use wbtree.New.
+type Map[K, V any] struct {
+	less  func(a, b K) bool
+	root  *node[K, V]
+	owner *owner
+
+	arena *mem.Arena[node[K, V]]
+	// shared reports whether nodes in arena may be reachable from a snapshot.
+	shared bool
+
+	onInsert func(K, V)
+	onUpdate func(k K, old, new V)
+	onRemove func(K, V)
+}
+
+type option[K, V any] func(*Map[K, V])
+
+// WithArena makes the map allocate its nodes in chunks of chunkSize, which
+// makes building a large map much cheaper. The memory of deleted nodes is
+// only reclaimed by Clear, so this suits maps that are built, used and
+// discarded or cleared as a whole.
+// It panics if chunkSize < 1.
+func WithArena[K, V any](chunkSize int) option[K, V] {
+	return func(m *Map[K, V]) {
+		m.arena = mem.NewArena[node[K, V]](chunkSize)
+	}
+}
+
+// WithOnInsert sets a function called with the key and value of each entry
+// added to the map.
+func WithOnInsert[K, V any](f func(K, V)) option[K, V] {
+	return func(m *Map[K, V]) {
+		m.onInsert = f
+	}
+}
+
+// WithOnUpdate sets a function called with the key and the old and new
+// values of each entry whose value Set replaces.
+func WithOnUpdate[K, V any](f func(k K, old, new V)) option[K, V] {
+	return func(m *Map[K, V]) {
+		m.onUpdate = f
+	}
+}
+
+// WithOnRemove sets a function called with the key and value of each entry
+// removed from the map by Delete or Clear.
+func WithOnRemove[K, V any](f func(K, V)) option[K, V] {
+	return func(m *Map[K, V]) {
+		m.onRemove = f
+	}
+}
+
+// New returns an empty map ordered according to the less function.
+func New[K, V any](less func(a, b K) bool, opts ...option[K, V]) *Map[K, V] {
+	m := &Map[K, V]{less: less, owner: new(owner)}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Len returns the number of entries of map m.
+// The complexity is O(1).
+func (m *Map[K, V]) Len() int { return size(m.root) }
+
+// Clear removes all entries from map m.
+// If m allocates from an arena that no snapshot refers to, Clear recycles
+// its memory.
+func (m *Map[K, V]) Clear() {
+	if m.onRemove != nil {
+		for k, v := range m.All() {
+			m.onRemove(k, v)
+		}
+	}
+
+	m.root = nil
+	if m.shared {
+		m.arena, m.shared = m.arena.Empty(), false
+	} else {
+		m.arena.Reset()
+	}
+}
+
+// Snapshot returns an independent copy of map m.
+// Later modifications of either map are not visible through the other.
+// The copy has no observers.
+// The complexity is O(1); the cost of copying is paid incrementally by
+// subsequent writes, which copy the nodes they touch.
+func (m *Map[K, V]) Snapshot() *Map[K, V] {
+	m.owner = new(owner)
+	m.shared = m.arena != nil
+	return &Map[K, V]{less: m.less, root: m.root, owner: new(owner), arena: m.arena.Empty()}
+}
+
+// Clone returns an independent copy of map m. It is equivalent to Snapshot.
+func (m *Map[K, V]) Clone() *Map[K, V] { return m.Snapshot() }
+
+// CloneFunc returns a copy of map m with each value copied by f, which
+// allows a deep clone.
+// The complexity is O(n).
+func (m *Map[K, V]) CloneFunc(f func(V) V) *Map[K, V] {
+	c := New[K, V](m.less)
+	c.arena = m.arena.Empty()
+
+	var clone func(n *node[K, V]) *node[K, V]
+	clone = func(n *node[K, V]) *node[K, V] {
+		if n == nil {
+			return nil
+		}
+
+		p := c.arena.New()
+		*p = node[K, V]{
+			key:   n.key,
+			value: f(n.value),
+			left:  clone(n.left),
+			right: clone(n.right),
+			size:  n.size,
+			owner: c.owner,
+		}
+		return p
+	}
+
+	c.root = clone(m.root)
+	return c
+}
+
+// mut returns a version of n that m may modify in place.
+func (m *Map[K, V]) mut(n *node[K, V]) *node[K, V] {
+	if n.owner == m.owner {
+		return n
+	}
+
+	c := m.arena.New()
+	*c = *n
+	c.owner = m.owner
+	return c
+}
+
+func (m *Map[K, V]) find(k K) *node[K, V] {
+	n := m.root
+	for n != nil {
+		switch {
+		case m.less(k, n.key):
+			n = n.left
+		case m.less(n.key, k):
+			n = n.right
+		default:
+			return n
+		}
+	}
+
+	return nil
+}
+
+// Get returns the value stored for key k.
+// The ok result reports whether the key was present.
+func (m *Map[K, V]) Get(k K) (v V, ok bool) {
+	if n := m.find(k); n != nil {
+		return n.value, true
+	}
+
+	return v, false
+}
+
+// Contains reports whether map m has an entry for key k.
+func (m *Map[K, V]) Contains(k K) bool { return m.find(k) != nil }
+
+// Set sets the value for key k to v.
+// The complexity is O(log n).
+func (m *Map[K, V]) Set(k K, v V) {
+	if m.onInsert == nil && m.onUpdate == nil {
+		m.root = m.insert(m.root, k, v)
+		return
+	}
+
+	n := m.find(k)
+	var old V
+	if n != nil {
+		old = n.value
+	}
+	m.root = m.insert(m.root, k, v)
+
+	switch {
+	case n == nil && m.onInsert != nil:
+		m.onInsert(k, v)
+	case n != nil && m.onUpdate != nil:
+		m.onUpdate(k, old, v)
+	}
+}
+
+func (m *Map[K, V]) insert(n *node[K, V], k K, v V) *node[K, V] {
+	if n == nil {
+		n = m.arena.New()
+		*n = node[K, V]{key: k, value: v, size: 1, owner: m.owner}
+		return n
+	}
+
+	switch {
+	case m.less(k, n.key):
+		n = m.mut(n)
+		n.left = m.insert(n.left, k, v)
+		return m.balanceR(n)
+	case m.less(n.key, k):
+		n = m.mut(n)
+		n.right = m.insert(n.right, k, v)
+		return m.balanceL(n)
+	default:
+		n = m.mut(n)
+		n.key, n.value = k, v
+		return n
+	}
+}
+
+// Delete removes the entry for key k from map m and returns its value.
+// The ok result reports whether the key was present.
+// The complexity is O(log n).
+func (m *Map[K, V]) Delete(k K) (v V, ok bool) {
+	if n := m.find(k); n != nil {
+		v, ok = n.value, true
+		m.root = m.delete(m.root, k)
+		if m.onRemove != nil {
+			m.onRemove(n.key, v)
+		}
+	}
+
+	return v, ok
+}
+
+func (m *Map[K, V]) delete(n *node[K, V], k K) *node[K, V] {
+	switch {
+	case m.less(k, n.key):
+		n = m.mut(n)
+		n.left = m.delete(n.left, k)
+		return m.balanceL(n)
+	case m.less(n.key, k):
+		n = m.mut(n)
+		n.right = m.delete(n.right, k)
+		return m.balanceR(n)
+	}
+
+	switch {
+	case n.left == nil:
+		return n.right
+	case n.right == nil:
+		return n.left
+	}
+
+	var successor *node[K, V]
+	right := m.deleteMin(n.right, &successor)
+	successor = m.mut(successor)
+	successor.left, successor.right = n.left, right
+	return m.balanceR(successor)
+}
+
+// deleteMin removes the minimum node of non-empty n, storing it in *min.
+func (m *Map[K, V]) deleteMin(n *node[K, V], min **node[K, V]) *node[K, V] {
+	if n.left == nil {
+		*min = n
+		return n.right
+	}
+
+	n = m.mut(n)
+	n.left = m.deleteMin(n.left, min)
+	return m.balanceL(n)
+}
+
+func isBalanced[K, V any](a, b *node[K, V]) bool {
+	return delta*(size(a)+1) >= size(b)+1
+}
+
+func isSingle[K, V any](a, b *node[K, V]) bool {
+	return size(a)+1 < gamma*(size(b)+1)
+}
+
+// balanceL restores balance of owned node n after its left subtree shrank
+// or its right subtree grew.
+func (m *Map[K, V]) balanceL(n *node[K, V]) *node[K, V] {
+	if isBalanced(n.left, n.right) {
+		n.update()
+		return n
+	}
+
+	if !isSingle(n.right.left, n.right.right) {
+		n.right = m.rotateR(m.mut(n.right))
+	}
+
+	return m.rotateL(n)
+}
+
+// balanceR restores balance of owned node n after its right subtree shrank
+// or its left subtree grew.
+func (m *Map[K, V]) balanceR(n *node[K, V]) *node[K, V] {
+	if isBalanced(n.right, n.left) {
+		n.update()
+		return n
+	}
+
+	if !isSingle(n.left.right, n.left.left) {
+		n.left = m.rotateL(m.mut(n.left))
+	}
+
+	return m.rotateR(n)
+}
+
+func (m *Map[K, V]) rotateL(n *node[K, V]) *node[K, V] {
+	r := m.mut(n.right)
+	n.right = r.left
+	n.update()
+	r.left = n
+	r.update()
+	return r
+}
+
+func (m *Map[K, V]) rotateR(n *node[K, V]) *node[K, V] {
+	l := m.mut(n.left)
+	n.left = l.right
+	n.update()
+	l.right = n
+	l.update()
+	return l
+}
+
+// Min returns the entry with the smallest key in map m.
+// The ok result reports whether m is non-empty.
+func (m *Map[K, V]) Min() (k K, v V, ok bool) {
+	n := m.root
+	if n == nil {
+		return k, v, false
+	}
+
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n.key, n.value, true
+}
+
+// Max returns the entry with the largest key in map m.
+// The ok result reports whether m is non-empty.
+func (m *Map[K, V]) Max() (k K, v V, ok bool) {
+	n := m.root
+	if n == nil {
+		return k, v, false
+	}
+
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n.key, n.value, true
+}
+
+// At returns the entry with the i'th smallest key, counting from zero.
+// The complexity is O(log n).
+func (m *Map[K, V]) At(i int) (K, V) {
+	if i < 0 || i >= m.Len() {
+		panic("wbtree: index out of range")
+	}
+
+	n := m.root
+	for {
+		switch l := size(n.left); {
+		case i < l:
+			n = n.left
+		case i > l:
+			i -= l + 1
+			n = n.right
+		default:
+			return n.key, n.value
+		}
+	}
+}
+
+// Rank returns the number of keys in map m less than k.
+// The complexity is O(log n).
+func (m *Map[K, V]) Rank(k K) int {
+	r := 0
+	for n := m.root; n != nil; {
+		if m.less(n.key, k) {
+			r += size(n.left) + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+
+	return r
+}
+
+func (n *node[K, V]) all(yield func(K, V) bool) bool {
+	return n == nil || n.left.all(yield) && yield(n.key, n.value) && n.right.all(yield)
+}
+
+func (n *node[K, V]) backward(yield func(K, V) bool) bool {
+	return n == nil || n.right.backward(yield) && yield(n.key, n.value) && n.left.backward(yield)
+}
+
+// All returns an iterator over the entries of map m in ascending key order.
+// The map must not be modified during iteration; iterate a Snapshot instead.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.all(yield)
+	}
+}
+
+// Backward returns an iterator over the entries of map m in descending key order.
+// The map must not be modified during iteration; iterate a Snapshot instead.
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.backward(yield)
+	}
+}
+
+// Keys returns an iterator over the keys of map m in ascending order.
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.root.all(func(k K, _ V) bool { return yield(k) })
+	}
+}
+
+// Values returns an iterator over the values of map m in ascending key order.
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		m.root.all(func(_ K, v V) bool { return yield(v) })
+	}
+}
+
+// Ascend returns an iterator over the entries of map m with keys in
+// [lo, hi), in ascending order.
+func (m *Map[K, V]) Ascend(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.ascend(m.root, lo, hi, yield)
+	}
+}
+
+func (m *Map[K, V]) ascend(n *node[K, V], lo, hi K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	if m.less(lo, n.key) && !m.ascend(n.left, lo, hi, yield) {
+		return false
+	}
+
+	if !m.less(n.key, lo) && m.less(n.key, hi) && !yield(n.key, n.value) {
+		return false
+	}
+
+	return !m.less(n.key, hi) || m.ascend(n.right, lo, hi, yield)
+}
+
+// MemStats returns statistics about the memory used by map m, including
+// nodes it shares with snapshots. With an arena, Cap counts the unused
+// nodes of the arena's chunks.
+func (m *Map[K, V]) MemStats() container.MemStats {
+	n := m.Len()
+	if m.arena != nil {
+		return container.MemStats{
+			Len:   n,
+			Cap:   n + m.arena.Cap() - m.arena.Len(),
+			Bytes: memsize.Of[Map[K, V]]() + int64(n+m.arena.Cap()-m.arena.Len())*memsize.Of[node[K, V]](),
+		}
+	}
+
+	return container.MemStats{Len: n, Cap: n, Bytes: memsize.Of[Map[K, V]]() + int64(n)*memsize.Of[node[K, V]]()}
+}
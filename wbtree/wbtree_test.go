@@ -0,0 +1,329 @@
+package wbtree
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var (
+	_ container.Container     = (*Map[int, int])(nil)
+	_ container.Iterable[int] = (*Map[int, int])(nil)
+	_ container.Sizer         = (*Map[int, int])(nil)
+)
+
+func less(a, b int) bool { return a < b }
+
+func (m *Map[K, V]) verify(t *testing.T) {
+	t.Helper()
+
+	var check func(n *node[K, V]) int
+	check = func(n *node[K, V]) int {
+		if n == nil {
+			return 0
+		}
+
+		if n.left != nil && !m.less(n.left.key, n.key) {
+			t.Fatalf("left child %v not less than %v", n.left.key, n.key)
+		}
+
+		if n.right != nil && !m.less(n.key, n.right.key) {
+			t.Fatalf("right child %v not greater than %v", n.right.key, n.key)
+		}
+
+		if !isBalanced(n.left, n.right) || !isBalanced(n.right, n.left) {
+			t.Fatalf("node %v unbalanced: sizes %d, %d", n.key, size(n.left), size(n.right))
+		}
+
+		if s := 1 + check(n.left) + check(n.right); n.size != s {
+			t.Fatalf("node %v size = %d, want %d", n.key, n.size, s)
+		}
+
+		return n.size
+	}
+	check(m.root)
+}
+
+func checkMap(t *testing.T, m *Map[int, int], want map[int]int) {
+	t.Helper()
+
+	m.verify(t)
+
+	if n := m.Len(); len(want) != n {
+		t.Fatalf("m.Len() = %d, want %d", n, len(want))
+	}
+
+	keys := slices.Sorted(maps.Keys(want))
+	if got := slices.Collect(m.Keys()); !slices.Equal(keys, got) {
+		t.Fatalf("m.Keys() = %v, want %v", got, keys)
+	}
+
+	for i, k := range keys {
+		if v, ok := m.Get(k); !ok || want[k] != v {
+			t.Fatalf("m.Get(%d) = %d, %t, want %d, true", k, v, ok, want[k])
+		}
+
+		if x, _ := m.At(i); k != x {
+			t.Fatalf("m.At(%d) = %d, want %d", i, x, k)
+		}
+
+		if r := m.Rank(k); i != r {
+			t.Fatalf("m.Rank(%d) = %d, want %d", k, r, i)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	m := New[int, int](less)
+	want := make(map[int]int)
+	checkMap(t, m, want)
+
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(500)
+		if r.Intn(3) == 0 {
+			v, ok := m.Delete(k)
+			wv, wok := want[k]
+			if v != wv || ok != wok {
+				t.Fatalf("m.Delete(%d) = %d, %t, want %d, %t", k, v, ok, wv, wok)
+			}
+			delete(want, k)
+		} else {
+			m.Set(k, i)
+			want[k] = i
+		}
+	}
+	checkMap(t, m, want)
+
+	if k, _, ok := m.Min(); !ok || k != slices.Min(slices.Collect(maps.Keys(want))) {
+		t.Errorf("m.Min() = %d, %t", k, ok)
+	}
+
+	if k, _, ok := m.Max(); !ok || k != slices.Max(slices.Collect(maps.Keys(want))) {
+		t.Errorf("m.Max() = %d, %t", k, ok)
+	}
+
+	var got []int
+	for k := range m.Ascend(100, 200) {
+		got = append(got, k)
+	}
+	for _, k := range got {
+		if k < 100 || k >= 200 {
+			t.Fatalf("m.Ascend(100, 200) yielded %d", k)
+		}
+	}
+	if !slices.IsSorted(got) {
+		t.Errorf("m.Ascend(100, 200) not sorted: %v", got)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(2))
+
+	m := New[int, int](less)
+	want := make(map[int]int)
+	for i := 0; i < 200; i++ {
+		m.Set(i, i)
+		want[i] = i
+	}
+
+	var snaps []*Map[int, int]
+	var wants []map[int]int
+	for round := 0; round < 10; round++ {
+		snaps = append(snaps, m.Snapshot())
+		wants = append(wants, maps.Clone(want))
+
+		for i := 0; i < 100; i++ {
+			k := r.Intn(300)
+			if r.Intn(2) == 0 {
+				m.Delete(k)
+				delete(want, k)
+			} else {
+				m.Set(k, -i)
+				want[k] = -i
+			}
+		}
+	}
+
+	checkMap(t, m, want)
+	for i, s := range snaps {
+		checkMap(t, s, wants[i])
+	}
+
+	// snapshots are writable and independent
+	s := snaps[0]
+	s.Set(-1, -1)
+	if m.Contains(-1) || snaps[1].Contains(-1) {
+		t.Errorf("write to snapshot visible in other versions")
+	}
+}
+
+func BenchmarkSet(b *testing.B) {
+	m := New[int, int](less)
+	for i := 0; i < b.N; i++ {
+		m.Set(i, i)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	m := New[int, int](less)
+	want := make(map[int]int)
+	for i := range 100 {
+		m.Set(i, i)
+		want[i] = i
+	}
+
+	c := m.Clone()
+	c.Set(1000, 0)
+	c.Delete(0)
+	checkMap(t, m, want)
+
+	doubled := m.CloneFunc(func(v int) int { return v * 2 })
+	for k := range want {
+		want[k] *= 2
+	}
+	checkMap(t, doubled, want)
+
+	doubled.Set(5, 5)
+	if v, _ := m.Get(5); v != 5 {
+		t.Errorf("changing the clone changed the map")
+	}
+	if v, _ := doubled.Get(6); v != 12 {
+		t.Errorf("doubled.Get(6) = %d, want 12", v)
+	}
+}
+
+func TestArena(t *testing.T) {
+	t.Parallel()
+
+	m := New(less, WithArena[int, int](16))
+	want := make(map[int]int)
+	for i := range 100 {
+		m.Set(i, i)
+		want[i] = i
+	}
+	checkMap(t, m, want)
+
+	// Clearing a map whose nodes a snapshot shares must not reuse them.
+	s := m.Snapshot()
+	m.Clear()
+	for i := range 100 {
+		m.Set(i, -i)
+	}
+	checkMap(t, s, want)
+	if s.arena == m.arena {
+		t.Errorf("snapshot shares the arena of the map")
+	}
+
+	// Without snapshots Clear recycles the arena.
+	n := m.arena.Chunks()
+	m.Clear()
+	for i := range 100 {
+		m.Set(i, i)
+	}
+	checkMap(t, m, want)
+	if c := m.arena.Chunks(); c != n {
+		t.Errorf("m.arena.Chunks() after Clear = %d, want %d", c, n)
+	}
+}
+
+func TestTxn(t *testing.T) {
+	t.Parallel()
+
+	m := New[int, int](less)
+	want := make(map[int]int)
+	for i := range 100 {
+		m.Set(i, i)
+		want[i] = i
+	}
+
+	tx := m.Txn()
+	tx.Set(1000, 1)
+	tx.Delete(0)
+	if v, ok := tx.Get(1000); !ok || v != 1 || tx.Contains(0) || tx.Len() != 100 {
+		t.Errorf("transaction does not see its own writes")
+	}
+	checkMap(t, m, want)
+
+	tx.Rollback()
+	tx.Rollback()
+	checkMap(t, m, want)
+
+	tx = m.Txn()
+	for i := range 50 {
+		tx.Delete(i)
+		delete(want, i)
+	}
+	tx.Set(-1, -1)
+	want[-1] = -1
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("tx.Commit() = %v", err)
+	}
+	checkMap(t, m, want)
+
+	// the map keeps working on the nodes it took over
+	m.Set(-2, -2)
+	want[-2] = -2
+	checkMap(t, m, want)
+
+	tx = m.Txn()
+	tx.Set(5000, 0)
+	m.Delete(99)
+	delete(want, 99)
+	if err := tx.Commit(); err != ErrConflict {
+		t.Errorf("tx.Commit() after a concurrent write = %v, want %v", err, ErrConflict)
+	}
+	checkMap(t, m, want)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("tx.Set after Commit did not panic")
+		}
+	}()
+	tx.Set(1, 1)
+}
+
+func TestObservers(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+	m := New(less,
+		WithOnInsert(func(k, v int) { events = append(events, fmt.Sprint("insert ", k, v)) }),
+		WithOnUpdate(func(k, old, new int) { events = append(events, fmt.Sprint("update ", k, old, new)) }),
+		WithOnRemove(func(k, v int) { events = append(events, fmt.Sprint("remove ", k, v)) }))
+
+	m.Set(1, 1)
+	m.Set(1, 10)
+	m.Delete(2)
+
+	tx := m.Txn()
+	tx.Set(2, 2)
+	tx.Delete(1)
+	if len(events) != 2 {
+		t.Errorf("events before Commit = %q", events)
+	}
+	tx.Commit()
+
+	tx = m.Txn()
+	tx.Set(3, 3)
+	tx.Rollback()
+
+	m.Snapshot().Set(4, 4)
+	m.Clear()
+
+	want := []string{"insert 1 1", "update 1 1 10", "insert 2 2", "remove 1 10", "remove 2 2"}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+}
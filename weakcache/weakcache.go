@@ -0,0 +1,184 @@
+// Package weakcache implements a cache that holds its values weakly.
+//
+// Values are referenced through weak pointers, so the garbage collector may
+// reclaim any value that is not otherwise in use, for example under memory
+// pressure. A lookup of a reclaimed value transparently reloads it by
+// calling the cache's loader. This suits memoizing large decoded objects
+// that are cheap enough to rebuild but expensive to keep around forever.
+//
+// A Cache is safe for concurrent use by multiple goroutines.
+package weakcache
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+	"weak"
+
+	"github.com/weiwenchen2022/container/cachestats"
+)
+
+// ErrLoadPanicked is returned by Get to the callers waiting for a load
+// whose loader panicked.
+var ErrLoadPanicked = errors.New("weakcache: loader panicked")
+
+// call is an in-flight or completed load.
+type call[V any] struct {
+	done chan struct{}
+	v    *V
+	err  error
+}
+
+// Cache is a cache of weakly held values of type *V.
+// To create a cache use weakcache.New.
+type Cache[K comparable, V any] struct {
+	load func(K) (*V, error)
+
+	mu       sync.Mutex
+	items    map[K]weak.Pointer[V]
+	inflight map[K]*call[V]
+
+	stats cachestats.Recorder
+}
+
+// New returns an empty cache that loads missing values with load.
+func New[K comparable, V any](load func(K) (*V, error)) *Cache[K, V] {
+	return &Cache[K, V]{
+		load:     load,
+		items:    make(map[K]weak.Pointer[V]),
+		inflight: make(map[K]*call[V]),
+	}
+}
+
+// Len returns the number of entries in cache c, including entries whose
+// values have been reclaimed but not yet removed.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
+}
+
+// Get returns the value for key k, loading it if it is absent or has been
+// reclaimed. Concurrent calls for the same key share a single load.
+// A failed load or a nil value is not cached. If the loader panics, the
+// panic propagates to this call and concurrent callers for k get
+// ErrLoadPanicked.
+func (c *Cache[K, V]) Get(k K) (*V, error) {
+	c.mu.Lock()
+
+	if v := c.items[k].Value(); v != nil {
+		c.stats.Hit()
+		c.mu.Unlock()
+		return v, nil
+	}
+
+	c.stats.Miss()
+	if cl, ok := c.inflight[k]; ok {
+		c.mu.Unlock()
+		<-cl.done
+		return cl.v, cl.err
+	}
+
+	cl := &call[V]{done: make(chan struct{}), err: ErrLoadPanicked}
+	c.inflight[k] = cl
+	c.mu.Unlock()
+
+	// Finish the call even if load panics, so waiters are not blocked.
+	defer close(cl.done)
+	start := time.Now()
+	defer func() {
+		d := time.Since(start)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.stats.Load(d)
+		delete(c.inflight, k)
+		if cl.err == nil {
+			c.put(k, cl.v)
+		}
+	}()
+
+	cl.v, cl.err = c.load(k)
+	return cl.v, cl.err
+}
+
+// Peek returns the value for key k if it is present and has not been
+// reclaimed, without loading it or updating the statistics.
+func (c *Cache[K, V]) Peek(k K) *V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.items[k].Value()
+}
+
+// Put stores v as the value for key k. If v is nil, the entry for k is
+// removed.
+func (c *Cache[K, V]) Put(k K, v *V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.put(k, v)
+}
+
+// put stores v for k and arranges for the entry to be removed once v is
+// reclaimed. A nil v removes the entry, as it cannot be held weakly.
+// c.mu must be held.
+func (c *Cache[K, V]) put(k K, v *V) {
+	if v == nil {
+		delete(c.items, k)
+		return
+	}
+
+	p := weak.Make(v)
+	c.items[k] = p
+
+	runtime.AddCleanup(v, func(k K) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.items[k] == p {
+			delete(c.items, k)
+			c.stats.Evict()
+		}
+	}, k)
+}
+
+// Remove removes the entry for key k and reports whether it was present.
+func (c *Cache[K, V]) Remove(k K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.items[k]
+	delete(c.items, k)
+	return ok
+}
+
+// Clone returns a copy of cache c with the same loader, statistics and
+// live entries. The clone refers to the same values: a copy of a value
+// would be referenced only weakly and could be reclaimed at once.
+func (c *Cache[K, V]) Clone() *Cache[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := New(c.load)
+	d.stats = c.stats.Clone()
+	for k, p := range c.items {
+		if v := p.Value(); v != nil {
+			d.put(k, v)
+		}
+	}
+
+	return d
+}
+
+// Stats returns a snapshot of the statistics of cache c.
+// Entries whose values were reclaimed by the garbage collector are counted as evictions.
+func (c *Cache[K, V]) Stats() cachestats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats.Stats()
+}
@@ -0,0 +1,207 @@
+package weakcache
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type blob struct {
+	key  int
+	data [1 << 12]byte
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	var loads atomic.Int32
+	c := New(func(k int) (*blob, error) {
+		loads.Add(1)
+		if k < 0 {
+			return nil, errors.New("negative key")
+		}
+
+		return &blob{key: k}, nil
+	})
+
+	b, err := c.Get(1)
+	if err != nil || b.key != 1 {
+		t.Fatalf("c.Get(1) = %v, %v", b, err)
+	}
+
+	b2, _ := c.Get(1)
+	if b != b2 {
+		t.Errorf("c.Get(1) returned a different value while the first is alive")
+	}
+	runtime.KeepAlive(b)
+
+	if _, err := c.Get(-1); err == nil {
+		t.Errorf("c.Get(-1) succeeded")
+	}
+	if c.Peek(-1) != nil {
+		t.Errorf("failed load was cached")
+	}
+
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 2 || s.Loads != 2 {
+		t.Errorf("c.Stats() = %+v", s)
+	}
+
+	if !c.Remove(1) || c.Remove(1) {
+		t.Errorf("c.Remove(1) results wrong")
+	}
+}
+
+func TestReclaim(t *testing.T) {
+	t.Parallel()
+
+	var loads atomic.Int32
+	c := New(func(k int) (*blob, error) {
+		loads.Add(1)
+		return &blob{key: k}, nil
+	})
+
+	c.Get(1)
+
+	// Nothing holds the value, so it can be reclaimed and the entry removed.
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Len() > 0 && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if n := c.Len(); n != 0 {
+		t.Fatalf("c.Len() = %d after GC, want 0", n)
+	}
+
+	b, _ := c.Get(1)
+	if b.key != 1 || loads.Load() != 2 {
+		t.Errorf("reload: key %d, loads %d", b.key, loads.Load())
+	}
+
+	if s := c.Stats(); s.Evictions != 1 {
+		t.Errorf("c.Stats().Evictions = %d, want 1", s.Evictions)
+	}
+	runtime.KeepAlive(b)
+}
+
+func TestConcurrentLoad(t *testing.T) {
+	t.Parallel()
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	c := New(func(k int) (*blob, error) {
+		loads.Add(1)
+		<-release
+		return &blob{key: k}, nil
+	})
+
+	var wg sync.WaitGroup
+	results := make([]*blob, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = c.Get(7)
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, b := range results {
+		if b != results[0] {
+			t.Fatalf("concurrent Get returned different values")
+		}
+	}
+	if n := loads.Load(); n > 2 {
+		t.Errorf("loads = %d, want a shared load", n)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	c := New(func(k int) (*blob, error) { return &blob{key: k}, nil })
+	b, _ := c.Get(1)
+
+	d := c.Clone()
+	if d.Peek(1) != b {
+		t.Errorf("clone does not share the live value")
+	}
+	if s := d.Stats(); s.Misses != 1 || s.Loads != 1 {
+		t.Errorf("clone stats = %+v, want 1 miss and 1 load", s)
+	}
+
+	d.Remove(1)
+	if c.Peek(1) != b {
+		t.Errorf("removing from the clone removed from the cache")
+	}
+	if x, _ := d.Get(2); x.key != 2 || c.Peek(2) != nil {
+		t.Errorf("loading into the clone loaded into the cache")
+	}
+	runtime.KeepAlive(b)
+}
+
+func TestNilAndPanic(t *testing.T) {
+	t.Parallel()
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	c := New(func(k int) (*blob, error) {
+		loads.Add(1)
+		switch k {
+		case 0:
+			return nil, nil
+		case 1:
+			<-release
+			panic("load failed")
+		}
+		return &blob{key: k}, nil
+	})
+
+	if b, err := c.Get(0); b != nil || err != nil {
+		t.Errorf("c.Get(0) = %v, %v, want nil, nil", b, err)
+	}
+	if b, err := c.Get(0); b != nil || err != nil || loads.Load() != 2 || c.Len() != 0 {
+		t.Errorf("nil value was cached")
+	}
+
+	panicked := make(chan any)
+	go func() {
+		defer func() { panicked <- recover() }()
+		c.Get(1)
+	}()
+	for c.Stats().Misses < 3 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waited := make(chan error)
+	go func() {
+		_, err := c.Get(1)
+		waited <- err
+	}()
+	for c.Stats().Misses < 4 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	if r := <-panicked; r == nil {
+		t.Errorf("c.Get(1) did not propagate the loader panic")
+	}
+	select {
+	case err := <-waited:
+		if err != ErrLoadPanicked {
+			t.Errorf("waiting c.Get(1) error = %v, want %v", err, ErrLoadPanicked)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("waiting c.Get(1) blocked after the loader panicked")
+	}
+
+	if b, err := c.Get(2); err != nil || b.key != 2 {
+		t.Errorf("c.Get(2) after a panic = %v, %v", b, err)
+	}
+}
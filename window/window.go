@@ -0,0 +1,219 @@
+// Package window implements aggregation over a sliding time window.
+//
+// An Aggregator divides its window into a ring of equal buckets. Each
+// bucket keeps the count, sum, minimum and maximum of the values added
+// during its time span, and optionally a custom reduction, so memory is
+// proportional to the number of buckets rather than the number of values.
+// As time advances the oldest bucket is recycled, so the live window covers
+// between window-window/buckets and window of the most recent time; more
+// buckets give a smoother window.
+//
+// For example, to track request latencies over the last minute:
+//
+//	latency := window.New[time.Duration](time.Minute, 60)
+//	latency.Add(elapsed)
+//	slow, _ := latency.Max()
+package window
+
+import "time"
+
+// Number is the set of types an Aggregator can sum.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+type bucket[E Number] struct {
+	slot  int64 // index of the time span the bucket holds; valid if count > 0
+	count int
+	sum   E
+	min   E
+	max   E
+	acc   E // reduction of the values
+}
+
+// An Aggregator aggregates the values added during a sliding time window.
+// To create an aggregator use window.New.
+type Aggregator[E Number] struct {
+	span    time.Duration
+	buckets []bucket[E]
+	reduce  func(a, b E) E
+
+	now func() time.Time
+}
+
+type option[E Number] func(*Aggregator[E])
+
+// WithReduce sets an associative function combining two values, whose
+// reduction over the live window is returned by Reduce.
+func WithReduce[E Number](f func(a, b E) E) option[E] {
+	return func(a *Aggregator[E]) {
+		a.reduce = f
+	}
+}
+
+// New returns an aggregator over a window of the given duration divided
+// into n buckets. It panics if n < 1 or window is shorter than n
+// nanoseconds.
+func New[E Number](window time.Duration, n int, opts ...option[E]) *Aggregator[E] {
+	if n < 1 {
+		panic("window: non-positive bucket count")
+	}
+	if window < time.Duration(n) {
+		panic("window: window too short")
+	}
+
+	a := &Aggregator[E]{span: window / time.Duration(n), buckets: make([]bucket[E], n), now: time.Now}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// slot returns the index of the time span containing t.
+func (a *Aggregator[E]) slot(t time.Time) int64 {
+	ns, span := t.UnixNano(), int64(a.span)
+	s := ns / span
+	if ns%span < 0 {
+		s--
+	}
+
+	return s
+}
+
+func (a *Aggregator[E]) bucket(slot int64) *bucket[E] {
+	n := int64(len(a.buckets))
+	return &a.buckets[(slot%n+n)%n]
+}
+
+// Add adds x at the current time.
+func (a *Aggregator[E]) Add(x E) { a.AddAt(a.now(), x) }
+
+// AddAt adds x at time t and reports whether t is recent enough to fall in
+// the live window; values older than the window are ignored. Values may be
+// added out of order.
+func (a *Aggregator[E]) AddAt(t time.Time, x E) bool {
+	s := a.slot(t)
+	if s <= a.slot(a.now())-int64(len(a.buckets)) {
+		return false
+	}
+
+	b := a.bucket(s)
+	if b.count > 0 && b.slot > s {
+		return false // the bucket already holds a later span
+	}
+	if b.count == 0 || b.slot < s {
+		*b = bucket[E]{slot: s, count: 1, sum: x, min: x, max: x, acc: x}
+		return true
+	}
+
+	b.count++
+	b.sum += x
+	b.min = min(b.min, x)
+	b.max = max(b.max, x)
+	if a.reduce != nil {
+		b.acc = a.reduce(b.acc, x)
+	}
+
+	return true
+}
+
+// live calls f for each bucket holding values in the live window, oldest
+// first.
+func (a *Aggregator[E]) live(f func(b *bucket[E])) {
+	cur, n := a.slot(a.now()), int64(len(a.buckets))
+	for s := cur - n + 1; s <= cur; s++ {
+		if b := a.bucket(s); b.count > 0 && b.slot == s {
+			f(b)
+		}
+	}
+}
+
+// Len returns the number of values in the live window.
+func (a *Aggregator[E]) Len() int { return a.Count() }
+
+// Count returns the number of values in the live window.
+func (a *Aggregator[E]) Count() int {
+	n := 0
+	a.live(func(b *bucket[E]) { n += b.count })
+	return n
+}
+
+// Sum returns the sum of the values in the live window.
+func (a *Aggregator[E]) Sum() E {
+	var sum E
+	a.live(func(b *bucket[E]) { sum += b.sum })
+	return sum
+}
+
+// Mean returns the mean of the values in the live window, or 0 if it is
+// empty.
+func (a *Aggregator[E]) Mean() float64 {
+	var sum float64
+	n := 0
+	a.live(func(b *bucket[E]) {
+		sum += float64(b.sum)
+		n += b.count
+	})
+
+	if n == 0 {
+		return 0
+	}
+
+	return sum / float64(n)
+}
+
+// Rate returns the number of values in the live window per second of the
+// window.
+func (a *Aggregator[E]) Rate() float64 {
+	return float64(a.Count()) / (a.span * time.Duration(len(a.buckets))).Seconds()
+}
+
+// Min returns the minimum of the values in the live window.
+// The ok result is false if the window is empty.
+func (a *Aggregator[E]) Min() (m E, ok bool) {
+	a.live(func(b *bucket[E]) {
+		if !ok || b.min < m {
+			m, ok = b.min, true
+		}
+	})
+
+	return m, ok
+}
+
+// Max returns the maximum of the values in the live window.
+// The ok result is false if the window is empty.
+func (a *Aggregator[E]) Max() (m E, ok bool) {
+	a.live(func(b *bucket[E]) {
+		if !ok || b.max > m {
+			m, ok = b.max, true
+		}
+	})
+
+	return m, ok
+}
+
+// Reduce returns the reduction of the values in the live window, in time
+// order of their buckets, by the function set with WithReduce.
+// The ok result is false if the window is empty.
+// It panics if the aggregator has no reduce function.
+func (a *Aggregator[E]) Reduce() (r E, ok bool) {
+	if a.reduce == nil {
+		panic("window: Reduce without reduce function")
+	}
+
+	a.live(func(b *bucket[E]) {
+		if ok {
+			r = a.reduce(r, b.acc)
+		} else {
+			r, ok = b.acc, true
+		}
+	})
+
+	return r, ok
+}
+
+// Clear removes all values from the aggregator.
+func (a *Aggregator[E]) Clear() { clear(a.buckets) }
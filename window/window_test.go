@@ -0,0 +1,87 @@
+package window
+
+import (
+	"testing"
+	"time"
+
+	"github.com/weiwenchen2022/container"
+)
+
+var _ container.Container = (*Aggregator[int])(nil)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func TestAggregator(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	a := New(10*time.Second, 10, WithReduce(func(x, y int) int { return x * y }))
+	a.now = clock.now
+
+	if _, ok := a.Min(); ok || a.Count() != 0 || a.Mean() != 0 {
+		t.Errorf("empty aggregator has values")
+	}
+
+	for i := 1; i <= 10; i++ {
+		a.Add(i)
+		clock.t = clock.t.Add(time.Second)
+	}
+	// the value added at 1000s has now expired
+	if n, s := a.Count(), a.Sum(); n != 9 || s != 54 {
+		t.Errorf("a.Count(), a.Sum() = %d, %d, want 9, 54", n, s)
+	}
+	if m, _ := a.Min(); m != 2 {
+		t.Errorf("a.Min() = %d, want 2", m)
+	}
+	if m, _ := a.Max(); m != 10 {
+		t.Errorf("a.Max() = %d, want 10", m)
+	}
+	if r, _ := a.Reduce(); r != 3628800 {
+		t.Errorf("a.Reduce() = %d, want 3628800", r)
+	}
+	if m := a.Mean(); m != 6 {
+		t.Errorf("a.Mean() = %v, want 6", m)
+	}
+	if r := a.Rate(); r != 0.9 {
+		t.Errorf("a.Rate() = %v, want 0.9", r)
+	}
+
+	// out-of-order values inside the window count; older ones are dropped
+	if !a.AddAt(clock.t.Add(-5*time.Second), 100) {
+		t.Errorf("a.AddAt(5s ago) = false")
+	}
+	if a.AddAt(clock.t.Add(-20*time.Second), 100) {
+		t.Errorf("a.AddAt(20s ago) = true")
+	}
+	if m, _ := a.Max(); m != 100 || a.Count() != 10 {
+		t.Errorf("a.Max(), a.Count() = %d, %d, want 100, 10", m, a.Count())
+	}
+
+	clock.t = clock.t.Add(time.Hour)
+	if n := a.Len(); n != 0 {
+		t.Errorf("a.Len() after an hour = %d, want 0", n)
+	}
+
+	a.Add(1)
+	a.Clear()
+	if n := a.Len(); n != 0 {
+		t.Errorf("a.Len() after Clear = %d, want 0", n)
+	}
+}
+
+func TestNegativeTime(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(-5, 0)}
+	a := New[float64](3*time.Second, 3)
+	a.now = clock.now
+
+	a.Add(1.5)
+	clock.t = clock.t.Add(time.Second)
+	a.Add(2.5)
+	if s := a.Sum(); s != 4 {
+		t.Errorf("a.Sum() = %v, want 4", s)
+	}
+}
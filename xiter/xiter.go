@@ -0,0 +1,213 @@
+// Package xiter provides combinators over iter.Seq that compose with the
+// Values and All iterators of the containers in this module, so pipelines
+// need no intermediate slices.
+//
+// For example, to collect the squares of the first ten even values of a
+// pvector.Vector v:
+//
+//	even := xiter.Filter(v.Values(), func(v int) bool { return v%2 == 0 })
+//	squares := xiter.Map(xiter.Take(even, 10), func(v int) int { return v * v })
+//	s := slices.Collect(squares)
+package xiter
+
+import "iter"
+
+// Map returns an iterator over f applied to the values of seq.
+func Map[In, Out any](seq iter.Seq[In], f func(In) Out) iter.Seq[Out] {
+	return func(yield func(Out) bool) {
+		for v := range seq {
+			if !yield(f(v)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns an iterator over the values of seq for which keep returns true.
+func Filter[E any](seq iter.Seq[E], keep func(E) bool) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for v := range seq {
+			if keep(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Take returns an iterator over the first n values of seq.
+// Values after the first n are not requested from seq.
+func Take[E any](seq iter.Seq[E], n int) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		if n <= 0 {
+			return
+		}
+
+		i := 0
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+
+			if i++; i == n {
+				return
+			}
+		}
+	}
+}
+
+// Drop returns an iterator over the values of seq after the first n.
+func Drop[E any](seq iter.Seq[E], n int) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		i := 0
+		for v := range seq {
+			if i < n {
+				i++
+				continue
+			}
+
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns an iterator over consecutive slices of up to n values of
+// seq. All but the last slice have length n, and each slice is newly
+// allocated.
+// It panics if n is less than 1.
+func Chunk[E any](seq iter.Seq[E], n int) iter.Seq[[]E] {
+	if n < 1 {
+		panic("xiter: chunk size less than 1")
+	}
+
+	return func(yield func([]E) bool) {
+		var chunk []E
+		for v := range seq {
+			if chunk == nil {
+				chunk = make([]E, 0, n)
+			}
+
+			chunk = append(chunk, v)
+			if len(chunk) == n {
+				if !yield(chunk) {
+					return
+				}
+				chunk = nil
+			}
+		}
+
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Zip returns an iterator over pairs of corresponding values of a and b.
+// It stops when either sequence ends.
+func Zip[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		next, stop := iter.Pull(b)
+		defer stop()
+
+		for va := range a {
+			vb, ok := next()
+			if !ok || !yield(va, vb) {
+				return
+			}
+		}
+	}
+}
+
+// Concat returns an iterator over the values of each of seqs in turn.
+func Concat[E any](seqs ...iter.Seq[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, seq := range seqs {
+			for v := range seq {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Tee returns n iterators that each yield the values of seq, which is
+// iterated only once. Values are buffered until every returned iterator
+// has consumed them, so iterators consumed at different paces hold the
+// difference in memory.
+//
+// Each returned iterator may be used once. seq is released when every
+// returned iterator has finished or stopped early; an iterator that is
+// never used keeps it from being released.
+// The returned iterators are not safe for concurrent use.
+func Tee[E any](seq iter.Seq[E], n int) []iter.Seq[E] {
+	t := &tee[E]{seq: seq, bufs: make([][]E, n), finished: make([]bool, n), active: n}
+	seqs := make([]iter.Seq[E], n)
+	for i := range seqs {
+		seqs[i] = func(yield func(E) bool) { t.iterate(i, yield) }
+	}
+
+	return seqs
+}
+
+type tee[E any] struct {
+	seq  iter.Seq[E]
+	next func() (E, bool)
+	stop func()
+	done bool
+
+	bufs     [][]E // values not yet consumed by each iterator
+	finished []bool
+	active   int // iterators not yet finished
+}
+
+func (t *tee[E]) iterate(i int, yield func(E) bool) {
+	if t.finished[i] {
+		return
+	}
+	defer t.finish(i)
+
+	for {
+		if len(t.bufs[i]) > 0 {
+			v := t.bufs[i][0]
+			t.bufs[i] = t.bufs[i][1:]
+			if !yield(v) {
+				return
+			}
+
+			continue
+		}
+
+		if t.done {
+			return
+		}
+
+		if t.next == nil {
+			t.next, t.stop = iter.Pull(t.seq)
+		}
+
+		v, ok := t.next()
+		if !ok {
+			t.done = true
+			return
+		}
+
+		for j := range t.bufs {
+			if j != i && !t.finished[j] {
+				t.bufs[j] = append(t.bufs[j], v)
+			}
+		}
+
+		if !yield(v) {
+			return
+		}
+	}
+}
+
+func (t *tee[E]) finish(i int) {
+	t.bufs[i], t.finished[i] = nil, true
+	if t.active--; t.active == 0 && t.stop != nil {
+		t.stop()
+	}
+}
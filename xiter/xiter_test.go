@@ -0,0 +1,125 @@
+package xiter
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// count returns an iterator over 0, 1, ..., n-1 that records how many
+// values were requested.
+func count(n int, requested *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			*requested = i + 1
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestMapFilter(t *testing.T) {
+	t.Parallel()
+
+	vs := []int{1, 2, 3, 4, 5, 6}
+	even := Filter(slices.Values(vs), func(v int) bool { return v%2 == 0 })
+	got := slices.Collect(Map(even, func(v int) string { return string(rune('a' + v)) }))
+	if want := []string{"c", "e", "g"}; !slices.Equal(want, got) {
+		t.Errorf("Map(Filter) = %v, want %v", got, want)
+	}
+}
+
+func TestTakeDrop(t *testing.T) {
+	t.Parallel()
+
+	var requested int
+	if got := slices.Collect(Take(count(100, &requested), 3)); !slices.Equal(got, []int{0, 1, 2}) || requested != 3 {
+		t.Errorf("Take = %v after %d values, want [0 1 2] after 3", got, requested)
+	}
+	if got := slices.Collect(Take(count(2, &requested), 3)); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("Take past the end = %v", got)
+	}
+	if got := slices.Collect(Take(count(2, &requested), 0)); len(got) != 0 {
+		t.Errorf("Take(0) = %v", got)
+	}
+
+	if got := slices.Collect(Drop(count(5, &requested), 3)); !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("Drop = %v, want [3 4]", got)
+	}
+	if got := slices.Collect(Drop(count(2, &requested), 3)); len(got) != 0 {
+		t.Errorf("Drop past the end = %v", got)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	t.Parallel()
+
+	var requested int
+	got := slices.Collect(Chunk(count(7, &requested), 3))
+	want := [][]int{{0, 1, 2}, {3, 4, 5}, {6}}
+	if !slices.EqualFunc(want, got, slices.Equal) {
+		t.Errorf("Chunk = %v, want %v", got, want)
+	}
+
+	for c := range Chunk(count(7, &requested), 3) {
+		if len(c) != 3 {
+			t.Errorf("first chunk = %v", c)
+		}
+		break
+	}
+}
+
+func TestZipConcat(t *testing.T) {
+	t.Parallel()
+
+	var requested int
+	got := maps.Collect(Zip(slices.Values([]string{"a", "b", "c"}), count(2, &requested)))
+	if want := map[string]int{"a": 0, "b": 1}; !maps.Equal(want, got) {
+		t.Errorf("Zip = %v, want %v", got, want)
+	}
+
+	cat := Concat(slices.Values([]int{1, 2}), slices.Values([]int(nil)), slices.Values([]int{3}))
+	if got := slices.Collect(cat); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Concat = %v, want [1 2 3]", got)
+	}
+	if got := slices.Collect(Take(cat, 2)); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Take(Concat) = %v, want [1 2]", got)
+	}
+}
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	var requested int
+	seqs := Tee(count(5, &requested), 3)
+
+	a := slices.Collect(seqs[0])
+	b := slices.Collect(Take(seqs[1], 2))
+	c := slices.Collect(seqs[2])
+
+	if want := []int{0, 1, 2, 3, 4}; !slices.Equal(a, want) || !slices.Equal(c, want) {
+		t.Errorf("Tee = %v, %v, want %v", a, c, want)
+	}
+	if !slices.Equal(b, []int{0, 1}) {
+		t.Errorf("Take(Tee) = %v, want [0 1]", b)
+	}
+	if requested != 5 {
+		t.Errorf("source iterated %d values, want 5", requested)
+	}
+
+	if got := slices.Collect(seqs[0]); len(got) != 0 {
+		t.Errorf("second use of a tee iterator = %v, want none", got)
+	}
+
+	// interleaved consumption
+	seqs = Tee(count(4, &requested), 2)
+	var got [][2]int
+	for v, w := range Zip(seqs[0], seqs[1]) {
+		got = append(got, [2]int{v, w})
+	}
+	if want := [][2]int{{0, 0}, {1, 1}, {2, 2}, {3, 3}}; !slices.Equal(want, got) {
+		t.Errorf("Zip(Tee) = %v, want %v", got, want)
+	}
+}
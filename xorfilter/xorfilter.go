@@ -0,0 +1,323 @@
+// Package xorfilter implements binary fuse filters, static approximate
+// membership structures.
+//
+// A binary fuse filter (Graf and Lemire, "Binary Fuse Filters: Fast and
+// Smaller Than Xor Filters") is built once from a set of keys and cannot be
+// modified afterwards. It answers membership queries with three memory
+// accesses, uses about 9 bits per key with 8-bit fingerprints, and has a
+// false positive rate of about 1/256, making it smaller and faster than a
+// Bloom filter for immutable sets. A built filter can be serialized with
+// MarshalBinary and embedded in a binary.
+//
+// Keys are 64-bit integers; other data should first be hashed with a
+// stable hash function such as hash/fnv, so that a serialized filter
+// remains valid across processes.
+package xorfilter
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+const (
+	arity         = 3
+	maxIterations = 1024
+	headerSize    = 8 + 4 + 4
+)
+
+// BinaryFuse8 is a binary fuse filter with 8-bit fingerprints.
+// To create a filter use xorfilter.NewBinaryFuse8.
+type BinaryFuse8 struct {
+	seed uint64
+
+	segmentLength      uint32
+	segmentLengthMask  uint32
+	segmentCount       uint32
+	segmentCountLength uint32
+
+	fingerprints []uint8
+}
+
+func segmentLength(size uint32) uint32 {
+	if size == 0 {
+		return 4
+	}
+
+	return min(uint32(1)<<int(math.Floor(math.Log(float64(size))/math.Log(3.33)+2.25)), 1<<18)
+}
+
+func sizeFactor(size uint32) float64 {
+	return max(1.125, 0.875+0.25*math.Log(1000000)/math.Log(float64(size)))
+}
+
+func (f *BinaryFuse8) init(size uint32) {
+	f.segmentLength = segmentLength(size)
+	f.segmentLengthMask = f.segmentLength - 1
+
+	capacity := uint32(0)
+	if size > 1 {
+		capacity = uint32(math.Round(float64(size) * sizeFactor(size)))
+	}
+
+	segments := (capacity + f.segmentLength - 1) / f.segmentLength
+	f.segmentCount = max(segments, arity) - (arity - 1)
+	f.segmentCountLength = f.segmentCount * f.segmentLength
+	f.fingerprints = make([]uint8, (f.segmentCount+arity-1)*f.segmentLength)
+}
+
+// indexes returns the three fingerprint indexes of a hash.
+func (f *BinaryFuse8) indexes(hash uint64) (uint32, uint32, uint32) {
+	hi, _ := bits.Mul64(hash, uint64(f.segmentCountLength))
+	h0 := uint32(hi)
+	h1 := h0 + f.segmentLength
+	h2 := h1 + f.segmentLength
+	h1 ^= uint32(hash>>18) & f.segmentLengthMask
+	h2 ^= uint32(hash) & f.segmentLengthMask
+	return h0, h1, h2
+}
+
+func fingerprint(hash uint64) uint8 { return uint8(hash ^ hash>>32) }
+
+func murmur64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func splitmix64(seed *uint64) uint64 {
+	*seed += 0x9e3779b97f4a7c15
+	z := *seed
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+func mod3(x uint8) uint8 {
+	if x > 2 {
+		x -= 3
+	}
+
+	return x
+}
+
+// NewBinaryFuse8 returns a filter containing keys.
+// Duplicate keys are allowed. An error is returned only if construction
+// fails repeatedly, which is vanishingly unlikely.
+func NewBinaryFuse8(keys []uint64) (*BinaryFuse8, error) {
+	size := uint32(len(keys))
+	f := new(BinaryFuse8)
+	f.init(size)
+
+	rng := uint64(1)
+	f.seed = splitmix64(&rng)
+	capacity := uint32(len(f.fingerprints))
+
+	alone := make([]uint32, capacity)
+	// the low 2 bits hold the xor of which index (0, 1 or 2) each hash
+	// maps here, the remaining 6 bits count the hashes
+	t2count := make([]uint8, capacity)
+	t2hash := make([]uint64, capacity)
+	reverseH := make([]uint8, size)
+	reverseOrder := make([]uint64, size+1)
+	reverseOrder[size] = 1
+
+	blockBits := 1
+	for 1<<blockBits < f.segmentCount {
+		blockBits++
+	}
+
+	var h012 [5]uint32
+	deduplicated := false
+	for iterations := 0; ; iterations++ {
+		if iterations > maxIterations {
+			return nil, errors.New("xorfilter: construction failed")
+		}
+
+		// order the hashes roughly by segment for locality
+		startPos := make([]uint, 1<<blockBits)
+		for i := range startPos {
+			startPos[i] = uint(uint64(i) * uint64(size) >> blockBits)
+		}
+
+		for _, key := range keys {
+			hash := murmur64(key + f.seed)
+			seg := hash >> (64 - blockBits)
+			for reverseOrder[startPos[seg]] != 0 {
+				seg = (seg + 1) & (1<<blockBits - 1)
+			}
+			reverseOrder[startPos[seg]] = hash
+			startPos[seg]++
+		}
+
+		failed := false
+		duplicates := uint32(0)
+		for i := uint32(0); i < size; i++ {
+			hash := reverseOrder[i]
+			i0, i1, i2 := f.indexes(hash)
+			t2count[i0] += 4
+			t2hash[i0] ^= hash
+			t2count[i1] += 4
+			t2count[i1] ^= 1
+			t2hash[i1] ^= hash
+			t2count[i2] += 4
+			t2count[i2] ^= 2
+			t2hash[i2] ^= hash
+
+			// a duplicate key cancels out its twin
+			if t2hash[i0]&t2hash[i1]&t2hash[i2] == 0 {
+				if t2hash[i0] == 0 && t2count[i0] == 8 ||
+					t2hash[i1] == 0 && t2count[i1] == 8 ||
+					t2hash[i2] == 0 && t2count[i2] == 8 {
+					duplicates++
+					t2count[i0] -= 4
+					t2hash[i0] ^= hash
+					t2count[i1] -= 4
+					t2count[i1] ^= 1
+					t2hash[i1] ^= hash
+					t2count[i2] -= 4
+					t2count[i2] ^= 2
+					t2hash[i2] ^= hash
+				}
+			}
+
+			if t2count[i0] < 4 || t2count[i1] < 4 || t2count[i2] < 4 {
+				failed = true // counter overflow
+			}
+		}
+
+		stackSize := uint32(0)
+		if !failed {
+			// peel: repeatedly remove slots with a single hash
+			qsize := 0
+			for i := uint32(0); i < capacity; i++ {
+				alone[qsize] = i
+				if t2count[i]>>2 == 1 {
+					qsize++
+				}
+			}
+
+			for qsize > 0 {
+				qsize--
+				index := alone[qsize]
+				if t2count[index]>>2 != 1 {
+					continue
+				}
+
+				hash := t2hash[index]
+				found := t2count[index] & 3
+				reverseH[stackSize] = found
+				reverseOrder[stackSize] = hash
+				stackSize++
+
+				i0, i1, i2 := f.indexes(hash)
+				h012[1], h012[2], h012[3], h012[4] = i1, i2, i0, i1
+
+				for _, k := range [...]uint8{1, 2} {
+					other := h012[found+k]
+					alone[qsize] = other
+					if t2count[other]>>2 == 2 {
+						qsize++
+					}
+					t2count[other] -= 4
+					t2count[other] ^= mod3(found + k)
+					t2hash[other] ^= hash
+				}
+			}
+		}
+
+		if !failed && stackSize+duplicates == size {
+			size = stackSize
+			break
+		}
+
+		// Duplicates that share all their slots with other keys go
+		// undetected and make peeling fail; remove them once and retry.
+		if !deduplicated {
+			deduplicated = true
+			if unique := slices.Compact(slices.Sorted(slices.Values(keys))); len(unique) < len(keys) {
+				return NewBinaryFuse8(unique)
+			}
+		}
+
+		clear(reverseOrder[:size])
+		clear(t2count)
+		clear(t2hash)
+		f.seed = splitmix64(&rng)
+	}
+
+	for i := int(size) - 1; i >= 0; i-- {
+		hash := reverseOrder[i]
+		i0, i1, i2 := f.indexes(hash)
+		found := reverseH[i]
+		h012[0], h012[1], h012[2], h012[3], h012[4] = i0, i1, i2, i0, i1
+		f.fingerprints[h012[found]] = fingerprint(hash) ^ f.fingerprints[h012[found+1]] ^ f.fingerprints[h012[found+2]]
+	}
+
+	return f, nil
+}
+
+// Contains reports whether key may be in filter f.
+// A false result means key is definitely not in f.
+func (f *BinaryFuse8) Contains(key uint64) bool {
+	hash := murmur64(key + f.seed)
+	i0, i1, i2 := f.indexes(hash)
+	return fingerprint(hash)^f.fingerprints[i0]^f.fingerprints[i1]^f.fingerprints[i2] == 0
+}
+
+// Size returns the size of filter f in bytes, excluding fixed overhead.
+func (f *BinaryFuse8) Size() int { return len(f.fingerprints) }
+
+// Clone returns a copy of filter f.
+func (f *BinaryFuse8) Clone() *BinaryFuse8 {
+	c := *f
+	c.fingerprints = slices.Clone(f.fingerprints)
+	return &c
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (f *BinaryFuse8) MarshalBinary() ([]byte, error) {
+	return f.AppendBinary(make([]byte, 0, headerSize+len(f.fingerprints)))
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface.
+func (f *BinaryFuse8) AppendBinary(b []byte) ([]byte, error) {
+	b = binary.LittleEndian.AppendUint64(b, f.seed)
+	b = binary.LittleEndian.AppendUint32(b, f.segmentLength)
+	b = binary.LittleEndian.AppendUint32(b, f.segmentCount)
+	return append(b, f.fingerprints...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// The filter retains a copy of data.
+func (f *BinaryFuse8) UnmarshalBinary(data []byte) error {
+	if len(data) < headerSize {
+		return errors.New("xorfilter: data too short")
+	}
+
+	seed := binary.LittleEndian.Uint64(data)
+	length := binary.LittleEndian.Uint32(data[8:])
+	count := binary.LittleEndian.Uint32(data[12:])
+	fps := data[headerSize:]
+
+	if length == 0 || length&(length-1) != 0 || count == 0 ||
+		uint64(len(fps)) != (uint64(count)+arity-1)*uint64(length) {
+		return errors.New("xorfilter: invalid data")
+	}
+
+	*f = BinaryFuse8{
+		seed:               seed,
+		segmentLength:      length,
+		segmentLengthMask:  length - 1,
+		segmentCount:       count,
+		segmentCountLength: count * length,
+		fingerprints:       append([]uint8(nil), fps...),
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package xorfilter
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBinaryFuse8(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{0, 1, 2, 10, 1000, 100000} {
+		r := rand.New(rand.NewSource(int64(n)))
+		keys := make([]uint64, n)
+		for i := range keys {
+			keys[i] = r.Uint64()
+		}
+
+		f, err := NewBinaryFuse8(keys)
+		if err != nil {
+			t.Fatalf("NewBinaryFuse8(%d keys): %v", n, err)
+		}
+
+		for _, k := range keys {
+			if !f.Contains(k) {
+				t.Fatalf("%d keys: f.Contains(%d) = false", n, k)
+			}
+		}
+
+		if n < 1000 {
+			continue
+		}
+
+		if bpk := float64(8*f.Size()) / float64(n); n >= 100000 && bpk > 10 {
+			t.Errorf("%d keys: %.2f bits per key, want about 9", n, bpk)
+		}
+
+		fp := 0
+		const queries = 1000000
+		for i := 0; i < queries; i++ {
+			if f.Contains(r.Uint64()) {
+				fp++
+			}
+		}
+		if rate := float64(fp) / queries; rate > 0.006 {
+			t.Errorf("%d keys: false positive rate %v, want about 1/256", n, rate)
+		}
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]uint64, 0, 3000)
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, uint64(i), uint64(i), uint64(i))
+	}
+
+	f, err := NewBinaryFuse8(keys)
+	if err != nil {
+		t.Fatalf("NewBinaryFuse8: %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		if !f.Contains(uint64(i)) {
+			t.Fatalf("f.Contains(%d) = false", i)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	keys := []uint64{1, 2, 3, 5, 8, 13, 21}
+	f, _ := NewBinaryFuse8(keys)
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("f.MarshalBinary(): %v", err)
+	}
+
+	var g BinaryFuse8
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatalf("g.UnmarshalBinary(): %v", err)
+	}
+	for k := uint64(0); k < 100; k++ {
+		if f.Contains(k) != g.Contains(k) {
+			t.Fatalf("Contains(%d) differs after round trip", k)
+		}
+	}
+
+	if err := g.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("UnmarshalBinary of truncated data succeeded")
+	}
+	if err := g.UnmarshalBinary(nil); err == nil {
+		t.Errorf("UnmarshalBinary(nil) succeeded")
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	keys := []uint64{1, 2, 3, 4, 5}
+	f, err := NewBinaryFuse8(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := f.Clone()
+	clear(c.fingerprints)
+	for _, k := range keys {
+		if !f.Contains(k) {
+			t.Errorf("f.Contains(%d) = false after changing the clone", k)
+		}
+	}
+}